@@ -5,6 +5,7 @@ import (
 	"hash"
 	"io"
 	"net"
+	"time"
 
 	"google.golang.org/grpc"
 )
@@ -33,6 +34,7 @@ type Client interface {
 	Conn() *grpc.ClientConn
 	Close() error
 	Shutdown(ctx context.Context) error
+	Drain(ctx context.Context, timeout time.Duration) error
 	Upload(ctx context.Context, meta *UploadMetadata, src io.Reader) (*Archive, error)
 	RemoteDeploy(ctx context.Context, initiator string, dopts *DeployOptions, a *Archive, peers ...*Peer) error
 	Deploy(context.Context, *DeployOptions, *Archive) (*Deploy, error)
@@ -41,9 +43,11 @@ type Client interface {
 	NodeCancel(ctx context.Context) error
 	QuorumInfo(ctx context.Context) (*InfoResponse, error)
 	Info(ctx context.Context) (*StatusResponse, error)
+	Deployed(ctx context.Context) (*DeployedResponse, error)
 	Watch(ctx context.Context, out chan<- *Message) error
 	Dispatch(ctx context.Context, messages ...*Message) error
 	Logs(context.Context, *Peer, []byte) io.ReadCloser
+	Follow(context.Context, *Peer, []byte) io.ReadCloser
 }
 
 // DeployClient - facade interface.
@@ -53,6 +57,7 @@ type DeployClient interface {
 	RemoteDeploy(ctx context.Context, initiator string, dopts *DeployOptions, a *Archive, peers ...*Peer) error
 	Watch(ctx context.Context, out chan<- *Message) error
 	Logs(context.Context, *Peer, []byte) io.ReadCloser
+	Follow(context.Context, *Peer, []byte) io.ReadCloser
 	Cancel(context.Context, *CancelRequest) error
 }
 
@@ -348,6 +348,9 @@ type QuorumClient interface {
 	Info(ctx context.Context, in *InfoRequest, opts ...grpc.CallOption) (*InfoResponse, error)
 	Cancel(ctx context.Context, in *CancelRequest, opts ...grpc.CallOption) (*CancelResponse, error)
 	History(ctx context.Context, in *HistoryRequest, opts ...grpc.CallOption) (*HistoryResponse, error)
+	SetFlag(ctx context.Context, in *SetFlagRequest, opts ...grpc.CallOption) (*SetFlagResponse, error)
+	GetFlag(ctx context.Context, in *GetFlagRequest, opts ...grpc.CallOption) (*GetFlagResponse, error)
+	WatchFlags(ctx context.Context, in *WatchFlagsRequest, opts ...grpc.CallOption) (Quorum_WatchFlagsClient, error)
 }
 
 type quorumClient struct {
@@ -469,6 +472,56 @@ func (c *quorumClient) History(ctx context.Context, in *HistoryRequest, opts ...
 	return out, nil
 }
 
+func (c *quorumClient) SetFlag(ctx context.Context, in *SetFlagRequest, opts ...grpc.CallOption) (*SetFlagResponse, error) {
+	out := new(SetFlagResponse)
+	err := c.cc.Invoke(ctx, "/agent.Quorum/SetFlag", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *quorumClient) GetFlag(ctx context.Context, in *GetFlagRequest, opts ...grpc.CallOption) (*GetFlagResponse, error) {
+	out := new(GetFlagResponse)
+	err := c.cc.Invoke(ctx, "/agent.Quorum/GetFlag", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *quorumClient) WatchFlags(ctx context.Context, in *WatchFlagsRequest, opts ...grpc.CallOption) (Quorum_WatchFlagsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Quorum_ServiceDesc.Streams[2], "/agent.Quorum/WatchFlags", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &quorumWatchFlagsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Quorum_WatchFlagsClient interface {
+	Recv() (*Flag, error)
+	grpc.ClientStream
+}
+
+type quorumWatchFlagsClient struct {
+	grpc.ClientStream
+}
+
+func (x *quorumWatchFlagsClient) Recv() (*Flag, error) {
+	m := new(Flag)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // QuorumServer is the server API for Quorum service.
 // All implementations must embed UnimplementedQuorumServer
 // for forward compatibility
@@ -480,6 +533,9 @@ type QuorumServer interface {
 	Info(context.Context, *InfoRequest) (*InfoResponse, error)
 	Cancel(context.Context, *CancelRequest) (*CancelResponse, error)
 	History(context.Context, *HistoryRequest) (*HistoryResponse, error)
+	SetFlag(context.Context, *SetFlagRequest) (*SetFlagResponse, error)
+	GetFlag(context.Context, *GetFlagRequest) (*GetFlagResponse, error)
+	WatchFlags(*WatchFlagsRequest, Quorum_WatchFlagsServer) error
 	mustEmbedUnimplementedQuorumServer()
 }
 
@@ -508,6 +564,15 @@ func (UnimplementedQuorumServer) Cancel(context.Context, *CancelRequest) (*Cance
 func (UnimplementedQuorumServer) History(context.Context, *HistoryRequest) (*HistoryResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method History not implemented")
 }
+func (UnimplementedQuorumServer) SetFlag(context.Context, *SetFlagRequest) (*SetFlagResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetFlag not implemented")
+}
+func (UnimplementedQuorumServer) GetFlag(context.Context, *GetFlagRequest) (*GetFlagResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFlag not implemented")
+}
+func (UnimplementedQuorumServer) WatchFlags(*WatchFlagsRequest, Quorum_WatchFlagsServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchFlags not implemented")
+}
 func (UnimplementedQuorumServer) mustEmbedUnimplementedQuorumServer() {}
 
 // UnsafeQuorumServer may be embedded to opt out of forward compatibility for this service.
@@ -658,6 +723,63 @@ func _Quorum_History_Handler(srv interface{}, ctx context.Context, dec func(inte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Quorum_SetFlag_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetFlagRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QuorumServer).SetFlag(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/agent.Quorum/SetFlag",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QuorumServer).SetFlag(ctx, req.(*SetFlagRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Quorum_GetFlag_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetFlagRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QuorumServer).GetFlag(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/agent.Quorum/GetFlag",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QuorumServer).GetFlag(ctx, req.(*GetFlagRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Quorum_WatchFlags_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchFlagsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(QuorumServer).WatchFlags(m, &quorumWatchFlagsServer{stream})
+}
+
+type Quorum_WatchFlagsServer interface {
+	Send(*Flag) error
+	grpc.ServerStream
+}
+
+type quorumWatchFlagsServer struct {
+	grpc.ServerStream
+}
+
+func (x *quorumWatchFlagsServer) Send(m *Flag) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 // Quorum_ServiceDesc is the grpc.ServiceDesc for Quorum service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -685,6 +807,14 @@ var Quorum_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "History",
 			Handler:    _Quorum_History_Handler,
 		},
+		{
+			MethodName: "SetFlag",
+			Handler:    _Quorum_SetFlag_Handler,
+		},
+		{
+			MethodName: "GetFlag",
+			Handler:    _Quorum_GetFlag_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -697,6 +827,11 @@ var Quorum_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _Quorum_Watch_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "WatchFlags",
+			Handler:       _Quorum_WatchFlags_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "agent.proto",
 }
@@ -711,6 +846,8 @@ type AgentClient interface {
 	Cancel(ctx context.Context, in *CancelRequest, opts ...grpc.CallOption) (*CancelResponse, error)
 	Shutdown(ctx context.Context, in *ShutdownRequest, opts ...grpc.CallOption) (*ShutdownResponse, error)
 	Logs(ctx context.Context, in *LogRequest, opts ...grpc.CallOption) (Agent_LogsClient, error)
+	Drain(ctx context.Context, in *DrainRequest, opts ...grpc.CallOption) (*DrainResponse, error)
+	Deployed(ctx context.Context, in *DeployedRequest, opts ...grpc.CallOption) (*DeployedResponse, error)
 }
 
 type agentClient struct {
@@ -798,6 +935,24 @@ func (x *agentLogsClient) Recv() (*LogResponse, error) {
 	return m, nil
 }
 
+func (c *agentClient) Drain(ctx context.Context, in *DrainRequest, opts ...grpc.CallOption) (*DrainResponse, error) {
+	out := new(DrainResponse)
+	err := c.cc.Invoke(ctx, "/agent.Agent/Drain", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agentClient) Deployed(ctx context.Context, in *DeployedRequest, opts ...grpc.CallOption) (*DeployedResponse, error) {
+	out := new(DeployedResponse)
+	err := c.cc.Invoke(ctx, "/agent.Agent/Deployed", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // AgentServer is the server API for Agent service.
 // All implementations must embed UnimplementedAgentServer
 // for forward compatibility
@@ -808,6 +963,8 @@ type AgentServer interface {
 	Cancel(context.Context, *CancelRequest) (*CancelResponse, error)
 	Shutdown(context.Context, *ShutdownRequest) (*ShutdownResponse, error)
 	Logs(*LogRequest, Agent_LogsServer) error
+	Drain(context.Context, *DrainRequest) (*DrainResponse, error)
+	Deployed(context.Context, *DeployedRequest) (*DeployedResponse, error)
 	mustEmbedUnimplementedAgentServer()
 }
 
@@ -833,6 +990,12 @@ func (UnimplementedAgentServer) Shutdown(context.Context, *ShutdownRequest) (*Sh
 func (UnimplementedAgentServer) Logs(*LogRequest, Agent_LogsServer) error {
 	return status.Errorf(codes.Unimplemented, "method Logs not implemented")
 }
+func (UnimplementedAgentServer) Drain(context.Context, *DrainRequest) (*DrainResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Drain not implemented")
+}
+func (UnimplementedAgentServer) Deployed(context.Context, *DeployedRequest) (*DeployedResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Deployed not implemented")
+}
 func (UnimplementedAgentServer) mustEmbedUnimplementedAgentServer() {}
 
 // UnsafeAgentServer may be embedded to opt out of forward compatibility for this service.
@@ -957,6 +1120,42 @@ func (x *agentLogsServer) Send(m *LogResponse) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _Agent_Drain_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DrainRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServer).Drain(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/agent.Agent/Drain",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServer).Drain(ctx, req.(*DrainRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Agent_Deployed_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeployedRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServer).Deployed(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/agent.Agent/Deployed",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServer).Deployed(ctx, req.(*DeployedRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Agent_ServiceDesc is the grpc.ServiceDesc for Agent service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -984,6 +1183,14 @@ var Agent_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Shutdown",
 			Handler:    _Agent_Shutdown_Handler,
 		},
+		{
+			MethodName: "Drain",
+			Handler:    _Agent_Drain_Handler,
+		},
+		{
+			MethodName: "Deployed",
+			Handler:    _Agent_Deployed_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -1279,3 +1486,116 @@ var Cluster_ServiceDesc = grpc.ServiceDesc{
 	},
 	Metadata: "agent.proto",
 }
+
+// MembershipClient is the client API for Membership service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type MembershipClient interface {
+	Watch(ctx context.Context, in *MembershipRequest, opts ...grpc.CallOption) (Membership_WatchClient, error)
+}
+
+type membershipClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewMembershipClient(cc grpc.ClientConnInterface) MembershipClient {
+	return &membershipClient{cc}
+}
+
+func (c *membershipClient) Watch(ctx context.Context, in *MembershipRequest, opts ...grpc.CallOption) (Membership_WatchClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Membership_ServiceDesc.Streams[0], "/agent.Membership/Watch", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &membershipWatchClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Membership_WatchClient interface {
+	Recv() (*MembershipEvent, error)
+	grpc.ClientStream
+}
+
+type membershipWatchClient struct {
+	grpc.ClientStream
+}
+
+func (x *membershipWatchClient) Recv() (*MembershipEvent, error) {
+	m := new(MembershipEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// MembershipServer is the server API for Membership service.
+// All implementations must embed UnimplementedMembershipServer
+// for forward compatibility
+type MembershipServer interface {
+	Watch(*MembershipRequest, Membership_WatchServer) error
+	mustEmbedUnimplementedMembershipServer()
+}
+
+// UnimplementedMembershipServer must be embedded to have forward compatible implementations.
+type UnimplementedMembershipServer struct {
+}
+
+func (UnimplementedMembershipServer) Watch(*MembershipRequest, Membership_WatchServer) error {
+	return status.Errorf(codes.Unimplemented, "method Watch not implemented")
+}
+func (UnimplementedMembershipServer) mustEmbedUnimplementedMembershipServer() {}
+
+// UnsafeMembershipServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to MembershipServer will
+// result in compilation errors.
+type UnsafeMembershipServer interface {
+	mustEmbedUnimplementedMembershipServer()
+}
+
+func RegisterMembershipServer(s grpc.ServiceRegistrar, srv MembershipServer) {
+	s.RegisterService(&Membership_ServiceDesc, srv)
+}
+
+func _Membership_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(MembershipRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MembershipServer).Watch(m, &membershipWatchServer{stream})
+}
+
+type Membership_WatchServer interface {
+	Send(*MembershipEvent) error
+	grpc.ServerStream
+}
+
+type membershipWatchServer struct {
+	grpc.ServerStream
+}
+
+func (x *membershipWatchServer) Send(m *MembershipEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// Membership_ServiceDesc is the grpc.ServiceDesc for Membership service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Membership_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "agent.Membership",
+	HandlerType: (*MembershipServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Watch",
+			Handler:       _Membership_Watch_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "agent.proto",
+}
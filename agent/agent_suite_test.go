@@ -4,6 +4,7 @@ import (
 	"io"
 	"log"
 
+	"github.com/james-lawrence/bw/internal/testingx"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
@@ -15,3 +16,5 @@ func TestAgent(t *testing.T) {
 	RegisterFailHandler(Fail)
 	RunSpecs(t, "Agent Suite")
 }
+
+var _ = SynchronizedAfterSuite(func() {}, testingx.Cleanup)
@@ -2,18 +2,73 @@ package agent
 
 import (
 	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"math"
 	"net"
+	"os"
 	"path/filepath"
+	"regexp"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/hashicorp/memberlist"
 
 	"github.com/james-lawrence/bw"
+	"github.com/james-lawrence/bw/backoff"
+	"github.com/james-lawrence/bw/internal/netx"
+	"github.com/james-lawrence/bw/internal/stringsx"
 	"github.com/james-lawrence/bw/internal/systemx"
+	"github.com/james-lawrence/bw/internal/tlsx"
+	"github.com/pkg/errors"
 )
 
+// progress formats supported by the client.
+const (
+	ProgressFormatHuman  = "human"
+	ProgressFormatJSON   = "json"
+	ProgressFormatNDJSON = "ndjson"
+)
+
+// executors supported by the agents for running a deploy.
+const (
+	ExecutorDirective = "directive" // runs the directives within the archive, the default.
+	ExecutorShell     = "shell"     // runs the deploy script within the archive.
+	ExecutorSystemd   = "systemd"   // links and restarts the systemd units within the archive.
+	ExecutorCompose   = "compose"   // brings up the docker compose project within the archive.
+)
+
+// ReservedEnvPrefix prefix of the environment variables populated by the agents during a deploy.
+const ReservedEnvPrefix = "BW_ENVIRONMENT_"
+
+// EnvironmentToken expanded to the name of the environment within the credentials directory
+// of the client configuration, e.g.) ~/.bw/{env}/creds.
+const EnvironmentToken = "{env}"
+
+// client certificate policies supported by the agent's listeners.
+const (
+	ClientAuthRequire = "require" // clients must present a certificate issued by the cluster's authorities.
+	ClientAuthRequest = "request" // clients presenting a certificate must present one issued by the cluster's authorities.
+	ClientAuthNone    = "none"    // clients are never asked for a certificate.
+)
+
+// modes for recovering the raft state when the agent starts.
+const (
+	RaftRecoveryWipe       = "wipe"        // discard the raft state, the agent resynchronizes from the quorum.
+	RaftRecoveryBestEffort = "best-effort" // retain the intact raft state, discarding it only when it is unrecoverable.
+)
+
+// policies for an agent failing to bootstrap the latest deploy.
+const (
+	BootstrapFailurePolicyShutdown = "shutdown" // shutdown the agent, the default.
+	BootstrapFailurePolicyContinue = "continue" // continue running without the latest deploy.
+)
+
+// Redacted placeholder for values omitted from encoded configurations.
+const Redacted = "[redacted]"
+
 // ConfigClientOption options for the client configuration.
 type ConfigClientOption func(*ConfigClient)
 
@@ -22,6 +77,13 @@ func CCOptionTLSConfig(name string) ConfigClientOption {
 	return ConfigClientTLS(name)
 }
 
+// CCOptionPin only accept the agents' certificate with the hex encoded sha256 fingerprint.
+func CCOptionPin(fingerprint string) ConfigClientOption {
+	return func(c *ConfigClient) {
+		c.Credentials.Pin = fingerprint
+	}
+}
+
 // CCOptionInsecure insecure tls configuration
 func CCOptionInsecure(b bool) ConfigClientOption {
 	return func(c *ConfigClient) {
@@ -44,10 +106,33 @@ func CCOptionDeployDataDir(s string) ConfigClientOption {
 	}
 }
 
-// CCOptionConcurrency set the deployment configuration directory for the configuration.
+// CCOptionConcurrency set the deployment concurrency for the configuration.
 func CCOptionConcurrency(d float64) ConfigClientOption {
 	return func(c *ConfigClient) {
-		c.Concurrency = d
+		c.Concurrency = Concurrency(strconv.FormatFloat(d, 'f', -1, 64))
+	}
+}
+
+// CCOptionConcurrencyByLabel set the deployment concurrency of the nodes with the labels,
+// nodes without a matching label use the global concurrency. see CCOptionConcurrency.
+func CCOptionConcurrencyByLabel(m map[string]float64) ConfigClientOption {
+	return func(c *ConfigClient) {
+		c.Deployment.ConcurrencyByLabel = m
+	}
+}
+
+// CCOptionEnvironmentName set the name of the environment the configuration belongs to.
+func CCOptionEnvironmentName(s string) ConfigClientOption {
+	return func(c *ConfigClient) {
+		c.name = s
+	}
+}
+
+// CCOptionTimeout set the deployment timeout for the configuration. decoding the configuration
+// replaces the timeout, overrides must be applied to the loaded configuration, see NewConfigClient.
+func CCOptionTimeout(d time.Duration) ConfigClientOption {
+	return func(c *ConfigClient) {
+		c.Deployment.Timeout = d
 	}
 }
 
@@ -58,6 +143,181 @@ func CCOptionEnvironment(s string) ConfigClientOption {
 	}
 }
 
+// CCOptionEnv merge the variables into the environment of the deploy, environment variables
+// within the values are expanded like the rest of the configuration.
+func CCOptionEnv(env map[string]string) ConfigClientOption {
+	return func(c *ConfigClient) {
+		if c.Deployment.Env == nil {
+			c.Deployment.Env = make(map[string]string, len(env))
+		}
+
+		for k, v := range env {
+			c.Deployment.Env[k] = os.ExpandEnv(v)
+		}
+	}
+}
+
+// CCOptionProgressFormat set the format used to emit deploy progress.
+// one of ProgressFormatHuman, ProgressFormatJSON, or ProgressFormatNDJSON.
+func CCOptionProgressFormat(kind string) ConfigClientOption {
+	return func(c *ConfigClient) {
+		c.ProgressFormat = kind
+	}
+}
+
+// CCOptionSimulate exercise the deploy without executing it on the nodes.
+func CCOptionSimulate(b bool) ConfigClientOption {
+	return func(c *ConfigClient) {
+		c.Deployment.Simulate = b
+	}
+}
+
+// CCOptionDryRun plan the deploy without initiating it on the nodes.
+func CCOptionDryRun(b bool) ConfigClientOption {
+	return func(c *ConfigClient) {
+		c.Deployment.DryRun = b
+	}
+}
+
+// CCOptionReproducibleArchive package the deployspace into a byte identical archive when unchanged.
+func CCOptionReproducibleArchive(b bool) ConfigClientOption {
+	return func(c *ConfigClient) {
+		c.Deployment.ReproducibleArchive = b
+	}
+}
+
+// CCOptionTracing record trace spans for the deploy.
+func CCOptionTracing(b bool) ConfigClientOption {
+	return func(c *ConfigClient) {
+		c.Deployment.Tracing = b
+	}
+}
+
+// CCOptionRetryFailedOnce retry the nodes that fail once after the rollout completes.
+func CCOptionRetryFailedOnce(b bool) ConfigClientOption {
+	return func(c *ConfigClient) {
+		c.Deployment.RetryFailedOnce = b
+	}
+}
+
+// CCOptionRetries re-attempt a node that fails transiently up to n times before the deploy halts.
+func CCOptionRetries(n int) ConfigClientOption {
+	return func(c *ConfigClient) {
+		c.Deployment.Retries = n
+	}
+}
+
+// CCOptionRetryBackoff wait the duration before re-attempting a failed node, see CCOptionRetries.
+func CCOptionRetryBackoff(d time.Duration) ConfigClientOption {
+	return func(c *ConfigClient) {
+		c.Deployment.RetryBackoff = d
+	}
+}
+
+// CCOptionFollow stream the deploy output of each node to the client.
+func CCOptionFollow(b bool) ConfigClientOption {
+	return func(c *ConfigClient) {
+		c.Deployment.Follow = b
+	}
+}
+
+// CCOptionWritePlan write the plan of the deploy to the json file before it is initiated.
+func CCOptionWritePlan(path string) ConfigClientOption {
+	return func(c *ConfigClient) {
+		c.Deployment.WritePlan = path
+	}
+}
+
+// CCOptionRequireQuorum refuse to deploy while the cluster lacks quorum.
+func CCOptionRequireQuorum(b bool) ConfigClientOption {
+	return func(c *ConfigClient) {
+		c.Deployment.RequireQuorum = b
+	}
+}
+
+// CCOptionPrompt prompt before a deploy is started, blank disables the prompt.
+func CCOptionPrompt(s string) ConfigClientOption {
+	return func(c *ConfigClient) {
+		c.Deployment.Prompt = s
+	}
+}
+
+// CCOptionNoPrompt start deploys without prompting.
+func CCOptionNoPrompt() ConfigClientOption {
+	return CCOptionPrompt("")
+}
+
+// CCOptionZone set the zone the client resides within, peers within the zone are preferred.
+func CCOptionZone(zone string) ConfigClientOption {
+	return func(c *ConfigClient) {
+		c.Zone = zone
+	}
+}
+
+// CCOptionRollback rollback to the deploy prior to the latest deploy recorded by the agents.
+func CCOptionRollback() ConfigClientOption {
+	return CCOptionRollbackTo("")
+}
+
+// CCOptionRollbackTo rollback to the archive recorded by the agents matching the ref,
+// either the commit or the deployment id. the archive must still be retained by the agents, see keepN.
+func CCOptionRollbackTo(ref string) ConfigClientOption {
+	return func(c *ConfigClient) {
+		c.Deployment.Rollback.Enabled = true
+		c.Deployment.Rollback.Ref = ref
+	}
+}
+
+// CCOptionPerNodeConcurrency limit the simultaneous operations on each node, <= 0 is unbounded.
+func CCOptionPerNodeConcurrency(n int) ConfigClientOption {
+	return func(c *ConfigClient) {
+		c.Deployment.PerNodeConcurrency = n
+	}
+}
+
+// CCOptionSkipIfUnchanged skip the deploy when every node already runs an identical archive of the commit.
+func CCOptionSkipIfUnchanged(b bool) ConfigClientOption {
+	return func(c *ConfigClient) {
+		c.Deployment.SkipIfUnchanged = b
+	}
+}
+
+// CCOptionPreHook command, in argv form, run by the client before the deploy starts.
+func CCOptionPreHook(argv ...string) ConfigClientOption {
+	return func(c *ConfigClient) {
+		c.Deployment.PreHook = argv
+	}
+}
+
+// CCOptionPostHook command, in argv form, run by the client after the deploy finishes.
+func CCOptionPostHook(argv ...string) ConfigClientOption {
+	return func(c *ConfigClient) {
+		c.Deployment.PostHook = argv
+	}
+}
+
+// CCOptionIncludePeers only deploy to the nodes with names matching any of the glob patterns.
+func CCOptionIncludePeers(patterns ...string) ConfigClientOption {
+	return func(c *ConfigClient) {
+		c.Deployment.IncludePeers = patterns
+	}
+}
+
+// CCOptionExcludePeers skip the nodes with names matching any of the glob patterns,
+// applied after CCOptionIncludePeers.
+func CCOptionExcludePeers(patterns ...string) ConfigClientOption {
+	return func(c *ConfigClient) {
+		c.Deployment.ExcludePeers = patterns
+	}
+}
+
+// CCOptionDeployRoot set the working directory of the deploy, see ConfigClient.WorkDir.
+func CCOptionDeployRoot(dir string) ConfigClientOption {
+	return func(c *ConfigClient) {
+		c.DeployRoot = dir
+	}
+}
+
 // NewConfigClient ...
 func NewConfigClient(template ConfigClient, options ...ConfigClientOption) ConfigClient {
 	for _, opt := range options {
@@ -86,55 +346,206 @@ func DefaultConfigClient(options ...ConfigClientOption) ConfigClient {
 	return NewConfigClient(config, options...)
 }
 
-// ExampleConfigClient creates an example configuration.
+// ExampleConfigClient creates an example configuration, by default the example prompts
+// before deploying, see CCOptionPrompt and CCOptionNoPrompt.
 func ExampleConfigClient(options ...ConfigClientOption) ConfigClient {
-	deploy := defaultDeployment()
-	deploy.Prompt = "are you sure you want to deploy? (remove this field to disable the prompt)"
 	config := ConfigClient{
-		Deployment: deploy,
+		Deployment: defaultDeployment(),
 		Address:    systemx.HostnameOrLocalhost(),
 	}
 
 	ConfigClientTLS(bw.DefaultEnvironmentName)(&config)
+	CCOptionPrompt("are you sure you want to deploy? (remove this field to disable the prompt)")(&config)
 
 	return NewConfigClient(config, options...)
 }
 
 type Deployment struct {
-	DataDir   string        `yaml:"dir"`
-	Timeout   time.Duration `yaml:"timeout"`
-	Prompt    string        `yaml:"prompt"`  // used to prompt before a deploy is started, useful for deploying to sensitive systems like production.
-	CommitRef string        `yaml:"treeish"` // used to populate commit information in the environment
+	DataDir              string                   `yaml:"dir"`
+	Timeout              time.Duration            `yaml:"timeout"`
+	Prompt               string                   `yaml:"prompt"`               // used to prompt before a deploy is started, useful for deploying to sensitive systems like production.
+	CommitRef            string                   `yaml:"treeish"`              // used to populate commit information in the environment
+	Executor             string                   `yaml:"executor"`             // mechanism used by the agents to run the deploy: directive (default), shell, systemd, or compose.
+	Simulate             bool                     `yaml:"-"`                    // exercise the deploy without executing it on the nodes, only set from the command line.
+	MaxTotal             time.Duration            `yaml:"maxTotal"`             // hard cap on the duration of the entire deploy, once exceeded the remaining nodes are skipped. <= 0 disables the cap.
+	TimeoutByEnvironment map[string]time.Duration `yaml:"timeoutByEnvironment"` // timeout of the named environments, overrides the timeout for the active environment.
+	DryRun               bool                     `yaml:"dryRun"`               // plan the deploy without initiating it on the nodes, the archive is still uploaded.
+	ConcurrencyByLabel   map[string]float64       `yaml:"concurrencyByLabel"`   // concurrency of the nodes with the label, nodes without a matching label use the global concurrency. the global concurrency caps the nodes across the labels.
+	Follow               bool                     `yaml:"follow"`               // stream the deploy output of each node to the client while the deploy is in progress.
+	WritePlan            string                   `yaml:"writePlan"`            // json file the plan of the deploy is written to before it is initiated, blank disables the file.
+	RequireQuorum        bool                     `yaml:"requireQuorum"`        // refuse to deploy unless the cluster has a leader and the minimum nodes required by the agents are alive.
+	Env                  map[string]string        `yaml:"env"`                  // additional environment variables of the deploy, e.g.) the build number. keys prefixed with BW_ENVIRONMENT_ are reserved.
+	PerNodeConcurrency   int                      `yaml:"perNodeConcurrency"`   // maximum number of simultaneous operations on each node, e.g.) restarting services. <= 0 is unbounded.
+	ReproducibleArchive  bool                     `yaml:"reproducibleArchive"`  // normalize timestamps, ownership, and permissions of the archive so unchanged deployspaces produce identical archives.
+	Tracing              bool                     `yaml:"tracing"`              // record trace spans for the deploy, the plan, each node, and the agent execution. spans are discarded unless an exporter is registered.
+	RetryFailedOnce      bool                     `yaml:"retryFailedOnce"`      // retry the nodes that fail once after the rollout completes instead of halting the deploy.
+	Retries              int                      `yaml:"retries"`              // number of times a node that fails transiently is re-attempted before the deploy halts, <= 0 disables retries.
+	RetryBackoff         time.Duration            `yaml:"retryBackoff"`         // duration to wait before re-attempting a failed node, the node must be alive for the retry to proceed.
+	SkipIfUnchanged      bool                     `yaml:"skipIfUnchanged"`      // skip the upload and the deploy when every node already runs an identical archive of the commit, requires reproducibleArchive.
+	IncludePeers         []string                 `yaml:"includePeers"`         // glob patterns of the names of the nodes to deploy to, blank includes every node.
+	ExcludePeers         []string                 `yaml:"excludePeers"`         // glob patterns of the names of the nodes to skip, applied after IncludePeers. e.g.) canary-*
+	PreHook              []string                 `yaml:"preHook"`              // command run by the client before the deploy, redeploy, or rollback starts, e.g.) building the artifact. a failure aborts the deploy.
+	PostHook             []string                 `yaml:"postHook"`             // command run by the client after the deploy, redeploy, or rollback finishes regardless of the outcome, e.g.) notifications. failures are only logged.
+	Rollback             struct {
+		Enabled bool   // redeploy a previously recorded archive instead of the located one.
+		Ref     string // commit or deployment id of the archive to rollback to, blank for the deploy prior to the latest.
+	} `yaml:"-"` // only set from the command line.
+}
+
+// detached copies the maps of the deployment.
+func (t Deployment) detached() Deployment {
+	if t.TimeoutByEnvironment != nil {
+		dup := make(map[string]time.Duration, len(t.TimeoutByEnvironment))
+		for k, v := range t.TimeoutByEnvironment {
+			dup[k] = v
+		}
+		t.TimeoutByEnvironment = dup
+	}
+
+	if t.ConcurrencyByLabel != nil {
+		dup := make(map[string]float64, len(t.ConcurrencyByLabel))
+		for k, v := range t.ConcurrencyByLabel {
+			dup[k] = v
+		}
+		t.ConcurrencyByLabel = dup
+	}
+
+	if t.Env != nil {
+		dup := make(map[string]string, len(t.Env))
+		for k, v := range t.Env {
+			dup[k] = v
+		}
+		t.Env = dup
+	}
+
+	return t
 }
 
 // ConfigClient ...
 type ConfigClient struct {
 	root        string `yaml:"-"` // filepath of the configuration on disk.
+	name        string `yaml:"-"` // name of the environment.
 	Address     string // cluster address
-	Concurrency float64
+	Concurrency Concurrency
 	Deployment  Deployment `yaml:"deploy"`
 	Credentials struct {
 		Mode      string `yaml:"source"`
-		Directory string `yaml:"directory"`
+		Directory string `yaml:"directory"` // directory of the credentials, EnvironmentToken is expanded to the name of the environment.
 		Insecure  bool   `yaml:"-"`
+		Pin       string `yaml:"pin"` // hex encoded sha256 fingerprint of the agents' certificate, only the pinned certificate is accepted.
 	} `yaml:"credentials"`
-	CA          string
-	ServerName  string
-	Environment string
+	CA             string
+	ServerName     string
+	Environment    string
+	ProgressFormat string `yaml:"progress"`   // format of the deploy progress emitted by the client: human, json, or ndjson.
+	Zone           string `yaml:"zone"`       // zone the client resides within, peers within the zone are preferred.
+	DeployRoot     string `yaml:"deployRoot"` // working directory of the deploy, overrides the directory derived from the configuration location. relative to the configuration directory.
 }
 
 // LoadConfig create a new configuration from the specified path using the current
 // configuration as the default values for the new configuration.
 func (t ConfigClient) LoadConfig(path string) (ConfigClient, error) {
-	if err := bw.ExpandAndDecodeFile(path, &t); err != nil {
-		return t, err
+	return t.LoadConfigFiles(path)
+}
+
+// LoadConfigFiles create a new configuration from the files decoded in order over the current
+// configuration, i.e.) a base configuration followed by the overrides of the environment.
+// values of later files take precedence over earlier files, maps (e.g. deploy.env) merge
+// key by key while lists are replaced. missing files are ignored. the configuration is
+// relative to the directory of the last file. values may reference other fields of their file,
+// see bw.ExpandEnvironAndDecodeReferences.
+func (t ConfigClient) LoadConfigFiles(paths ...string) (ConfigClient, error) {
+	if len(paths) == 0 {
+		return t, errors.New("at least one configuration file is required")
+	}
+
+	// decoding merges into the existing maps, detach them from the current configuration.
+	t.Deployment = t.Deployment.detached()
+
+	for _, path := range paths {
+		if err := bw.ExpandAndDecodeFileReferences(path, &t); err != nil {
+			return t, errors.Wrapf(err, "unable to load configuration %s", path)
+		}
+	}
+
+	switch t.ProgressFormat {
+	case "", ProgressFormatHuman, ProgressFormatJSON, ProgressFormatNDJSON:
+	default:
+		return t, errors.Errorf("unknown progress format: %s", t.ProgressFormat)
+	}
+
+	switch t.Deployment.Executor {
+	case "", ExecutorDirective, ExecutorShell, ExecutorSystemd, ExecutorCompose:
+	default:
+		return t, errors.Errorf("unknown deploy executor: %s", t.Deployment.Executor)
+	}
+
+	for k := range t.Deployment.Env {
+		if strings.HasPrefix(k, ReservedEnvPrefix) {
+			return t, errors.Errorf("deploy environment variable %s is reserved, variables prefixed with %s are populated by the agents, e.g.) the deploy commit", k, ReservedEnvPrefix)
+		}
+	}
+
+	for _, pattern := range append(append([]string(nil), t.Deployment.IncludePeers...), t.Deployment.ExcludePeers...) {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return t, errors.Wrapf(err, "invalid peer pattern %s", pattern)
+		}
+	}
+
+	if strings.Contains(t.Credentials.Directory, EnvironmentToken) {
+		if t.name == "" {
+			return t, errors.Errorf("credentials directory %s references %s but the name of the environment is unknown", t.Credentials.Directory, EnvironmentToken)
+		}
+
+		t.Credentials.Directory = strings.ReplaceAll(t.Credentials.Directory, EnvironmentToken, t.name)
 	}
 
-	t.root = filepath.Dir(path)
+	t.root = filepath.Dir(paths[len(paths)-1])
+
+	if timeout, ok := t.Deployment.TimeoutByEnvironment[t.name]; ok {
+		t.Deployment.Timeout = timeout
+	}
 
 	return t, nil
 }
 
+// Environ the environment the agents receive, the environment of the configuration
+// followed by the deploy environment variables ordered by name.
+func (t ConfigClient) Environ() string {
+	if len(t.Deployment.Env) == 0 {
+		return t.Environment
+	}
+
+	keys := make([]string, 0, len(t.Deployment.Env))
+	for k := range t.Deployment.Env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	b := strings.Builder{}
+	b.WriteString(t.Environment)
+	if t.Environment != "" && !strings.HasSuffix(t.Environment, "\n") {
+		b.WriteString("\n")
+	}
+
+	for _, k := range keys {
+		// single quotes prevent the value from being expanded by the agents.
+		v := "'" + t.Deployment.Env[k] + "'"
+		if strings.ContainsAny(t.Deployment.Env[k], "'\n") {
+			v = strconv.Quote(t.Deployment.Env[k])
+		}
+
+		b.WriteString(k + "=" + v + "\n")
+	}
+
+	return b.String()
+}
+
+// EnvironmentName the name of the environment the configuration belongs to.
+func (t ConfigClient) EnvironmentName() string {
+	return t.name
+}
+
 // Dir path to the configuration on disk
 func (t ConfigClient) Dir() string {
 	return t.root
@@ -149,30 +560,120 @@ func (t ConfigClient) Deployspace() string {
 	return cdir
 }
 
+// WorkDir the working directory of the deploy, derived from the location of the
+// configuration unless overridden by the deploy root.
 func (t ConfigClient) WorkDir() string {
-	return filepath.Dir(filepath.Dir(t.Dir()))
+	if t.DeployRoot == "" {
+		return filepath.Dir(filepath.Dir(t.Dir()))
+	}
+
+	if filepath.IsAbs(t.DeployRoot) {
+		return t.DeployRoot
+	}
+
+	return filepath.Join(t.Dir(), t.DeployRoot)
+}
+
+// MarshalJSON the effective configuration including the resolved deployspace and work directory.
+// the credentials directory is redacted for insecure configurations.
+func (t ConfigClient) MarshalJSON() ([]byte, error) {
+	type plain ConfigClient
+	dup := plain(t)
+	if dup.Credentials.Insecure {
+		dup.Credentials.Directory = Redacted
+	}
+
+	return json.Marshal(struct {
+		plain
+		Name        string
+		Deployspace string
+		WorkDir     string
+	}{
+		plain:       dup,
+		Name:        t.name,
+		Deployspace: t.Deployspace(),
+		WorkDir:     t.WorkDir(),
+	})
 }
 
 // Partitioner ...
 func (t ConfigClient) Partitioner() (_ bw.Partitioner) {
-	return bw.PartitionFromFloat64(t.Concurrency)
+	p, err := bw.ParsePartitioner(string(t.Concurrency))
+	if err != nil {
+		return bw.PartitionFromFloat64(0)
+	}
+
+	return p
+}
+
+// Partitions previews the batches of the peers deployed to simultaneously using the
+// concurrency of the configuration, peers are partitioned by their labels.
+func (t ConfigClient) Partitions(peers ...*Peer) [][]*Peer {
+	size := bw.ConstantPartitioner(t.Partitioner().Partition(len(peers)))
+	return NewLabelPartitioner(size, t.Deployment.ConcurrencyByLabel).Partitions(peers...)
+}
+
+// Concurrency specification for how many nodes deploy simultaneously.
+// either a number (see bw.PartitionFromFloat64) or auto(min,pct,max).
+type Concurrency string
+
+// UnmarshalYAML validates the concurrency specification.
+func (t *Concurrency) UnmarshalYAML(unmarshal func(interface{}) error) (err error) {
+	var (
+		spec string
+	)
+
+	if err = unmarshal(&spec); err != nil {
+		return err
+	}
+
+	if _, err = bw.ParsePartitioner(spec); err != nil {
+		return err
+	}
+
+	*t = Concurrency(spec)
+
+	return nil
+}
+
+// MarshalYAML numeric specifications are encoded as numbers.
+func (t Concurrency) MarshalYAML() (interface{}, error) {
+	if f, err := strconv.ParseFloat(string(t), 64); err == nil {
+		return f, nil
+	}
+
+	return string(t), nil
 }
 
 // NewConfig creates a default configuration.
 func NewConfig(options ...ConfigOption) Config {
 	c := Config{
-		Name:              systemx.HostnameOrLocalhost(),
-		Root:              bw.DefaultCacheDirectory(),
-		KeepN:             3,
-		SnapshotFrequency: time.Hour,
-		MinimumNodes:      3,
+		Name:                systemx.HostnameOrLocalhost(),
+		Root:                bw.DefaultCacheDirectory(),
+		KeepN:               3,
+		SnapshotFrequency:   time.Hour,
+		MinimumNodes:        3,
+		CertClockSkew:       bw.DefaultCertClockSkew,
+		MaxArchiveBytes:     bw.DefaultMaxArchiveBytes,
+		TLSHandshakeTimeout: tlsx.DefaultHandshakeTimeout,
+		AddressFamily:       netx.FamilyAuto,
+		DrainTimeout:        bw.DefaultDrainTimeout,
 		Bootstrap: bootstrap{
-			Attempts: math.MaxInt32,
+			Attempts:   math.MaxInt32,
+			Backoff:    time.Second,
+			MaxBackoff: 30 * time.Second,
 		},
 		DNSBind: dnsBind{
-			TTL:       60,
-			Frequency: time.Hour,
+			TTL:         60,
+			Frequency:   time.Hour,
+			HealthyOnly: true,
+		},
+		Gossip: gossip{
+			ProbeInterval: bw.DefaultGossipProbeInterval,
+			ProbeTimeout:  bw.DefaultGossipProbeTimeout,
+			SuspicionMult: bw.DefaultGossipSuspicionMult,
 		},
+		RaftSnapshotRetain: bw.DefaultRaftSnapshotRetain,
 	}
 
 	newTLSAgent(bw.DefaultEnvironmentName)(&c)
@@ -206,6 +707,25 @@ func ConfigOptionDefaultBind(ip net.IP) ConfigOption {
 	)
 }
 
+// ConfigOptionBindFromCIDR bind the first local address within the network belonging to the
+// address family, retaining the port of the bind. an address that can't be found is reported by Validate.
+func ConfigOptionBindFromCIDR(n *net.IPNet) ConfigOption {
+	return func(c *Config) {
+		ip, err := netx.LocalWithin(n, c.AddressFamily)
+		if err != nil {
+			c.problems = append(c.problems, errors.Wrap(err, "p2pBind"))
+			return
+		}
+
+		bind := &net.TCPAddr{IP: ip, Port: bw.DefaultP2PPort}
+		if c.P2PBind != nil {
+			bind.Port = c.P2PBind.Port
+		}
+
+		c.P2PBind = bind
+	}
+}
+
 // ConfigOptionP2P sets the address to bind.
 func ConfigOptionP2P(p *net.TCPAddr) ConfigOption {
 	return func(c *Config) {
@@ -220,6 +740,41 @@ func ConfigOptionAdvertised(ip *net.TCPAddr) ConfigOption {
 	}
 }
 
+// ConfigOptionAdvertisedName set the hostname to advertise instead of the ip address.
+func ConfigOptionAdvertisedName(name string) ConfigOption {
+	return func(c *Config) {
+		c.AdvertisedName = name
+	}
+}
+
+// ConfigOptionMetricsBind serve the prometheus metrics of the agent at the address, nil disables the endpoint.
+func ConfigOptionMetricsBind(addr *net.TCPAddr) ConfigOption {
+	return func(c *Config) {
+		c.MetricsBind = addr
+	}
+}
+
+// ConfigOptionRaftSnapshotRetain set the number of raft snapshots retained on disk.
+func ConfigOptionRaftSnapshotRetain(n int) ConfigOption {
+	return func(c *Config) {
+		c.RaftSnapshotRetain = n
+	}
+}
+
+// ConfigOptionSnapshotFrequency set how often the peers of the cluster are snapshotted, <= 0 disables snapshots.
+func ConfigOptionSnapshotFrequency(d time.Duration) ConfigOption {
+	return func(c *Config) {
+		c.SnapshotFrequency = d
+	}
+}
+
+// ConfigOptionAddressFamily set the address family preferred by the agent, see netx.FamilyAuto.
+func ConfigOptionAddressFamily(family string) ConfigOption {
+	return func(c *Config) {
+		c.AddressFamily = family
+	}
+}
+
 // ConfigOptionSecondaryBindings set additional ip/ports to bindings to use.
 func ConfigOptionSecondaryBindings(alternates ...*net.TCPAddr) ConfigOption {
 	return func(c *Config) {
@@ -227,6 +782,22 @@ func ConfigOptionSecondaryBindings(alternates ...*net.TCPAddr) ConfigOption {
 	}
 }
 
+// ConfigOptionAlternateAdvertised set additional addresses advertised to peers, see Config.AlternateAdvertised.
+func ConfigOptionAlternateAdvertised(alternates ...*net.TCPAddr) ConfigOption {
+	return func(c *Config) {
+		c.AlternateAdvertised = alternates
+	}
+}
+
+// ConfigOptionGossip set the failure detection timing of the peering protocol.
+func ConfigOptionGossip(probeInterval, probeTimeout time.Duration, suspicionMult int) ConfigOption {
+	return func(c *Config) {
+		c.Gossip.ProbeInterval = probeInterval
+		c.Gossip.ProbeTimeout = probeTimeout
+		c.Gossip.SuspicionMult = suspicionMult
+	}
+}
+
 // ConfigOptionName set the name of the agent.
 func ConfigOptionName(name string) ConfigOption {
 	return func(c *Config) {
@@ -235,9 +806,59 @@ func ConfigOptionName(name string) ConfigOption {
 }
 
 type bootstrap struct {
-	Attempts         int    `yaml:"attempts"`
-	ReadOnly         bool   `yaml:"readonly"`
-	ArchiveDirectory string `yaml:"archiveDirectory"`
+	Attempts           int                          `yaml:"attempts"`
+	ReadOnly           bool                         `yaml:"readonly"`
+	ArchiveDirectory   string                       `yaml:"archiveDirectory"`
+	FailurePolicy      string                       `yaml:"failurePolicy"`      // shutdown (default) or continue.
+	Environments       map[string]bootstrapOverride `yaml:"environments"`       // overrides keyed by the environment (ServerName) of the agent.
+	Backoff            time.Duration                `yaml:"backoff"`            // initial delay between failed attempts to join the cluster, doubles with each attempt.
+	MaxBackoff         time.Duration                `yaml:"maxBackoff"`         // upper bound of the delay between failed attempts to join the cluster.
+	KeepLastSuccessful bool                         `yaml:"keepLastSuccessful"` // always keep the most recent successful deploy in addition to the keepN most recent deploys.
+	NonVoter           bool                         `yaml:"nonVoter"`           // participate in raft as a non-voter, the agent never counts towards the quorum but still serves archives.
+}
+
+// gossip failure detection timing of the peering protocol, see memberlist.Config. zero uses the defaults.
+type gossip struct {
+	ProbeInterval time.Duration `yaml:"probeInterval"` // interval between the failure detection probes of the peers.
+	ProbeTimeout  time.Duration `yaml:"probeTimeout"`  // duration to wait for a probed peer to acknowledge before probing indirectly, must be less than the probe interval.
+	SuspicionMult int           `yaml:"suspicionMult"` // multiplier of the probe interval a peer is suspected before it's declared dead.
+}
+
+// bootstrapOverride environment specific bootstrap settings, unset fields use the global settings.
+type bootstrapOverride struct {
+	Attempts      *int   `yaml:"attempts"`
+	FailurePolicy string `yaml:"failurePolicy"`
+}
+
+// BootstrapPolicy the bootstrap settings of the agent, the settings of the agent's
+// environment (ServerName) are applied over the global settings.
+func (t Config) BootstrapPolicy() bootstrap {
+	b := t.Bootstrap
+	if o, ok := t.Bootstrap.Environments[t.ServerName]; ok {
+		if o.Attempts != nil {
+			b.Attempts = *o.Attempts
+		}
+
+		if o.FailurePolicy != "" {
+			b.FailurePolicy = o.FailurePolicy
+		}
+	}
+
+	return b
+}
+
+// Retry the delay between failed attempts to join the cluster, exponential with jitter.
+func (t bootstrap) Retry() backoff.Strategy {
+	return backoff.New(
+		backoff.Exponential(t.Backoff),
+		backoff.Jitter(0.25),
+		backoff.Maximum(t.MaxBackoff),
+	)
+}
+
+// Fatal whether a bootstrap failure should shutdown the agent.
+func (t bootstrap) Fatal() bool {
+	return t.FailurePolicy != BootstrapFailurePolicyContinue
 }
 
 // Config - configuration for agent processes.
@@ -247,7 +868,7 @@ type Config struct {
 	KeepN             int           `yaml:"keepN"`
 	MinimumNodes      int           `yaml:"minimumNodes"`
 	Bootstrap         bootstrap     `yaml:"bootstrap"`
-	SnapshotFrequency time.Duration `yaml:"snapshotFrequency"`
+	SnapshotFrequency time.Duration `yaml:"snapshotFrequency"` // how often the peers of the cluster are snapshotted, <= 0 disables snapshots.
 	P2PBind           *net.TCPAddr
 	P2PAdvertised     *net.TCPAddr
 	AlternateBinds    []*net.TCPAddr
@@ -264,15 +885,121 @@ type Config struct {
 	DNSBootstrap []string `yaml:"dnsBootstrap"`
 	AWSBootstrap struct {
 		AutoscalingGroups []string `yaml:"autoscalingGroups"` // additional autoscaling groups to check for instances.
+		RequireIMDSv2     bool     `yaml:"requireIMDSv2"`     // exclusively use the token based (v2) instance metadata service.
 	} `yaml:"awsBootstrap"`
+	RaftApplyBatchSize int           `yaml:"raftApplyBatchSize"` // maximum number of raft log entries to apply in a single batch, <= 1 disables batching.
+	SessionTicketKeys  []string      `yaml:"sessionTicketKeys"`  // files containing TLS session ticket keys, the first is active. when empty a random key is used.
+	ExpectedPeerSANs   []string      `yaml:"expectedPeerSANs"`   // when set peers presenting certificates must have a SAN matching one of these patterns.
+	CertClockSkew      time.Duration `yaml:"certClockSkew"`      // leeway applied to peer certificate validity windows, <= 0 uses the standard verification.
+	BootstrapSources   struct {
+		DNS          *bool `yaml:"dns"`
+		AWS          *bool `yaml:"aws"`
+		GCloud       *bool `yaml:"gcloud"`
+		Azure        *bool `yaml:"azure"`
+		Consul       *bool `yaml:"consul"`
+		Kubernetes   *bool `yaml:"kubernetes"`
+		DigitalOcean *bool `yaml:"digitalocean"`
+	} `yaml:"bootstrapSources"` // when set overrides the enabled bootstrap sources, reapplied when the agent receives SIGHUP.
+	GossipCredentials struct {
+		Directory string `yaml:"directory"`
+		CA        string `yaml:"ca"`
+	} `yaml:"gossipCredentials"` // identity used by the memberlist transport, defaults to the agent's credentials.
+	ClientAuthPolicy struct {
+		P2P        string `yaml:"p2p"`        // policy of the primary listener (P2PBind).
+		Alternates string `yaml:"alternates"` // policy of the alternate listeners (AlternateBinds).
+	} `yaml:"clientAuthPolicy"` // client certificate policy per listener: require, request, or none. blank leaves the default policy.
+	Zone             string        `yaml:"zone"`             // zone the agent resides within, agents and clients prefer peers within their own zone.
+	RaftRecoveryMode string        `yaml:"raftRecoveryMode"` // recovery of the raft state on startup: wipe (default) or best-effort.
+	MaxArchiveBytes  int64         `yaml:"maxArchiveBytes"`  // largest archive accepted from clients, <= 0 accepts archives of any size.
+	SnapshotWarmup   time.Duration `yaml:"snapshotWarmup"`   // duration the cluster's peers must be stable before the initial snapshot, <= 0 snapshots immediately.
+	Multiplex        bool          `yaml:"multiplex"`        // multiplex the rpc and raft connections to each peer over a single tcp connection.
+	AzureBootstrap   struct {
+		ScaleSets []string `yaml:"scaleSets"` // additional scale sets within the agent's resource group to check for instances.
+	} `yaml:"azureBootstrap"`
+	StatusFile         string        `yaml:"statusFile"`        // json status file written on the snapshot cadence, relative paths are within Root. blank disables the file.
+	Labels             []string      `yaml:"labels"`            // labels classifying the agent, published to the cluster. see Deployment.ConcurrencyByLabel.
+	DiscoveryInterval  time.Duration `yaml:"discoveryInterval"` // frequency the bootstrap sources are re-queried for new peers, <= 0 disables discovery after joining.
+	PeerCircuitBreaker struct {
+		Threshold int           `yaml:"threshold"` // consecutive failures to a peer that opens its circuit, <= 0 disables the breaker.
+		Cooldown  time.Duration `yaml:"cooldown"`  // duration the circuit remains open before the peer is retried.
+	} `yaml:"peerCircuitBreaker"` // skips peers that consistently fail to connect when dialing and joining.
+	ConsulBootstrap struct {
+		Service    string `yaml:"service"`    // name of the consul service the agents are registered as, defaults to bw.
+		Datacenter string `yaml:"datacenter"` // datacenter of the service, blank uses the datacenter of the consul agent.
+	} `yaml:"consulBootstrap"`
+	EnableExpvar         bool   `yaml:"enableExpvar"`         // publish the raft metrics via expvar under the bw.raft key, served by the metrics endpoint.
+	MinHealthyForDeploy  int    `yaml:"minHealthyForDeploy"`  // deploys are rejected while fewer members of the cluster are healthy, <= 0 disables the check.
+	PrimaryClusterToken  string `yaml:"primaryClusterToken"`  // token used as the primary gossip encryption key, clusterTokens become decryption only keys. blank uses the first cluster token.
+	ClusterTokensFile    string `yaml:"clusterTokensFile"`    // file of additional cluster tokens (newline or comma separated), relative paths are within Root.
+	VerifyArchiveWorkers int    `yaml:"verifyArchiveWorkers"` // verify downloaded archives against their checksum reading with n workers, <= 0 disables verification.
+	KubernetesBootstrap  struct {
+		Namespace  string `yaml:"namespace"`  // namespace of the agents, blank uses the namespace of the service account or kubeconfig context.
+		Service    string `yaml:"service"`    // headless service whose ready endpoints are the peers.
+		Selector   string `yaml:"selector"`   // label selector of the agent pods, takes precedence over the service.
+		Kubeconfig string `yaml:"kubeconfig"` // kubeconfig used outside of a cluster, defaults to KUBECONFIG then ~/.kube/config.
+	} `yaml:"kubernetesBootstrap"`
+	StartupMaintenance    time.Duration `yaml:"startupMaintenance"` // duration the agent remains in maintenance after starting, avoiding leadership during rolling restarts. <= 0 disables.
+	JoinRateLimit         int           `yaml:"joinRateLimit"`      // joins accepted per second from peers that aren't members, throttled peers are told when to retry. <= 0 disables the limit.
+	AdvertisedName        string        `yaml:"advertisedName"`     // hostname advertised to peers alongside the advertised ip, resolved by the dialers of the peers before falling back to the ip.
+	MaxClockDrift         time.Duration `yaml:"maxClockDrift"`      // refuse to join peers whose clock drifts from the agent's clock by more than the duration. <= 0 disables the check.
+	TLSMinVersion         string        `yaml:"tlsMinVersion"`      // minimum tls version accepted by the agent, e.g.) 1.3. blank uses the go default.
+	TLSCipherSuites       []string      `yaml:"tlsCipherSuites"`    // names of the cipher suites negotiated by the agent for tls 1.2, empty uses the go defaults.
+	AllowedClientCIDRs    []string      `yaml:"allowedClientCIDRs"` // ip ranges allowed to connect to the agent's rpc, connections from other addresses are closed. empty allows every address.
+	DigitalOceanBootstrap struct {
+		Tag    string `yaml:"tag"`    // tag of the droplets running the agents, defaults to bw.
+		Region string `yaml:"region"` // region slug of the droplets, blank uses the droplets from every region.
+	} `yaml:"digitaloceanBootstrap"`
+	TLSHandshakeTimeout       time.Duration  `yaml:"tlsHandshakeTimeout"`       // maximum duration of the tls handshake of accepted connections and of establishing dialed connections, stalled handshakes are aborted.
+	MaxEnvironmentConcurrency map[string]int `yaml:"maxEnvironmentConcurrency"` // cap on the nodes simultaneously deployed to by a deploy, keyed by the environment (ServerName) of the agent. enforced by the leader regardless of the concurrency requested by the client.
+	AddressFamily             string         `yaml:"addressFamily"`             // address family preferred by dual-stack agents when resolving peers and selecting the advertised address: ipv4, ipv6, or auto.
+	DrainTimeout              time.Duration  `yaml:"drainTimeout"`              // duration the agent has to drain on shutdown, transferring raft leadership and leaving the cluster. <= 0 exits without draining.
+	MetricsBind               *net.TCPAddr   `yaml:"metricsBind"`               // address serving the prometheus metrics of the agent at /metrics, nil disables the endpoint.
+	AlternateAdvertised       []*net.TCPAddr `yaml:"alternateAdvertised"`       // additional addresses advertised to peers for split-horizon networks, peers prefer the address within their local networks.
+	Gossip                    gossip         `yaml:"gossip"`                    // failure detection timing of the peering protocol, relax for high latency links.
+	RaftSnapshotRetain        int            `yaml:"raftSnapshotRetain"`        // number of raft snapshots retained on disk, independent of keepN. defaults to 5.
+	problems                  []error        // encountered while applying the options, reported by Validate.
+}
+
+// MarshalJSON the sanitized configuration, the cluster tokens are never encoded.
+func (t Config) MarshalJSON() ([]byte, error) {
+	type plain Config
+	return json.Marshal(plain(t.Sanitize()))
 }
 
 func (t Config) Sanitize() Config {
 	dup := t
 	dup.ClusterTokens = []string{}
+	dup.PrimaryClusterToken = ""
 	return dup
 }
 
+// Fingerprint of the configuration, agents sharing a configuration have the same fingerprint.
+// the identity of the agent (name and addresses) and the settings that legitimately differ
+// between the agents of a cluster (zone, labels, and raft membership) are excluded. the
+// cluster tokens are included, only their digest is exposed.
+func (t Config) Fingerprint() (string, error) {
+	// the configuration is encoded without sanitizing, see MarshalJSON.
+	type plain Config
+	dup := t
+	dup.Name = ""
+	dup.P2PBind = nil
+	dup.P2PAdvertised = nil
+	dup.AdvertisedName = ""
+	dup.AlternateBinds = nil
+	dup.AlternateAdvertised = nil
+	dup.Zone = ""
+	dup.Labels = nil
+	dup.Bootstrap.NonVoter = false
+
+	encoded, err := json.Marshal(plain(dup))
+	if err != nil {
+		return "", errors.Wrap(err, "failed to encode configuration")
+	}
+
+	digest := sha256.Sum256(encoded)
+	return hex.EncodeToString(digest[:]), nil
+}
+
 // EnsureDefaults values after configuration load
 func (t Config) EnsureDefaults() Config {
 	if t.CredentialsDir == "" {
@@ -287,16 +1014,244 @@ func (t Config) EnsureDefaults() Config {
 		t.CA = filepath.Join(t.CredentialsDir, bw.DefaultTLSCertCA)
 	}
 
+	if t.GossipCredentials.Directory == "" {
+		t.GossipCredentials.Directory = t.CredentialsDir
+	}
+
+	if t.GossipCredentials.CA == "" {
+		t.GossipCredentials.CA = t.CA
+	}
+
 	if t.P2PAdvertised == nil {
 		t.P2PAdvertised = t.P2PBind
 	}
 
+	// a wildcard bind is unroutable when advertised to the peers, advertise the
+	// address of the primary interface instead. an unresolvable address is reported by Validate.
+	if t.P2PAdvertised != nil && unspecified(t.P2PAdvertised.IP) {
+		if ip, err := netx.PrimaryIP(t.AddressFamily); err == nil {
+			dup := *t.P2PAdvertised
+			dup.IP = ip
+			t.P2PAdvertised = &dup
+		}
+	}
+
+	if t.ClusterTokensFile != "" && !filepath.IsAbs(t.ClusterTokensFile) {
+		t.ClusterTokensFile = filepath.Join(t.Root, t.ClusterTokensFile)
+	}
+
+	if t.Gossip.ProbeInterval == 0 {
+		t.Gossip.ProbeInterval = bw.DefaultGossipProbeInterval
+	}
+
+	if t.Gossip.ProbeTimeout == 0 {
+		t.Gossip.ProbeTimeout = bw.DefaultGossipProbeTimeout
+	}
+
+	if t.Gossip.SuspicionMult == 0 {
+		t.Gossip.SuspicionMult = bw.DefaultGossipSuspicionMult
+	}
+
+	if t.RaftSnapshotRetain == 0 {
+		t.RaftSnapshotRetain = bw.DefaultRaftSnapshotRetain
+	}
+
+	t.ClusterTokens = t.clusterTokens()
+
 	return t
 }
 
+// clusterTokens merges the inline cluster tokens with the tokens from the tokens file
+// and then the environment, removing duplicates. the inline tokens retain their order.
+// an unreadable tokens file is reported by Validate.
+func (t Config) clusterTokens() (tokens []string) {
+	var (
+		sources []string
+		seen    = make(map[string]struct{}, len(t.ClusterTokens))
+	)
+
+	if t.ClusterTokensFile != "" {
+		if raw, err := os.ReadFile(t.ClusterTokensFile); err == nil {
+			sources = append(sources, string(raw))
+		}
+	}
+
+	sources = append(sources, os.Getenv(bw.EnvAgentClusterTokens))
+
+	candidates := append([]string{}, t.ClusterTokens...)
+	for _, src := range sources {
+		candidates = append(candidates, strings.FieldsFunc(src, func(r rune) bool {
+			return r == '\n' || r == ','
+		})...)
+	}
+
+	for _, token := range candidates {
+		token = strings.TrimSpace(token)
+		if token == "" {
+			continue
+		}
+
+		if _, ok := seen[token]; ok {
+			continue
+		}
+
+		seen[token] = struct{}{}
+		tokens = append(tokens, token)
+	}
+
+	return tokens
+}
+
+// ConfigErrors every problem found while validating a configuration.
+type ConfigErrors []error
+
+func (t ConfigErrors) Error() string {
+	msgs := make([]string, 0, len(t))
+	for _, err := range t {
+		msgs = append(msgs, err.Error())
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+// Validate the configuration, reports every problem at once as ConfigErrors.
+func (t Config) Validate() error {
+	var (
+		problems = append(ConfigErrors(nil), t.problems...)
+	)
+
+	if strings.TrimSpace(t.Name) == "" {
+		problems = append(problems, errors.New("name must not be empty"))
+	}
+
+	if t.MinimumNodes < 1 {
+		problems = append(problems, errors.Errorf("minimumNodes must be at least 1: %d", t.MinimumNodes))
+	}
+
+	if t.KeepN < 0 {
+		problems = append(problems, errors.Errorf("keepN must not be negative: %d", t.KeepN))
+	}
+
+	if t.P2PBind != nil && t.P2PBind.Port == 0 {
+		problems = append(problems, errors.Errorf("p2pBind must specify a port: %s", t.P2PBind))
+	}
+
+	if t.P2PAdvertised != nil && unspecified(t.P2PAdvertised.IP) {
+		problems = append(problems, errors.Errorf("p2pAdvertised must be a routable address, unable to resolve the address of the primary interface: %s", t.P2PAdvertised))
+	}
+
+	for idx, alt := range t.AlternateAdvertised {
+		if alt == nil || unspecified(alt.IP) || alt.Port == 0 {
+			problems = append(problems, errors.Errorf("alternateAdvertised[%d] must be a routable address with a port: %s", idx, alt))
+		}
+	}
+
+	if t.SnapshotFrequency > 0 && t.SnapshotFrequency < time.Second {
+		problems = append(problems, errors.Errorf("snapshotFrequency must be at least 1s or <= 0 to disable snapshots: %s", t.SnapshotFrequency))
+	}
+
+	if t.ClusterTokensFile != "" {
+		if _, err := os.ReadFile(t.ClusterTokensFile); err != nil {
+			problems = append(problems, errors.Wrap(err, "clusterTokensFile must be readable"))
+		}
+	}
+
+	if info, err := os.Stat(t.CA); err == nil && info.IsDir() {
+		problems = append(problems, errors.Errorf("ca must be a file not a directory: %s", t.CA))
+	}
+
+	if t.Gossip.ProbeInterval < 0 || t.Gossip.ProbeTimeout < 0 || t.Gossip.SuspicionMult < 0 {
+		problems = append(problems, errors.Errorf("gossip.probeInterval, gossip.probeTimeout, and gossip.suspicionMult must not be negative: %s %s %d", t.Gossip.ProbeInterval, t.Gossip.ProbeTimeout, t.Gossip.SuspicionMult))
+	} else if t.Gossip.ProbeInterval > 0 && t.Gossip.ProbeTimeout >= t.Gossip.ProbeInterval {
+		problems = append(problems, errors.Errorf("gossip.probeTimeout must be less than gossip.probeInterval: %s >= %s", t.Gossip.ProbeTimeout, t.Gossip.ProbeInterval))
+	}
+
+	if t.RaftSnapshotRetain < 0 {
+		problems = append(problems, errors.Errorf("raftSnapshotRetain must not be negative: %d", t.RaftSnapshotRetain))
+	}
+
+	if t.Bootstrap.Backoff <= 0 {
+		problems = append(problems, errors.Errorf("bootstrap.backoff must be positive: %s", t.Bootstrap.Backoff))
+	}
+
+	if t.Bootstrap.MaxBackoff < t.Bootstrap.Backoff {
+		problems = append(problems, errors.Errorf("bootstrap.maxBackoff must be at least bootstrap.backoff: %s < %s", t.Bootstrap.MaxBackoff, t.Bootstrap.Backoff))
+	}
+
+	if _, err := tlsx.ParseVersion(t.TLSMinVersion); err != nil {
+		problems = append(problems, errors.Wrap(err, "tlsMinVersion"))
+	}
+
+	if _, err := tlsx.ParseCipherSuites(t.TLSCipherSuites...); err != nil {
+		problems = append(problems, errors.Wrap(err, "tlsCipherSuites"))
+	}
+
+	if _, err := netx.ParseFamily(t.AddressFamily); err != nil {
+		problems = append(problems, errors.Wrap(err, "addressFamily"))
+	}
+
+	if _, err := netx.ParseCIDRs(t.AllowedClientCIDRs...); err != nil {
+		problems = append(problems, errors.Wrap(err, "allowedClientCIDRs"))
+	}
+
+	if t.DNSBind.Zone != "" || t.DNSBind.RecordName != "" {
+		if name := t.DNSBind.Name(t.ServerName); !validDNSName(name) {
+			problems = append(problems, errors.Errorf("dnsBind zone and recordName must form a valid dns name: %s", name))
+		}
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+
+	return errors.Wrap(problems, "invalid configuration")
+}
+
+func unspecified(ip net.IP) bool {
+	return len(ip) == 0 || ip.IsUnspecified()
+}
+
+// DistinctGossipCredentials true when the memberlist transport uses a different identity
+// than the rest of the agent.
+func (t Config) DistinctGossipCredentials() bool {
+	return t.GossipCredentials.Directory != t.CredentialsDir || t.GossipCredentials.CA != t.CA
+}
+
 type dnsBind struct {
-	TTL       uint32 // TTL for the generated records.
-	Frequency time.Duration
+	TTL         uint32 // TTL for the generated records.
+	Frequency   time.Duration
+	HealthyOnly bool         `yaml:"healthyOnly"` // omit nodes memberlist considers unhealthy from the records.
+	Zone        string       `yaml:"zone"`        // domain the records are served within, e.g.) bw.internal. blank uses the server name.
+	RecordName  string       `yaml:"recordName"`  // name of the record within the zone, blank serves the zone itself.
+	Address     *net.TCPAddr `yaml:"address"`     // address the agent serves the records at over udp and tcp, nil disables the server.
+}
+
+// Name of the records served for the cluster, defaults to the server name.
+func (t dnsBind) Name(servername string) string {
+	zone := stringsx.DefaultIfBlank(strings.TrimSuffix(t.Zone, "."), servername)
+	if t.RecordName == "" {
+		return zone
+	}
+
+	return strings.TrimSuffix(t.RecordName, ".") + "." + zone
+}
+
+// valid dns label, underscores are permitted for service records.
+var dnslabel = regexp.MustCompile(`^[A-Za-z0-9_]([A-Za-z0-9-]{0,61}[A-Za-z0-9])?$`)
+
+// validDNSName checks the name is a valid dns name.
+func validDNSName(name string) bool {
+	if name == "" || len(name) > 253 {
+		return false
+	}
+
+	for _, label := range strings.Split(name, ".") {
+		if !dnslabel.MatchString(label) {
+			return false
+		}
+	}
+
+	return true
 }
 
 // Clone the config applying any provided options.
@@ -309,16 +1264,42 @@ func (t Config) Clone(options ...ConfigOption) Config {
 }
 
 // Peer - builds the Peer information from the configuration.
+// the advertised name is carried alongside the advertised ip, see DialRoutes.
 func (t Config) Peer() *Peer {
 	return &Peer{
-		Status:  Peer_Node,
-		Name:    t.Name,
-		Ip:      t.P2PAdvertised.IP.String(),
-		P2PPort: uint32(t.P2PAdvertised.Port),
+		Status:         Peer_Node,
+		Name:           t.Name,
+		Ip:             t.P2PAdvertised.IP.String(),
+		AdvertisedName: t.AdvertisedName,
+		P2PPort:        uint32(t.P2PAdvertised.Port),
+		Zone:           t.Zone,
+		Labels:         t.Labels,
+		Nonvoter:       t.Bootstrap.NonVoter,
+		Alternates:     netx.AddrToString(t.AlternateAdvertised...),
 	}
 }
 
+// Lint reports weaknesses of the configuration that don't prevent the agent from running,
+// currently weak cluster tokens. see bw.GenerateClusterToken for generating strong tokens.
+func (t Config) Lint() (warnings []error) {
+	for idx, token := range t.ClusterTokens {
+		if err := bw.ClusterTokenStrength(token); err != nil {
+			warnings = append(warnings, errors.Wrapf(err, "clusterTokens[%d]", idx))
+		}
+	}
+
+	if t.PrimaryClusterToken != "" {
+		if err := bw.ClusterTokenStrength(t.PrimaryClusterToken); err != nil {
+			warnings = append(warnings, errors.Wrap(err, "primaryClusterToken"))
+		}
+	}
+
+	return warnings
+}
+
 // Keyring - returns the hash of the Secret.
+// when PrimaryClusterToken is set it is the primary key and every cluster token
+// is a secondary key, otherwise the first cluster token is the primary key.
 func (t Config) Keyring() (ring *memberlist.Keyring, err error) {
 	var (
 		tokens [][]byte
@@ -329,6 +1310,11 @@ func (t Config) Keyring() (ring *memberlist.Keyring, err error) {
 		tokens = append(tokens, hashed[:])
 	}
 
+	if t.PrimaryClusterToken != "" {
+		hashed := sha256.Sum256([]byte(t.PrimaryClusterToken))
+		return memberlist.NewKeyring(tokens, hashed[:])
+	}
+
 	switch len(tokens) {
 	case 0:
 		hashed := sha256.Sum256([]byte(t.ServerName))
@@ -0,0 +1,525 @@
+package agent_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	yaml "gopkg.in/yaml.v2"
+
+	"github.com/james-lawrence/bw"
+	. "github.com/james-lawrence/bw/agent"
+	"github.com/james-lawrence/bw/directives/shell"
+	"github.com/james-lawrence/bw/internal/testingx"
+)
+
+var _ = Describe("ConfigClient", func() {
+	DescribeTable("Partitioner", func(content string, length, expected int) {
+		path := filepath.Join(testingx.TempDir(), "config.yml")
+		Expect(os.WriteFile(path, []byte(content), 0600)).To(Succeed())
+		c, err := DefaultConfigClient().LoadConfig(path)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(c.Partitioner().Partition(length)).To(Equal(expected))
+	},
+		Entry("auto at the minimum", "concurrency: auto(2,10,20)\n", 10, 2),
+		Entry("auto within range", "concurrency: auto(2,10,20)\n", 120, 12),
+		Entry("auto at the maximum", "concurrency: auto(2,10,20)\n", 500, 20),
+		Entry("percentage", "concurrency: 0.25\n", 100, 25),
+		Entry("constant", "concurrency: 4\n", 100, 4),
+	)
+
+	DescribeTable("timeoutByEnvironment", func(environment string, expected time.Duration) {
+		path := filepath.Join(testingx.TempDir(), "config.yml")
+		content := "deploy:\n  timeout: 10m\n  timeoutByEnvironment:\n    onprem: 30m\n    ci: 2m\n"
+		Expect(os.WriteFile(path, []byte(content), 0600)).To(Succeed())
+		c, err := DefaultConfigClient(CCOptionEnvironmentName(environment)).LoadConfig(path)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(c.Deployment.Timeout).To(Equal(expected))
+	},
+		Entry("environment timeout", "onprem", 30*time.Minute),
+		Entry("fast environment timeout", "ci", 2*time.Minute),
+		Entry("scalar timeout", "production", 10*time.Minute),
+	)
+
+	It("should allow overriding the timeout of the loaded configuration", func() {
+		path := filepath.Join(testingx.TempDir(), "config.yml")
+		Expect(os.WriteFile(path, []byte("deploy:\n  timeout: 10m\n  timeoutByEnvironment:\n    onprem: 30m\n"), 0600)).To(Succeed())
+		c, err := DefaultConfigClient(CCOptionEnvironmentName("onprem")).LoadConfig(path)
+		Expect(err).ToNot(HaveOccurred())
+		c = NewConfigClient(c, CCOptionTimeout(time.Minute))
+		Expect(c.Deployment.Timeout).To(Equal(time.Minute))
+	})
+
+	It("should encode the resolved paths as json", func() {
+		path := filepath.Join(testingx.TempDir(), ".bw", "environments", "production", "config.yml")
+		Expect(os.MkdirAll(filepath.Dir(path), 0700)).To(Succeed())
+		Expect(os.WriteFile(path, []byte("deploy:\n  dir: archive\ncredentials:\n  directory: /etc/bw\n"), 0600)).To(Succeed())
+		c, err := DefaultConfigClient(CCOptionEnvironmentName("production")).LoadConfig(path)
+		Expect(err).ToNot(HaveOccurred())
+
+		var decoded map[string]interface{}
+		encoded, err := json.Marshal(c)
+		Expect(err).To(Succeed())
+		Expect(json.Unmarshal(encoded, &decoded)).To(Succeed())
+		Expect(decoded).To(HaveKeyWithValue("Name", "production"))
+		Expect(decoded).To(HaveKeyWithValue("WorkDir", c.WorkDir()))
+		Expect(decoded).To(HaveKeyWithValue("Deployspace", filepath.Join(c.WorkDir(), "archive")))
+		Expect(decoded["Credentials"]).To(HaveKeyWithValue("Directory", "/etc/bw"))
+	})
+
+	It("should use the deploy root for the deployspace when set", func() {
+		root := filepath.Join(string(filepath.Separator), "srv", "deploys")
+		path := filepath.Join(testingx.TempDir(), ".bw", "environments", "production", "config.yml")
+		Expect(os.MkdirAll(filepath.Dir(path), 0700)).To(Succeed())
+		Expect(os.WriteFile(path, []byte(fmt.Sprintf("deployRoot: %s\ndeploy:\n  dir: archive\n", root)), 0600)).To(Succeed())
+		c, err := DefaultConfigClient().LoadConfig(path)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(c.WorkDir()).To(Equal(root))
+		Expect(c.Deployspace()).To(Equal(filepath.Join(root, "archive")))
+	})
+
+	It("should derive the deployspace from the configuration location by default", func() {
+		workdir := testingx.TempDir()
+		path := filepath.Join(workdir, ".bw", "environments", "production", "config.yml")
+		Expect(os.MkdirAll(filepath.Dir(path), 0700)).To(Succeed())
+		Expect(os.WriteFile(path, []byte("deploy:\n  dir: archive\n"), 0600)).To(Succeed())
+		c, err := DefaultConfigClient().LoadConfig(path)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(c.WorkDir()).To(Equal(filepath.Join(workdir, ".bw")))
+		Expect(c.Deployspace()).To(Equal(filepath.Join(workdir, ".bw", "archive")))
+	})
+
+	It("should resolve a relative deploy root against the configuration directory", func() {
+		c := NewConfigClient(DefaultConfigClient(), CCOptionDeployRoot("workspace"))
+		Expect(c.WorkDir()).To(Equal(filepath.Join(c.Dir(), "workspace")))
+	})
+
+	It("should merge the deploy environment variables into the environment", func() {
+		GinkgoT().Setenv("BW_TEST_BUILD_NUMBER", "42")
+		path := filepath.Join(testingx.TempDir(), "config.yml")
+		content := "environment: |\n  FOO=bar\ndeploy:\n  env:\n    BUILD_NUMBER: ${BW_TEST_BUILD_NUMBER}\n    ARTIFACT_URL: https://example.com/a b\n"
+		Expect(os.WriteFile(path, []byte(content), 0600)).To(Succeed())
+		c, err := DefaultConfigClient(CCOptionEnv(map[string]string{"RELEASE": "$BW_TEST_BUILD_NUMBER-rc"})).LoadConfig(path)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(c.Deployment.Env).To(Equal(map[string]string{"BUILD_NUMBER": "42", "ARTIFACT_URL": "https://example.com/a b", "RELEASE": "42-rc"}))
+		Expect(shell.Environ(c.Environ())).To(ConsistOf("FOO=bar", "BUILD_NUMBER=42", "ARTIFACT_URL=\"https://example.com/a b\"", "RELEASE=42-rc"))
+	})
+
+	It("should resolve references to other fields of the configuration", func() {
+		path := filepath.Join(testingx.TempDir(), "config.yml")
+		Expect(os.WriteFile(path, []byte("deploy:\n  dir: archive\n  env:\n    ARCHIVE: ${deploy.dir}\n"), 0600)).To(Succeed())
+		c, err := DefaultConfigClient().LoadConfig(path)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(c.Deployment.Env).To(HaveKeyWithValue("ARCHIVE", "archive"))
+
+		Expect(os.WriteFile(path, []byte("deploy:\n  env:\n    ARCHIVE: ${BW_TEST_UNDEFINED}\n"), 0600)).To(Succeed())
+		_, err = DefaultConfigClient().LoadConfig(path)
+		Expect(err).To(MatchError(ContainSubstring("undefined environment variable: ${BW_TEST_UNDEFINED}")))
+	})
+
+	It("should reject deploy environment variables colliding with the reserved variables", func() {
+		path := filepath.Join(testingx.TempDir(), "config.yml")
+		Expect(os.WriteFile(path, []byte("deploy:\n  env:\n    BW_ENVIRONMENT_DEPLOY_COMMIT: abc\n"), 0600)).To(Succeed())
+		_, err := DefaultConfigClient().LoadConfig(path)
+		Expect(err).To(MatchError(ContainSubstring("BW_ENVIRONMENT_DEPLOY_COMMIT is reserved")))
+	})
+
+	It("should allow disabling the prompt of the example configuration", func() {
+		Expect(ExampleConfigClient().Deployment.Prompt).ToNot(BeEmpty())
+		Expect(ExampleConfigClient(CCOptionPrompt("deploy to production?")).Deployment.Prompt).To(Equal("deploy to production?"))
+		Expect(ExampleConfigClient(CCOptionNoPrompt()).Deployment.Prompt).To(BeEmpty())
+	})
+
+	DescribeTable("should expand the environment within the credentials directory", func(directory, expected string) {
+		path := filepath.Join(testingx.TempDir(), "config.yml")
+		Expect(os.WriteFile(path, []byte(fmt.Sprintf("credentials:\n  directory: %s\n", directory)), 0600)).To(Succeed())
+
+		c, err := DefaultConfigClient(CCOptionEnvironmentName("production")).LoadConfig(path)
+		Expect(err).To(Succeed())
+		Expect(c.Credentials.Directory).To(Equal(expected))
+	},
+		Entry("token", "/home/bw/.bw/{env}/creds", "/home/bw/.bw/production/creds"),
+		Entry("repeated tokens", "/srv/{env}/creds/{env}", "/srv/production/creds/production"),
+		Entry("no token", "/home/bw/.bw/creds", "/home/bw/.bw/creds"),
+	)
+
+	It("should reject the environment token when the environment is unknown", func() {
+		path := filepath.Join(testingx.TempDir(), "config.yml")
+		Expect(os.WriteFile(path, []byte("credentials:\n  directory: /srv/{env}/creds\n"), 0600)).To(Succeed())
+
+		_, err := DefaultConfigClient().LoadConfig(path)
+		Expect(err).To(MatchError(ContainSubstring(EnvironmentToken)))
+	})
+
+	It("should redact the credentials directory of insecure configurations", func() {
+		c := NewConfigClient(DefaultConfigClient(), CCOptionInsecure(true))
+		c.Credentials.Directory = "/etc/bw"
+		encoded, err := json.Marshal(c)
+		Expect(err).To(Succeed())
+		Expect(string(encoded)).ToNot(ContainSubstring("/etc/bw"))
+		Expect(string(encoded)).To(ContainSubstring(Redacted))
+	})
+
+	It("should merge the configuration files in order", func() {
+		var (
+			base     = filepath.Join(testingx.TempDir(), "base.yml")
+			override = filepath.Join(testingx.TempDir(), "production.yml")
+			template = DefaultConfigClient()
+		)
+		template.Deployment.Env = map[string]string{"TEMPLATE": "1"}
+
+		Expect(os.WriteFile(base, []byte("address: bw.example.com:2000\nzone: us-east-1a\ndeploy:\n  timeout: 1m\n  includePeers: [\"web-*\", \"worker-*\"]\n  env:\n    FOO: bar\n    BUILD: \"1\"\n"), 0600)).To(Succeed())
+		Expect(os.WriteFile(override, []byte("zone: us-west-2b\ndeploy:\n  timeout: 5m\n  includePeers: [\"web-*\"]\n  env:\n    BUILD: \"2\"\n    RELEASE: rc\n"), 0600)).To(Succeed())
+
+		c, err := template.LoadConfigFiles(base, override)
+		Expect(err).To(Succeed())
+		Expect(c.Address).To(Equal("bw.example.com:2000"))
+		Expect(c.Zone).To(Equal("us-west-2b"))
+		Expect(c.Deployment.Timeout).To(Equal(5 * time.Minute))
+		Expect(c.Deployment.IncludePeers).To(Equal([]string{"web-*"}))
+		Expect(c.Deployment.Env).To(Equal(map[string]string{"TEMPLATE": "1", "FOO": "bar", "BUILD": "2", "RELEASE": "rc"}))
+		Expect(c.Dir()).To(Equal(filepath.Dir(override)))
+		Expect(template.Deployment.Env).To(Equal(map[string]string{"TEMPLATE": "1"}))
+	})
+
+	It("should reject loading without configuration files", func() {
+		_, err := DefaultConfigClient().LoadConfigFiles()
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should reject malformed peer patterns", func() {
+		path := filepath.Join(testingx.TempDir(), "config.yml")
+		Expect(os.WriteFile(path, []byte("deploy:\n  excludePeers: [\"canary-[\"]\n"), 0600)).To(Succeed())
+		_, err := DefaultConfigClient().LoadConfig(path)
+		Expect(err).To(MatchError(ContainSubstring("invalid peer pattern canary-[")))
+	})
+
+	It("should reject an invalid concurrency", func() {
+		path := filepath.Join(testingx.TempDir(), "config.yml")
+		Expect(os.WriteFile(path, []byte("concurrency: auto(2)\n"), 0600)).To(Succeed())
+		_, err := DefaultConfigClient().LoadConfig(path)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("Config", func() {
+	DescribeTable("BootstrapPolicy", func(servername string, attempts int, fatal bool) {
+		var c Config
+		encoded := `
+servername: ` + servername + `
+bootstrap:
+  attempts: 100
+  environments:
+    ci.example.com:
+      attempts: 1
+      failurePolicy: continue
+    staging.example.com:
+      failurePolicy: continue
+`
+		Expect(yaml.Unmarshal([]byte(encoded), &c)).To(Succeed())
+		policy := c.BootstrapPolicy()
+		Expect(policy.Attempts).To(Equal(attempts))
+		Expect(policy.Fatal()).To(Equal(fatal))
+	},
+		Entry("global policy", "production.example.com", 100, true),
+		Entry("environment policy", "ci.example.com", 1, false),
+		Entry("partial environment policy", "staging.example.com", 100, false),
+	)
+
+	It("should flag weak cluster tokens", func() {
+		c := NewConfig()
+		c.ClusterTokens = []string{bw.GenerateClusterToken(), "secret", bw.GenerateClusterToken()}
+		warnings := c.Lint()
+		Expect(warnings).To(HaveLen(1))
+		Expect(warnings[0].Error()).To(ContainSubstring("clusterTokens[1]"))
+	})
+
+	It("should accept generated cluster tokens", func() {
+		c := NewConfig()
+		c.ClusterTokens = []string{bw.GenerateClusterToken()}
+		Expect(c.Lint()).To(BeEmpty())
+	})
+
+	DescribeTable("Keyring", func(primary string, tokens []string, expected string, secondaries ...string) {
+		digest := func(s string) []byte {
+			d := sha256.Sum256([]byte(s))
+			return d[:]
+		}
+
+		c := NewConfig()
+		c.ServerName = "example.com"
+		c.PrimaryClusterToken = primary
+		c.ClusterTokens = tokens
+		ring, err := c.Keyring()
+		Expect(err).To(Succeed())
+		Expect(ring.GetPrimaryKey()).To(Equal(digest(expected)))
+
+		keys := ring.GetKeys()
+		Expect(keys).To(HaveLen(len(secondaries) + 1))
+		for _, s := range secondaries {
+			Expect(keys).To(ContainElement(digest(s)))
+		}
+	},
+		Entry("no tokens", "", []string(nil), "example.com"),
+		Entry("single token", "", []string{"token1"}, "token1"),
+		Entry("several tokens", "", []string{"token1", "token2", "token3"}, "token1", "token2", "token3"),
+		Entry("primary without tokens", "primary", []string(nil), "primary"),
+		Entry("primary with a single token", "primary", []string{"token1"}, "primary", "token1"),
+		Entry("primary with several tokens", "primary", []string{"token1", "token2", "token3"}, "primary", "token1", "token2", "token3"),
+		Entry("promoted token", "token2", []string{"token1", "token2"}, "token2", "token1"),
+	)
+
+	It("should never encode the cluster tokens as json", func() {
+		c := NewConfig()
+		c.ClusterTokens = []string{"token1", "token2"}
+		c.PrimaryClusterToken = "primary"
+		encoded, err := json.Marshal(c)
+		Expect(err).To(Succeed())
+		Expect(string(encoded)).ToNot(ContainSubstring("token1"))
+		Expect(string(encoded)).ToNot(ContainSubstring("primary"))
+		Expect(string(encoded)).To(ContainSubstring(`"Name":"`))
+	})
+
+	DescribeTable("DNSBind", func(zone, record, expected string, valid bool) {
+		c := NewConfig(ConfigOptionDefaultBind(net.ParseIP("127.0.0.1"))).EnsureDefaults()
+		c.ServerName = "example.com"
+		c.DNSBind.Zone = zone
+		c.DNSBind.RecordName = record
+		Expect(c.DNSBind.Name(c.ServerName)).To(Equal(expected))
+		if valid {
+			Expect(c.Validate()).To(Succeed())
+		} else {
+			Expect(c.Validate()).To(MatchError(ContainSubstring("dnsBind")))
+		}
+	},
+		Entry("server name", "", "", "example.com", true),
+		Entry("zone", "bw.internal", "", "bw.internal", true),
+		Entry("record within the zone", "internal.", "bw", "bw.internal", true),
+		Entry("record within the server name", "", "bw", "bw.example.com", true),
+		Entry("invalid zone", "bw..internal", "", "bw..internal", false),
+		Entry("invalid record name", "bw.internal", "-bw", "-bw.bw.internal", false),
+		Entry("invalid characters", "bw internal", "", "bw internal", false),
+	)
+
+	Context("cluster tokens", func() {
+		AfterEach(func() {
+			os.Unsetenv(bw.EnvAgentClusterTokens)
+		})
+
+		It("should merge the inline, file, and environment tokens", func() {
+			c := NewConfig()
+			c.Root = testingx.TempDir()
+			c.ClusterTokens = []string{"token1", "token2"}
+			c.ClusterTokensFile = "tokens"
+			Expect(os.WriteFile(filepath.Join(c.Root, "tokens"), []byte("token2\ntoken3,token4\n\n"), 0600)).To(Succeed())
+			os.Setenv(bw.EnvAgentClusterTokens, "token4,token5")
+
+			c = c.EnsureDefaults()
+			Expect(c.ClusterTokensFile).To(Equal(filepath.Join(c.Root, "tokens")))
+			Expect(c.ClusterTokens).To(Equal([]string{"token1", "token2", "token3", "token4", "token5"}))
+			Expect(c.EnsureDefaults().ClusterTokens).To(Equal(c.ClusterTokens))
+			Expect(c.Sanitize().ClusterTokens).To(BeEmpty())
+		})
+
+		It("should report an unreadable tokens file", func() {
+			c := NewConfig(ConfigOptionDefaultBind(net.ParseIP("127.0.0.1")))
+			c.Root = testingx.TempDir()
+			c.ClusterTokensFile = "missing"
+			Expect(c.EnsureDefaults().Validate()).To(MatchError(ContainSubstring("clusterTokensFile")))
+		})
+	})
+
+	It("should backoff exponentially between bootstrap attempts", func() {
+		retry := NewConfig().BootstrapPolicy().Retry()
+		Expect(retry.Backoff(0)).To(BeNumerically("~", 1125*time.Millisecond, 125*time.Millisecond))
+		Expect(retry.Backoff(2)).To(BeNumerically("~", 4500*time.Millisecond, 500*time.Millisecond))
+		Expect(retry.Backoff(100)).To(Equal(30 * time.Second))
+	})
+
+	It("should reject a bootstrap backoff exceeding the maximum", func() {
+		c := NewConfig(ConfigOptionDefaultBind(net.ParseIP("127.0.0.1"))).EnsureDefaults()
+		c.Bootstrap.Backoff = time.Minute
+		Expect(c.Validate()).To(MatchError(ContainSubstring("bootstrap.maxBackoff")))
+	})
+
+	It("should reject an unknown tls version", func() {
+		c := NewConfig(ConfigOptionDefaultBind(net.ParseIP("127.0.0.1"))).EnsureDefaults()
+		c.TLSMinVersion = "1.4"
+		Expect(c.Validate()).To(MatchError(ContainSubstring("tlsMinVersion")))
+	})
+
+	It("should reject invalid allowed client cidrs", func() {
+		c := NewConfig(ConfigOptionDefaultBind(net.ParseIP("127.0.0.1"))).EnsureDefaults()
+		c.AllowedClientCIDRs = []string{"10.0.0.0/8", "192.168.1.1"}
+		Expect(c.Validate()).To(MatchError(ContainSubstring("allowedClientCIDRs")))
+	})
+
+	It("should reject an unknown address family", func() {
+		c := NewConfig(ConfigOptionDefaultBind(net.ParseIP("127.0.0.1")), ConfigOptionAddressFamily("ipx")).EnsureDefaults()
+		Expect(c.Validate()).To(MatchError(ContainSubstring("addressFamily")))
+	})
+
+	It("should accept the default configuration", func() {
+		Expect(NewConfig(ConfigOptionDefaultBind(net.ParseIP("127.0.0.1"))).EnsureDefaults().Validate()).To(Succeed())
+	})
+
+	It("should allow disabling snapshots", func() {
+		c := NewConfig(ConfigOptionDefaultBind(net.ParseIP("127.0.0.1")), ConfigOptionSnapshotFrequency(0)).EnsureDefaults()
+		Expect(c.SnapshotFrequency).To(Equal(time.Duration(0)))
+		Expect(c.Validate()).To(Succeed())
+	})
+
+	It("should decode partial gossip timing over the defaults", func() {
+		c := NewConfig()
+		Expect(yaml.Unmarshal([]byte("gossip:\n  probeTimeout: 4s\n"), &c)).To(Succeed())
+		Expect(c.Gossip.ProbeInterval).To(Equal(bw.DefaultGossipProbeInterval))
+		Expect(c.Gossip.ProbeTimeout).To(Equal(4 * time.Second))
+		Expect(c.Gossip.SuspicionMult).To(Equal(bw.DefaultGossipSuspicionMult))
+	})
+
+	It("should reject a probe timeout exceeding the probe interval", func() {
+		c := NewConfig(ConfigOptionDefaultBind(net.ParseIP("127.0.0.1")), ConfigOptionGossip(time.Second, 2*time.Second, 4)).EnsureDefaults()
+		Expect(c.Validate()).To(MatchError(ContainSubstring("gossip.probeTimeout")))
+	})
+
+	It("should default the raft snapshot retention", func() {
+		Expect(Config{}.EnsureDefaults().RaftSnapshotRetain).To(Equal(bw.DefaultRaftSnapshotRetain))
+
+		c := NewConfig(ConfigOptionDefaultBind(net.ParseIP("127.0.0.1")), ConfigOptionRaftSnapshotRetain(-1)).EnsureDefaults()
+		Expect(c.Validate()).To(MatchError(ContainSubstring("raftSnapshotRetain")))
+	})
+
+	It("should report every problem at once", func() {
+		c := NewConfig(ConfigOptionDefaultBind(net.ParseIP("127.0.0.1")))
+		c.Name = ""
+		c.MinimumNodes = 0
+		c.KeepN = -1
+		c.P2PBind.Port = 0
+		c.SnapshotFrequency = time.Millisecond
+		c.CA = testingx.TempDir()
+
+		err := c.Validate()
+		Expect(err).To(HaveOccurred())
+
+		var problems ConfigErrors
+		Expect(errors.As(err, &problems)).To(BeTrue())
+		Expect(problems).To(HaveLen(6))
+		Expect(err.Error()).To(And(
+			ContainSubstring("name"),
+			ContainSubstring("minimumNodes"),
+			ContainSubstring("keepN"),
+			ContainSubstring("p2pBind"),
+			ContainSubstring("snapshotFrequency"),
+			ContainSubstring("ca must be a file"),
+		))
+	})
+	It("should advertise the ip of the agent", func() {
+		c := NewConfig(ConfigOptionDefaultBind(net.ParseIP("127.0.0.1"))).EnsureDefaults()
+		Expect(c.Peer().Ip).To(Equal("127.0.0.1"))
+	})
+
+	It("should advertise a routable address for a wildcard bind", func() {
+		c := NewConfig(ConfigOptionP2P(&net.TCPAddr{IP: net.IPv4zero, Port: 2000})).EnsureDefaults()
+		Expect(c.P2PBind.IP.IsUnspecified()).To(BeTrue())
+		Expect(c.P2PAdvertised.IP.IsGlobalUnicast()).To(BeTrue())
+		Expect(c.P2PAdvertised.Port).To(Equal(2000))
+		Expect(c.Peer().Ip).ToNot(Equal("0.0.0.0"))
+	})
+
+	It("should reject an unroutable advertised address", func() {
+		c := NewConfig(ConfigOptionDefaultBind(net.ParseIP("127.0.0.1"))).EnsureDefaults()
+		c.P2PAdvertised = &net.TCPAddr{IP: net.IPv4zero, Port: 2000}
+		Expect(c.Validate()).To(MatchError(ContainSubstring("p2pAdvertised")))
+	})
+
+	It("should bind the local address within the cidr", func() {
+		_, n, err := net.ParseCIDR("127.0.0.0/8")
+		Expect(err).ToNot(HaveOccurred())
+		c := NewConfig(
+			ConfigOptionP2P(&net.TCPAddr{IP: net.IPv4zero, Port: 2000}),
+			ConfigOptionBindFromCIDR(n),
+		)
+		Expect(n.Contains(c.P2PBind.IP)).To(BeTrue())
+		Expect(c.P2PBind.Port).To(Equal(2000))
+	})
+
+	It("should report the considered addresses when no local address is within the cidr", func() {
+		_, n, err := net.ParseCIDR("198.51.100.0/24")
+		Expect(err).ToNot(HaveOccurred())
+		c := NewConfig(ConfigOptionBindFromCIDR(n)).EnsureDefaults()
+		Expect(c.Validate()).To(MatchError(And(ContainSubstring("p2pBind"), ContainSubstring("considered"))))
+	})
+
+	It("should advertise the name of the agent alongside the ip when provided", func() {
+		c := NewConfig(
+			ConfigOptionDefaultBind(net.ParseIP("127.0.0.1")),
+			ConfigOptionAdvertisedName("localhost"),
+		).EnsureDefaults()
+
+		p := MustPeer(NodeToPeer(PeerToNode(c.Peer())))
+		Expect(p.Ip).To(Equal("127.0.0.1"))
+		Expect(p.AdvertisedName).To(Equal("localhost"))
+		Expect(RaftAddress(p)).To(Equal(fmt.Sprintf("127.0.0.1:%d", bw.DefaultP2PPort)))
+
+		routes := DialRoutes(context.Background(), p)
+		Expect(routes).To(HaveLen(2))
+		Expect(net.ParseIP(routes[0].Ip).IsLoopback()).To(BeTrue())
+		Expect(routes[1]).To(Equal(p))
+	})
+
+	It("should dial the advertised addresses when the advertised name is unresolvable", func() {
+		p := NewPeer("node1", PeerOptionIP(net.ParseIP("127.0.0.1")))
+		p.AdvertisedName = "unresolvable.invalid"
+		Expect(DialRoutes(context.Background(), p)).To(Equal([]*Peer{p}))
+	})
+
+	It("should advertise the alternate addresses of the agent", func() {
+		c := NewConfig(
+			ConfigOptionDefaultBind(net.ParseIP("127.0.0.1")),
+			ConfigOptionAdvertised(&net.TCPAddr{IP: net.ParseIP("203.0.113.10"), Port: 2000}),
+			ConfigOptionAlternateAdvertised(&net.TCPAddr{IP: net.ParseIP("10.0.0.10"), Port: 2001}),
+		).EnsureDefaults()
+		Expect(c.Validate()).To(Succeed())
+
+		p := MustPeer(NodeToPeer(PeerToNode(c.Peer())))
+		Expect(p.Ip).To(Equal("203.0.113.10"))
+		Expect(p.Alternates).To(Equal([]string{"10.0.0.10:2001"}))
+	})
+
+	It("should reject alternate advertised addresses without a port", func() {
+		c := NewConfig(
+			ConfigOptionDefaultBind(net.ParseIP("127.0.0.1")),
+			ConfigOptionAlternateAdvertised(&net.TCPAddr{IP: net.ParseIP("10.0.0.10")}),
+		).EnsureDefaults()
+		Expect(c.Validate()).To(MatchError(ContainSubstring("alternateAdvertised[0]")))
+	})
+
+	It("should prefer the advertised address within the networks", func() {
+		var (
+			p       = &Peer{Name: "agent1", Ip: "203.0.113.10", P2PPort: 2000, Alternates: []string{"malformed", "10.0.0.10:2001"}}
+			address = func(routes []*Peer) (results []string) {
+				for _, r := range routes {
+					results = append(results, RaftAddress(r))
+				}
+				return results
+			}
+		)
+		_, onprem, err := net.ParseCIDR("10.0.0.0/8")
+		Expect(err).To(Succeed())
+		_, cloud, err := net.ParseCIDR("203.0.113.0/24")
+		Expect(err).To(Succeed())
+
+		Expect(address(Routes(p))).To(Equal([]string{"203.0.113.10:2000", "10.0.0.10:2001"}))
+		Expect(address(Routes(p, onprem))).To(Equal([]string{"10.0.0.10:2001", "203.0.113.10:2000"}))
+		Expect(address(Routes(p, cloud))).To(Equal([]string{"203.0.113.10:2000", "10.0.0.10:2001"}))
+		Expect(SWIMAddress(&Peer{Ip: "127.0.0.1", P2PPort: 2000})).To(Equal(fmt.Sprintf("%s://127.0.0.1:2000", bw.ProtocolSWIM)))
+	})
+})
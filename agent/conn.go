@@ -6,6 +6,7 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"io"
+	"time"
 
 	"google.golang.org/grpc"
 	codes "google.golang.org/grpc/codes"
@@ -14,6 +15,7 @@ import (
 	"github.com/james-lawrence/bw/internal/errorsx"
 	"github.com/james-lawrence/bw/internal/grpcx"
 	"github.com/james-lawrence/bw/internal/iox"
+	"github.com/james-lawrence/bw/internal/tracex"
 	"github.com/pkg/errors"
 )
 
@@ -84,6 +86,17 @@ func (t Conn) Shutdown(ctx context.Context) (err error) {
 	return nil
 }
 
+// Drain causes the agent to drain and leave the cluster before shutting down.
+func (t Conn) Drain(ctx context.Context, timeout time.Duration) (err error) {
+	rpc := NewAgentClient(t.conn)
+
+	if _, err = rpc.Drain(ctx, &DrainRequest{Timeout: int64(timeout)}); err != nil {
+		return errors.WithStack(err)
+	}
+
+	return nil
+}
+
 // Cancel proxy the cancellation through the quorum nodes.
 // this cleans up the raft state in addition to the individual nodes.
 func (t Conn) Cancel(ctx context.Context, req *CancelRequest) error {
@@ -155,7 +168,7 @@ func (t Conn) RemoteDeploy(ctx context.Context, initiator string, dopts *DeployO
 		Peers:     peers,
 	}
 
-	if _, err = rpc.Deploy(ctx, &req); err != nil {
+	if _, err = rpc.Deploy(tracex.Outgoing(ctx), &req); err != nil {
 		return errors.WithStack(err)
 	}
 
@@ -169,6 +182,7 @@ func (t Conn) Deploy(ctx context.Context, options *DeployOptions, archive *Archi
 	)
 
 	rpc := NewAgentClient(t.conn)
+	ctx = tracex.Outgoing(ctx)
 
 	err = grpcx.Retry(func() error {
 		if ar, err = rpc.Deploy(ctx, &DeployRequest{Options: options, Archive: archive}); err != nil {
@@ -211,6 +225,16 @@ func (t Conn) Info(ctx context.Context) (info *StatusResponse, err error) {
 	return info, nil
 }
 
+// Deployed the commit ref of the latest successful deploy of the agent.
+func (t Conn) Deployed(ctx context.Context) (d *DeployedResponse, err error) {
+	rpc := NewAgentClient(t.conn)
+	if d, err = rpc.Deployed(ctx, &DeployedRequest{}); err != nil {
+		return d, errors.Wrap(err, "failed to retrieve the deployed commit")
+	}
+
+	return d, nil
+}
+
 // Watch for messages sent to the leader. blocks.
 func (t Conn) Watch(ctx context.Context, out chan<- *Message) (err error) {
 	var (
@@ -232,6 +256,25 @@ func (t Conn) Watch(ctx context.Context, out chan<- *Message) (err error) {
 	return errorsx.Compact(errors.WithStack(err), src.CloseSend())
 }
 
+// Membership streams the membership of the cluster, a snapshot of the members followed
+// by the changes. blocks.
+func (t Conn) Membership(ctx context.Context, out chan<- *MembershipEvent) (err error) {
+	var (
+		src Membership_WatchClient
+		evt *MembershipEvent
+	)
+
+	if src, err = NewMembershipClient(t.conn).Watch(ctx, &MembershipRequest{}); err != nil {
+		return errors.WithStack(err)
+	}
+
+	for evt, err = src.Recv(); err == nil; evt, err = src.Recv() {
+		out <- evt
+	}
+
+	return errorsx.Compact(errors.WithStack(err), src.CloseSend())
+}
+
 // Dispatch messages to the leader.
 func (t Conn) Dispatch(ctx context.Context, messages ...*Message) (err error) {
 	var (
@@ -249,6 +292,45 @@ func (t Conn) Dispatch(ctx context.Context, messages ...*Message) (err error) {
 	return nil
 }
 
+// SetFlag sets the cluster wide flag, a blank value unsets the flag.
+func (t Conn) SetFlag(ctx context.Context, key, value string) (err error) {
+	_, err = NewQuorumClient(t.conn).SetFlag(ctx, &SetFlagRequest{Flag: &Flag{Key: key, Value: value}})
+	return errors.WithStack(err)
+}
+
+// GetFlag returns the value of the cluster wide flag, ok is false when the flag is unset.
+func (t Conn) GetFlag(ctx context.Context, key string) (value string, ok bool, err error) {
+	var (
+		resp *GetFlagResponse
+	)
+
+	if resp, err = NewQuorumClient(t.conn).GetFlag(ctx, &GetFlagRequest{Key: key}); err != nil {
+		return "", false, errors.WithStack(err)
+	}
+
+	value = resp.GetFlag().GetValue()
+	return value, value != "", nil
+}
+
+// WatchFlags for changes to the flag with the key, a blank key watches every flag.
+// the current value of the flags are sent first. blocks.
+func (t Conn) WatchFlags(ctx context.Context, key string, out chan<- *Flag) (err error) {
+	var (
+		src  Quorum_WatchFlagsClient
+		flag *Flag
+	)
+
+	if src, err = NewQuorumClient(t.conn).WatchFlags(ctx, &WatchFlagsRequest{Key: key}); err != nil {
+		return errors.WithStack(err)
+	}
+
+	for flag, err = src.Recv(); err == nil; flag, err = src.Recv() {
+		out <- flag
+	}
+
+	return errorsx.Compact(errors.WithStack(err), src.CloseSend())
+}
+
 // Logs return the logs for the given deployment.
 func (t Conn) Logs(ctx context.Context, p *Peer, did []byte) io.ReadCloser {
 	var (
@@ -264,6 +346,21 @@ func (t Conn) Logs(ctx context.Context, p *Peer, did []byte) io.ReadCloser {
 	return readLogs(c)
 }
 
+// Follow streams the logs of the given deployment as they are written, until the deploy completes.
+func (t Conn) Follow(ctx context.Context, p *Peer, did []byte) io.ReadCloser {
+	var (
+		err error
+		c   Agent_LogsClient
+	)
+
+	rpc := NewAgentClient(t.conn)
+	if c, err = rpc.Logs(ctx, &LogRequest{Peer: p, DeploymentID: did, Follow: true}); err != nil {
+		return io.NopCloser(iox.ErrReader(err))
+	}
+
+	return readLogs(c)
+}
+
 type logsClient interface {
 	Recv() (*LogResponse, error)
 }
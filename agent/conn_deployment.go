@@ -150,3 +150,18 @@ func (t DeployConn) Logs(ctx context.Context, p *Peer, did []byte) io.ReadCloser
 
 	return readLogs(c)
 }
+
+// Follow streams the logs of the given deployment as they are written, until the deploy completes.
+func (t DeployConn) Follow(ctx context.Context, p *Peer, did []byte) io.ReadCloser {
+	var (
+		err error
+		c   Deployments_LogsClient
+	)
+
+	rpc := NewDeploymentsClient(t.conn)
+	if c, err = rpc.Logs(ctx, &LogRequest{Peer: p, DeploymentID: did, Follow: true}); err != nil {
+		return io.NopCloser(iox.ErrReader(err))
+	}
+
+	return readLogs(c)
+}
@@ -3,6 +3,7 @@ package agent
 import (
 	"context"
 	"log"
+	"math"
 	"math/rand"
 
 	"github.com/hashicorp/memberlist"
@@ -39,9 +40,46 @@ func QuorumPeers(c rendez) []*Peer {
 	return Shuffle(NodesToPeers(QuorumNodes(c)...))
 }
 
-// QuorumNodes return the quorum nodes.
+// QuorumNodes return the quorum nodes, non-voters are never members of the quorum.
 func QuorumNodes(c rendez) []*memberlist.Node {
-	return c.GetN(QuorumDefault, []byte(QuorumKey))
+	return voters(c, QuorumDefault, []byte(QuorumKey))
+}
+
+// NonVoterNodes return the non-voter nodes of the cluster.
+func NonVoterNodes(c rendez) (nodes []*memberlist.Node) {
+	for _, n := range c.GetN(math.MaxInt32, []byte(QuorumKey)) {
+		if IsNonVoter(n) {
+			nodes = append(nodes, n)
+		}
+	}
+
+	return nodes
+}
+
+// voters returns the first n voting nodes for the key, requesting additional
+// nodes from the cluster to replace any non-voters.
+func voters(c rendez, n int, key []byte) []*memberlist.Node {
+	for m := n; ; {
+		nodes := c.GetN(m, key)
+		selected := make([]*memberlist.Node, 0, n)
+		for _, node := range nodes {
+			if IsNonVoter(node) {
+				continue
+			}
+
+			selected = append(selected, node)
+		}
+
+		if len(selected) >= n {
+			return selected[:n]
+		}
+
+		if len(nodes) < m {
+			return selected
+		}
+
+		m += n - len(selected)
+	}
 }
 
 // LargeQuorum returns 2x the nodes required to achieve quorum.
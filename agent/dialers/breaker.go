@@ -0,0 +1,135 @@
+package dialers
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/james-lawrence/bw/agent"
+	"github.com/james-lawrence/bw/internal/errorsx"
+	"github.com/james-lawrence/bw/internal/timex"
+)
+
+// ErrCircuitOpen returned when dialing an address whose circuit is open.
+const ErrCircuitOpen = errorsx.String("circuit open, skipping peer")
+
+// BreakerOption options for the circuit breaker.
+type BreakerOption func(*Breaker)
+
+// BreakerOptionThreshold number of consecutive failures to an address that opens its circuit,
+// <= 0 disables the circuit breaker. defaults to 5.
+func BreakerOptionThreshold(n int) BreakerOption {
+	return func(b *Breaker) {
+		b.threshold = n
+	}
+}
+
+// BreakerOptionCooldown duration a circuit remains open before a single dial is allowed
+// through (half-open), a successful dial closes the circuit and a failure reopens it. defaults to 30s.
+func BreakerOptionCooldown(d time.Duration) BreakerOption {
+	return func(b *Breaker) {
+		b.cooldown = d
+	}
+}
+
+// BreakerOptionConfig thresholds of the agent's peer circuit breaker.
+func BreakerOptionConfig(c agent.Config) BreakerOption {
+	return func(b *Breaker) {
+		b.threshold = c.PeerCircuitBreaker.Threshold
+		b.cooldown = timex.DurationOrDefault(c.PeerCircuitBreaker.Cooldown, b.cooldown)
+	}
+}
+
+// NewBreaker circuit breaker for dialing peers, addresses that consistently fail to
+// connect are skipped until the cooldown elapses.
+func NewBreaker(d dialer, options ...BreakerOption) *Breaker {
+	b := &Breaker{
+		d:         d,
+		threshold: 5,
+		cooldown:  30 * time.Second,
+		circuits:  make(map[string]*circuit),
+	}
+
+	for _, opt := range options {
+		opt(b)
+	}
+
+	return b
+}
+
+// Breaker tracks consecutive dial failures per address.
+type Breaker struct {
+	d         dialer
+	threshold int
+	cooldown  time.Duration
+	m         sync.Mutex
+	circuits  map[string]*circuit
+}
+
+type circuit struct {
+	failures int
+	opened   time.Time
+	probing  bool // a half-open dial is in flight.
+}
+
+// DialContext dials the address unless its circuit is open.
+func (t *Breaker) DialContext(ctx context.Context, network, address string) (conn net.Conn, err error) {
+	if t.threshold <= 0 {
+		return t.d.DialContext(ctx, network, address)
+	}
+
+	if err = t.allow(address); err != nil {
+		return nil, err
+	}
+
+	conn, err = t.d.DialContext(ctx, network, address)
+	// cancelled dials say nothing about the peer.
+	t.record(address, err, ctx.Err() != nil)
+	return conn, err
+}
+
+func (t *Breaker) allow(address string) error {
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	c, ok := t.circuits[address]
+	if !ok || c.failures < t.threshold {
+		return nil
+	}
+
+	if c.probing || time.Since(c.opened) < t.cooldown {
+		return errors.Wrap(ErrCircuitOpen, address)
+	}
+
+	c.probing = true
+	return nil
+}
+
+func (t *Breaker) record(address string, err error, cancelled bool) {
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	if err == nil {
+		delete(t.circuits, address)
+		return
+	}
+
+	c, ok := t.circuits[address]
+	if !ok {
+		c = &circuit{}
+		t.circuits[address] = c
+	}
+
+	c.probing = false
+	if cancelled {
+		return
+	}
+
+	c.failures++
+	if c.failures >= t.threshold {
+		c.opened = time.Now()
+	}
+}
@@ -0,0 +1,67 @@
+package dialers_test
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/james-lawrence/bw/agent/dialers"
+)
+
+// failingDialer counts the dials, failing every one of them.
+type failingDialer struct {
+	dials *int64
+}
+
+func (t failingDialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	atomic.AddInt64(t.dials, 1)
+	return nil, &net.OpError{Op: "dial", Net: network, Err: context.DeadlineExceeded}
+}
+
+var _ = Describe("Breaker", func() {
+	It("should skip a consistently failing peer while open and retry after the cooldown", func() {
+		const cooldown = 100 * time.Millisecond
+		d := failingDialer{dials: new(int64)}
+		b := NewBreaker(d, BreakerOptionThreshold(3), BreakerOptionCooldown(cooldown))
+
+		for i := 0; i < 3; i++ {
+			_, err := b.DialContext(context.Background(), "tcp", "127.0.0.1:2000")
+			Expect(err).ToNot(MatchError(ErrCircuitOpen))
+		}
+		Expect(atomic.LoadInt64(d.dials)).To(Equal(int64(3)))
+
+		// open, the peer is skipped.
+		_, err := b.DialContext(context.Background(), "tcp", "127.0.0.1:2000")
+		Expect(err).To(MatchError(ErrCircuitOpen))
+		Expect(atomic.LoadInt64(d.dials)).To(Equal(int64(3)))
+
+		// other peers are unaffected.
+		_, err = b.DialContext(context.Background(), "tcp", "127.0.0.2:2000")
+		Expect(err).ToNot(MatchError(ErrCircuitOpen))
+		Expect(atomic.LoadInt64(d.dials)).To(Equal(int64(4)))
+
+		// half open, a single dial is retried and the failure reopens the circuit.
+		time.Sleep(cooldown)
+		_, err = b.DialContext(context.Background(), "tcp", "127.0.0.1:2000")
+		Expect(err).ToNot(MatchError(ErrCircuitOpen))
+		Expect(atomic.LoadInt64(d.dials)).To(Equal(int64(5)))
+
+		_, err = b.DialContext(context.Background(), "tcp", "127.0.0.1:2000")
+		Expect(err).To(MatchError(ErrCircuitOpen))
+		Expect(atomic.LoadInt64(d.dials)).To(Equal(int64(5)))
+	})
+
+	It("should never open when disabled", func() {
+		d := failingDialer{dials: new(int64)}
+		b := NewBreaker(d, BreakerOptionThreshold(0))
+		for i := 0; i < 10; i++ {
+			_, err := b.DialContext(context.Background(), "tcp", "127.0.0.1:2000")
+			Expect(err).ToNot(MatchError(ErrCircuitOpen))
+		}
+		Expect(atomic.LoadInt64(d.dials)).To(Equal(int64(10)))
+	})
+})
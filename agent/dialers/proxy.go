@@ -36,13 +36,15 @@ func (t Proxy) DialContext(ctx context.Context, options ...grpc.DialOption) (con
 	}
 
 	for _, q := range agent.Shuffle(cinfo.Quorum) {
-		addr := agent.RPCAddress(q)
-		if conn, err = grpc.DialContext(ctx, addr, t.d.Defaults(options...)...); err != nil {
-			log.Println("failed to dial", addr, err)
-			continue
+		for _, r := range agent.DialRoutes(ctx, q) {
+			addr := agent.RPCAddress(r)
+			if conn, err = grpc.DialContext(ctx, addr, t.d.Defaults(options...)...); err != nil {
+				log.Println("failed to dial", addr, err)
+				continue
+			}
+
+			return conn, nil
 		}
-
-		return conn, nil
 	}
 
 	return nil, errors.WithMessage(err, "failed to connect to a member of the quorum")
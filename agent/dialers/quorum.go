@@ -17,9 +17,16 @@ func NewQuorum(c rendezvous, defaults ...grpc.DialOption) Quorum {
 // Quorum dialers members of quorum.
 type Quorum struct {
 	c        rendezvous
+	zone     string
 	defaults []grpc.DialOption
 }
 
+// PreferZone returns a dialer preferring the members of quorum within the zone, see PreferZone.
+func (t Quorum) PreferZone(zone string) Quorum {
+	t.zone = zone
+	return t
+}
+
 // Dial with given the options
 func (t Quorum) Dial(options ...grpc.DialOption) (c *grpc.ClientConn, err error) {
 	return t.DialContext(context.Background(), options...)
@@ -30,11 +37,13 @@ func (t Quorum) DialContext(ctx context.Context, options ...grpc.DialOption) (c
 	err = errors.New("unable to connect")
 	opts := append(t.defaults, options...)
 
-	for _, p := range agent.QuorumPeers(t.c) {
-		if c, err = grpc.DialContext(ctx, agent.RPCAddress(p), opts...); err == nil {
-			return c, err
+	for _, p := range PreferZone(t.zone, agent.QuorumPeers(t.c)...) {
+		for _, r := range agent.DialRoutes(ctx, p) {
+			if c, err = grpc.DialContext(ctx, agent.RPCAddress(r), opts...); err == nil {
+				return c, err
+			}
+			log.Println("failed to connect to peer", r.Name, r.Ip)
 		}
-		log.Println("failed to connect to peer", p.Name, p.Ip)
 	}
 
 	return nil, errors.WithMessage(err, "failed to connect to a member of the quorum")
@@ -18,9 +18,16 @@ func NewRendezvous(key []byte, c rendezvous, defaults ...grpc.DialOption) Rendez
 type Rendezvous struct {
 	key      []byte
 	c        rendezvous
+	zone     string
 	defaults []grpc.DialOption
 }
 
+// PreferZone returns a dialer preferring the peers within the zone, see PreferZone.
+func (t Rendezvous) PreferZone(zone string) Rendezvous {
+	t.zone = zone
+	return t
+}
+
 // Dial with given the options
 func (t Rendezvous) Dial(options ...grpc.DialOption) (c *grpc.ClientConn, err error) {
 	return t.DialContext(context.Background(), options...)
@@ -31,11 +38,13 @@ func (t Rendezvous) DialContext(ctx context.Context, options ...grpc.DialOption)
 	err = errors.New("unable to connect")
 	opts := append(t.defaults, options...)
 
-	for _, p := range agent.RendezvousPeers(t.key, t.c) {
-		if c, err = grpc.DialContext(ctx, agent.RPCAddress(p), opts...); err == nil {
-			return c, err
+	for _, p := range PreferZone(t.zone, agent.RendezvousPeers(t.key, t.c)...) {
+		for _, r := range agent.DialRoutes(ctx, p) {
+			if c, err = grpc.DialContext(ctx, agent.RPCAddress(r), opts...); err == nil {
+				return c, err
+			}
+			log.Println("failed to connect to peer", r.Name, r.Ip)
 		}
-		log.Println("failed to connect to peer", p.Name, p.Ip)
 	}
 
 	return nil, errors.WithMessage(err, "failed to connect to the quorum leader")
@@ -0,0 +1,22 @@
+package dialers
+
+import (
+	"sort"
+
+	"github.com/james-lawrence/bw/agent"
+)
+
+// PreferZone orders the peers by zone affinity, the peers within the zone are dialed first
+// and the peers in other zones are only used as a fallback. otherwise the order of the peers
+// is preserved. a blank zone leaves the peers untouched.
+func PreferZone(zone string, peers ...*agent.Peer) []*agent.Peer {
+	if zone == "" {
+		return peers
+	}
+
+	sort.SliceStable(peers, func(i, j int) bool {
+		return peers[i].Zone == zone && peers[j].Zone != zone
+	})
+
+	return peers
+}
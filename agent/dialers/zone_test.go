@@ -0,0 +1,50 @@
+package dialers_test
+
+import (
+	"github.com/james-lawrence/bw/agent"
+	. "github.com/james-lawrence/bw/agent/dialers"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PreferZone", func() {
+	peer := func(name, zone string) *agent.Peer {
+		return agent.NewPeer(name, agent.PeerOptionZone(zone))
+	}
+
+	fleet := func() []*agent.Peer {
+		return []*agent.Peer{
+			peer("a1", "us-east-1a"),
+			peer("b1", "us-east-1b"),
+			peer("a2", "us-east-1a"),
+			peer("c1", "us-east-1c"),
+			peer("b2", "us-east-1b"),
+		}
+	}
+
+	names := func(peers ...*agent.Peer) (results []string) {
+		for _, p := range peers {
+			results = append(results, p.Name)
+		}
+		return results
+	}
+
+	It("should prefer the peers within the zone", func() {
+		Expect(names(PreferZone("us-east-1b", fleet()...)...)).To(Equal([]string{"b1", "b2", "a1", "a2", "c1"}))
+	})
+
+	It("should fallback to the peers in other zones when none are local", func() {
+		Expect(names(PreferZone("us-west-2a", fleet()...)...)).To(Equal([]string{"a1", "b1", "a2", "c1", "b2"}))
+	})
+
+	It("should leave the peers untouched without a zone", func() {
+		Expect(names(PreferZone("", fleet()...)...)).To(Equal([]string{"a1", "b1", "a2", "c1", "b2"}))
+	})
+
+	It("should propagate the zone through the peer metadata", func() {
+		p, err := agent.NodeToPeer(agent.PeerToNode(peer("a1", "us-east-1a")))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(p.Zone).To(Equal("us-east-1a"))
+	})
+})
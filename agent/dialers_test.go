@@ -0,0 +1,71 @@
+package agent_test
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/hashicorp/memberlist"
+	"github.com/james-lawrence/bw/clustering/rendezvous"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/james-lawrence/bw/agent"
+)
+
+type staticRendez []*memberlist.Node
+
+func (t staticRendez) GetN(n int, key []byte) []*memberlist.Node {
+	return rendezvous.MaxN(n, key, t)
+}
+
+var _ = Describe("QuorumNodes", func() {
+	nodes := func(voters, nonvoters int) (c staticRendez) {
+		for i := 0; i < voters+nonvoters; i++ {
+			c = append(c, PeerToNode(NewPeer(
+				fmt.Sprintf("node-%d", i),
+				PeerOptionIP(net.IPv4(127, 0, 0, byte(i+1))),
+				PeerOptionNonVoter(i >= voters),
+			)))
+		}
+
+		return c
+	}
+
+	names := func(nodes ...*memberlist.Node) (r []string) {
+		for _, n := range nodes {
+			r = append(r, n.Name)
+		}
+
+		return r
+	}
+
+	It("should never select non-voters", func() {
+		c := nodes(4, 4)
+		quorum := QuorumNodes(c)
+		Expect(quorum).To(HaveLen(QuorumDefault))
+		for _, n := range quorum {
+			Expect(IsNonVoter(n)).To(BeFalse())
+		}
+	})
+
+	It("should select the voters with the highest rendezvous score", func() {
+		c := nodes(5, 3)
+		var expected []string
+		for _, n := range c.GetN(len(c), []byte(QuorumKey)) {
+			if !IsNonVoter(n) && len(expected) < QuorumDefault {
+				expected = append(expected, n.Name)
+			}
+		}
+		Expect(names(QuorumNodes(c)...)).To(Equal(expected))
+	})
+
+	It("should select every voter when the voters cannot fill the quorum", func() {
+		Expect(QuorumNodes(nodes(2, 5))).To(HaveLen(2))
+		Expect(QuorumNodes(nodes(0, 5))).To(BeEmpty())
+	})
+
+	It("should return the non-voters", func() {
+		Expect(names(NonVoterNodes(nodes(3, 2))...)).To(ConsistOf("node-3", "node-4"))
+		Expect(NonVoterNodes(nodes(3, 0))).To(BeEmpty())
+	})
+})
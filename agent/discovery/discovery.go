@@ -4,6 +4,7 @@ package discovery
 
 import (
 	"context"
+	"time"
 
 	"github.com/hashicorp/memberlist"
 	"github.com/pkg/errors"
@@ -71,6 +72,29 @@ func Snapshot(address string, options ...grpc.DialOption) (nodes []*memberlist.N
 	return nodes, err
 }
 
+// ClockDrift measures the drift of the clock of the agent at the address relative to the local clock,
+// accounting for the round trip of the request. positive when the agent's clock is ahead.
+func ClockDrift(ctx context.Context, address string, options ...grpc.DialOption) (_ time.Duration, err error) {
+	var (
+		cc   *grpc.ClientConn
+		resp *ClockResponse
+	)
+
+	if cc, err = dialers.NewDirect(address).DialContext(ctx, options...); err != nil {
+		return 0, err
+	}
+	defer cc.Close()
+
+	sent := time.Now()
+	if resp, err = NewDiscoveryClient(cc).Clock(ctx, &ClockRequest{}); err != nil {
+		return 0, errors.WithStack(err)
+	}
+	received := time.Now()
+
+	midpoint := sent.Add(received.Sub(sent) / 2)
+	return time.Unix(0, resp.Unixnano).Sub(midpoint), nil
+}
+
 // CheckCredentials against discovery
 func CheckCredentials(address string, path string, d dialers.Defaults) (err error) {
 	var (
@@ -24,6 +24,7 @@ const _ = grpc.SupportPackageIsVersion7
 type DiscoveryClient interface {
 	Quorum(ctx context.Context, in *QuorumRequest, opts ...grpc.CallOption) (*QuorumResponse, error)
 	Agents(ctx context.Context, in *AgentsRequest, opts ...grpc.CallOption) (Discovery_AgentsClient, error)
+	Clock(ctx context.Context, in *ClockRequest, opts ...grpc.CallOption) (*ClockResponse, error)
 }
 
 type discoveryClient struct {
@@ -75,12 +76,22 @@ func (x *discoveryAgentsClient) Recv() (*AgentsResponse, error) {
 	return m, nil
 }
 
+func (c *discoveryClient) Clock(ctx context.Context, in *ClockRequest, opts ...grpc.CallOption) (*ClockResponse, error) {
+	out := new(ClockResponse)
+	err := c.cc.Invoke(ctx, "/discovery.Discovery/Clock", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // DiscoveryServer is the server API for Discovery service.
 // All implementations must embed UnimplementedDiscoveryServer
 // for forward compatibility
 type DiscoveryServer interface {
 	Quorum(context.Context, *QuorumRequest) (*QuorumResponse, error)
 	Agents(*AgentsRequest, Discovery_AgentsServer) error
+	Clock(context.Context, *ClockRequest) (*ClockResponse, error)
 	mustEmbedUnimplementedDiscoveryServer()
 }
 
@@ -94,6 +105,9 @@ func (UnimplementedDiscoveryServer) Quorum(context.Context, *QuorumRequest) (*Qu
 func (UnimplementedDiscoveryServer) Agents(*AgentsRequest, Discovery_AgentsServer) error {
 	return status.Errorf(codes.Unimplemented, "method Agents not implemented")
 }
+func (UnimplementedDiscoveryServer) Clock(context.Context, *ClockRequest) (*ClockResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Clock not implemented")
+}
 func (UnimplementedDiscoveryServer) mustEmbedUnimplementedDiscoveryServer() {}
 
 // UnsafeDiscoveryServer may be embedded to opt out of forward compatibility for this service.
@@ -146,6 +160,24 @@ func (x *discoveryAgentsServer) Send(m *AgentsResponse) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _Discovery_Clock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DiscoveryServer).Clock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/discovery.Discovery/Clock",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DiscoveryServer).Clock(ctx, req.(*ClockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Discovery_ServiceDesc is the grpc.ServiceDesc for Discovery service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -157,6 +189,10 @@ var Discovery_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Quorum",
 			Handler:    _Discovery_Quorum_Handler,
 		},
+		{
+			MethodName: "Clock",
+			Handler:    _Discovery_Clock_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -3,6 +3,7 @@ package discovery
 import (
 	"context"
 	"sync/atomic"
+	"time"
 
 	"google.golang.org/grpc"
 )
@@ -64,3 +65,8 @@ func (t Discovery) Agents(req *AgentsRequest, dst Discovery_AgentsServer) (err e
 
 	return err
 }
+
+// Clock returns the current time of the agent, used by peers to measure clock drift.
+func (t Discovery) Clock(ctx context.Context, req *ClockRequest) (*ClockResponse, error) {
+	return &ClockResponse{Unixnano: time.Now().UnixNano()}, nil
+}
@@ -0,0 +1,222 @@
+package agent
+
+import (
+	"context"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"github.com/hashicorp/raft"
+	"github.com/pkg/errors"
+)
+
+type leadership interface {
+	State() raft.RaftState
+	LeadershipTransfer() raft.Future
+}
+
+type leaver interface {
+	Leave(time.Duration) error
+}
+
+type noopLeadership struct{}
+
+func (t noopLeadership) State() raft.RaftState           { return raft.Shutdown }
+func (t noopLeadership) LeadershipTransfer() raft.Future { return nil }
+
+type noopLeaver struct{}
+
+func (t noopLeaver) Leave(time.Duration) error { return nil }
+
+// NewDrainGate gate used to reject new deploys while the agent is draining.
+func NewDrainGate() DrainGate {
+	return DrainGate{draining: new(int32)}
+}
+
+// DrainGate tracks if the agent is draining.
+type DrainGate struct {
+	draining *int32
+}
+
+// Close the gate, no new deploys will be accepted.
+func (t DrainGate) Close() {
+	if t.draining == nil {
+		return
+	}
+
+	atomic.StoreInt32(t.draining, 1)
+}
+
+// Open the gate, deploys will be accepted again.
+func (t DrainGate) Open() {
+	if t.draining == nil {
+		return
+	}
+
+	atomic.StoreInt32(t.draining, 0)
+}
+
+// Draining returns true once the gate is closed.
+func (t DrainGate) Draining() bool {
+	return t.draining != nil && atomic.LoadInt32(t.draining) == 1
+}
+
+// DrainOption options for draining an agent.
+type DrainOption func(*drain)
+
+// DrainOptionGate gate to close preventing new deploys.
+func DrainOptionGate(g DrainGate) DrainOption {
+	return func(d *drain) {
+		d.gate = g
+	}
+}
+
+// DrainOptionDeployer deployer to wait on for in flight deploys to complete.
+func DrainOptionDeployer(dd deployer) DrainOption {
+	return func(d *drain) {
+		d.deployer = dd
+	}
+}
+
+// DrainOptionLeadership raft instance to transfer leadership away from.
+func DrainOptionLeadership(l leadership) DrainOption {
+	return func(d *drain) {
+		d.leadership = l
+	}
+}
+
+// DrainOptionCluster cluster to leave once drained.
+func DrainOptionCluster(c leaver) DrainOption {
+	return func(d *drain) {
+		d.cluster = c
+	}
+}
+
+// DrainOptionPollFrequency frequency to check for in flight deploys.
+func DrainOptionPollFrequency(f time.Duration) DrainOption {
+	return func(d *drain) {
+		d.poll = f
+	}
+}
+
+type drain struct {
+	gate       DrainGate
+	deployer   deployer
+	leadership leadership
+	cluster    leaver
+	poll       time.Duration
+}
+
+// Drain gracefully removes the agent from the cluster. stops accepting new deploys,
+// waits for in flight deploys to complete, transfers raft leadership if the agent is the
+// leader, and then leaves the cluster. the entire sequence must complete within the timeout.
+// if the drain fails the gate is reopened and the agent resumes accepting deploys.
+func Drain(ctx context.Context, timeout time.Duration, options ...DrainOption) (err error) {
+	d := drain{
+		gate:       NewDrainGate(),
+		deployer:   noopDeployer{},
+		leadership: noopLeadership{},
+		cluster:    noopLeaver{},
+		poll:       time.Second,
+	}
+
+	for _, opt := range options {
+		opt(&d)
+	}
+
+	ctx, done := context.WithTimeout(ctx, timeout)
+	defer done()
+
+	log.Println("drain initiated")
+	defer log.Println("drain completed")
+
+	d.gate.Close()
+	defer func() {
+		if err != nil {
+			log.Println("drain failed, accepting deploys", err)
+			d.gate.Open()
+		}
+	}()
+
+	if err = d.inflight(ctx); err != nil {
+		return err
+	}
+
+	if d.leadership.State() == raft.Leader {
+		log.Println("drain transferring leadership")
+		if err = d.transfer(ctx); err != nil {
+			return errors.Wrap(err, "failed to transfer leadership")
+		}
+	}
+
+	if err = ctx.Err(); err != nil {
+		return errors.Wrap(err, "drain timed out")
+	}
+
+	deadline, _ := ctx.Deadline()
+	return errors.Wrap(d.cluster.Leave(time.Until(deadline)), "failed to leave cluster")
+}
+
+// DrainOnSignal drains the agent once the signal context is done, e.g.) the agent received
+// a shutdown signal. returns without draining when the agent stops before the signal is received
+// or the timeout is <= 0. blocking.
+func DrainOnSignal(signal, stopped context.Context, timeout time.Duration, options ...DrainOption) error {
+	select {
+	case <-stopped.Done():
+		return nil
+	case <-signal.Done():
+	}
+
+	if timeout <= 0 {
+		return nil
+	}
+
+	return Drain(stopped, timeout, options...)
+}
+
+// transfer leadership, bounded by the context.
+func (t drain) transfer(ctx context.Context) error {
+	transferred := make(chan error, 1)
+	go func() {
+		transferred <- t.leadership.LeadershipTransfer().Error()
+	}()
+
+	select {
+	case <-ctx.Done():
+		return errors.Wrap(ctx.Err(), "drain timed out transferring leadership")
+	case err := <-transferred:
+		return err
+	}
+}
+
+// inflight wait for active deploys to complete.
+func (t drain) inflight(ctx context.Context) (err error) {
+	var (
+		deploys []*Deploy
+	)
+
+	ticker := time.NewTicker(t.poll)
+	defer ticker.Stop()
+
+	for {
+		if deploys, err = t.deployer.Deployments(); err != nil {
+			return errors.Wrap(err, "failed to read deployments")
+		}
+
+		if !deploying(deploys...) {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return errors.Wrap(ctx.Err(), "drain timed out waiting for deploys to complete")
+		case <-ticker.C:
+		}
+	}
+}
+
+// deploying checks if the most recent deploy is still active, deployments are
+// ordered from newest to oldest.
+func deploying(deploys ...*Deploy) bool {
+	return len(deploys) > 0 && deploys[0].Stage == Deploy_Deploying
+}
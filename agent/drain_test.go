@@ -0,0 +1,337 @@
+package agent_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/hashicorp/raft"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	. "github.com/james-lawrence/bw/agent"
+)
+
+type fakeFuture struct{}
+
+func (t fakeFuture) Error() error { return nil }
+
+// fakeRaft transfers leadership away on request.
+type fakeRaft struct {
+	state raft.RaftState
+}
+
+func (t *fakeRaft) State() raft.RaftState { return t.state }
+
+func (t *fakeRaft) LeadershipTransfer() raft.Future {
+	t.state = raft.Follower
+	return fakeFuture{}
+}
+
+// stuckRaft is the leader and never completes a leadership transfer.
+type stuckRaft struct{}
+
+func (t stuckRaft) State() raft.RaftState { return raft.Leader }
+
+func (t stuckRaft) LeadershipTransfer() raft.Future { return stuckFuture{} }
+
+type stuckFuture struct{}
+
+func (t stuckFuture) Error() error { select {} }
+
+type fakeLeaver struct {
+	left    bool
+	timeout time.Duration
+	// state of the raft at the time of leaving.
+	state raft.RaftState
+	r     *fakeRaft
+}
+
+func (t *fakeLeaver) Leave(d time.Duration) error {
+	t.left = true
+	t.timeout = d
+	t.state = t.r.State()
+	return nil
+}
+
+// fakeDeployer reports an active deploy for the first n checks.
+type fakeDeployer struct {
+	n *int32
+}
+
+func (t fakeDeployer) Deploy(context.Context, string, *DeployOptions, *Archive) (*Deploy, error) {
+	return &Deploy{}, nil
+}
+
+func (t fakeDeployer) Cancel()      {}
+func (t fakeDeployer) Reset() error { return nil }
+
+func (t fakeDeployer) Deployments() ([]*Deploy, error) {
+	if atomic.AddInt32(t.n, -1) >= 0 {
+		return []*Deploy{{Stage: Deploy_Deploying}}, nil
+	}
+
+	return []*Deploy{{Stage: Deploy_Completed}, {Stage: Deploy_Deploying}}, nil
+}
+
+func (t fakeDeployer) Logs([]byte) io.ReadCloser { return nil }
+
+// shutdownLeaver shuts down the raft protocol when leaving, the agent exits once drained.
+type shutdownLeaver struct {
+	r *raft.Raft
+}
+
+func (t shutdownLeaver) Leave(time.Duration) error {
+	return t.r.Shutdown().Error()
+}
+
+// newRaftCluster bootstraps an in memory raft cluster of the given size.
+func newRaftCluster(n int) (protocols []*raft.Raft) {
+	var (
+		servers    []raft.Server
+		transports []*raft.InmemTransport
+	)
+
+	for i := 0; i < n; i++ {
+		config := raft.DefaultConfig()
+		config.LogOutput = io.Discard
+		config.LocalID = raft.ServerID(fmt.Sprintf("server%d", i))
+		config.HeartbeatTimeout = 100 * time.Millisecond
+		config.ElectionTimeout = 100 * time.Millisecond
+		config.LeaderLeaseTimeout = 100 * time.Millisecond
+		config.CommitTimeout = 10 * time.Millisecond
+
+		storage := raft.NewInmemStore()
+		addr, transport := raft.NewInmemTransport("")
+		protocol, err := raft.NewRaft(config, &raft.MockFSM{}, storage, storage, raft.NewInmemSnapshotStore(), transport)
+		Expect(err).To(Succeed())
+
+		servers = append(servers, raft.Server{Address: addr, ID: config.LocalID, Suffrage: raft.Voter})
+		transports = append(transports, transport)
+		protocols = append(protocols, protocol)
+	}
+
+	for _, local := range transports {
+		for _, peer := range transports {
+			local.Connect(peer.LocalAddr(), peer)
+		}
+	}
+
+	for _, p := range protocols {
+		Expect(p.BootstrapCluster(raft.Configuration{Servers: servers}).Error()).To(Succeed())
+	}
+
+	return protocols
+}
+
+// leaderOf returns the leader of the protocols, nil if there isn't exactly one.
+func leaderOf(protocols ...*raft.Raft) (leader *raft.Raft) {
+	for _, p := range protocols {
+		if p.State() != raft.Leader {
+			continue
+		}
+
+		if leader != nil {
+			return nil
+		}
+
+		leader = p
+	}
+
+	return leader
+}
+
+func termOf(p *raft.Raft) uint64 {
+	term, err := strconv.ParseUint(p.Stats()["term"], 10, 64)
+	Expect(err).To(Succeed())
+	return term
+}
+
+var _ = Describe("Drain", func() {
+	It("should transfer leadership away and leave the cluster once deploys complete", func() {
+		var (
+			r      = &fakeRaft{state: raft.Leader}
+			c      = &fakeLeaver{r: r}
+			g      = NewDrainGate()
+			active = int32(3)
+		)
+
+		Expect(Drain(
+			context.Background(),
+			time.Second,
+			DrainOptionGate(g),
+			DrainOptionDeployer(fakeDeployer{n: &active}),
+			DrainOptionLeadership(r),
+			DrainOptionCluster(c),
+			DrainOptionPollFrequency(10*time.Millisecond),
+		)).To(Succeed())
+
+		Expect(g.Draining()).To(BeTrue())
+		Expect(active).To(BeNumerically("<", 0))
+		Expect(c.left).To(BeTrue())
+		Expect(c.state).To(Equal(raft.Follower))
+		Expect(c.timeout).To(BeNumerically(">", 0))
+		Expect(c.timeout).To(BeNumerically("<=", time.Second))
+	})
+
+	It("should fail without leaving when in flight deploys exceed the timeout", func() {
+		var (
+			r      = &fakeRaft{state: raft.Leader}
+			c      = &fakeLeaver{r: r}
+			g      = NewDrainGate()
+			active = int32(1000)
+		)
+
+		Expect(Drain(
+			context.Background(),
+			50*time.Millisecond,
+			DrainOptionGate(g),
+			DrainOptionDeployer(fakeDeployer{n: &active}),
+			DrainOptionLeadership(r),
+			DrainOptionCluster(c),
+			DrainOptionPollFrequency(10*time.Millisecond),
+		)).ToNot(Succeed())
+
+		Expect(c.left).To(BeFalse())
+		Expect(r.State()).To(Equal(raft.Leader))
+		// a failed drain resumes accepting deploys.
+		Expect(g.Draining()).To(BeFalse())
+	})
+
+	It("should bound the leadership transfer by the timeout", func() {
+		var (
+			r      = &stuckRaft{}
+			c      = &fakeLeaver{r: &fakeRaft{}}
+			g      = NewDrainGate()
+			active = int32(0)
+		)
+
+		err := Drain(
+			context.Background(),
+			50*time.Millisecond,
+			DrainOptionGate(g),
+			DrainOptionDeployer(fakeDeployer{n: &active}),
+			DrainOptionLeadership(r),
+			DrainOptionCluster(c),
+			DrainOptionPollFrequency(10*time.Millisecond),
+		)
+		Expect(err).To(MatchError(context.DeadlineExceeded))
+		Expect(c.left).To(BeFalse())
+		Expect(g.Draining()).To(BeFalse())
+	})
+
+	It("should hand off leadership within a 3 node cluster without a re-election storm", func() {
+		protocols := newRaftCluster(3)
+		defer func() {
+			for _, p := range protocols {
+				p.Shutdown()
+			}
+		}()
+
+		Eventually(func() *raft.Raft { return leaderOf(protocols...) }, 5*time.Second).ShouldNot(BeNil())
+		leader := leaderOf(protocols...)
+		term := termOf(leader)
+
+		remaining := make([]*raft.Raft, 0, len(protocols))
+		for _, p := range protocols {
+			if p != leader {
+				remaining = append(remaining, p)
+			}
+		}
+
+		active := int32(0)
+		Expect(Drain(
+			context.Background(),
+			5*time.Second,
+			DrainOptionDeployer(fakeDeployer{n: &active}),
+			DrainOptionLeadership(leader),
+			DrainOptionCluster(shutdownLeaver{r: leader}),
+		)).To(Succeed())
+
+		Eventually(func() *raft.Raft { return leaderOf(remaining...) }, 5*time.Second).ShouldNot(BeNil())
+		elected := leaderOf(remaining...)
+		// the transfer is a single election, the cluster remains stable after the leader exits.
+		Expect(termOf(elected)).To(Equal(term + 1))
+		Consistently(func() bool {
+			return leaderOf(remaining...) == elected && termOf(remaining[0]) == term+1 && termOf(remaining[1]) == term+1
+		}, time.Second, 50*time.Millisecond).Should(BeTrue())
+	})
+
+	It("should drain once signaled", func() {
+		var (
+			r      = &fakeRaft{state: raft.Leader}
+			c      = &fakeLeaver{r: r}
+			active = int32(0)
+		)
+
+		signal, signaled := context.WithCancel(context.Background())
+		signaled()
+
+		Expect(DrainOnSignal(
+			signal,
+			context.Background(),
+			time.Second,
+			DrainOptionDeployer(fakeDeployer{n: &active}),
+			DrainOptionLeadership(r),
+			DrainOptionCluster(c),
+		)).To(Succeed())
+
+		Expect(c.left).To(BeTrue())
+		Expect(c.state).To(Equal(raft.Follower))
+	})
+
+	It("should not drain when stopped without a signal", func() {
+		var (
+			r = &fakeRaft{state: raft.Leader}
+			c = &fakeLeaver{r: r}
+		)
+
+		stopped, stop := context.WithCancel(context.Background())
+		stop()
+
+		Expect(DrainOnSignal(context.Background(), stopped, time.Second, DrainOptionLeadership(r), DrainOptionCluster(c))).To(Succeed())
+		Expect(c.left).To(BeFalse())
+		Expect(r.State()).To(Equal(raft.Leader))
+	})
+
+	It("should not drain when disabled", func() {
+		var (
+			r = &fakeRaft{state: raft.Leader}
+			c = &fakeLeaver{r: r}
+		)
+
+		signal, signaled := context.WithCancel(context.Background())
+		signaled()
+
+		Expect(DrainOnSignal(signal, context.Background(), 0, DrainOptionLeadership(r), DrainOptionCluster(c))).To(Succeed())
+		Expect(c.left).To(BeFalse())
+	})
+
+	It("should report timeouts as deadline exceeded", func() {
+		active := int32(1000)
+		s := NewServer(
+			nil,
+			ServerOptionAuth(testauth{}),
+			ServerOptionDeployer(fakeDeployer{n: &active}),
+			ServerOptionDrain(NewDrainGate(), DrainOptionPollFrequency(10*time.Millisecond)),
+		)
+		_, err := s.Drain(context.Background(), &DrainRequest{Timeout: int64(50 * time.Millisecond)})
+		Expect(status.Code(err)).To(Equal(codes.DeadlineExceeded))
+	})
+
+	It("should reject deploys while draining", func() {
+		g := NewDrainGate()
+		s := NewServer(nil, ServerOptionAuth(testauth{}), ServerOptionDrain(g))
+		_, err := s.Deploy(context.Background(), &DeployRequest{})
+		Expect(err).ToNot(HaveOccurred())
+
+		g.Close()
+		_, err = s.Deploy(context.Background(), &DeployRequest{})
+		Expect(status.Code(err)).To(Equal(codes.Unavailable))
+	})
+})
@@ -0,0 +1,78 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"time"
+)
+
+// frequency a followed deploy log is checked for new content.
+const followPoll = 500 * time.Millisecond
+
+// followLogs streams the log of the deploy as it is written. waits for the deploy to start
+// and completes once the deploy is no longer in progress.
+func followLogs(ctx context.Context, d deployer, did []byte) io.ReadCloser {
+	r, w := io.Pipe()
+	go func() {
+		w.CloseWithError(tail(ctx, d, did, w))
+	}()
+
+	return r
+}
+
+func tail(ctx context.Context, d deployer, did []byte, dst io.Writer) (err error) {
+	var (
+		logs io.ReadCloser
+	)
+
+	defer func() {
+		if logs != nil {
+			logs.Close()
+		}
+	}()
+
+	poll := time.NewTicker(followPoll)
+	defer poll.Stop()
+
+	for {
+		stage, found := deployStage(d, did)
+
+		if found && logs == nil {
+			logs = d.Logs(did)
+		}
+
+		if logs != nil {
+			if _, err = io.Copy(dst, logs); err != nil {
+				return err
+			}
+		}
+
+		// the log is fully written once the deploy is no longer in progress.
+		if found && stage != Deploy_Deploying {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-poll.C:
+		}
+	}
+}
+
+// deployStage of the deploy recorded by the deployer, false when the deploy hasn't started.
+func deployStage(d deployer, did []byte) (Deploy_Stage, bool) {
+	deploys, err := d.Deployments()
+	if err != nil {
+		return Deploy_Deploying, false
+	}
+
+	for _, dep := range deploys {
+		if dep.Archive != nil && bytes.Equal(dep.Archive.DeploymentID, did) {
+			return dep.Stage, true
+		}
+	}
+
+	return Deploy_Deploying, false
+}
@@ -0,0 +1,125 @@
+package agent
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// HealthCheck returns an error describing why the agent is unable to serve.
+type HealthCheck func(context.Context) error
+
+// HealthCheckJoined fails until the agent has joined the cluster.
+func HealthCheckJoined(c interface{ Quorum() []*Peer }) HealthCheck {
+	return func(context.Context) error {
+		if len(c.Quorum()) == 0 {
+			return errors.New("agent has not joined the cluster")
+		}
+
+		return nil
+	}
+}
+
+// HealthCheckQuorum fails while the cluster lacks a leader.
+func HealthCheckQuorum(q interface {
+	Info(context.Context) (InfoResponse, error)
+}) HealthCheck {
+	return func(ctx context.Context) error {
+		info, err := q.Info(ctx)
+		if err != nil {
+			return errors.Wrap(err, "unable to determine the quorum of the cluster")
+		}
+
+		if info.Leader == nil {
+			return errors.New("cluster does not have a leader")
+		}
+
+		return nil
+	}
+}
+
+// HealthCheckIdle fails while the agent is deploying.
+func HealthCheckIdle(d interface{ Deployments() ([]*Deploy, error) }) HealthCheck {
+	return func(context.Context) error {
+		deploys, err := d.Deployments()
+		if err != nil {
+			return errors.Wrap(err, "unable to read deployments")
+		}
+
+		if len(deploys) > 0 && deploys[0].Stage == Deploy_Deploying {
+			return errors.New("agent is deploying")
+		}
+
+		return nil
+	}
+}
+
+// NewHealth the serving status of the agent, the agent is serving once all the checks pass.
+func NewHealth(checks ...HealthCheck) *Health {
+	srv := health.NewServer()
+	srv.SetServingStatus("", grpc_health_v1.HealthCheckResponse_NOT_SERVING)
+
+	return &Health{
+		srv:    srv,
+		checks: checks,
+		status: grpc_health_v1.HealthCheckResponse_NOT_SERVING,
+	}
+}
+
+// Health implements the standard grpc health service (grpc_health_v1).
+type Health struct {
+	srv    *health.Server
+	checks []HealthCheck
+	status grpc_health_v1.HealthCheckResponse_ServingStatus
+}
+
+// Bind to a grpc server.
+func (t *Health) Bind(srv *grpc.Server) *Health {
+	grpc_health_v1.RegisterHealthServer(srv, t.srv)
+	return t
+}
+
+// Check evaluates the checks and updates the serving status.
+func (t *Health) Check(ctx context.Context) grpc_health_v1.HealthCheckResponse_ServingStatus {
+	status := grpc_health_v1.HealthCheckResponse_SERVING
+
+	for _, check := range t.checks {
+		if err := check(ctx); err != nil {
+			if t.status != grpc_health_v1.HealthCheckResponse_NOT_SERVING {
+				log.Println("agent is not serving", err)
+			}
+
+			status = grpc_health_v1.HealthCheckResponse_NOT_SERVING
+			break
+		}
+	}
+
+	t.status = status
+	t.srv.SetServingStatus("", status)
+
+	return status
+}
+
+// Run evaluates the checks at the given frequency until the context is done, once done the agent
+// is permanently not serving, e.g.) during a graceful shutdown. blocking.
+func (t *Health) Run(ctx context.Context, freq time.Duration) {
+	tick := time.NewTicker(freq)
+	defer tick.Stop()
+
+	t.Check(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			t.srv.Shutdown()
+			return
+		case <-tick.C:
+			t.Check(ctx)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package agent_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
+
+	. "github.com/james-lawrence/bw/agent"
+)
+
+var _ = Describe("Health", func() {
+	var (
+		healthy atomic.Value
+		client  grpc_health_v1.HealthClient
+		ctx     context.Context
+		done    context.CancelFunc
+	)
+
+	status := func() grpc_health_v1.HealthCheckResponse_ServingStatus {
+		resp, err := client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+		Expect(err).To(Succeed())
+		return resp.Status
+	}
+
+	BeforeEach(func() {
+		healthy.Store(true)
+
+		socket, err := net.Listen("tcp", ":0")
+		Expect(err).To(Succeed())
+
+		srv := grpc.NewServer()
+		DeferCleanup(srv.Stop)
+
+		ctx, done = context.WithCancel(context.Background())
+		DeferCleanup(done)
+
+		h := NewHealth(func(context.Context) error {
+			if healthy.Load().(bool) {
+				return nil
+			}
+
+			return errors.New("unhealthy")
+		}).Bind(srv)
+
+		go srv.Serve(socket)
+		go h.Run(ctx, 10*time.Millisecond)
+
+		conn, err := grpc.Dial(socket.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+		Expect(err).To(Succeed())
+		DeferCleanup(conn.Close)
+
+		client = grpc_health_v1.NewHealthClient(conn)
+	})
+
+	It("should be serving while the checks pass", func() {
+		Eventually(status).Should(Equal(grpc_health_v1.HealthCheckResponse_SERVING))
+	})
+
+	It("should not be serving while a check fails", func() {
+		Eventually(status).Should(Equal(grpc_health_v1.HealthCheckResponse_SERVING))
+		healthy.Store(false)
+		Eventually(status).Should(Equal(grpc_health_v1.HealthCheckResponse_NOT_SERVING))
+		healthy.Store(true)
+		Eventually(status).Should(Equal(grpc_health_v1.HealthCheckResponse_SERVING))
+	})
+
+	It("should not be serving once shutdown", func() {
+		Eventually(status).Should(Equal(grpc_health_v1.HealthCheckResponse_SERVING))
+		done()
+		Eventually(status).Should(Equal(grpc_health_v1.HealthCheckResponse_NOT_SERVING))
+		Consistently(status, 100*time.Millisecond).Should(Equal(grpc_health_v1.HealthCheckResponse_NOT_SERVING))
+	})
+})
+
+var _ = Describe("HealthCheckIdle", func() {
+	It("should fail while the agent is deploying", func() {
+		deploying := deployedDeployer{{Stage: Deploy_Deploying}, {Stage: Deploy_Completed}}
+		Expect(HealthCheckIdle(deploying)(context.Background())).ToNot(Succeed())
+		Expect(HealthCheckIdle(deploying[1:])(context.Background())).To(Succeed())
+	})
+})
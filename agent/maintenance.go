@@ -0,0 +1,232 @@
+package agent
+
+import (
+	"context"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"github.com/hashicorp/raft"
+	"github.com/pkg/errors"
+)
+
+// MaintenanceOption options for the maintenance mode of an agent.
+type MaintenanceOption func(*Maintenance)
+
+// MaintenanceOptionPollFrequency frequency to check if the agent acquired leadership while in maintenance.
+func MaintenanceOptionPollFrequency(f time.Duration) MaintenanceOption {
+	return func(m *Maintenance) {
+		m.poll = f
+	}
+}
+
+// MaintenanceOptionTransferTimeout maximum duration of a leadership transfer.
+func MaintenanceOptionTransferTimeout(d time.Duration) MaintenanceOption {
+	return func(m *Maintenance) {
+		m.timeout = d
+	}
+}
+
+// MaintenanceOptionCluster advertises the maintenance of the agent to the cluster via a cluster
+// wide flag, see MaintenanceFlag. leadership is only transferred while a voter of the cluster
+// is outside of maintenance, otherwise the voters in maintenance would pass leadership between
+// themselves indefinitely.
+func MaintenanceOptionCluster(c maintenanceCluster, f flagger) MaintenanceOption {
+	return func(m *Maintenance) {
+		m.cluster = c
+		m.flags = f
+	}
+}
+
+// MaintenanceFlag key of the cluster wide flag set while the named agent is in maintenance.
+func MaintenanceFlag(name string) string {
+	return "bw.maintenance." + name
+}
+
+type maintenanceCluster interface {
+	Local() *Peer
+	Quorum() []*Peer
+}
+
+type flagger interface {
+	SetFlag(ctx context.Context, key, value string) error
+	GetFlag(ctx context.Context, key string) (*Flag, error)
+}
+
+// NewMaintenance maintenance mode of the agent, see Maintenance.
+func NewMaintenance(l leadership, options ...MaintenanceOption) Maintenance {
+	m := Maintenance{
+		active:     new(int32),
+		published:  new(int32),
+		retaining:  new(int32),
+		leadership: l,
+		poll:       time.Second,
+		timeout:    10 * time.Second,
+	}
+
+	for _, opt := range options {
+		opt(&m)
+	}
+
+	return m
+}
+
+// Maintenance marks the agent as non-preferred for leadership, e.g.) during a rolling restart.
+// raft has no election priorities, instead while in maintenance the agent transfers leadership
+// to a peer whenever it is elected, leadership tends to stay on the stable nodes.
+type Maintenance struct {
+	active     *int32
+	published  *int32 // the maintenance flag of the agent is set.
+	retaining  *int32 // leadership is retained for the lack of a voter outside of maintenance.
+	leadership leadership
+	cluster    maintenanceCluster
+	flags      flagger
+	poll       time.Duration
+	timeout    time.Duration
+}
+
+// EnterMaintenance marks the agent as non-preferred for leadership, transferring leadership
+// if the agent is currently the leader.
+func (t Maintenance) EnterMaintenance() error {
+	atomic.StoreInt32(t.active, 1)
+	log.Println("entered maintenance")
+
+	// the flag is published by Run once the quorum is available.
+	if err := t.publish(); err != nil {
+		log.Println("maintenance failed to publish the maintenance flag", err)
+	}
+
+	return t.demote()
+}
+
+// ExitMaintenance restores the agent as a candidate for leadership.
+func (t Maintenance) ExitMaintenance() {
+	atomic.StoreInt32(t.active, 0)
+	log.Println("exited maintenance")
+
+	if err := t.unpublish(); err != nil {
+		log.Println("maintenance failed to clear the maintenance flag", err)
+	}
+}
+
+// InMaintenance returns true while the agent is in maintenance.
+func (t Maintenance) InMaintenance() bool {
+	return atomic.LoadInt32(t.active) == 1
+}
+
+// Run transfers leadership away from the agent whenever it is elected while in maintenance.
+// blocks until the context is done or the agent exits maintenance.
+func (t Maintenance) Run(ctx context.Context) {
+	ticker := time.NewTicker(t.poll)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !t.InMaintenance() {
+				return
+			}
+
+			if err := t.publish(); err != nil {
+				log.Println("maintenance failed to publish the maintenance flag", err)
+			}
+
+			if err := t.demote(); err != nil {
+				log.Println("maintenance failed to transfer leadership", err)
+			}
+		}
+	}
+}
+
+// demote transfers leadership when the agent is the leader while in maintenance.
+func (t Maintenance) demote() error {
+	if !t.InMaintenance() || t.leadership.State() != raft.Leader {
+		return nil
+	}
+
+	eligible, err := t.eligible()
+	if err != nil {
+		return err
+	}
+
+	if !eligible {
+		if atomic.CompareAndSwapInt32(t.retaining, 0, 1) {
+			log.Println("maintenance retaining leadership, every voter is in maintenance")
+		}
+		return nil
+	}
+	atomic.StoreInt32(t.retaining, 0)
+
+	log.Println("maintenance transferring leadership")
+
+	transferred := make(chan error, 1)
+	go func() {
+		transferred <- t.leadership.LeadershipTransfer().Error()
+	}()
+
+	select {
+	case <-time.After(t.timeout):
+		return errors.New("timed out transferring leadership")
+	case err := <-transferred:
+		return errors.Wrap(err, "failed to transfer leadership")
+	}
+}
+
+// eligible checks if a voter outside of maintenance exists to transfer leadership to.
+func (t Maintenance) eligible() (bool, error) {
+	if t.cluster == nil {
+		return true, nil
+	}
+
+	ctx, done := context.WithTimeout(context.Background(), t.timeout)
+	defer done()
+
+	local := t.cluster.Local()
+	for _, p := range t.cluster.Quorum() {
+		if p.Name == local.Name {
+			continue
+		}
+
+		f, err := t.flags.GetFlag(ctx, MaintenanceFlag(p.Name))
+		if err != nil {
+			return false, errors.Wrap(err, "unable to determine the maintenance of the voters")
+		}
+
+		if f.GetValue() == "" {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// publish the maintenance flag of the agent.
+func (t Maintenance) publish() error {
+	if t.cluster == nil || atomic.LoadInt32(t.published) == 1 {
+		return nil
+	}
+
+	ctx, done := context.WithTimeout(context.Background(), t.timeout)
+	defer done()
+
+	if err := t.flags.SetFlag(ctx, MaintenanceFlag(t.cluster.Local().Name), "true"); err != nil {
+		return err
+	}
+
+	atomic.StoreInt32(t.published, 1)
+	return nil
+}
+
+// unpublish clears the maintenance flag of the agent.
+func (t Maintenance) unpublish() error {
+	if t.cluster == nil || !atomic.CompareAndSwapInt32(t.published, 1, 0) {
+		return nil
+	}
+
+	ctx, done := context.WithTimeout(context.Background(), t.timeout)
+	defer done()
+
+	return t.flags.SetFlag(ctx, MaintenanceFlag(t.cluster.Local().Name), "")
+}
@@ -0,0 +1,213 @@
+package agent_test
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/raft"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/james-lawrence/bw/agent"
+)
+
+// fakeElection leadership shared by the nodes of a fake raft cluster.
+type fakeElection struct {
+	m      sync.Mutex
+	leader string
+	nodes  []string
+}
+
+func (t *fakeElection) elect(id string) {
+	t.m.Lock()
+	defer t.m.Unlock()
+	t.leader = id
+}
+
+func (t *fakeElection) current() string {
+	t.m.Lock()
+	defer t.m.Unlock()
+	return t.leader
+}
+
+// fakeNode raft instance of a single node within the fake election, transfers
+// leadership to the next node of the cluster.
+type fakeNode struct {
+	id       string
+	election *fakeElection
+}
+
+func (t fakeNode) State() raft.RaftState {
+	if t.election.current() == t.id {
+		return raft.Leader
+	}
+
+	return raft.Follower
+}
+
+func (t fakeNode) LeadershipTransfer() raft.Future {
+	t.election.m.Lock()
+	defer t.election.m.Unlock()
+
+	for _, n := range t.election.nodes {
+		if n != t.id {
+			t.election.leader = n
+			break
+		}
+	}
+
+	return fakeFuture{}
+}
+
+// fakeMaintenanceCluster voters of the fake election.
+type fakeMaintenanceCluster struct {
+	local  string
+	voters []string
+}
+
+func (t fakeMaintenanceCluster) Local() *Peer {
+	return &Peer{Name: t.local}
+}
+
+func (t fakeMaintenanceCluster) Quorum() (peers []*Peer) {
+	for _, v := range t.voters {
+		peers = append(peers, &Peer{Name: v})
+	}
+
+	return peers
+}
+
+// fakeFlags cluster wide flags shared by the nodes of the fake election.
+type fakeFlags struct {
+	m     sync.Mutex
+	flags map[string]string
+}
+
+func (t *fakeFlags) SetFlag(ctx context.Context, key, value string) error {
+	t.m.Lock()
+	defer t.m.Unlock()
+	t.flags[key] = value
+	return nil
+}
+
+func (t *fakeFlags) GetFlag(ctx context.Context, key string) (*Flag, error) {
+	t.m.Lock()
+	defer t.m.Unlock()
+	return &Flag{Key: key, Value: t.flags[key]}, nil
+}
+
+var _ = Describe("Maintenance", func() {
+	var (
+		election *fakeElection
+		node1    fakeNode
+	)
+
+	BeforeEach(func() {
+		election = &fakeElection{leader: "node1", nodes: []string{"node1", "node2"}}
+		node1 = fakeNode{id: "node1", election: election}
+	})
+
+	It("should transfer leadership when entering maintenance", func() {
+		m := NewMaintenance(node1)
+		Expect(m.EnterMaintenance()).To(Succeed())
+		Expect(m.InMaintenance()).To(BeTrue())
+		Expect(election.current()).To(Equal("node2"))
+	})
+
+	It("should not transfer leadership from a follower", func() {
+		election.elect("node2")
+		m := NewMaintenance(node1)
+		Expect(m.EnterMaintenance()).To(Succeed())
+		Expect(election.current()).To(Equal("node2"))
+	})
+
+	It("should avoid leadership in favor of a peer while in maintenance", func() {
+		ctx, done := context.WithCancel(context.Background())
+		defer done()
+
+		m := NewMaintenance(node1, MaintenanceOptionPollFrequency(time.Millisecond))
+		Expect(m.EnterMaintenance()).To(Succeed())
+		go m.Run(ctx)
+
+		election.elect("node1")
+		Eventually(election.current).Should(Equal("node2"))
+	})
+
+	It("should retain leadership once maintenance exits", func() {
+		ctx, done := context.WithCancel(context.Background())
+		defer done()
+
+		m := NewMaintenance(node1, MaintenanceOptionPollFrequency(time.Millisecond))
+		Expect(m.EnterMaintenance()).To(Succeed())
+		go m.Run(ctx)
+
+		m.ExitMaintenance()
+		Expect(m.InMaintenance()).To(BeFalse())
+		election.elect("node1")
+		Consistently(election.current, 50*time.Millisecond).Should(Equal("node1"))
+	})
+
+	It("should stop once maintenance exits", func() {
+		m := NewMaintenance(node1, MaintenanceOptionPollFrequency(time.Millisecond))
+		Expect(m.EnterMaintenance()).To(Succeed())
+
+		stopped := make(chan struct{})
+		go func() {
+			defer close(stopped)
+			m.Run(context.Background())
+		}()
+
+		m.ExitMaintenance()
+		Eventually(stopped).Should(BeClosed())
+	})
+
+	Context("within a cluster", func() {
+		var (
+			flags *fakeFlags
+			node2 fakeNode
+		)
+
+		cluster := func(local string) MaintenanceOption {
+			return MaintenanceOptionCluster(fakeMaintenanceCluster{local: local, voters: election.nodes}, flags)
+		}
+
+		BeforeEach(func() {
+			flags = &fakeFlags{flags: map[string]string{}}
+			node2 = fakeNode{id: "node2", election: election}
+		})
+
+		It("should advertise maintenance to the cluster", func() {
+			m := NewMaintenance(node1, cluster("node1"))
+			Expect(m.EnterMaintenance()).To(Succeed())
+			Expect(flags.GetFlag(context.Background(), MaintenanceFlag("node1"))).To(HaveField("Value", Not(BeEmpty())))
+			Expect(election.current()).To(Equal("node2"))
+
+			m.ExitMaintenance()
+			Expect(flags.GetFlag(context.Background(), MaintenanceFlag("node1"))).To(HaveField("Value", BeEmpty()))
+		})
+
+		It("should retain leadership when every voter is in maintenance", func() {
+			ctx, done := context.WithCancel(context.Background())
+			defer done()
+
+			m1 := NewMaintenance(node1, cluster("node1"), MaintenanceOptionPollFrequency(time.Millisecond))
+			m2 := NewMaintenance(node2, cluster("node2"), MaintenanceOptionPollFrequency(time.Millisecond))
+			Expect(m2.EnterMaintenance()).To(Succeed())
+			Expect(m1.EnterMaintenance()).To(Succeed())
+			go m1.Run(ctx)
+			go m2.Run(ctx)
+
+			Consistently(election.current, 50*time.Millisecond).Should(Equal("node1"))
+
+			// leadership moves once a voter exits maintenance.
+			m2.ExitMaintenance()
+			Eventually(election.current).Should(Equal("node2"))
+		})
+	})
+
+	It("should time out a stuck leadership transfer", func() {
+		m := NewMaintenance(stuckRaft{}, MaintenanceOptionTransferTimeout(10*time.Millisecond))
+		Expect(m.EnterMaintenance()).ToNot(Succeed())
+	})
+})
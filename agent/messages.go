@@ -295,3 +295,22 @@ func ApplyToStateMachine(r *raft.Raft, m *Message, d time.Duration) (err error)
 
 	return nil
 }
+
+// FlagEvent sets the cluster wide flag, a blank value unsets the flag.
+// flags are persisted by the quorum's snapshots, never appearing in the deploy logs.
+func FlagEvent(p *Peer, key, value string) *Message {
+	return &Message{
+		Id:        uuid.Must(uuid.NewV4()).String(),
+		Type:      Message_FlagEvent,
+		Ephemeral: true,
+		Hidden:    true,
+		Peer:      p,
+		Ts:        time.Now().Unix(),
+		Event: &Message_Flag{
+			Flag: &Flag{
+				Key:   key,
+				Value: value,
+			},
+		},
+	}
+}
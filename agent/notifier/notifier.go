@@ -57,6 +57,11 @@ func (t Notifier) Start(ctx context.Context, local, proxy *agent.Peer, d dialer)
 }
 
 func notifyDeployCommand(n notifications.Notifier, dc *agent.DeployCommand) {
+	// simulated deploys don't change the cluster, nothing to notify about.
+	if dc.Options.GetSimulate() {
+		return
+	}
+
 	switch dc.Command {
 	case agent.DeployCommand_Begin, agent.DeployCommand_Cancel, agent.DeployCommand_Done, agent.DeployCommand_Failed:
 		n.Notify(dc)
@@ -0,0 +1,79 @@
+package agent
+
+import (
+	"context"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// NotifyReady signals systemd the agent is ready (READY=1), see sd_notify(3).
+// noop when the agent isn't run by systemd as a Type=notify service.
+func NotifyReady() error {
+	return sdnotify("READY=1")
+}
+
+// NotifyWatchdog pings the systemd watchdog (WATCHDOG=1) at half the configured
+// interval until the context is done. noop when the watchdog isn't enabled for the agent.
+func NotifyWatchdog(ctx context.Context) error {
+	interval, ok := watchdog()
+	if !ok {
+		return nil
+	}
+
+	t := time.NewTicker(interval / 2)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-t.C:
+			if err := sdnotify("WATCHDOG=1"); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// watchdog interval configured by systemd for this process.
+func watchdog() (interval time.Duration, ok bool) {
+	if pid := os.Getenv("WATCHDOG_PID"); pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		return 0, false
+	}
+
+	usec, err := strconv.ParseInt(os.Getenv("WATCHDOG_USEC"), 10, 64)
+	if err != nil || usec <= 0 {
+		return 0, false
+	}
+
+	return time.Duration(usec) * time.Microsecond, true
+}
+
+func sdnotify(state string) (err error) {
+	var (
+		conn *net.UnixConn
+	)
+
+	socket := os.Getenv("NOTIFY_SOCKET")
+	if socket == "" {
+		return nil
+	}
+
+	// abstract namespace sockets.
+	if strings.HasPrefix(socket, "@") {
+		socket = "\x00" + socket[1:]
+	}
+
+	if conn, err = net.DialUnix("unixgram", nil, &net.UnixAddr{Name: socket, Net: "unixgram"}); err != nil {
+		return errors.Wrap(err, "failed to connect to the systemd notify socket")
+	}
+	defer conn.Close()
+
+	_, err = conn.Write([]byte(state))
+	return errors.Wrapf(err, "failed to notify systemd: %s", state)
+}
@@ -0,0 +1,71 @@
+package agent_test
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/james-lawrence/bw/agent"
+)
+
+var _ = Describe("NotifyReady", func() {
+	// notifysocket fakes the systemd notify socket, returning the received states.
+	notifysocket := func() <-chan string {
+		path := filepath.Join(GinkgoT().TempDir(), "notify.sock")
+		conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(conn.Close)
+		GinkgoT().Setenv("NOTIFY_SOCKET", path)
+
+		states := make(chan string, 10)
+		go func() {
+			buf := make([]byte, 1024)
+			for {
+				n, err := conn.Read(buf)
+				if err != nil {
+					return
+				}
+				states <- string(buf[:n])
+			}
+		}()
+
+		return states
+	}
+
+	It("should send READY=1 to the notify socket", func() {
+		states := notifysocket()
+		Expect(NotifyReady()).To(Succeed())
+		Eventually(states).Should(Receive(Equal("READY=1")))
+	})
+
+	It("should be a noop when not run by systemd", func() {
+		GinkgoT().Setenv("NOTIFY_SOCKET", "")
+		Expect(NotifyReady()).To(Succeed())
+	})
+
+	It("should ping the watchdog when enabled", func() {
+		states := notifysocket()
+		GinkgoT().Setenv("WATCHDOG_USEC", strconv.Itoa(int((20 * time.Millisecond).Microseconds())))
+		GinkgoT().Setenv("WATCHDOG_PID", strconv.Itoa(os.Getpid()))
+
+		ctx, done := context.WithTimeout(context.Background(), time.Second)
+		defer done()
+		go func() {
+			_ = NotifyWatchdog(ctx)
+		}()
+
+		Eventually(states).Should(Receive(Equal("WATCHDOG=1")))
+	})
+
+	It("should not ping the watchdog of another process", func() {
+		GinkgoT().Setenv("WATCHDOG_USEC", "1000")
+		GinkgoT().Setenv("WATCHDOG_PID", strconv.Itoa(os.Getpid()+1))
+		Expect(NotifyWatchdog(context.Background())).To(Succeed())
+	})
+})
@@ -0,0 +1,95 @@
+package agent
+
+import (
+	"github.com/james-lawrence/bw"
+)
+
+// NewLabelPartitioner partitions the peers with a label using the concurrency of the label,
+// see bw.PartitionFromFloat64. peers without a matching label use the default partitioner.
+func NewLabelPartitioner(d bw.Partitioner, concurrency map[string]float64) LabelPartitioner {
+	labels := make(map[string]bw.Partitioner, len(concurrency))
+	for label, c := range concurrency {
+		labels[label] = bw.PartitionFromFloat64(c)
+	}
+
+	return LabelPartitioner{
+		Default: d,
+		Labels:  labels,
+	}
+}
+
+// LabelPartitioner selects the partition size of a peer based on its labels.
+type LabelPartitioner struct {
+	Default bw.Partitioner
+	Labels  map[string]bw.Partitioner
+}
+
+// Partition implements bw.Partitioner using the default partitioner, caps the
+// peers deployed to simultaneously across the labels.
+func (t LabelPartitioner) Partition(length int) int {
+	return t.Default.Partition(length)
+}
+
+// Classify the peer by the first of its labels with a partitioner, peers
+// without a matching label have a blank label and use the default partitioner.
+func (t LabelPartitioner) Classify(p *Peer) (label string, _ bw.Partitioner) {
+	for _, label := range p.Labels {
+		if partitioner, ok := t.Labels[label]; ok {
+			return label, partitioner
+		}
+	}
+
+	return "", t.Default
+}
+
+// Partitions previews the batches of peers the partitioner deploys to simultaneously,
+// the batches are in the order they're deployed. see Partitions.
+func (t LabelPartitioner) Partitions(peers ...*Peer) [][]*Peer {
+	return Partitions(t, peers...)
+}
+
+// Partitions previews the batches of peers the partitioner deploys to simultaneously,
+// the batches are in the order they're deployed. partitioners that classify peers,
+// e.g.) LabelPartitioner, batch each class independently ordered by the first peer of the class.
+func Partitions(p bw.Partitioner, peers ...*Peer) (batches [][]*Peer) {
+	type classifier interface {
+		Classify(*Peer) (string, bw.Partitioner)
+	}
+
+	var (
+		order       []string
+		groups      = map[string][]*Peer{}
+		partitioner = map[string]bw.Partitioner{}
+	)
+
+	for _, peer := range peers {
+		class, cp := "", p
+		if c, ok := p.(classifier); ok {
+			class, cp = c.Classify(peer)
+		}
+
+		if _, ok := groups[class]; !ok {
+			order = append(order, class)
+			partitioner[class] = cp
+		}
+
+		groups[class] = append(groups[class], peer)
+	}
+
+	batches = make([][]*Peer, 0, len(peers))
+	for _, class := range order {
+		group := groups[class]
+		size := partitioner[class].Partition(len(group))
+		for len(group) > 0 {
+			n := size
+			if n > len(group) {
+				n = len(group)
+			}
+
+			batches = append(batches, group[:n:n])
+			group = group[n:]
+		}
+	}
+
+	return batches
+}
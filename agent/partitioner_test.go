@@ -0,0 +1,59 @@
+package agent_test
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/james-lawrence/bw"
+	. "github.com/james-lawrence/bw/agent"
+)
+
+var _ = Describe("Partitions", func() {
+	peers := func(n int, options ...PeerOption) (r []*Peer) {
+		for i := 0; i < n; i++ {
+			r = append(r, NewPeer(fmt.Sprintf("node%d", i+1), options...))
+		}
+		return r
+	}
+
+	sizes := func(batches [][]*Peer) (r []int) {
+		r = []int{}
+		for _, b := range batches {
+			r = append(r, len(b))
+		}
+		return r
+	}
+
+	DescribeTable("should batch the peers using the concurrency", func(concurrency float64, n int, expected ...int) {
+		batches := Partitions(bw.PartitionFromFloat64(concurrency), peers(n)...)
+		Expect(sizes(batches)).To(Equal(append([]int{}, expected...)))
+	},
+		Entry("zero peers", 2.0, 0),
+		Entry("one peer", 2.0, 1, 1),
+		Entry("zero concurrency deploys one at a time", 0.0, 3, 1, 1, 1),
+		Entry("fractional concurrency", 0.25, 10, 2, 2, 2, 2, 2),
+		Entry("fractional concurrency of a small cluster", 0.25, 3, 1, 1, 1),
+		Entry("constant concurrency with a remainder", 2.0, 5, 2, 2, 1),
+	)
+
+	It("should preserve the order of the peers", func() {
+		batches := Partitions(bw.ConstantPartitioner(2), peers(3)...)
+		Expect(batches).To(HaveLen(2))
+		Expect(batches[0][0].Name).To(Equal("node1"))
+		Expect(batches[0][1].Name).To(Equal("node2"))
+		Expect(batches[1][0].Name).To(Equal("node3"))
+	})
+
+	It("should batch each label independently", func() {
+		all := append(peers(3), peers(2, PeerOptionLabels("canary"))...)
+		p := NewLabelPartitioner(bw.ConstantPartitioner(2), map[string]float64{"canary": 1})
+		Expect(sizes(p.Partitions(all...))).To(Equal([]int{2, 1, 2}))
+	})
+
+	It("should preview the batches of the client configuration", func() {
+		config := NewConfigClient(DefaultConfigClient(), CCOptionConcurrency(0.25))
+		Expect(sizes(config.Partitions(peers(8)...))).To(Equal([]int{2, 2, 2, 2}))
+	})
+})
@@ -1,12 +1,18 @@
 package agent
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net"
+	"sort"
+	"strconv"
+	"time"
 
 	"github.com/hashicorp/memberlist"
 	"github.com/james-lawrence/bw"
+	"github.com/james-lawrence/bw/internal/envx"
+	"github.com/james-lawrence/bw/internal/netx"
 	"github.com/james-lawrence/bw/internal/stringsx"
 	"github.com/james-lawrence/bw/internal/systemx"
 	"github.com/pkg/errors"
@@ -56,8 +62,9 @@ func AutocertAddress(p *Peer) string {
 	return stringsx.DefaultIfBlank(URIPeer(p, bw.ProtocolAutocert), net.JoinHostPort(p.Ip, fmt.Sprint(p.P2PPort)))
 }
 
-// SWIMAddress for peer.
+// SWIMAddress for peer, using the advertised address of the peer preferred by LocalRoutes.
 func SWIMAddress(p *Peer) string {
+	p = LocalRoutes(p)[0]
 	return stringsx.DefaultIfBlank(URIPeer(p, bw.ProtocolSWIM), net.JoinHostPort(p.Ip, fmt.Sprint(p.P2PPort)))
 }
 
@@ -66,6 +73,92 @@ func RaftAddress(p *Peer) string {
 	return stringsx.DefaultIfBlank(P2PRawAddress(p), net.JoinHostPort(p.Ip, fmt.Sprint(p.P2PPort)))
 }
 
+// Routes to the peer via each of the advertised addresses, the primary address followed by the alternates.
+// addresses within the networks are ordered first, i.e.) on-prem peers prefer an alternate on the private
+// network while cloud peers use the primary public address. malformed alternates are skipped.
+func Routes(p *Peer, networks ...*net.IPNet) []*Peer {
+	routes := make([]*Peer, 0, 1+len(p.Alternates))
+	routes = append(routes, p)
+
+	for _, alt := range p.Alternates {
+		host, port, err := net.SplitHostPort(alt)
+		if err != nil {
+			log.Println("skipping malformed alternate address", p.Name, alt, err)
+			continue
+		}
+
+		n, err := strconv.ParseUint(port, 10, 16)
+		if err != nil {
+			log.Println("skipping malformed alternate address", p.Name, alt, err)
+			continue
+		}
+
+		dup := proto.Clone(p).(*Peer)
+		dup.Ip, dup.P2PPort, dup.Alternates = host, uint32(n), nil
+		routes = append(routes, dup)
+	}
+
+	within := func(r *Peer) bool {
+		ip := net.ParseIP(r.Ip)
+		for _, n := range networks {
+			if ip != nil && n.Contains(ip) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	sort.SliceStable(routes, func(i, j int) bool {
+		return within(routes[i]) && !within(routes[j])
+	})
+
+	return routes
+}
+
+// localnetworks routes are computed for every probe and dial of a peer, the networks
+// of the local interfaces are cached.
+var localnetworks = netx.NewNetworkCache(time.Minute)
+
+// LocalRoutes to the peer preferring the advertised addresses within the networks of the local interfaces, see Routes.
+func LocalRoutes(p *Peer) []*Peer {
+	if len(p.Alternates) == 0 {
+		return []*Peer{p}
+	}
+
+	local, err := localnetworks.Networks()
+	if err != nil {
+		log.Println("unable to determine the local networks, preferring the primary address of the peer", p.Name, err)
+	}
+
+	return Routes(p, local...)
+}
+
+// DialRoutes to the peer for the dialers, see LocalRoutes. a peer advertising a name is
+// routed via the address the name resolves to first. the resolution is bounded by the
+// resolve timeout (see bw.EnvResolveTimeout), falling back to the advertised addresses.
+func DialRoutes(ctx context.Context, p *Peer) []*Peer {
+	routes := LocalRoutes(p)
+
+	if p.AdvertisedName == "" {
+		return routes
+	}
+
+	rctx, done := context.WithTimeout(ctx, envx.Duration(bw.DefaultResolveTimeout, bw.EnvResolveTimeout))
+	defer done()
+
+	ips, err := net.DefaultResolver.LookupIPAddr(rctx, p.AdvertisedName)
+	if err != nil || len(ips) == 0 {
+		log.Println("unable to resolve the advertised name of the peer, using the advertised addresses", p.Name, p.AdvertisedName, err)
+		return routes
+	}
+
+	dup := proto.Clone(p).(*Peer)
+	dup.Ip, dup.Alternates = ips[0].IP.String(), nil
+
+	return append([]*Peer{dup}, routes...)
+}
+
 // StaticPeeringStrategy ...
 func StaticPeeringStrategy(peers ...*Peer) []string {
 	results := make([]string, 0, len(peers))
@@ -107,13 +200,41 @@ func PeerOptionPublicKey(k []byte) PeerOption {
 	}
 }
 
+// PeerOptionZone zone the peer resides within.
+func PeerOptionZone(zone string) PeerOption {
+	return func(p *Peer) {
+		p.Zone = zone
+	}
+}
+
+// PeerOptionLabels labels classifying the peer.
+func PeerOptionLabels(labels ...string) PeerOption {
+	return func(p *Peer) {
+		p.Labels = labels
+	}
+}
+
+// PeerOptionNonVoter peer participates in raft as a non-voter.
+func PeerOptionNonVoter(b bool) PeerOption {
+	return func(p *Peer) {
+		p.Nonvoter = b
+	}
+}
+
+// PeerOptionAlternates additional advertised addresses (host:port) of the peer, see Routes.
+func PeerOptionAlternates(addresses ...string) PeerOption {
+	return func(p *Peer) {
+		p.Alternates = addresses
+	}
+}
+
 // NewPeer ...
 func NewPeer(id string, opts ...PeerOption) *Peer {
 	hn := systemx.HostnameOrLocalhost()
 	p := Peer{
 		Name:    id,
 		Ip:      systemx.HostIP(hn).String(),
-		P2PPort: bw.DefaultP2PPort,
+		P2PPort: uint32(bw.DefaultP2PPort),
 		Status:  Peer_Node,
 	}
 
@@ -152,8 +273,13 @@ func NodesToPeers(nodes ...*memberlist.Node) []*Peer {
 // PeerToMetadata ...
 func PeerToMetadata(p *Peer) *PeerMetadata {
 	return &PeerMetadata{
-		Status:  int32(p.Status),
-		P2PPort: p.P2PPort,
+		Status:         int32(p.Status),
+		P2PPort:        p.P2PPort,
+		Zone:           p.Zone,
+		Labels:         p.Labels,
+		Nonvoter:       p.Nonvoter,
+		Alternates:     p.Alternates,
+		AdvertisedName: p.AdvertisedName,
 	}
 }
 
@@ -198,13 +324,32 @@ func NodeToPeer(n *memberlist.Node) (_zerop *Peer, err error) {
 	}
 
 	return &Peer{
-		Status:  Peer_State(m.Status),
-		Name:    n.Name,
-		Ip:      n.Addr.String(),
-		P2PPort: m.P2PPort,
+		Status:         Peer_State(m.Status),
+		Name:           n.Name,
+		Ip:             n.Addr.String(),
+		P2PPort:        m.P2PPort,
+		Zone:           m.Zone,
+		Labels:         m.Labels,
+		Nonvoter:       m.Nonvoter,
+		Alternates:     m.Alternates,
+		AdvertisedName: m.AdvertisedName,
 	}, nil
 }
 
+// IsNonVoter returns true when the node participates in raft as a non-voter,
+// nodes with invalid metadata are considered voters.
+func IsNonVoter(n *memberlist.Node) bool {
+	var (
+		m PeerMetadata
+	)
+
+	if n == nil || proto.Unmarshal(n.Meta, &m) != nil {
+		return false
+	}
+
+	return m.Nonvoter
+}
+
 // MustPeer if err is not nil panics.
 func MustPeer(p *Peer, err error) *Peer {
 	if err != nil {
@@ -5,6 +5,7 @@ import (
 
 	"github.com/james-lawrence/bw/internal/grpcx"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
 )
 
 type quorum interface {
@@ -15,6 +16,9 @@ type quorum interface {
 	Dispatch(context.Context, ...*Message) error
 	Info(context.Context) (InfoResponse, error)
 	Cancel(context.Context, *CancelRequest) error
+	SetFlag(ctx context.Context, key, value string) error
+	GetFlag(ctx context.Context, key string) (*Flag, error)
+	WatchFlags(ctx context.Context, key string, out func(*Flag) error) error
 }
 
 // NewQuorum ...
@@ -56,7 +60,7 @@ func (t Quorum) Info(ctx context.Context, _ *InfoRequest) (_ *InfoResponse, err
 // Deploy ...
 func (t Quorum) Deploy(ctx context.Context, req *DeployCommandRequest) (_ *DeployCommandResult, err error) {
 	if err := t.auth.Deploy(ctx); err != nil {
-		return nil, err
+		return nil, NewDeployRejection(DeployRejection_Unauthorized, status.Convert(err).Message())
 	}
 
 	if err = t.q.Deploy(ctx, req.Initiator, req.Options, req.Archive, req.Peers...); grpcx.IsUnavailable(err) {
@@ -119,3 +123,38 @@ func (t Quorum) Cancel(ctx context.Context, req *CancelRequest) (*CancelResponse
 
 	return &CancelResponse{}, t.q.Cancel(ctx, req)
 }
+
+// SetFlag sets the cluster wide flag.
+func (t Quorum) SetFlag(ctx context.Context, req *SetFlagRequest) (*SetFlagResponse, error) {
+	if err := t.auth.Deploy(ctx); err != nil {
+		return nil, err
+	}
+
+	return &SetFlagResponse{}, t.q.SetFlag(ctx, req.GetFlag().GetKey(), req.GetFlag().GetValue())
+}
+
+// GetFlag returns the cluster wide flag.
+func (t Quorum) GetFlag(ctx context.Context, req *GetFlagRequest) (_ *GetFlagResponse, err error) {
+	var (
+		flag *Flag
+	)
+
+	if err = t.auth.Deploy(ctx); err != nil {
+		return nil, err
+	}
+
+	if flag, err = t.q.GetFlag(ctx, req.Key); err != nil {
+		return nil, err
+	}
+
+	return &GetFlagResponse{Flag: flag}, nil
+}
+
+// WatchFlags watch for changes to the cluster wide flags.
+func (t Quorum) WatchFlags(req *WatchFlagsRequest, out Quorum_WatchFlagsServer) (err error) {
+	if err := t.auth.Deploy(out.Context()); err != nil {
+		return err
+	}
+
+	return t.q.WatchFlags(out.Context(), req.Key, out.Send)
+}
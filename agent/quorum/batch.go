@@ -0,0 +1,167 @@
+package quorum
+
+import (
+	"sync"
+	"time"
+
+	"github.com/hashicorp/raft"
+	"github.com/pkg/errors"
+)
+
+// DefaultApplyFlush default interval to wait for a batch to fill before applying it.
+const DefaultApplyFlush = 5 * time.Millisecond
+
+type applier interface {
+	apply(timeout time.Duration, encoded ...[]byte) error
+}
+
+// serialApplier applies each entry to raft and waits for it to be committed
+// before applying the next.
+type serialApplier struct {
+	r *raft.Raft
+}
+
+func (t serialApplier) apply(timeout time.Duration, encoded ...[]byte) (err error) {
+	for _, e := range encoded {
+		if err = awaitApply(t.r.Apply(e, timeout)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func newBatchApplier(r *raft.Raft, size int, flush time.Duration) *batchApplier {
+	return &batchApplier{
+		r:      r,
+		size:   size,
+		flush:  flush,
+		m:      &sync.Mutex{},
+		commit: &sync.Mutex{},
+	}
+}
+
+// batchApplier collects entries from concurrent callers and pipelines them into
+// raft, issuing up to size applies before waiting on their futures. a batch is
+// committed once it is full or the flush interval has elapsed.
+type batchApplier struct {
+	r       *raft.Raft
+	size    int
+	flush   time.Duration
+	m       *sync.Mutex
+	commit  *sync.Mutex
+	pending []*pendingApply
+	entries int // number of entries pending.
+	full    chan struct{}
+}
+
+// pendingApply the entries of a single call.
+type pendingApply struct {
+	encoded [][]byte
+	timeout time.Duration
+	done    chan error
+}
+
+// apply the entries to raft, returns the first error encountered.
+// matches the serial semantics: the entries of a single call are applied in order,
+// and once an entry fails the remaining entries of the call are not applied.
+// entries from concurrent calls may be interleaved.
+func (t *batchApplier) apply(timeout time.Duration, encoded ...[]byte) (err error) {
+	if len(encoded) == 0 {
+		return nil
+	}
+
+	call := &pendingApply{encoded: encoded, timeout: timeout, done: make(chan error, 1)}
+
+	t.m.Lock()
+	flusher := len(t.pending) == 0
+	if flusher {
+		t.full = make(chan struct{})
+	}
+	filled := t.entries < t.size && t.entries+len(encoded) >= t.size
+	t.pending = append(t.pending, call)
+	t.entries += len(encoded)
+	if filled {
+		close(t.full)
+	}
+	full := t.full
+	t.m.Unlock()
+
+	// the caller that opens a batch is responsible for committing it.
+	if flusher {
+		flush := time.NewTimer(t.flush)
+		select {
+		case <-full:
+		case <-flush.C:
+		}
+		flush.Stop()
+		t.drain()
+	}
+
+	return <-call.done
+}
+
+func (t *batchApplier) drain() {
+	// commits are serialized and the pending entries are claimed while holding
+	// the commit lock to ensure entries reach raft in the order they were received.
+	t.commit.Lock()
+	defer t.commit.Unlock()
+
+	t.m.Lock()
+	pending := t.pending
+	t.pending = nil
+	t.entries = 0
+	t.m.Unlock()
+
+	// each round pipelines the next entry of up to size calls, a call's following entry
+	// is only issued once the previous entry has been committed successfully.
+	for len(pending) > 0 {
+		n := min(t.size, len(pending))
+		futures := make([]raft.ApplyFuture, 0, n)
+		for _, p := range pending[:n] {
+			futures = append(futures, t.r.Apply(p.encoded[0], p.timeout))
+		}
+
+		remaining := make([]*pendingApply, 0, len(pending))
+		for i, f := range futures {
+			p := pending[i]
+			if err := awaitApply(f); err != nil {
+				p.done <- err
+				continue
+			}
+
+			if p.encoded = p.encoded[1:]; len(p.encoded) == 0 {
+				p.done <- nil
+				continue
+			}
+
+			remaining = append(remaining, p)
+		}
+
+		pending = append(remaining, pending[n:]...)
+	}
+}
+
+func awaitApply(future raft.ApplyFuture) (err error) {
+	var (
+		ok bool
+	)
+
+	if err = future.Error(); err != nil {
+		return errors.WithStack(err)
+	}
+
+	if err, ok = future.Response().(error); ok {
+		return errors.WithStack(err)
+	}
+
+	return nil
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+
+	return b
+}
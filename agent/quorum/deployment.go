@@ -36,6 +36,7 @@ type deployment struct {
 	deploying            int32                // is a deploy process in progress.
 	runningDeploy        *agent.DeployCommand // currently active deployment.
 	lastSuccessfulDeploy *agent.DeployCommand // used for bootstrapping and recovering when a deploy proxy fails.
+	halt                 context.CancelFunc   // halts the deploy being run by the local node.
 	m                    *sync.RWMutex
 }
 
@@ -61,7 +62,7 @@ func (t *deployment) Decode(ctx TranscoderContext, m *agent.Message) error {
 	switch dc.Command {
 	case agent.DeployCommand_Begin:
 		if ctx.State != StateRecovering && !atomic.CompareAndSwapInt32(&t.deploying, none, deploying) {
-			return errors.New("deploy already in progress")
+			return agent.NewDeployRejection(agent.DeployRejection_Busy, "deploy already in progress")
 		}
 
 		t.m.Lock()
@@ -70,17 +71,47 @@ func (t *deployment) Decode(ctx TranscoderContext, m *agent.Message) error {
 	case agent.DeployCommand_Done:
 		atomic.SwapInt32(&t.deploying, none)
 		t.m.Lock()
-		t.lastSuccessfulDeploy = dc
+		// simulated deploys never ran, so they can't be used for bootstrapping.
+		if !dc.Options.GetSimulate() {
+			t.lastSuccessfulDeploy = dc
+		}
 		t.runningDeploy = nil
 		t.m.Unlock()
+		t.stop()
 	default:
 		atomic.SwapInt32(&t.deploying, none)
+		t.stop()
 	}
 
 	return nil
 }
 
-func (t *deployment) getInfo(leader *agent.Peer) agent.InfoResponse {
+// background context for a deploy run by the local node, the context is cancelled
+// once the deploy is cancelled, restarted, or finishes.
+func (t *deployment) background() context.Context {
+	ctx, halt := context.WithCancel(context.Background())
+
+	t.m.Lock()
+	defer t.m.Unlock()
+	if t.halt != nil {
+		t.halt()
+	}
+	t.halt = halt
+
+	return ctx
+}
+
+// stop the deploy being run by the local node.
+func (t *deployment) stop() {
+	t.m.Lock()
+	defer t.m.Unlock()
+	if t.halt != nil {
+		t.halt()
+		t.halt = nil
+	}
+}
+
+func (t *deployment) getInfo(leader *agent.Peer, minimumNodes, members int) agent.InfoResponse {
 	t.m.RLock()
 	defer t.m.RUnlock()
 
@@ -90,11 +121,13 @@ func (t *deployment) getInfo(leader *agent.Peer) agent.InfoResponse {
 	}
 
 	return agent.InfoResponse{
-		Mode:      m,
-		Deploying: t.runningDeploy,
-		Deployed:  t.lastSuccessfulDeploy,
-		Leader:    leader,
-		Quorum:    agent.QuorumPeers(t.c),
+		Mode:         m,
+		Deploying:    t.runningDeploy,
+		Deployed:     t.lastSuccessfulDeploy,
+		Leader:       leader,
+		Quorum:       agent.QuorumPeers(t.c),
+		MinimumNodes: int64(minimumNodes),
+		Members:      int64(members),
 	}
 }
 
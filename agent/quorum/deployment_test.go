@@ -0,0 +1,22 @@
+package quorum
+
+import (
+	"github.com/james-lawrence/bw/agent"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("deployment", func() {
+	It("should reject a deploy while one is in progress", func() {
+		p := agent.NewPeer("node")
+		d := newDeployment(nil)
+		begin := agent.NewDeployCommand(p, agent.DeployCommandBegin("foo", &agent.Archive{}, &agent.DeployOptions{}))
+		Expect(d.Decode(TranscoderContext{}, begin)).To(Succeed())
+
+		err := d.Decode(TranscoderContext{}, begin)
+		rejection, ok := agent.DeployRejectionFromError(err)
+		Expect(ok).To(BeTrue())
+		Expect(rejection.Reason).To(Equal(agent.DeployRejection_Busy))
+	})
+})
@@ -29,5 +29,5 @@ func (t DisabledMachine) Dispatch(_ context.Context, m ...*agent.Message) (err e
 }
 
 func (t DisabledMachine) Deploy(ctx context.Context, c cluster, dialer dialers.Defaults, by string, dopts *agent.DeployOptions, a *agent.Archive, peers ...*agent.Peer) (err error) {
-	return status.Error(codes.Unavailable, agent.ErrDisabledMachine.Error())
+	return agent.NewDeployRejection(agent.DeployRejection_NoQuorum, agent.ErrDisabledMachine.Error())
 }
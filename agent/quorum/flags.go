@@ -0,0 +1,113 @@
+package quorum
+
+import (
+	"io"
+	"sort"
+	"sync"
+
+	"github.com/james-lawrence/bw/agent"
+)
+
+// NewFlags tracks the cluster wide flags.
+func NewFlags() *Flags {
+	return &Flags{
+		values:   make(map[string]string),
+		watchers: make(map[*flagWatcher]struct{}),
+	}
+}
+
+// Flags cluster wide key/value store replicated by the quorum.
+// unset flags are retained with a blank value to ensure restoring
+// a snapshot overwrites the value.
+type Flags struct {
+	m        sync.RWMutex
+	values   map[string]string
+	watchers map[*flagWatcher]struct{}
+}
+
+type flagWatcher struct {
+	key    string
+	events chan *agent.Flag
+}
+
+// Decode updates the flag state.
+func (t *Flags) Decode(ctx TranscoderContext, m *agent.Message) error {
+	var (
+		flag *agent.Flag
+	)
+
+	if flag = m.GetFlag(); flag == nil {
+		return nil
+	}
+
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	t.values[flag.Key] = flag.Value
+
+	for w := range t.watchers {
+		if w.key != "" && w.key != flag.Key {
+			continue
+		}
+
+		select {
+		case w.events <- flag:
+		default:
+			// slow watchers miss updates rather than stalling the state machine.
+		}
+	}
+
+	return nil
+}
+
+// Encode the current flags.
+func (t *Flags) Encode(dst io.Writer) (err error) {
+	t.m.RLock()
+	defer t.m.RUnlock()
+
+	keys := make([]string, 0, len(t.values))
+	for k := range t.values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if err = encodeTo(dst, agent.FlagEvent(nil, k, t.values[k])); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Get the flag with the given key. an unset flag has a blank value.
+func (t *Flags) Get(key string) *agent.Flag {
+	t.m.RLock()
+	defer t.m.RUnlock()
+	return &agent.Flag{Key: key, Value: t.values[key]}
+}
+
+// Watch the flag with the given key, a blank key watches every flag.
+// the current value of the watched flags are delivered first. returns a function
+// to stop watching.
+func (t *Flags) Watch(key string) (events <-chan *agent.Flag, stop func()) {
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	w := &flagWatcher{key: key, events: make(chan *agent.Flag, len(t.values)+128)}
+	for k, v := range t.values {
+		if key != "" && key != k {
+			continue
+		}
+
+		w.events <- &agent.Flag{Key: k, Value: v}
+	}
+
+	t.watchers[w] = struct{}{}
+
+	return w.events, func() {
+		t.m.Lock()
+		defer t.m.Unlock()
+		delete(t.watchers, w)
+	}
+}
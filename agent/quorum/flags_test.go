@@ -0,0 +1,89 @@
+package quorum_test
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/hashicorp/raft"
+
+	"github.com/james-lawrence/bw/agent"
+	. "github.com/james-lawrence/bw/agent/quorum"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Flags", func() {
+	It("should replicate a flag to the followers", func() {
+		var (
+			servers    []raft.Server
+			transports []*raft.InmemTransport
+			protocols  []*raft.Raft
+			flags      []*Flags
+		)
+
+		for _, n := range []string{"server1", "server2", "server3"} {
+			f := NewFlags()
+			server, transport, protocol, err := newPeer(NewTranscoder(f), n, false)
+			Expect(err).To(Succeed())
+			servers = append(servers, server)
+			transports = append(transports, transport)
+			protocols = append(protocols, protocol)
+			flags = append(flags, f)
+		}
+
+		for i := range protocols {
+			connect(transports[i], transports...)
+			Expect(protocols[i].BootstrapCluster(raft.Configuration{Servers: servers}).Error()).To(Succeed())
+		}
+
+		leader := findFirstState(raft.Leader, protocols...)
+		sm := NewMachine(agent.NewPeer("node"), leader)
+		Expect(sm.Dispatch(context.Background(), agent.FlagEvent(agent.NewPeer("node"), "foo", "bar"))).To(Succeed())
+
+		for _, f := range flags {
+			Eventually(func() string { return f.Get("foo").Value }, 5*time.Second).Should(Equal("bar"))
+		}
+	})
+
+	It("should be readable after a restart", func() {
+		flags := NewFlags()
+		protocols, _, err := newCluster(NewTranscoder(flags), "server1")
+		Expect(err).To(Succeed())
+
+		leader := findFirstState(raft.Leader, protocols...)
+		sm := NewMachine(agent.NewPeer("node"), leader)
+		Expect(sm.Dispatch(
+			context.Background(),
+			agent.FlagEvent(agent.NewPeer("node"), "foo", "bar"),
+			agent.FlagEvent(agent.NewPeer("node"), "unset", "baz"),
+			agent.FlagEvent(agent.NewPeer("node"), "unset", ""),
+		)).To(Succeed())
+
+		snapshot := leader.Snapshot()
+		Expect(snapshot.Error()).To(Succeed())
+		_, ior, err := snapshot.Open()
+		Expect(err).To(Succeed())
+
+		restored := NewFlags()
+		wal := NewWAL(NewTranscoder(restored))
+		Expect(wal.Restore(io.NopCloser(ior))).To(Succeed())
+		Expect(restored.Get("foo").Value).To(Equal("bar"))
+		Expect(restored.Get("unset").Value).To(Equal(""))
+	})
+
+	It("should deliver the current value and updates to watchers", func() {
+		flags := NewFlags()
+		Expect(flags.Decode(TranscoderContext{}, agent.FlagEvent(nil, "foo", "bar"))).To(Succeed())
+
+		events, stop := flags.Watch("foo")
+		defer stop()
+
+		Expect(flags.Decode(TranscoderContext{}, agent.FlagEvent(nil, "other", "ignored"))).To(Succeed())
+		Expect(flags.Decode(TranscoderContext{}, agent.FlagEvent(nil, "foo", "baz"))).To(Succeed())
+
+		Expect((<-events).Value).To(Equal("bar"))
+		Expect((<-events).Value).To(Equal("baz"))
+		Consistently(events).ShouldNot(Receive())
+	})
+})
@@ -7,6 +7,7 @@ import (
 	"github.com/hashicorp/raft"
 	"github.com/james-lawrence/bw/agent"
 	"github.com/james-lawrence/bw/agent/dialers"
+	"github.com/james-lawrence/bw/internal/tracex"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -67,12 +68,16 @@ func (t *ProxyMachine) Deploy(ctx context.Context, c cluster, dialer dialers.Def
 		conn *grpc.ClientConn
 	)
 
+	if t.Leader() == nil {
+		return agent.NewDeployRejection(agent.DeployRejection_NoQuorum, "failed to locate leader")
+	}
+
 	if conn, err = t.DialLeader(t.dialer); err != nil {
 		return err
 	}
 	defer conn.Close()
 
-	if _, err = agent.NewQuorumClient(conn).Deploy(ctx, &agent.DeployCommandRequest{Initiator: by, Options: dopts, Archive: a, Peers: peers}); err != nil {
+	if _, err = agent.NewQuorumClient(conn).Deploy(tracex.Outgoing(ctx), &agent.DeployCommandRequest{Initiator: by, Options: dopts, Archive: a, Peers: peers}); err != nil {
 		return err
 	}
 
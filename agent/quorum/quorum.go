@@ -22,6 +22,7 @@ import (
 	"github.com/james-lawrence/bw/agent"
 	"github.com/james-lawrence/bw/agent/dialers"
 	"github.com/james-lawrence/bw/clustering/raftutil"
+	deployments "github.com/james-lawrence/bw/deployment"
 	"github.com/james-lawrence/bw/internal/errorsx"
 	"github.com/james-lawrence/bw/internal/logx"
 	"github.com/james-lawrence/bw/storage"
@@ -54,6 +55,45 @@ func OptionDialer(d dialers.Defaults) Option {
 	}
 }
 
+// OptionApplyBatch batch writes to the WAL up to n entries at a time when this
+// node is the leader. n <= 1 applies entries one at a time.
+func OptionApplyBatch(n int) Option {
+	return func(q *Quorum) {
+		q.applyBatch = n
+	}
+}
+
+// OptionMaxArchiveBytes rejects uploaded archives larger than n bytes.
+// n <= 0 accepts archives of any size.
+func OptionMaxArchiveBytes(n int64) Option {
+	return func(q *Quorum) {
+		q.maxArchiveBytes = n
+	}
+}
+
+// OptionMinHealthyForDeploy rejects deploys while fewer than n members of the cluster are healthy.
+// n <= 0 disables the check.
+func OptionMinHealthyForDeploy(n int) Option {
+	return func(q *Quorum) {
+		q.minHealthy = n
+	}
+}
+
+// OptionMinimumNodes nodes required to establish quorum, reported to clients by Info.
+func OptionMinimumNodes(n int) Option {
+	return func(q *Quorum) {
+		q.minimumNodes = n
+	}
+}
+
+// OptionMaxConcurrency cap the nodes simultaneously deployed to by a deploy regardless of the
+// concurrency requested by the client. n <= 0 is unbounded.
+func OptionMaxConcurrency(n int) Option {
+	return func(q *Quorum) {
+		q.limiter = deployments.NewLimiter(n)
+	}
+}
+
 // OptionStateMachineDispatch ...
 func OptionStateMachineDispatch(d stateMachine) Option {
 	return func(q *Quorum) {
@@ -66,6 +106,7 @@ func New(cd agent.ConnectableDispatcher, c cluster, codec transcoder, upload sto
 	deployment := newDeployment(c)
 	obs := NewObserver(cd)
 	history := NewHistory()
+	flags := NewFlags()
 	leadershipTransfer := NewLeadershipTransfer(c, rp)
 
 	wal := NewWAL(
@@ -74,6 +115,7 @@ func New(cd agent.ConnectableDispatcher, c cluster, codec transcoder, upload sto
 			codec,
 			obs,
 			history,
+			flags,
 			leadershipTransfer,
 		),
 	)
@@ -91,6 +133,7 @@ func New(cd agent.ConnectableDispatcher, c cluster, codec transcoder, upload sto
 		lost:                  make(chan struct{}),
 		disconnected:          make(chan struct{}),
 		history:               history,
+		flags:                 flags,
 		leadershipTransfer:    leadershipTransfer,
 	}
 
@@ -115,7 +158,13 @@ type Quorum struct {
 	disconnected       chan struct{}
 	rp                 raftutil.Protocol
 	history            History
+	flags              *Flags
 	leadershipTransfer *LeadershipTransfer
+	applyBatch         int
+	maxArchiveBytes    int64
+	minHealthy         int
+	minimumNodes       int
+	limiter            *deployments.Limiter
 }
 
 // Observe observes a raft cluster and updates the quorum state.
@@ -160,6 +209,9 @@ func (t *Quorum) Observe(events chan raft.Observation) {
 					sm := NewMachine(
 						t.c.Local(),
 						o.Raft,
+						MachineOptionApplyBatch(t.applyBatch, DefaultApplyFlush),
+						MachineOptionDeployContext(t.deployment.background),
+						MachineOptionLimiter(t.limiter),
 					)
 
 					// background this task so dispatches work.
@@ -184,9 +236,32 @@ func (t *Quorum) Observe(events chan raft.Observation) {
 	}
 }
 
+// State of the local raft node.
+func (t *Quorum) State() raft.RaftState {
+	return t.proxy().State()
+}
+
+// LeadershipTransfer transfer leadership away from the local node.
+// errors if the local node is not the leader.
+func (t *Quorum) LeadershipTransfer() raft.Future {
+	if sm, ok := t.proxy().(*StateMachine); ok {
+		return sm.state.LeadershipTransfer()
+	}
+
+	return errFuture{err: raft.ErrNotLeader}
+}
+
+type errFuture struct {
+	err error
+}
+
+func (t errFuture) Error() error {
+	return t.err
+}
+
 // Info return current info from the leader.
 func (t *Quorum) Info(ctx context.Context) (z agent.InfoResponse, err error) {
-	return t.deployment.getInfo(t.sm.Leader()), nil
+	return t.deployment.getInfo(t.sm.Leader(), t.minimumNodes, t.alive()), nil
 }
 
 // Cancel any active deploys
@@ -196,6 +271,10 @@ func (t *Quorum) Cancel(ctx context.Context, req *agent.CancelRequest) (err erro
 
 // Deploy ...
 func (t *Quorum) Deploy(ctx context.Context, by string, dopts *agent.DeployOptions, a *agent.Archive, peers ...*agent.Peer) (err error) {
+	if err = t.healthy(); err != nil {
+		return err
+	}
+
 	return t.proxy().Deploy(ctx, t.c, t.dialer, by, dopts, a, peers...)
 }
 
@@ -206,6 +285,7 @@ func (t *Quorum) Upload(stream agent.Quorum_UploadServer) (err error) {
 		location string
 		dst      agent.Uploader
 		chunk    *agent.UploadChunk
+		received uint64
 	)
 
 	if chunk, err = stream.Recv(); err != nil {
@@ -213,6 +293,12 @@ func (t *Quorum) Upload(stream agent.Quorum_UploadServer) (err error) {
 	}
 
 	metadata := chunk.GetMetadata()
+
+	// reject oversized archives before anything is written to disk.
+	if err = t.archiveLimit(metadata.Bytes); err != nil {
+		return err
+	}
+
 	if dst, err = t.uploads.NewUpload(metadata.Bytes); err != nil {
 		return err
 	}
@@ -244,6 +330,12 @@ func (t *Quorum) Upload(stream agent.Quorum_UploadServer) (err error) {
 			return err
 		}
 
+		// the declared size is provided by the client, ensure the archive doesn't exceed it.
+		received += uint64(len(chunk.Data))
+		if err = t.archiveLimit(received); err != nil {
+			return err
+		}
+
 		if _, err = dst.Upload(bytes.NewBuffer(chunk.Data)); err != nil {
 			log.Println("error uploading chunk", err)
 			return err
@@ -251,6 +343,39 @@ func (t *Quorum) Upload(stream agent.Quorum_UploadServer) (err error) {
 	}
 }
 
+func (t *Quorum) archiveLimit(n uint64) error {
+	if t.maxArchiveBytes <= 0 || n <= uint64(t.maxArchiveBytes) {
+		return nil
+	}
+
+	return status.Errorf(codes.ResourceExhausted, "archive exceeds the maximum size accepted by the agent: %d > %d bytes", n, t.maxArchiveBytes)
+}
+
+// healthy ensures enough members of the cluster are alive to deploy.
+func (t *Quorum) healthy() error {
+	if t.minHealthy <= 0 {
+		return nil
+	}
+
+	alive := t.alive()
+	if alive >= t.minHealthy {
+		return nil
+	}
+
+	return agent.NewDeployRejection(agent.DeployRejection_Degraded, fmt.Sprintf("too few healthy members to deploy: %d < %d", alive, t.minHealthy))
+}
+
+// alive members of the cluster.
+func (t *Quorum) alive() (n int) {
+	for _, m := range t.c.Members() {
+		if m.State == memberlist.StateAlive {
+			n++
+		}
+	}
+
+	return n
+}
+
 // Watch watch for events.
 func (t *Quorum) History(context.Context) (_ []*agent.Message, err error) {
 	if _, err = t.quorumOnly(); err != nil {
@@ -261,6 +386,52 @@ func (t *Quorum) History(context.Context) (_ []*agent.Message, err error) {
 
 }
 
+// SetFlag set the cluster wide flag, a blank value unsets the flag.
+func (t *Quorum) SetFlag(ctx context.Context, key, value string) (err error) {
+	if key == "" {
+		return status.Error(codes.InvalidArgument, "flag key is required")
+	}
+
+	return t.Dispatch(ctx, agent.FlagEvent(t.c.Local(), key, value))
+}
+
+// GetFlag read the cluster wide flag from the local replica.
+func (t *Quorum) GetFlag(ctx context.Context, key string) (_ *agent.Flag, err error) {
+	if _, err = t.quorumOnly(); err != nil {
+		return nil, errors.Wrap(err, "flag")
+	}
+
+	return t.flags.Get(key), nil
+}
+
+// WatchFlags watch the cluster wide flag with the given key, a blank key watches every flag.
+func (t *Quorum) WatchFlags(ctx context.Context, key string, out func(*agent.Flag) error) (err error) {
+	if _, err = t.quorumOnly(); err != nil {
+		return errors.Wrap(err, "watch flags")
+	}
+
+	events, stop := t.flags.Watch(key)
+	defer stop()
+
+	disconnected := t.disconnected
+	lost := t.lost
+
+	for {
+		select {
+		case <-ctx.Done():
+			return errors.WithStack(ctx.Err())
+		case <-disconnected:
+			return errors.New("disconnected")
+		case <-lost:
+			return errors.New("quorum membership lost")
+		case f := <-events:
+			if err = out(f); err != nil {
+				return errors.Wrap(err, "failed to deliver flag")
+			}
+		}
+	}
+}
+
 // Watch watch for events.
 func (t *Quorum) Watch(out agent.Quorum_WatchServer) (err error) {
 	var (
@@ -0,0 +1,180 @@
+package quorum_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"hash"
+	"io"
+	"net"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/hashicorp/memberlist"
+	"github.com/james-lawrence/bw/agent"
+	"github.com/james-lawrence/bw/agent/quorum"
+	"github.com/james-lawrence/bw/cluster"
+	"github.com/james-lawrence/bw/clustering"
+	"github.com/james-lawrence/bw/clustering/raftutil"
+	"github.com/james-lawrence/bw/storage"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type uploadStream struct {
+	grpc.ServerStream
+	chunks   []*agent.UploadChunk
+	received int
+	resp     *agent.UploadResponse
+}
+
+func (t *uploadStream) Recv() (*agent.UploadChunk, error) {
+	if t.received >= len(t.chunks) {
+		return nil, io.EOF
+	}
+
+	t.received++
+	return t.chunks[t.received-1], nil
+}
+
+func (t *uploadStream) SendAndClose(resp *agent.UploadResponse) error {
+	t.resp = resp
+	return nil
+}
+
+func newUploadStream(declared uint64, chunks ...[]byte) *uploadStream {
+	s := &uploadStream{
+		chunks: []*agent.UploadChunk{
+			{InitialChunkMetadata: &agent.UploadChunk_Metadata{Metadata: &agent.UploadMetadata{Bytes: declared}}},
+		},
+	}
+
+	for _, c := range chunks {
+		s.chunks = append(s.chunks, &agent.UploadChunk{Data: c})
+	}
+
+	return s
+}
+
+type memoryUploads struct {
+	started int
+	buf     bytes.Buffer
+}
+
+func (t *memoryUploads) NewUpload(uint64) (storage.Uploader, error) {
+	t.started++
+	return t, nil
+}
+
+func (t *memoryUploads) Upload(src io.Reader) (hash.Hash, error) {
+	_, err := io.Copy(&t.buf, src)
+	return sha256.New(), err
+}
+
+func (t *memoryUploads) Info() (hash.Hash, string, error) {
+	return sha256.New(), "memory", nil
+}
+
+func newQuorum(uploads storage.UploadProtocol, options ...quorum.Option) quorum.Quorum {
+	return quorum.New(
+		nil,
+		cluster.New(agent.NewPeer("node1"), clustering.NewSingleNode("node1", net.ParseIP("127.0.0.1"))),
+		quorum.NewTranscoder(),
+		uploads,
+		raftutil.Protocol{},
+		options...,
+	)
+}
+
+var _ = Describe("Deploy", func() {
+	It("should reject deploys when the node is not a member of the quorum", func() {
+		q := newQuorum(&memoryUploads{})
+		err := q.Deploy(context.Background(), "test user", &agent.DeployOptions{}, &agent.Archive{})
+		Expect(status.Code(err)).To(Equal(codes.Unavailable))
+		rejection, ok := agent.DeployRejectionFromError(err)
+		Expect(ok).To(BeTrue())
+		Expect(rejection.Reason).To(Equal(agent.DeployRejection_NoQuorum))
+	})
+
+	Context("minimum healthy members", func() {
+		members := func(states ...memberlist.NodeStateType) clustering.Mock {
+			local := &memberlist.Node{Name: "node0", Addr: net.ParseIP("127.0.0.1")}
+			peers := make([]*memberlist.Node, 0, len(states))
+			for idx, s := range states {
+				peers = append(peers, &memberlist.Node{Name: "node" + string(rune('1'+idx)), Addr: net.ParseIP("127.0.0.1"), State: s})
+			}
+			return clustering.NewMock(local, peers...)
+		}
+
+		deploy := func(c clustering.Mock, n int) agent.DeployRejection_Reason {
+			q := quorum.New(
+				nil,
+				cluster.New(agent.NewPeer("node0"), c),
+				quorum.NewTranscoder(),
+				&memoryUploads{},
+				raftutil.Protocol{},
+				quorum.OptionMinHealthyForDeploy(n),
+			)
+			rejection, ok := agent.DeployRejectionFromError(q.Deploy(context.Background(), "test user", &agent.DeployOptions{}, &agent.Archive{}))
+			Expect(ok).To(BeTrue())
+			return rejection.Reason
+		}
+
+		It("should reject deploys when too few members are healthy", func() {
+			c := members(memberlist.StateAlive, memberlist.StateSuspect, memberlist.StateDead)
+			Expect(deploy(c, 3)).To(Equal(agent.DeployRejection_Degraded))
+		})
+
+		It("should accept deploys when enough members are healthy", func() {
+			c := members(memberlist.StateAlive, memberlist.StateAlive, memberlist.StateSuspect)
+			// passes the health check, rejected by the disabled state machine.
+			Expect(deploy(c, 3)).To(Equal(agent.DeployRejection_NoQuorum))
+		})
+
+		It("should ignore the health of the members when disabled", func() {
+			c := members(memberlist.StateDead, memberlist.StateDead)
+			Expect(deploy(c, 0)).To(Equal(agent.DeployRejection_NoQuorum))
+		})
+	})
+})
+
+var _ = Describe("Upload", func() {
+
+	It("should accept an archive within the limit", func() {
+		uploads := &memoryUploads{}
+		q := newQuorum(uploads, quorum.OptionMaxArchiveBytes(8))
+		stream := newUploadStream(8, []byte("0123"), []byte("4567"))
+		Expect(q.Upload(stream)).To(Succeed())
+		Expect(stream.resp).ToNot(BeNil())
+		Expect(uploads.buf.String()).To(Equal("01234567"))
+	})
+
+	It("should reject an archive declared over the limit before writing it", func() {
+		uploads := &memoryUploads{}
+		q := newQuorum(uploads, quorum.OptionMaxArchiveBytes(8))
+		stream := newUploadStream(9, []byte("0123"), []byte("45678"))
+		err := q.Upload(stream)
+		Expect(status.Code(err)).To(Equal(codes.ResourceExhausted))
+		Expect(uploads.started).To(Equal(0))
+		Expect(stream.received).To(Equal(1))
+	})
+
+	It("should reject an archive exceeding the limit before it is fully received", func() {
+		uploads := &memoryUploads{}
+		q := newQuorum(uploads, quorum.OptionMaxArchiveBytes(8))
+		stream := newUploadStream(4, []byte("0123"), []byte("45678"), []byte("9"))
+		err := q.Upload(stream)
+		Expect(status.Code(err)).To(Equal(codes.ResourceExhausted))
+		Expect(uploads.buf.String()).To(Equal("0123"))
+		Expect(stream.received).To(Equal(3))
+	})
+
+	It("should accept any size when the limit is disabled", func() {
+		uploads := &memoryUploads{}
+		q := newQuorum(uploads, quorum.OptionMaxArchiveBytes(0))
+		stream := newUploadStream(16, []byte("0123456789abcdef"))
+		Expect(q.Upload(stream)).To(Succeed())
+	})
+})
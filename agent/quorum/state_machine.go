@@ -14,6 +14,7 @@ import (
 	deployments "github.com/james-lawrence/bw/deployment"
 	"github.com/james-lawrence/bw/internal/envx"
 	"github.com/james-lawrence/bw/internal/errorsx"
+	"github.com/james-lawrence/bw/internal/tracex"
 	"github.com/pkg/errors"
 	"google.golang.org/grpc"
 	"google.golang.org/protobuf/proto"
@@ -24,20 +25,68 @@ type Initializer interface {
 	Initialize(agent.Dispatcher) error
 }
 
+// MachineOption options for the state machine.
+type MachineOption func(*StateMachine)
+
+// MachineOptionInitializers set the initializers to run when the state machine is initialized.
+func MachineOptionInitializers(inits ...Initializer) MachineOption {
+	return func(sm *StateMachine) {
+		sm.inits = inits
+	}
+}
+
+// MachineOptionApplyBatch batch writes to the WAL up to n entries or until the flush
+// interval elapses. n <= 1 disables batching.
+func MachineOptionApplyBatch(n int, flush time.Duration) MachineOption {
+	return func(sm *StateMachine) {
+		if n <= 1 {
+			sm.applier = serialApplier{r: sm.state}
+			return
+		}
+
+		sm.applier = newBatchApplier(sm.state, n, flush)
+	}
+}
+
+// MachineOptionDeployContext derive the context of the deploys run by the state machine,
+// the deploy halts once the context is cancelled.
+func MachineOptionDeployContext(background func() context.Context) MachineOption {
+	return func(sm *StateMachine) {
+		sm.background = background
+	}
+}
+
+// MachineOptionLimiter cap the nodes simultaneously deployed to by the deploys run by the state machine.
+func MachineOptionLimiter(l *deployments.Limiter) MachineOption {
+	return func(sm *StateMachine) {
+		sm.limiter = l
+	}
+}
+
 // NewMachine ...
-func NewMachine(l *agent.Peer, rp *raft.Raft, inits ...Initializer) *StateMachine {
-	return &StateMachine{
-		l:     l,
-		state: rp,
-		inits: inits,
+func NewMachine(l *agent.Peer, rp *raft.Raft, options ...MachineOption) *StateMachine {
+	sm := &StateMachine{
+		l:          l,
+		state:      rp,
+		applier:    serialApplier{r: rp},
+		background: context.Background,
+	}
+
+	for _, opt := range options {
+		opt(sm)
 	}
+
+	return sm
 }
 
 // StateMachine wraps the raft protocol giving more convient access to the protocol.
 type StateMachine struct {
-	l     *agent.Peer
-	state *raft.Raft
-	inits []Initializer
+	l          *agent.Peer
+	state      *raft.Raft
+	inits      []Initializer
+	applier    applier
+	background func() context.Context
+	limiter    *deployments.Limiter
 }
 
 func (t *StateMachine) initialize() (err error) {
@@ -79,14 +128,27 @@ func (t *StateMachine) Deploy(ctx context.Context, c cluster, dialer dialers.Def
 		filter = deployments.Peers(peers...)
 	}
 
+	var checker deployments.OperationFunc = check(dialer)
+	if dopts.GetSimulate() {
+		checker = deployments.OperationSimulated(checker)
+	}
+
+	// the deploy outlives the request, inherit the trace of the request.
+	dctx := tracex.Inherit(t.background(), ctx)
 	options := []deployments.Option{
-		deployments.DeployOptionChecker(deployments.OperationFunc(check(dialer))),
+		deployments.DeployOptionContext(dctx),
+		deployments.DeployOptionLimiter(t.limiter),
+		deployments.DeployOptionChecker(checker),
 		deployments.DeployOptionDeployer(deployments.OperationFunc(deploy(dopts, archive, dialer))),
 		deployments.DeployOptionFilter(filter),
-		deployments.DeployOptionPartitioner(bw.ConstantPartitioner(dopts.Concurrency)),
+		deployments.DeployOptionPartitioner(agent.NewLabelPartitioner(bw.ConstantPartitioner(dopts.Concurrency), dopts.ConcurrencyByLabel)),
 		deployments.DeployOptionIgnoreFailures(dopts.IgnoreFailures),
 		deployments.DeployOptionTimeoutGrace(time.Duration(dopts.Timeout)),
 		deployments.DeployOptionHeartbeatFrequency(time.Duration(dopts.Heartbeat)),
+		deployments.DeployOptionMaxTotal(time.Duration(dopts.MaxTotal)),
+		deployments.DeployOptionTracing(dopts.Tracing),
+		deployments.DeployOptionRetryFailedOnce(dopts.RetryFailedOnce),
+		deployments.DeployOptionRetries(int(dopts.Retries), time.Duration(dopts.RetryBackoff)),
 		deployments.DeployOptionMonitor(deployments.NewMonitor(
 			deployments.MonitorTicklerEvent(c.Local(), qd),
 			deployments.MonitorTicklerPeriodicAuto(time.Minute),
@@ -98,6 +160,10 @@ func (t *StateMachine) Deploy(ctx context.Context, c cluster, dialer dialers.Def
 		dcmd := agent.DeployCommandFailed(by, archive.DeployOption, dopts.DeployOption)
 		if _, success := deployments.RunDeploy(c.Local(), c, d, options...); success {
 			dcmd = agent.DeployCommandDone(by, archive.DeployOption, dopts.DeployOption)
+		} else if dctx.Err() != nil {
+			// the cancellation was already recorded.
+			log.Println("deployment halted", by)
+			return
 		}
 
 		if envx.Boolean(false, bw.EnvLogsDeploy, bw.EnvLogsVerbose) {
@@ -153,36 +219,23 @@ func deploy(dopts *agent.DeployOptions, archive *agent.Archive, d dialers.Defaul
 
 // Dispatch a message to the WAL.
 func (t *StateMachine) Dispatch(ctx context.Context, messages ...*agent.Message) (err error) {
-	for _, m := range messages {
-		if err = t.writeWAL(m, 10*time.Second); err != nil {
-			return err
-		}
-	}
-
-	return nil
+	return t.writeWAL(10*time.Second, messages...)
 }
 
-func (t *StateMachine) writeWAL(m *agent.Message, d time.Duration) (err error) {
-	var (
-		encoded []byte
-		future  raft.ApplyFuture
-		ok      bool
-	)
-
-	if encoded, err = proto.Marshal(m); err != nil {
-		return errors.WithStack(err)
-	}
-
-	// write the event to the WAL.
-	future = t.state.Apply(encoded, 10*time.Second)
+func (t *StateMachine) writeWAL(d time.Duration, messages ...*agent.Message) (err error) {
+	encoded := make([][]byte, 0, len(messages))
+	for _, m := range messages {
+		var (
+			buf []byte
+		)
 
-	if err = future.Error(); err != nil {
-		return errors.WithStack(err)
-	}
+		if buf, err = proto.Marshal(m); err != nil {
+			return errors.WithStack(err)
+		}
 
-	if err, ok = future.Response().(error); ok {
-		return errors.WithStack(err)
+		encoded = append(encoded, buf)
 	}
 
-	return err
+	// write the events to the WAL.
+	return t.applier.apply(d, encoded...)
 }
@@ -3,9 +3,11 @@ package quorum_test
 import (
 	"context"
 	"errors"
+	"fmt"
 	"io"
 	"log"
 	"runtime"
+	"time"
 
 	"github.com/hashicorp/raft"
 	"google.golang.org/protobuf/proto"
@@ -263,4 +265,103 @@ var _ = Describe("StateMachine", func() {
 			Expect(proto.Equal(expected, m)).To(BeTrue())
 		}
 	})
+
+	Context("batching", func() {
+		messages := func(n int) (results []*agent.Message) {
+			for i := 0; i < n; i++ {
+				results = append(results, agent.LogEvent(local.Local(), fmt.Sprintf("message %d", i)))
+			}
+			return results
+		}
+
+		applied := func(msgs []*agent.Message, options ...MachineOption) []*agent.Message {
+			obs := make(chan *agent.Message, len(msgs))
+			protocols, _, err := newCluster(NewTranscoder(NewEvery(obs)), "server1")
+			Expect(err).ToNot(HaveOccurred())
+			leader := findFirstState(raft.Leader, protocols...)
+			defer leader.Shutdown()
+
+			sm := NewMachine(agent.NewPeer("node"), leader, options...)
+			Expect(sm.Dispatch(context.Background(), msgs[:5]...)).To(Succeed())
+			Expect(sm.Dispatch(context.Background(), msgs[5:]...)).To(Succeed())
+
+			results := make([]*agent.Message, 0, len(msgs))
+			for range msgs {
+				results = append(results, <-obs)
+			}
+			return results
+		}
+
+		It("should produce the same state as unbatched applies", func() {
+			msgs := messages(25)
+			unbatched := applied(msgs)
+			batched := applied(msgs, MachineOptionApplyBatch(4, time.Millisecond))
+			Expect(batched).To(HaveLen(len(unbatched)))
+			for i := range unbatched {
+				Expect(proto.Equal(batched[i], unbatched[i])).To(BeTrue())
+				Expect(proto.Equal(batched[i], msgs[i])).To(BeTrue())
+			}
+		})
+
+		It("should flush a partial batch after the flush interval", func() {
+			const flush = 100 * time.Millisecond
+			protocols, _, err := newCluster(NewTranscoder(), "server1")
+			Expect(err).ToNot(HaveOccurred())
+			leader := findFirstState(raft.Leader, protocols...)
+			defer leader.Shutdown()
+
+			sm := NewMachine(agent.NewPeer("node"), leader, MachineOptionApplyBatch(10, flush))
+			ts := time.Now()
+			Expect(sm.Dispatch(context.Background(), messages(1)...)).To(Succeed())
+			Expect(time.Since(ts)).To(BeNumerically(">=", flush))
+		})
+
+		It("should stop applying a call's messages once one fails", func() {
+			msgs := messages(5)
+			obs := make(chan *agent.Message, len(msgs)+1)
+			protocols, _, err := newCluster(NewTranscoder(failOn{log: "message 2"}, NewEvery(obs)), "server1")
+			Expect(err).ToNot(HaveOccurred())
+			leader := findFirstState(raft.Leader, protocols...)
+			defer leader.Shutdown()
+
+			sm := NewMachine(agent.NewPeer("node"), leader, MachineOptionApplyBatch(4, time.Millisecond))
+			Expect(sm.Dispatch(context.Background(), msgs...)).To(HaveOccurred())
+
+			// a subsequent dispatch is the next message applied.
+			next := agent.LogEvent(local.Local(), "next")
+			Expect(sm.Dispatch(context.Background(), next)).To(Succeed())
+
+			Expect(proto.Equal(<-obs, msgs[0])).To(BeTrue())
+			Expect(proto.Equal(<-obs, msgs[1])).To(BeTrue())
+			Expect(proto.Equal(<-obs, next)).To(BeTrue())
+		})
+
+		It("should apply a full batch without waiting for the flush interval", func() {
+			const flush = time.Hour
+			protocols, _, err := newCluster(NewTranscoder(), "server1")
+			Expect(err).ToNot(HaveOccurred())
+			leader := findFirstState(raft.Leader, protocols...)
+			defer leader.Shutdown()
+
+			sm := NewMachine(agent.NewPeer("node"), leader, MachineOptionApplyBatch(3, flush))
+			Expect(sm.Dispatch(context.Background(), messages(3)...)).To(Succeed())
+		})
+	})
 })
+
+// failOn fails to decode the log message with the matching content.
+type failOn struct {
+	log string
+}
+
+func (t failOn) Decode(ctx TranscoderContext, m *agent.Message) error {
+	if m.GetLog().GetLog() == t.log {
+		return errors.New("boom")
+	}
+
+	return nil
+}
+
+func (t failOn) Encode(dst io.Writer) error {
+	return nil
+}
@@ -0,0 +1,100 @@
+package agent
+
+import (
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// NewDeployRejection error for a rejected deploy, the reason is attached to the
+// status details allowing clients to react to the cause of the rejection.
+func NewDeployRejection(reason DeployRejection_Reason, description string) error {
+	return rejection{reason: reason, description: description}
+}
+
+type rejection struct {
+	reason      DeployRejection_Reason
+	description string
+}
+
+func (t rejection) Error() string {
+	return t.description
+}
+
+// GRPCStatus the status the rejection is transmitted as.
+func (t rejection) GRPCStatus() *status.Status {
+	s := status.New(t.reason.Code(), t.description)
+	if detailed, err := s.WithDetails(&DeployRejection{Reason: t.reason, Description: t.description}); err == nil {
+		return detailed
+	}
+
+	return s
+}
+
+// DeployRejectionFromError extracts the deploy rejection from the status details of the error.
+func DeployRejectionFromError(err error) (*DeployRejection, bool) {
+	s, ok := status.FromError(errors.Cause(err))
+	if !ok || s == nil {
+		return nil, false
+	}
+
+	for _, detail := range s.Details() {
+		if r, ok := detail.(*DeployRejection); ok {
+			return r, true
+		}
+	}
+
+	return nil, false
+}
+
+// Code grpc status code used for the rejection reason.
+func (t DeployRejection_Reason) Code() codes.Code {
+	switch t {
+	case DeployRejection_Draining, DeployRejection_NoQuorum, DeployRejection_Degraded:
+		return codes.Unavailable
+	case DeployRejection_Busy:
+		return codes.FailedPrecondition
+	case DeployRejection_Unauthorized:
+		return codes.PermissionDenied
+	default:
+		return codes.Unknown
+	}
+}
+
+// Retryable whether the deploy can be retried as is once the condition is resolved.
+func (t DeployRejection_Reason) Retryable() bool {
+	switch t {
+	case DeployRejection_Draining, DeployRejection_NoQuorum, DeployRejection_Busy, DeployRejection_Degraded:
+		return true
+	default:
+		return false
+	}
+}
+
+// Hint actionable description of the rejection reason.
+func (t DeployRejection_Reason) Hint() string {
+	switch t {
+	case DeployRejection_Draining:
+		return "the agent is shutting down, retry against another agent"
+	case DeployRejection_NoQuorum:
+		return "the quorum is unavailable, ensure the quorum members are healthy and retry"
+	case DeployRejection_Busy:
+		return "a deploy is already in progress, wait for it to complete or cancel it with bw deploy cancel"
+	case DeployRejection_Unauthorized:
+		return "the credentials are not permitted to deploy, ensure the client is registered with the notary"
+	case DeployRejection_Degraded:
+		return "too few members of the cluster are healthy, wait for the cluster to recover and retry"
+	default:
+		return "unknown rejection"
+	}
+}
+
+// ExplainDeployRejection annotates deploy rejections with an actionable hint, other errors are returned as is.
+func ExplainDeployRejection(err error) error {
+	r, ok := DeployRejectionFromError(err)
+	if !ok {
+		return err
+	}
+
+	return errors.Wrap(err, r.Reason.Hint())
+}
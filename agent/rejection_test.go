@@ -0,0 +1,92 @@
+package agent_test
+
+import (
+	"context"
+	"errors"
+	"net"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+
+	. "github.com/james-lawrence/bw/agent"
+)
+
+type denyauth struct{}
+
+func (t denyauth) Deploy(ctx context.Context) error {
+	return status.Error(codes.PermissionDenied, "invalid credentials")
+}
+
+// busyDeployer rejects every deploy.
+type busyDeployer struct {
+	fakeDeployer
+}
+
+func (t busyDeployer) Deploy(context.Context, string, *DeployOptions, *Archive) (*Deploy, error) {
+	return nil, NewDeployRejection(DeployRejection_Busy, "already deploying")
+}
+
+var _ = Describe("DeployRejection", func() {
+	// deploy through a grpc server to ensure the reason survives the transport.
+	deploy := func(options ...ServerOption) error {
+		socket, err := net.Listen("tcp", ":0")
+		Expect(err).To(Succeed())
+		defer socket.Close()
+
+		grpcs := grpc.NewServer()
+		defer grpcs.Stop()
+		RegisterAgentServer(grpcs, NewServer(nil, options...))
+		go grpcs.Serve(socket)
+
+		conn, err := grpc.Dial(socket.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+		Expect(err).To(Succeed())
+		defer conn.Close()
+
+		_, err = NewAgentClient(conn).Deploy(context.Background(), &DeployRequest{})
+		return err
+	}
+
+	reason := func(err error) DeployRejection_Reason {
+		r, ok := DeployRejectionFromError(err)
+		Expect(ok).To(BeTrue())
+		return r.Reason
+	}
+
+	It("should reject unauthorized deploys", func() {
+		err := deploy(ServerOptionAuth(denyauth{}))
+		Expect(status.Code(err)).To(Equal(codes.PermissionDenied))
+		Expect(reason(err)).To(Equal(DeployRejection_Unauthorized))
+	})
+
+	It("should reject deploys while draining", func() {
+		g := NewDrainGate()
+		g.Close()
+		err := deploy(ServerOptionAuth(testauth{}), ServerOptionDrain(g))
+		Expect(status.Code(err)).To(Equal(codes.Unavailable))
+		Expect(reason(err)).To(Equal(DeployRejection_Draining))
+	})
+
+	It("should reject deploys while busy", func() {
+		err := deploy(ServerOptionAuth(testauth{}), ServerOptionDeployer(busyDeployer{}))
+		Expect(status.Code(err)).To(Equal(codes.FailedPrecondition))
+		Expect(reason(err)).To(Equal(DeployRejection_Busy))
+		Expect(reason(err).Retryable()).To(BeTrue())
+	})
+
+	It("should not detect a rejection in other errors", func() {
+		_, ok := DeployRejectionFromError(status.Error(codes.Unavailable, "boom"))
+		Expect(ok).To(BeFalse())
+		_, ok = DeployRejectionFromError(errors.New("boom"))
+		Expect(ok).To(BeFalse())
+		Expect(ExplainDeployRejection(errors.New("boom"))).To(MatchError("boom"))
+	})
+
+	It("should explain rejections", func() {
+		err := NewDeployRejection(DeployRejection_NoQuorum, "failed to locate leader")
+		Expect(ExplainDeployRejection(err)).To(MatchError(DeployRejection_NoQuorum.Hint() + ": failed to locate leader"))
+	})
+})
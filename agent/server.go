@@ -1,18 +1,22 @@
 package agent
 
 import (
-	"bytes"
 	"context"
 	"fmt"
 	"io"
 	"log"
 	"strings"
+	"time"
 
 	"github.com/james-lawrence/bw/internal/bytesx"
 	"github.com/james-lawrence/bw/internal/errorsx"
 	"github.com/james-lawrence/bw/internal/iox"
+	"github.com/james-lawrence/bw/internal/tracex"
 	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type connector interface {
@@ -74,6 +78,15 @@ func ServerOptionShutdown(cf context.CancelFunc) ServerOption {
 	}
 }
 
+// ServerOptionDrain configure how the agent drains, the server's deployer is
+// used to wait for in flight deploys.
+func ServerOptionDrain(g DrainGate, options ...DrainOption) ServerOption {
+	return func(s *Server) {
+		s.gate = g
+		s.drain = options
+	}
+}
+
 // ServerOptionAuth ...
 func ServerOptionAuth(a auth) ServerOption {
 	return func(s *Server) {
@@ -81,6 +94,13 @@ func ServerOptionAuth(a auth) ServerOption {
 	}
 }
 
+// ServerOptionFingerprint fingerprint of the agent's configuration reported by Info.
+func ServerOptionFingerprint(fingerprint string) ServerOption {
+	return func(s *Server) {
+		s.fingerprint = fingerprint
+	}
+}
+
 // NewServer ...
 func NewServer(c connector, options ...ServerOption) Server {
 	s := Server{
@@ -90,6 +110,7 @@ func NewServer(c connector, options ...ServerOption) Server {
 		}),
 		connector: c,
 		Deployer:  noopDeployer{},
+		gate:      NewDrainGate(),
 	}
 
 	for _, opt := range options {
@@ -103,9 +124,12 @@ func NewServer(c connector, options ...ServerOption) Server {
 type Server struct {
 	UnimplementedAgentServer
 	auth
-	shutdown  context.CancelFunc
-	Deployer  deployer
-	connector connector
+	shutdown    context.CancelFunc
+	Deployer    deployer
+	connector   connector
+	gate        DrainGate
+	drain       []DrainOption
+	fingerprint string
 }
 
 // Bind to a grpc server.
@@ -128,6 +152,28 @@ func (t Server) Shutdown(ctx context.Context, req *ShutdownRequest) (*ShutdownRe
 	return &ShutdownResponse{}, nil
 }
 
+// Drain the agent and then shutdown.
+func (t Server) Drain(ctx context.Context, req *DrainRequest) (*DrainResponse, error) {
+	if err := t.auth.Deploy(ctx); err != nil {
+		return nil, err
+	}
+
+	options := append([]DrainOption{DrainOptionGate(t.gate), DrainOptionDeployer(t.Deployer)}, t.drain...)
+	if err := Drain(ctx, time.Duration(req.Timeout), options...); err != nil {
+		switch {
+		case errors.Is(err, context.DeadlineExceeded):
+			return nil, status.Error(codes.DeadlineExceeded, err.Error())
+		case errors.Is(err, context.Canceled):
+			return nil, status.Error(codes.Canceled, err.Error())
+		default:
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+	}
+
+	t.shutdown()
+	return &DrainResponse{}, nil
+}
+
 // Deploy ...
 func (t Server) Deploy(ctx context.Context, dreq *DeployRequest) (*DeployResponse, error) {
 	var (
@@ -136,10 +182,18 @@ func (t Server) Deploy(ctx context.Context, dreq *DeployRequest) (*DeployRespons
 	)
 
 	if err := t.auth.Deploy(ctx); err != nil {
-		return nil, err
+		return nil, NewDeployRejection(DeployRejection_Unauthorized, status.Convert(err).Message())
+	}
+
+	if t.gate.Draining() {
+		return nil, NewDeployRejection(DeployRejection_Draining, "agent is draining")
 	}
 
-	if d, err = t.Deployer.Deploy(context.Background(), dreq.Initiator, dreq.Options, dreq.Archive); err != nil {
+	sctx, span := tracex.Start(ctx, "agent.deploy", trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	// the deploy outlives the request, but remains part of its trace.
+	if d, err = t.Deployer.Deploy(tracex.Inherit(context.Background(), sctx), dreq.Initiator, dreq.Options, dreq.Archive); err != nil {
 		return nil, err
 	}
 
@@ -176,9 +230,41 @@ func (t Server) Info(ctx context.Context, _ *StatusRequest) (_ *StatusResponse,
 	return &StatusResponse{
 		Peer:        tmp,
 		Deployments: d,
+		Fingerprint: t.fingerprint,
 	}, nil
 }
 
+// Deployed returns the commit ref of the latest successful deploy of the agent.
+func (t Server) Deployed(ctx context.Context, _ *DeployedRequest) (_ *DeployedResponse, err error) {
+	var (
+		d []*Deploy
+	)
+
+	if err := t.auth.Deploy(ctx); err != nil {
+		return nil, err
+	}
+
+	if d, err = t.Deployer.Deployments(); err != nil {
+		return nil, status.Error(codes.Internal, errors.Wrap(err, "failed to read deployments").Error())
+	}
+
+	resp := &DeployedResponse{Peer: t.connector.Local()}
+
+	// deployments are ordered from most to least recent.
+	for _, c := range d {
+		if c.Stage != Deploy_Completed || c.Archive == nil {
+			continue
+		}
+
+		resp.Commit = c.Archive.Commit
+		resp.DeploymentID = c.Archive.DeploymentID
+		resp.Ts = c.Archive.Dts
+		break
+	}
+
+	return resp, nil
+}
+
 // Connect ...
 func (t Server) Connect(ctx context.Context, _ *ConnectRequest) (_zeror *ConnectResponse, err error) {
 	if err := t.auth.Deploy(ctx); err != nil {
@@ -196,15 +282,21 @@ func (t Server) Logs(req *LogRequest, out Agent_LogsServer) (err error) {
 	}
 
 	const KB16 = 16 * bytesx.KiB
-	logs := t.Deployer.Logs(req.DeploymentID)
+	var logs io.ReadCloser
+	if req.Follow {
+		logs = followLogs(out.Context(), t.Deployer, req.DeploymentID)
+	} else {
+		logs = t.Deployer.Logs(req.DeploymentID)
+	}
 
-	buf := bytes.NewBuffer(make([]byte, 0, KB16))
+	// content is sent as soon as it is read, followed logs are written incrementally.
+	buf := make([]byte, KB16)
 	for err == nil {
-		err = errorsx.Compact(
-			iox.Error(io.CopyN(buf, logs, KB16)),
-			out.Send(&LogResponse{Content: buf.Bytes()}),
-		)
-		buf.Reset()
+		var n int
+		n, err = logs.Read(buf)
+		if n > 0 {
+			err = errorsx.Compact(out.Send(&LogResponse{Content: buf[:n]}), err)
+		}
 	}
 
 	return errorsx.Compact(iox.IgnoreEOF(err), logs.Close())
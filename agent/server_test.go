@@ -35,6 +35,10 @@ func (t harness) Cleanup() {
 }
 
 func testClient() harness {
+	return testClientWithOptions()
+}
+
+func testClientWithOptions(options ...ServerOption) harness {
 	socket, err := net.Listen("tcp", ":0")
 	Expect(err).ToNot(HaveOccurred())
 	peers := clusteringtestutil.NewNodes(5)
@@ -43,7 +47,7 @@ func testClient() harness {
 		NewPeer(fake.CharactersN(10)),
 		clustering.NewMock(peers[0], peers[1:]...),
 	)
-	s := NewServer(c, ServerOptionAuth(testauth{}))
+	s := NewServer(c, append([]ServerOption{ServerOptionAuth(testauth{})}, options...)...)
 
 	grpcs := grpc.NewServer()
 	RegisterAgentServer(grpcs, s)
@@ -57,6 +61,18 @@ func testClient() harness {
 	return harness{client: conn, cluster: c, listener: socket}
 }
 
+// deployedDeployer reports the given deployments, ordered from most to least recent.
+type deployedDeployer []*Deploy
+
+func (t deployedDeployer) Deploy(context.Context, string, *DeployOptions, *Archive) (*Deploy, error) {
+	return &Deploy{}, nil
+}
+
+func (t deployedDeployer) Cancel()                         {}
+func (t deployedDeployer) Reset() error                    { return nil }
+func (t deployedDeployer) Deployments() ([]*Deploy, error) { return t, nil }
+func (t deployedDeployer) Logs([]byte) io.ReadCloser       { return nil }
+
 var _ = Describe("Server", func() {
 	Context("Connect", func() {
 		It("should return cluster details", func() {
@@ -82,6 +98,42 @@ var _ = Describe("Server", func() {
 			tmp.Status = Peer_Node
 			Expect(proto.Equal(info.Peer, tmp)).To(BeTrue())
 		})
+
+		It("should return the fingerprint of the agent's configuration", func() {
+			h := testClientWithOptions(ServerOptionFingerprint("fingerprint"))
+			defer h.Cleanup()
+
+			info, err := h.client.Info(context.Background())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(info.Fingerprint).To(Equal("fingerprint"))
+		})
+	})
+
+	Context("Deployed", func() {
+		It("should return a blank commit when the agent has never deployed", func() {
+			h := testClient()
+			defer h.Cleanup()
+
+			deployed, err := h.client.Deployed(context.Background())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(deployed.Commit).To(BeEmpty())
+			Expect(deployed.Peer.Name).To(Equal(h.cluster.Local().Name))
+		})
+
+		It("should return the commit of the latest successful deploy", func() {
+			h := testClientWithOptions(ServerOptionDeployer(deployedDeployer{
+				{Stage: Deploy_Failed, Archive: &Archive{Commit: "c3", Dts: 3}},
+				{Stage: Deploy_Completed, Archive: &Archive{Commit: "c2", DeploymentID: []byte("d2"), Dts: 2}},
+				{Stage: Deploy_Completed, Archive: &Archive{Commit: "c1", Dts: 1}},
+			}))
+			defer h.Cleanup()
+
+			deployed, err := h.client.Deployed(context.Background())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(deployed.Commit).To(Equal("c2"))
+			Expect(deployed.DeploymentID).To(Equal([]byte("d2")))
+			Expect(deployed.Ts).To(Equal(int64(2)))
+		})
 	})
 
 	Context("Deploy", func() {
@@ -0,0 +1,114 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/james-lawrence/bw"
+)
+
+// Status summary of the agent written to the status file, for monitoring tools unable
+// to use the rpc api.
+type Status struct {
+	Name        string        `json:"name"`
+	Peers       int           `json:"peers"`            // number of agents within the cluster.
+	Leader      string        `json:"leader,omitempty"` // blank when the leader is unknown.
+	Quorum      []string      `json:"quorum"`
+	LastDeploy  *StatusDeploy `json:"lastDeploy,omitempty"`
+	Fingerprint string        `json:"fingerprint"` // fingerprint of the agent's configuration.
+	Updated     time.Time     `json:"updated"`
+}
+
+// StatusDeploy the latest deploy of the agent.
+type StatusDeploy struct {
+	ID        string    `json:"id"`
+	Commit    string    `json:"commit,omitempty"`
+	Stage     string    `json:"stage"`
+	Initiator string    `json:"initiator,omitempty"`
+	Error     string    `json:"error,omitempty"`
+	Deployed  time.Time `json:"deployed"`
+}
+
+// NewStatusDeploy summarizes the deploy, nil when the deploy has no archive.
+func NewStatusDeploy(d *Deploy) *StatusDeploy {
+	if d == nil || d.Archive == nil {
+		return nil
+	}
+
+	return &StatusDeploy{
+		ID:        bw.RandomID(d.Archive.DeploymentID).String(),
+		Commit:    d.Archive.Commit,
+		Stage:     d.Stage.String(),
+		Initiator: d.Initiator,
+		Error:     d.Error,
+		Deployed:  time.Unix(d.Archive.Dts, 0).UTC(),
+	}
+}
+
+// WriteStatusFile atomically replaces the status file, readers never observe a partial status.
+func WriteStatusFile(path string, s Status) (err error) {
+	var (
+		encoded []byte
+		tmp     *os.File
+	)
+
+	if encoded, err = json.MarshalIndent(s, "", "  "); err != nil {
+		return errors.Wrap(err, "failed to encode status")
+	}
+
+	if tmp, err = os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*"); err != nil {
+		return errors.Wrap(err, "failed to create status file")
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	if err = os.Chmod(tmp.Name(), 0644); err != nil {
+		return errors.Wrap(err, "failed to set status file permissions")
+	}
+
+	if _, err = tmp.Write(encoded); err != nil {
+		return errors.Wrap(err, "failed to write status file")
+	}
+
+	if err = tmp.Close(); err != nil {
+		return errors.Wrap(err, "failed to write status file")
+	}
+
+	return errors.Wrapf(os.Rename(tmp.Name(), path), "failed to replace status file: %s", path)
+}
+
+// StatusFile writes the status of the agent to the path immediately and then at the given
+// frequency until the context is done, a frequency <= 0 only writes the initial status. blocking.
+func StatusFile(ctx context.Context, path string, freq time.Duration, status func(context.Context) Status) {
+	write := func() {
+		s := status(ctx)
+		s.Updated = time.Now().UTC()
+		if err := WriteStatusFile(path, s); err != nil {
+			log.Println("failed to write status file", err)
+		}
+	}
+
+	write()
+
+	if freq <= 0 {
+		return
+	}
+
+	tick := time.NewTicker(freq)
+	defer tick.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-tick.C:
+			write()
+		}
+	}
+}
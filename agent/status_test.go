@@ -0,0 +1,120 @@
+package agent_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/james-lawrence/bw/agent"
+	"github.com/james-lawrence/bw/internal/testingx"
+)
+
+var _ = Describe("StatusFile", func() {
+	read := func(path string) (s Status) {
+		encoded, err := os.ReadFile(path)
+		Expect(err).To(Succeed())
+		Expect(json.Unmarshal(encoded, &s)).To(Succeed())
+		return s
+	}
+
+	It("should write the status on the cadence", func() {
+		var (
+			written int64
+		)
+
+		path := filepath.Join(testingx.TempDir(), "status.json")
+		ctx, done := context.WithCancel(context.Background())
+		defer done()
+
+		go StatusFile(ctx, path, 10*time.Millisecond, func(context.Context) Status {
+			atomic.AddInt64(&written, 1)
+			return Status{
+				Name:        "node1",
+				Peers:       3,
+				Leader:      "node2",
+				Quorum:      []string{"node1", "node2", "node3"},
+				Fingerprint: "fingerprint",
+				LastDeploy: NewStatusDeploy(&Deploy{
+					Stage:   Deploy_Completed,
+					Archive: &Archive{DeploymentID: []byte("deploy"), Commit: "abc123", Dts: 1},
+				}),
+			}
+		})
+
+		Eventually(func() bool { _, err := os.Stat(path); return err == nil }).Should(BeTrue())
+
+		initial := read(path)
+		Expect(initial.Name).To(Equal("node1"))
+		Expect(initial.Peers).To(Equal(3))
+		Expect(initial.Leader).To(Equal("node2"))
+		Expect(initial.Quorum).To(ConsistOf("node1", "node2", "node3"))
+		Expect(initial.Fingerprint).To(Equal("fingerprint"))
+		Expect(initial.LastDeploy).ToNot(BeNil())
+		Expect(initial.LastDeploy.Commit).To(Equal("abc123"))
+		Expect(initial.LastDeploy.Stage).To(Equal(Deploy_Completed.String()))
+		Expect(initial.Updated).ToNot(BeZero())
+
+		Eventually(func() time.Time { return read(path).Updated }).Should(BeTemporally(">", initial.Updated))
+		Expect(atomic.LoadInt64(&written)).To(BeNumerically(">", 1))
+	})
+
+	It("should not leave temporary files behind", func() {
+		dir := testingx.TempDir()
+		path := filepath.Join(dir, "status.json")
+		Expect(WriteStatusFile(path, Status{Name: "node1"})).To(Succeed())
+		Expect(WriteStatusFile(path, Status{Name: "node2"})).To(Succeed())
+		Expect(read(path).Name).To(Equal("node2"))
+
+		entries, err := os.ReadDir(dir)
+		Expect(err).To(Succeed())
+		Expect(entries).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("Config.Fingerprint", func() {
+	fingerprint := func(c Config) string {
+		f, err := c.Fingerprint()
+		Expect(err).To(Succeed())
+		return f
+	}
+
+	It("should ignore the identity of the agent", func() {
+		a := NewConfig()
+		b := a
+		b.Name = "other"
+		Expect(fingerprint(a)).To(Equal(fingerprint(b)))
+	})
+
+	It("should ignore the settings that differ between the agents of a cluster", func() {
+		a := NewConfig()
+		b := a
+		b.Zone = "us-east-1a"
+		b.Labels = []string{"big"}
+		b.Bootstrap.NonVoter = true
+		Expect(fingerprint(a)).To(Equal(fingerprint(b)))
+	})
+
+	It("should change with the configuration", func() {
+		a := NewConfig()
+		b := a
+		b.KeepN = a.KeepN + 1
+		Expect(fingerprint(a)).ToNot(Equal(fingerprint(b)))
+	})
+
+	It("should change with the cluster tokens", func() {
+		a := NewConfig()
+		b := a
+		b.ClusterTokens = []string{"token"}
+		Expect(fingerprint(a)).ToNot(Equal(fingerprint(b)))
+
+		c := a
+		c.PrimaryClusterToken = "token"
+		Expect(fingerprint(a)).ToNot(Equal(fingerprint(c)))
+	})
+})
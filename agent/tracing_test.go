@@ -0,0 +1,88 @@
+package agent_test
+
+import (
+	"context"
+	"net"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	. "github.com/james-lawrence/bw/agent"
+	"github.com/james-lawrence/bw/internal/tracex"
+)
+
+// tracingDeployer captures the context of the deploy.
+type tracingDeployer struct {
+	fakeDeployer
+	deploys chan context.Context
+}
+
+func (t tracingDeployer) Deploy(ctx context.Context, _ string, _ *DeployOptions, _ *Archive) (*Deploy, error) {
+	t.deploys <- ctx
+	return &Deploy{}, nil
+}
+
+var _ = Describe("Deploy tracing", func() {
+	var (
+		exporter *tracetest.InMemoryExporter
+	)
+
+	BeforeEach(func() {
+		exporter = tracetest.NewInMemoryExporter()
+		DeferCleanup(tracex.Register(exporter))
+	})
+
+	// deploy through a grpc server to ensure the span survives the transport.
+	deploy := func(ctx context.Context) context.Context {
+		d := tracingDeployer{deploys: make(chan context.Context, 1)}
+
+		socket, err := net.Listen("tcp", ":0")
+		Expect(err).To(Succeed())
+		defer socket.Close()
+
+		grpcs := grpc.NewServer()
+		defer grpcs.Stop()
+		RegisterAgentServer(grpcs, NewServer(nil, ServerOptionAuth(testauth{}), ServerOptionDeployer(d)))
+		go grpcs.Serve(socket)
+
+		conn, err := grpc.Dial(socket.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+		Expect(err).To(Succeed())
+		defer conn.Close()
+
+		_, err = NewAgentClient(conn).Deploy(tracex.Outgoing(ctx), &DeployRequest{})
+		Expect(err).To(Succeed())
+
+		return <-d.deploys
+	}
+
+	It("should continue the trace of the client within the deploy", func() {
+		ctx, span := tracex.StartRoot(context.Background(), "bw.deploy", true)
+		defer span.End()
+
+		dctx := deploy(ctx)
+
+		spans := exporter.GetSpans()
+		Expect(spans).To(HaveLen(1))
+		Expect(spans[0].Name).To(Equal("agent.deploy"))
+		Expect(spans[0].SpanKind).To(Equal(trace.SpanKindServer))
+		Expect(spans[0].Parent.SpanID()).To(Equal(span.SpanContext().SpanID()))
+
+		// the deploy outlives the request but remains a child of the agent's span.
+		Expect(dctx.Err()).To(Succeed())
+		Expect(trace.SpanContextFromContext(dctx).TraceID()).To(Equal(span.SpanContext().TraceID()))
+		Expect(trace.SpanContextFromContext(dctx).SpanID()).To(Equal(spans[0].SpanContext.SpanID()))
+	})
+
+	It("should not trace deploys without tracing enabled", func() {
+		ctx, span := tracex.StartRoot(context.Background(), "bw.deploy", false)
+		defer span.End()
+
+		dctx := deploy(ctx)
+		Expect(exporter.GetSpans()).To(BeEmpty())
+		Expect(trace.SpanContextFromContext(dctx).IsValid()).To(BeFalse())
+	})
+})
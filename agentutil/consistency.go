@@ -0,0 +1,90 @@
+package agentutil
+
+import (
+	"context"
+	"log"
+	"sort"
+
+	"github.com/james-lawrence/bw/agent"
+	"github.com/james-lawrence/bw/agent/dialers"
+)
+
+// ConfigFingerprint the configuration fingerprint reported by an agent.
+type ConfigFingerprint struct {
+	Peer        *agent.Peer
+	Fingerprint string
+}
+
+// ConfigReport the consistency of the configuration across the cluster.
+type ConfigReport struct {
+	Fingerprint string              // fingerprint shared by the majority of the agents.
+	Consistent  []ConfigFingerprint // agents running the majority configuration.
+	Divergent   []ConfigFingerprint // agents running a configuration different from the majority.
+	Unknown     []ConfigFingerprint // agents that didn't report a fingerprint, e.g.) older versions.
+}
+
+// Diverged returns true when any agent is running a configuration different from the majority.
+func (t ConfigReport) Diverged() bool {
+	return len(t.Divergent) > 0
+}
+
+// NewConfigReport compares the fingerprints of the agents against the fingerprint shared by the
+// majority of the agents, ties are broken by the lexical order of the fingerprints.
+func NewConfigReport(fingerprints ...ConfigFingerprint) (r ConfigReport) {
+	counts := map[string]int{}
+	for _, f := range fingerprints {
+		if f.Fingerprint == "" {
+			continue
+		}
+
+		counts[f.Fingerprint]++
+	}
+
+	candidates := make([]string, 0, len(counts))
+	for fingerprint := range counts {
+		candidates = append(candidates, fingerprint)
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if counts[candidates[i]] == counts[candidates[j]] {
+			return candidates[i] < candidates[j]
+		}
+
+		return counts[candidates[i]] > counts[candidates[j]]
+	})
+
+	if len(candidates) > 0 {
+		r.Fingerprint = candidates[0]
+	}
+
+	for _, f := range fingerprints {
+		switch f.Fingerprint {
+		case "":
+			r.Unknown = append(r.Unknown, f)
+		case r.Fingerprint:
+			r.Consistent = append(r.Consistent, f)
+		default:
+			r.Divergent = append(r.Divergent, f)
+		}
+	}
+
+	return r
+}
+
+// ConfigFingerprints gathers the configuration fingerprint of every agent, unreachable agents are
+// logged and reported with a blank fingerprint.
+func ConfigFingerprints(ctx context.Context, c peers, d dialers.Defaults) (results []ConfigFingerprint, err error) {
+	err = NewClusterOperation(ctx, Operation(func(ctx context.Context, p *agent.Peer, c agent.Client) error {
+		info, cause := c.Info(ctx)
+		if cause != nil {
+			log.Println("unable to retrieve the configuration fingerprint", p.Name, p.Ip, cause)
+			results = append(results, ConfigFingerprint{Peer: p})
+			return nil
+		}
+
+		results = append(results, ConfigFingerprint{Peer: p, Fingerprint: info.Fingerprint})
+		return nil
+	}))(c, d)
+
+	return results, err
+}
@@ -0,0 +1,63 @@
+package agentutil_test
+
+import (
+	"github.com/james-lawrence/bw/agent"
+	. "github.com/james-lawrence/bw/agentutil"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("NewConfigReport", func() {
+	var (
+		a1 = agent.NewPeer("a1")
+		a2 = agent.NewPeer("a2")
+		a3 = agent.NewPeer("a3")
+		a4 = agent.NewPeer("a4")
+	)
+
+	It("should flag the member with a different fingerprint as divergent", func() {
+		report := NewConfigReport(
+			ConfigFingerprint{Peer: a1, Fingerprint: "f1"},
+			ConfigFingerprint{Peer: a2, Fingerprint: "f2"},
+			ConfigFingerprint{Peer: a3, Fingerprint: "f1"},
+		)
+
+		Expect(report.Diverged()).To(BeTrue())
+		Expect(report.Fingerprint).To(Equal("f1"))
+		Expect(report.Consistent).To(HaveLen(2))
+		Expect(report.Divergent).To(Equal([]ConfigFingerprint{{Peer: a2, Fingerprint: "f2"}}))
+	})
+
+	It("should report a consistent cluster", func() {
+		report := NewConfigReport(
+			ConfigFingerprint{Peer: a1, Fingerprint: "f1"},
+			ConfigFingerprint{Peer: a2, Fingerprint: "f1"},
+		)
+
+		Expect(report.Diverged()).To(BeFalse())
+		Expect(report.Consistent).To(HaveLen(2))
+	})
+
+	It("should not consider members without a fingerprint as divergent", func() {
+		report := NewConfigReport(
+			ConfigFingerprint{Peer: a1, Fingerprint: "f1"},
+			ConfigFingerprint{Peer: a2},
+		)
+
+		Expect(report.Diverged()).To(BeFalse())
+		Expect(report.Unknown).To(Equal([]ConfigFingerprint{{Peer: a2}}))
+	})
+
+	It("should break ties by the order of the fingerprints", func() {
+		report := NewConfigReport(
+			ConfigFingerprint{Peer: a1, Fingerprint: "f2"},
+			ConfigFingerprint{Peer: a2, Fingerprint: "f1"},
+			ConfigFingerprint{Peer: a3, Fingerprint: "f2"},
+			ConfigFingerprint{Peer: a4, Fingerprint: "f1"},
+		)
+
+		Expect(report.Fingerprint).To(Equal("f1"))
+		Expect(report.Divergent).To(HaveLen(2))
+	})
+})
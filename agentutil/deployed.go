@@ -0,0 +1,135 @@
+package agentutil
+
+import (
+	"context"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/james-lawrence/bw/agent"
+	"github.com/james-lawrence/bw/agent/dialers"
+)
+
+// DeployedRef the commit ref deployed to an agent.
+type DeployedRef struct {
+	Peer         *agent.Peer
+	Commit       string    // blank when the agent has never deployed or was unreachable.
+	DeploymentID []byte    // sha256 of the deployed archive, blank when the agent has never deployed or was unreachable.
+	Deployed     time.Time // time the commit was deployed.
+}
+
+// RefReport the convergence of the deployed commit across the cluster.
+type RefReport struct {
+	Expected  string        // commit the agents are expected to be running.
+	InSync    []DeployedRef // agents running the expected commit.
+	OutOfSync []DeployedRef // agents running a different commit.
+	Unknown   []DeployedRef // agents without a deployed commit, e.g.) unreachable.
+}
+
+// Converged returns true when every agent is running the expected commit.
+func (t RefReport) Converged() bool {
+	return len(t.OutOfSync) == 0 && len(t.Unknown) == 0
+}
+
+// NewRefReport compares the deployed commits against the expected commit. the expected commit
+// can be abbreviated, when blank the commit deployed to the majority of the agents is expected,
+// ties are broken by the lexical order of the commits.
+func NewRefReport(expected string, refs ...DeployedRef) (r RefReport) {
+	if expected == "" {
+		expected = majorityRef(refs...)
+	}
+
+	r.Expected = expected
+
+	for _, ref := range refs {
+		switch {
+		case ref.Commit == "":
+			r.Unknown = append(r.Unknown, ref)
+		case expected != "" && strings.HasPrefix(ref.Commit, expected):
+			r.InSync = append(r.InSync, ref)
+		default:
+			r.OutOfSync = append(r.OutOfSync, ref)
+		}
+	}
+
+	return r
+}
+
+func majorityRef(refs ...DeployedRef) string {
+	counts := map[string]int{}
+	for _, ref := range refs {
+		if ref.Commit == "" {
+			continue
+		}
+
+		counts[ref.Commit]++
+	}
+
+	candidates := make([]string, 0, len(counts))
+	for commit := range counts {
+		candidates = append(candidates, commit)
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if counts[candidates[i]] == counts[candidates[j]] {
+			return candidates[i] < candidates[j]
+		}
+
+		return counts[candidates[i]] > counts[candidates[j]]
+	})
+
+	if len(candidates) == 0 {
+		return ""
+	}
+
+	return candidates[0]
+}
+
+// DeployedRefs gathers the deployed commit of every agent concurrently, unreachable agents are
+// logged and reported with a blank commit.
+func DeployedRefs(ctx context.Context, c peers, d dialers.Defaults) (results []DeployedRef, err error) {
+	var (
+		wg sync.WaitGroup
+	)
+
+	peers := c.Peers()
+	results = make([]DeployedRef, len(peers))
+	for idx, p := range peers {
+		wg.Add(1)
+		go func(idx int, p *agent.Peer) {
+			defer wg.Done()
+			results[idx] = deployedRef(ctx, d, p)
+		}(idx, p)
+	}
+
+	wg.Wait()
+
+	return results, nil
+}
+
+func deployedRef(ctx context.Context, d dialers.Defaults, p *agent.Peer) (ref DeployedRef) {
+	ref = DeployedRef{Peer: p}
+
+	err := dialAndVisit(ctx, d, p, Operation(func(ctx context.Context, p *agent.Peer, c agent.Client) error {
+		deployed, err := c.Deployed(ctx)
+		if err != nil {
+			return err
+		}
+
+		ref.Commit, ref.DeploymentID = deployed.Commit, deployed.DeploymentID
+		if deployed.Ts > 0 {
+			ref.Deployed = time.Unix(deployed.Ts, 0).UTC()
+		}
+
+		return nil
+	}))
+
+	if err != nil {
+		log.Println("unable to retrieve the deployed commit", p.Name, p.Ip, err)
+		return DeployedRef{Peer: p}
+	}
+
+	return ref
+}
@@ -0,0 +1,74 @@
+package agentutil_test
+
+import (
+	"context"
+	"time"
+
+	"github.com/james-lawrence/bw/agent"
+	"github.com/james-lawrence/bw/agent/dialers"
+	. "github.com/james-lawrence/bw/agentutil"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("NewRefReport", func() {
+	var (
+		a1 = agent.NewPeer("a1")
+		a2 = agent.NewPeer("a2")
+		a3 = agent.NewPeer("a3")
+		a4 = agent.NewPeer("a4")
+		ts = time.Unix(1, 0).UTC()
+	)
+
+	fleet := func() []DeployedRef {
+		return []DeployedRef{
+			{Peer: a1, Commit: "c2d8f0e", Deployed: ts},
+			{Peer: a2, Commit: "c2d8f0e", Deployed: ts},
+			{Peer: a3, Commit: "a0b1c2d", Deployed: ts},
+			{Peer: a4, Commit: "c2d8f0e", Deployed: ts},
+		}
+	}
+
+	It("should highlight the node running an old commit as out of sync", func() {
+		report := NewRefReport("c2d8f0e", fleet()...)
+
+		Expect(report.Converged()).To(BeFalse())
+		Expect(report.InSync).To(HaveLen(3))
+		Expect(report.OutOfSync).To(Equal([]DeployedRef{{Peer: a3, Commit: "a0b1c2d", Deployed: ts}}))
+	})
+
+	It("should default to the commit deployed to the majority of the nodes", func() {
+		report := NewRefReport("", fleet()...)
+
+		Expect(report.Expected).To(Equal("c2d8f0e"))
+		Expect(report.OutOfSync).To(HaveLen(1))
+		Expect(report.OutOfSync[0].Peer).To(Equal(a3))
+	})
+
+	It("should match abbreviated commits", func() {
+		report := NewRefReport("c2d", fleet()[:2]...)
+
+		Expect(report.Converged()).To(BeTrue())
+		Expect(report.InSync).To(HaveLen(2))
+	})
+
+	It("should not consider nodes without a deployed commit converged", func() {
+		report := NewRefReport("c2d8f0e", DeployedRef{Peer: a1, Commit: "c2d8f0e"}, DeployedRef{Peer: a2})
+
+		Expect(report.Converged()).To(BeFalse())
+		Expect(report.OutOfSync).To(BeEmpty())
+		Expect(report.Unknown).To(Equal([]DeployedRef{{Peer: a2}}))
+	})
+})
+
+var _ = Describe("DeployedRefs", func() {
+	It("should report every agent with a blank commit when they are unreachable", func() {
+		a1, a2 := agent.NewPeer("a1"), agent.NewPeer("a2")
+
+		// dialing without transport credentials always fails.
+		refs, err := DeployedRefs(context.Background(), PeerSet{a1, a2}, dialers.NewDefaults())
+		Expect(err).To(Succeed())
+		Expect(refs).To(Equal([]DeployedRef{{Peer: a1}, {Peer: a2}}))
+	})
+})
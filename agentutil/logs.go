@@ -1,12 +1,16 @@
 package agentutil
 
 import (
+	"bufio"
 	"context"
+	"fmt"
 	"io"
+	"sync"
 	"time"
 
 	"github.com/james-lawrence/bw/agent"
 	"github.com/james-lawrence/bw/agent/dialers"
+	"github.com/james-lawrence/bw/internal/bytesx"
 	"github.com/james-lawrence/bw/internal/iox"
 )
 
@@ -28,3 +32,55 @@ func DeploymentLogs(c cluster, d dialers.Defaults, deploymentID []byte) io.ReadC
 	}()
 	return r
 }
+
+type follower interface {
+	Follow(context.Context, *agent.Peer, []byte) io.ReadCloser
+}
+
+// FollowLogs streams the logs of the deployment from each of the peers as they are written,
+// interleaving the lines prefixed by the name of the peer. a peer disconnecting is reported
+// without interrupting the remaining streams. blocks until every stream completes.
+func FollowLogs(ctx context.Context, c follower, deploymentID []byte, dst io.Writer, peers ...*agent.Peer) {
+	var (
+		wg sync.WaitGroup
+	)
+
+	out := &lockedWriter{dst: dst}
+	for _, p := range peers {
+		wg.Add(1)
+		go func(p *agent.Peer) {
+			defer wg.Done()
+			logs := c.Follow(ctx, p, deploymentID)
+			defer logs.Close()
+
+			prefix := fmt.Sprintf("[%s] ", p.Name)
+			if err := followLines(out, prefix, logs); err != nil && ctx.Err() == nil {
+				out.WriteLine(prefix + "log stream disconnected: " + err.Error())
+			}
+		}(p)
+	}
+
+	wg.Wait()
+}
+
+func followLines(out *lockedWriter, prefix string, logs io.Reader) error {
+	scanner := bufio.NewScanner(logs)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), bytesx.MiB)
+	for scanner.Scan() {
+		out.WriteLine(prefix + scanner.Text())
+	}
+
+	return scanner.Err()
+}
+
+// lockedWriter writes whole lines to the destination from multiple goroutines.
+type lockedWriter struct {
+	m   sync.Mutex
+	dst io.Writer
+}
+
+func (t *lockedWriter) WriteLine(s string) {
+	t.m.Lock()
+	defer t.m.Unlock()
+	_, _ = io.WriteString(t.dst, s+"\n")
+}
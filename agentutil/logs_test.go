@@ -0,0 +1,77 @@
+package agentutil_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/james-lawrence/bw/agent"
+	. "github.com/james-lawrence/bw/agentutil"
+	"github.com/james-lawrence/bw/internal/iox"
+)
+
+// fakeFollower emits the configured log of each peer, peers with an error disconnect
+// after emitting their log.
+type fakeFollower struct {
+	logs   map[string]string
+	errors map[string]error
+}
+
+func (t fakeFollower) Follow(ctx context.Context, p *agent.Peer, did []byte) io.ReadCloser {
+	src := io.Reader(strings.NewReader(t.logs[p.Name]))
+	if err, ok := t.errors[p.Name]; ok {
+		src = io.MultiReader(src, iox.ErrReader(err))
+	}
+
+	return io.NopCloser(src)
+}
+
+var _ = Describe("FollowLogs", func() {
+	lines := func(buf *bytes.Buffer) []string {
+		return strings.Split(strings.TrimSpace(buf.String()), "\n")
+	}
+
+	It("should prefix the lines of each peer", func() {
+		buf := bytes.NewBuffer(nil)
+		c := fakeFollower{
+			logs: map[string]string{
+				"node1": "starting\ncompleted\n",
+				"node2": "starting\ncompleted",
+			},
+		}
+
+		FollowLogs(context.Background(), c, []byte("deploy"), buf, agent.NewPeer("node1"), agent.NewPeer("node2"))
+		Expect(lines(buf)).To(ConsistOf(
+			"[node1] starting",
+			"[node1] completed",
+			"[node2] starting",
+			"[node2] completed",
+		))
+	})
+
+	It("should report a peer disconnecting without interrupting the other peers", func() {
+		buf := bytes.NewBuffer(nil)
+		c := fakeFollower{
+			logs: map[string]string{
+				"node1": "starting\n",
+				"node2": "starting\ncompleted\n",
+			},
+			errors: map[string]error{
+				"node1": errors.New("connection reset"),
+			},
+		}
+
+		FollowLogs(context.Background(), c, []byte("deploy"), buf, agent.NewPeer("node1"), agent.NewPeer("node2"))
+		Expect(lines(buf)).To(ConsistOf(
+			"[node1] starting",
+			"[node1] log stream disconnected: connection reset",
+			"[node2] starting",
+			"[node2] completed",
+		))
+	})
+})
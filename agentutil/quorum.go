@@ -51,3 +51,17 @@ func EnsureLeader(ctx context.Context, d dialers.DefaultsDialer, proxy *agent.Pe
 		return info, err
 	}
 }
+
+// RequireQuorum ensures the cluster has elected a leader and the minimum nodes
+// required by the agents to establish quorum are alive.
+func RequireQuorum(info *agent.InfoResponse) error {
+	if info.Leader == nil {
+		return errors.New("cluster lacks quorum: no leader has been elected")
+	}
+
+	if info.Members < info.MinimumNodes {
+		return errors.Errorf("cluster lacks quorum: %d alive members, the agents require %d", info.Members, info.MinimumNodes)
+	}
+
+	return nil
+}
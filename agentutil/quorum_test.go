@@ -0,0 +1,29 @@
+package agentutil_test
+
+import (
+	"github.com/james-lawrence/bw/agent"
+	. "github.com/james-lawrence/bw/agentutil"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RequireQuorum", func() {
+	leader := &agent.Peer{Name: "leader", Ip: "127.0.0.1"}
+
+	DescribeTable("should refuse clusters lacking quorum",
+		func(info *agent.InfoResponse, expected string) {
+			err := RequireQuorum(info)
+			if expected == "" {
+				Expect(err).To(Succeed())
+				return
+			}
+
+			Expect(err).To(MatchError(ContainSubstring(expected)))
+		},
+		Entry("leader with the minimum nodes", &agent.InfoResponse{Leader: leader, MinimumNodes: 3, Members: 3}, ""),
+		Entry("leader with additional nodes", &agent.InfoResponse{Leader: leader, MinimumNodes: 3, Members: 5}, ""),
+		Entry("no leader", &agent.InfoResponse{MinimumNodes: 3, Members: 3}, "no leader has been elected"),
+		Entry("too few alive members", &agent.InfoResponse{Leader: leader, MinimumNodes: 3, Members: 2}, "2 alive members, the agents require 3"),
+	)
+})
@@ -0,0 +1,92 @@
+package agentutil
+
+import (
+	"context"
+	"log"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	"github.com/james-lawrence/bw"
+	"github.com/james-lawrence/bw/agent"
+	"github.com/james-lawrence/bw/agent/dialers"
+	"github.com/james-lawrence/bw/internal/errorsx"
+)
+
+// ErrNoRollback when the agents have no record of a deploy to rollback to.
+const ErrNoRollback = errorsx.String("no prior deployment to rollback to")
+
+// RollbackDeployment selects the deploy to rollback to from the deploys recorded by an agent.
+// a blank ref selects the successful deploy prior to the latest successful deploy, otherwise
+// the successful deploy whose commit or deployment id matches the ref is selected.
+func RollbackDeployment(ref string, deploys ...*agent.Deploy) (d *agent.Deploy, err error) {
+	completed := make([]*agent.Deploy, 0, len(deploys))
+	for _, d := range deploys {
+		if d.Stage == agent.Deploy_Completed && d.Archive != nil {
+			completed = append(completed, d)
+		}
+	}
+
+	// newest first.
+	sort.SliceStable(completed, func(i, j int) bool {
+		return completed[i].Archive.Dts > completed[j].Archive.Dts
+	})
+
+	if ref == "" {
+		if len(completed) < 2 {
+			return nil, errors.WithStack(ErrNoRollback)
+		}
+
+		return completed[1], nil
+	}
+
+	for _, d := range completed {
+		if d.Archive.Commit == ref || bw.RandomID(d.Archive.DeploymentID).String() == ref {
+			return d, nil
+		}
+	}
+
+	return nil, errors.Wrapf(ErrNoRollback, "ref %s is not retained by the agent", ref)
+}
+
+// LocateRollback returns the deploy to rollback to from the first agent with a record of it.
+// see RollbackDeployment.
+func LocateRollback(c cluster, d dialers.Defaults, ref string) (rollback *agent.Deploy, err error) {
+	const done = errorsx.String("done")
+
+	locate := func(ctx context.Context, p *agent.Peer, c agent.Client) (err error) {
+		var (
+			ds []*agent.Deploy
+		)
+
+		if ds, err = AgentDeployments(c); err != nil {
+			switch cause := errors.Cause(err); cause {
+			case ErrNoDeployments:
+				return nil
+			default:
+				log.Println(errors.Wrap(cause, "failed retrieving deployments, checking next agent"))
+				return nil
+			}
+		}
+
+		if rollback, err = RollbackDeployment(ref, ds...); err != nil {
+			return nil
+		}
+
+		return done
+	}
+
+	if err = NewClusterOperation(context.Background(), Operation(locate))(c, d); errors.Cause(err) == done {
+		return rollback, nil
+	}
+
+	if err == nil {
+		if ref == "" {
+			return nil, errors.WithStack(ErrNoRollback)
+		}
+
+		return nil, errors.Wrapf(ErrNoRollback, "ref %s is not retained by any agent", ref)
+	}
+
+	return nil, err
+}
@@ -0,0 +1,61 @@
+package agentutil_test
+
+import (
+	"github.com/james-lawrence/bw"
+	"github.com/james-lawrence/bw/agent"
+	. "github.com/james-lawrence/bw/agentutil"
+
+	. "github.com/onsi/ginkgo/v2"
+
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RollbackDeployment", func() {
+	deploy := func(commit string, dts int64, stage agent.Deploy_Stage) *agent.Deploy {
+		return &agent.Deploy{
+			Stage: stage,
+			Archive: &agent.Archive{
+				DeploymentID: bw.MustGenerateID(),
+				Commit:       commit,
+				Dts:          dts,
+			},
+		}
+	}
+
+	var (
+		latest   = deploy("c3", 3, agent.Deploy_Completed)
+		failed   = deploy("c2", 2, agent.Deploy_Failed)
+		previous = deploy("c1", 1, agent.Deploy_Completed)
+		oldest   = deploy("c0", 0, agent.Deploy_Completed)
+	)
+
+	It("should rollback to the deploy prior to the latest successful deploy", func() {
+		d, err := RollbackDeployment("", oldest, latest, failed, previous)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(d).To(Equal(previous))
+	})
+
+	It("should rollback to the retained deploy matching the commit", func() {
+		d, err := RollbackDeployment("c0", latest, failed, previous, oldest)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(d).To(Equal(oldest))
+	})
+
+	It("should rollback to the retained deploy matching the deployment id", func() {
+		d, err := RollbackDeployment(bw.RandomID(previous.Archive.DeploymentID).String(), latest, failed, previous, oldest)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(d).To(Equal(previous))
+	})
+
+	It("should error when there is no prior deploy", func() {
+		_, err := RollbackDeployment("", latest, failed)
+		Expect(err).To(MatchError(ErrNoRollback))
+		_, err = RollbackDeployment("")
+		Expect(err).To(MatchError(ErrNoRollback))
+	})
+
+	It("should error when the ref is not retained", func() {
+		_, err := RollbackDeployment("c2", latest, failed, previous)
+		Expect(err).To(MatchError(ErrNoRollback))
+	})
+})
@@ -3,6 +3,7 @@ package agentutil
 import (
 	"context"
 	"log"
+	"time"
 
 	"github.com/james-lawrence/bw/agent"
 	"github.com/james-lawrence/bw/agent/dialers"
@@ -27,3 +28,13 @@ func Shutdown(ctx context.Context, c peers, d dialers.Defaults) error {
 		}, codes.Unavailable)
 	}))(c, d)
 }
+
+// Drain drains the specified agents, removing them from the cluster.
+func Drain(ctx context.Context, c peers, d dialers.Defaults, timeout time.Duration) error {
+	return NewClusterOperation(ctx, Operation(func(ctx context.Context, p *agent.Peer, c agent.Client) error {
+		log.Println("drain initiated", p.Ip)
+		defer log.Println("drain completed", p.Ip)
+
+		return c.Drain(ctx, timeout)
+	}))(c, d)
+}
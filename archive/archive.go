@@ -6,12 +6,39 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/pkg/errors"
 )
 
 // Pack ...
 func Pack(dst io.Writer, paths ...string) (err error) {
+	return pack(dst, func(*tar.Header) {}, paths...)
+}
+
+// PackReproducible packs the paths like Pack, normalizing the timestamps, ownership, and
+// permissions of the entries so identical trees produce byte identical archives. entries are
+// already written in lexical order by the walk.
+func PackReproducible(dst io.Writer, paths ...string) (err error) {
+	return pack(dst, normalize, paths...)
+}
+
+// normalize the header removing the details that vary between identical trees.
+func normalize(h *tar.Header) {
+	mode := int64(0644)
+	if h.Typeflag == tar.TypeDir || h.Mode&0111 != 0 {
+		mode = 0755
+	}
+
+	h.Mode = mode
+	h.ModTime = time.Unix(0, 0).UTC()
+	h.AccessTime = time.Time{}
+	h.ChangeTime = time.Time{}
+	h.Uid, h.Gid = 0, 0
+	h.Uname, h.Gname = "", ""
+}
+
+func pack(dst io.Writer, normalize func(*tar.Header), paths ...string) (err error) {
 	var (
 		gw *gzip.Writer
 		tw *tar.Writer
@@ -33,7 +60,7 @@ func Pack(dst io.Writer, paths ...string) (err error) {
 				return nil
 			}
 
-			return write(basepath, path, tw, info)
+			return write(basepath, path, tw, info, normalize)
 		}
 
 		if err = filepath.Walk(basepath, walker); err != nil {
@@ -110,7 +137,7 @@ func Unpack(root string, r io.Reader) (err error) {
 	}
 }
 
-func write(basepath, path string, tw *tar.Writer, info os.FileInfo) (err error) {
+func write(basepath, path string, tw *tar.Writer, info os.FileInfo, normalize func(*tar.Header)) (err error) {
 	var (
 		src    *os.File
 		header *tar.Header
@@ -130,6 +157,7 @@ func write(basepath, path string, tw *tar.Writer, info os.FileInfo) (err error)
 		return errors.Wrap(err, "failed to created header")
 	}
 	header.Name = target
+	normalize(header)
 
 	if err = tw.WriteHeader(header); err != nil {
 		return errors.Wrapf(err, "failed to write header to tar archive: %s", path)
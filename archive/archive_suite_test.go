@@ -0,0 +1,16 @@
+package archive_test
+
+import (
+	"io"
+	"log"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestArchive(t *testing.T) {
+	log.SetOutput(io.Discard)
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Archive Suite")
+}
@@ -0,0 +1,86 @@
+package archive_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/james-lawrence/bw/archive"
+	"github.com/james-lawrence/bw/internal/testingx"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PackReproducible", func() {
+	tree := func(root string) {
+		Expect(os.MkdirAll(filepath.Join(root, "nested"), 0700)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(root, "a.txt"), []byte("hello"), 0600)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(root, "nested", "b.sh"), []byte("echo world"), 0700)).To(Succeed())
+	}
+
+	touch := func(root string, ts time.Time) {
+		Expect(filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+
+			return os.Chtimes(path, ts, ts)
+		})).To(Succeed())
+	}
+
+	It("should produce identical archives for an unchanged tree", func() {
+		var (
+			first  bytes.Buffer
+			second bytes.Buffer
+		)
+
+		root := testingx.TempDir()
+		tree(root)
+
+		touch(root, time.Now().Add(-time.Hour))
+		Expect(archive.PackReproducible(&first, root)).To(Succeed())
+
+		touch(root, time.Now())
+		Expect(archive.PackReproducible(&second, root)).To(Succeed())
+
+		Expect(first.Bytes()).To(Equal(second.Bytes()))
+	})
+
+	It("should produce identical archives for identical trees", func() {
+		var (
+			first  bytes.Buffer
+			second bytes.Buffer
+		)
+
+		root1, root2 := testingx.TempDir(), testingx.TempDir()
+		tree(root1)
+		tree(root2)
+		Expect(os.Chmod(filepath.Join(root2, "a.txt"), 0640)).To(Succeed())
+
+		Expect(archive.PackReproducible(&first, root1)).To(Succeed())
+		Expect(archive.PackReproducible(&second, root2)).To(Succeed())
+
+		Expect(first.Bytes()).To(Equal(second.Bytes()))
+	})
+
+	It("should unpack the archive", func() {
+		var (
+			buf bytes.Buffer
+		)
+
+		root, dst := testingx.TempDir(), testingx.TempDir()
+		tree(root)
+
+		Expect(archive.PackReproducible(&buf, root)).To(Succeed())
+		Expect(archive.Unpack(dst, &buf)).To(Succeed())
+
+		content, err := os.ReadFile(filepath.Join(dst, "nested", "b.sh"))
+		Expect(err).To(Succeed())
+		Expect(content).To(Equal([]byte("echo world")))
+
+		info, err := os.Stat(filepath.Join(dst, "nested", "b.sh"))
+		Expect(err).To(Succeed())
+		Expect(info.Mode().Perm()).To(Equal(os.FileMode(0755)))
+	})
+})
@@ -0,0 +1,104 @@
+package archive
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// ChecksumChunkSize size of the chunks read ahead of the checksum.
+const ChecksumChunkSize = 4 * 1024 * 1024
+
+// Verify the sha256 checksum of the archive matches the expected checksum.
+// see Checksum.
+func Verify(src io.ReaderAt, size int64, expected []byte, workers int) error {
+	actual, err := Checksum(src, size, workers)
+	if err != nil {
+		return err
+	}
+
+	if !bytes.Equal(actual, expected) {
+		return errors.Errorf("archive checksum mismatch: archive(%s), expected(%s)", hex.EncodeToString(actual), hex.EncodeToString(expected))
+	}
+
+	return nil
+}
+
+// Checksum computes the sha256 checksum of the archive. the checksum must match the digest of
+// the archive recorded at upload so it is computed serially by a single digest, when workers > 1
+// up to workers chunks of the archive are read ahead concurrently, overlapping the reads of slow
+// storage with hashing. the checksum is identical regardless of the workers.
+func Checksum(src io.ReaderAt, size int64, workers int) ([]byte, error) {
+	if workers <= 1 {
+		return checksumSerial(src, size)
+	}
+
+	return checksumReadAhead(src, size, workers)
+}
+
+func checksumSerial(src io.ReaderAt, size int64) ([]byte, error) {
+	digest := sha256.New()
+	if _, err := io.Copy(digest, io.NewSectionReader(src, 0, size)); err != nil {
+		return nil, errors.Wrap(err, "unable to read archive")
+	}
+
+	return digest.Sum(nil), nil
+}
+
+func checksumReadAhead(src io.ReaderAt, size int64, workers int) ([]byte, error) {
+	type chunk struct {
+		buf []byte
+		err error
+	}
+
+	var (
+		digest = sha256.New()
+		done   = make(chan struct{})
+		// chunks in the order they are hashed, the capacity bounds the chunks being read.
+		pending = make(chan chan chunk, workers-1)
+	)
+	defer close(done)
+
+	go func() {
+		defer close(pending)
+
+		for offset := int64(0); offset < size; offset += ChecksumChunkSize {
+			result := make(chan chunk, 1)
+
+			select {
+			case pending <- result:
+			case <-done:
+				return
+			}
+
+			go func(offset int64) {
+				n := size - offset
+				if n > ChecksumChunkSize {
+					n = ChecksumChunkSize
+				}
+
+				buf := make([]byte, n)
+				_, err := src.ReadAt(buf, offset)
+				if err == io.EOF {
+					err = nil
+				}
+
+				result <- chunk{buf: buf, err: err}
+			}(offset)
+		}
+	}()
+
+	for result := range pending {
+		c := <-result
+		if c.err != nil {
+			return nil, errors.Wrap(c.err, "unable to read archive")
+		}
+
+		digest.Write(c.buf)
+	}
+
+	return digest.Sum(nil), nil
+}
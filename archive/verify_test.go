@@ -0,0 +1,130 @@
+package archive_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/james-lawrence/bw/archive"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// concurrentReader records the maximum number of concurrent reads.
+type concurrentReader struct {
+	io.ReaderAt
+	m       sync.Mutex
+	active  int
+	maximum int
+}
+
+func (t *concurrentReader) ReadAt(b []byte, off int64) (int, error) {
+	t.m.Lock()
+	t.active++
+	if t.active > t.maximum {
+		t.maximum = t.active
+	}
+	t.m.Unlock()
+
+	// give the other workers a chance to overlap.
+	time.Sleep(5 * time.Millisecond)
+
+	defer func() {
+		t.m.Lock()
+		t.active--
+		t.m.Unlock()
+	}()
+
+	return t.ReaderAt.ReadAt(b, off)
+}
+
+func random(n int) []byte {
+	buf := make([]byte, n)
+	_, err := rand.Read(buf)
+	Expect(err).ToNot(HaveOccurred())
+	return buf
+}
+
+var _ = Describe("Checksum", func() {
+	DescribeTable("read ahead digest matches the serial digest",
+		func(size int, workers int) {
+			data := random(size)
+			expected := sha256.Sum256(data)
+
+			serial, err := archive.Checksum(bytes.NewReader(data), int64(size), 1)
+			Expect(err).ToNot(HaveOccurred())
+			readahead, err := archive.Checksum(bytes.NewReader(data), int64(size), workers)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(serial).To(Equal(expected[:]))
+			Expect(readahead).To(Equal(serial))
+		},
+		Entry("empty archive", 0, 4),
+		Entry("smaller than a chunk", 1024, 4),
+		Entry("exactly one chunk", archive.ChecksumChunkSize, 4),
+		Entry("partial trailing chunk", 3*archive.ChecksumChunkSize+17, 4),
+		Entry("more workers than chunks", 2*archive.ChecksumChunkSize+1, 16),
+	)
+
+	It("should read chunks concurrently when workers > 1", func() {
+		data := random(8 * archive.ChecksumChunkSize)
+		src := &concurrentReader{ReaderAt: bytes.NewReader(data)}
+
+		_, err := archive.Checksum(src, int64(len(data)), 4)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(src.maximum).To(BeNumerically(">", 1))
+		Expect(src.maximum).To(BeNumerically("<=", 4))
+	})
+
+	It("should read serially when workers <= 1", func() {
+		data := random(4 * archive.ChecksumChunkSize)
+		src := &concurrentReader{ReaderAt: bytes.NewReader(data)}
+
+		_, err := archive.Checksum(src, int64(len(data)), 1)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(src.maximum).To(Equal(1))
+	})
+
+	It("should report a checksum mismatch", func() {
+		data := random(archive.ChecksumChunkSize + 1)
+		expected := sha256.Sum256(data[1:])
+
+		err := archive.Verify(bytes.NewReader(data), int64(len(data)), expected[:], 4)
+		Expect(err).To(MatchError(ContainSubstring("archive checksum mismatch")))
+	})
+
+	It("should verify a matching checksum", func() {
+		data := random(archive.ChecksumChunkSize + 1)
+		expected := sha256.Sum256(data)
+
+		Expect(archive.Verify(bytes.NewReader(data), int64(len(data)), expected[:], 4)).To(Succeed())
+	})
+})
+
+func benchmarkChecksum(b *testing.B, workers int) {
+	data := make([]byte, 64*archive.ChecksumChunkSize)
+	if _, err := rand.Read(data); err != nil {
+		b.Fatal(err)
+	}
+
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := archive.Checksum(bytes.NewReader(data), int64(len(data)), workers); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkChecksumSerial(b *testing.B) {
+	benchmarkChecksum(b, 1)
+}
+
+func BenchmarkChecksumReadAhead(b *testing.B) {
+	benchmarkChecksum(b, 4)
+}
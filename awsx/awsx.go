@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/ec2metadata"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/autoscaling"
@@ -19,10 +20,6 @@ func AutoscalingPeers(ctx context.Context, supplimental ...string) (peers []ec2.
 	var (
 		sess  *session.Session
 		ident ec2metadata.EC2InstanceIdentityDocument
-		asgs  *autoscaling.AutoScaling
-		iao   *autoscaling.DescribeAutoScalingInstancesOutput
-		asg   *autoscaling.DescribeAutoScalingGroupsOutput
-		ec2io *ec2.DescribeInstancesOutput
 	)
 
 	if sess, err = session.NewSession(); err != nil {
@@ -42,6 +39,47 @@ func AutoscalingPeers(ctx context.Context, supplimental ...string) (peers []ec2.
 		return peers, errors.WithStack(err)
 	}
 
+	return autoscalingPeers(sess, ident, supplimental...)
+}
+
+// AutoscalingPeersIMDSv2 same as AutoscalingPeers but exclusively uses the token based (v2)
+// instance metadata service protocol for discovering the instance and its credentials.
+// if the metadata service is unreachable an empty set is returned.
+func AutoscalingPeersIMDSv2(ctx context.Context, md IMDS, supplimental ...string) (peers []ec2.Instance, err error) {
+	var (
+		sess  *session.Session
+		ident ec2metadata.EC2InstanceIdentityDocument
+	)
+
+	actx, done := context.WithTimeout(ctx, time.Second)
+	defer done()
+	// if unavailable just return an empty set.
+	if ident, err = md.InstanceIdentityDocument(actx); err != nil {
+		log.Println("instance metadata service (v2) unavailable", err)
+		return peers, nil
+	}
+
+	creds := credentials.NewChainCredentials([]credentials.Provider{
+		&credentials.EnvProvider{},
+		&credentials.SharedCredentialsProvider{},
+		md.RoleCredentials(),
+	})
+
+	if sess, err = session.NewSession(&aws.Config{Credentials: creds}); err != nil {
+		return peers, errors.WithStack(err)
+	}
+
+	return autoscalingPeers(sess, ident, supplimental...)
+}
+
+func autoscalingPeers(sess *session.Session, ident ec2metadata.EC2InstanceIdentityDocument, supplimental ...string) (peers []ec2.Instance, err error) {
+	var (
+		asgs  *autoscaling.AutoScaling
+		iao   *autoscaling.DescribeAutoScalingInstancesOutput
+		asg   *autoscaling.DescribeAutoScalingGroupsOutput
+		ec2io *ec2.DescribeInstancesOutput
+	)
+
 	sess = sess.Copy(&aws.Config{
 		Region: aws.String(ident.Region),
 	})
@@ -0,0 +1,16 @@
+package awsx_test
+
+import (
+	"io"
+	"log"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestAwsx(t *testing.T) {
+	log.SetOutput(io.Discard)
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Awsx Suite")
+}
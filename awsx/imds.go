@@ -0,0 +1,199 @@
+package awsx
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/ec2metadata"
+	"github.com/pkg/errors"
+)
+
+// DefaultIMDSEndpoint default address of the instance metadata service.
+const DefaultIMDSEndpoint = "http://169.254.169.254"
+
+// IMDSOption options for the instance metadata service client.
+type IMDSOption func(*IMDS)
+
+// IMDSOptionEndpoint set the endpoint of the instance metadata service.
+func IMDSOptionEndpoint(s string) IMDSOption {
+	return func(i *IMDS) {
+		i.endpoint = strings.TrimSuffix(s, "/")
+	}
+}
+
+// IMDSOptionClient set the http client used to communicate with the instance metadata service.
+func IMDSOptionClient(c *http.Client) IMDSOption {
+	return func(i *IMDS) {
+		i.client = c
+	}
+}
+
+// NewIMDS instance metadata service client that exclusively uses the token based (v2) protocol.
+// the endpoint defaults to the AWS_EC2_METADATA_SERVICE_ENDPOINT environment variable when set.
+func NewIMDS(options ...IMDSOption) IMDS {
+	endpoint := DefaultIMDSEndpoint
+	if s, ok := os.LookupEnv("AWS_EC2_METADATA_SERVICE_ENDPOINT"); ok && s != "" {
+		endpoint = s
+	}
+
+	md := IMDS{
+		endpoint: strings.TrimSuffix(endpoint, "/"),
+		client:   &http.Client{Timeout: time.Second},
+		ttl:      6 * time.Hour,
+	}
+
+	for _, opt := range options {
+		opt(&md)
+	}
+
+	return md
+}
+
+// IMDS instance metadata service v2 client.
+type IMDS struct {
+	endpoint string
+	client   *http.Client
+	ttl      time.Duration
+}
+
+// Token retrieve a session token from the instance metadata service.
+func (t IMDS) Token(ctx context.Context) (_ string, err error) {
+	var (
+		req  *http.Request
+		resp *http.Response
+		raw  []byte
+	)
+
+	if req, err = http.NewRequestWithContext(ctx, http.MethodPut, t.endpoint+"/latest/api/token", nil); err != nil {
+		return "", errors.WithStack(err)
+	}
+	req.Header.Set("X-aws-ec2-metadata-token-ttl-seconds", strconv.Itoa(int(t.ttl.Seconds())))
+
+	if resp, err = t.client.Do(req); err != nil {
+		return "", errors.Wrap(err, "unable to retrieve imds token")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.Errorf("unable to retrieve imds token: %s", resp.Status)
+	}
+
+	if raw, err = io.ReadAll(resp.Body); err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	return string(raw), nil
+}
+
+// InstanceIdentityDocument retrieve the identity document of the instance.
+func (t IMDS) InstanceIdentityDocument(ctx context.Context) (ident ec2metadata.EC2InstanceIdentityDocument, err error) {
+	var (
+		resp *http.Response
+	)
+
+	if resp, err = t.get(ctx, "/latest/dynamic/instance-identity/document"); err != nil {
+		return ident, err
+	}
+	defer resp.Body.Close()
+
+	return ident, errors.Wrap(json.NewDecoder(resp.Body).Decode(&ident), "unable to decode instance identity document")
+}
+
+// RoleCredentials credentials provider for the instance profile role using the
+// token based (v2) protocol.
+func (t IMDS) RoleCredentials() credentials.Provider {
+	return &imdsRoleProvider{md: t}
+}
+
+func (t IMDS) get(ctx context.Context, path string) (_ *http.Response, err error) {
+	var (
+		token string
+		req   *http.Request
+		resp  *http.Response
+	)
+
+	if token, err = t.Token(ctx); err != nil {
+		return nil, err
+	}
+
+	if req, err = http.NewRequestWithContext(ctx, http.MethodGet, t.endpoint+path, nil); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	req.Header.Set("X-aws-ec2-metadata-token", token)
+
+	if resp, err = t.client.Do(req); err != nil {
+		return nil, errors.Wrapf(err, "unable to retrieve %s", path)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, errors.Errorf("unable to retrieve %s: %s", path, resp.Status)
+	}
+
+	return resp, nil
+}
+
+type imdsRoleProvider struct {
+	credentials.Expiry
+	md IMDS
+}
+
+func (t *imdsRoleProvider) Retrieve() (v credentials.Value, err error) {
+	var (
+		resp  *http.Response
+		raw   []byte
+		creds struct {
+			Code            string
+			AccessKeyID     string `json:"AccessKeyId"`
+			SecretAccessKey string
+			Token           string
+			Expiration      time.Time
+		}
+	)
+
+	ctx, done := context.WithTimeout(context.Background(), 5*time.Second)
+	defer done()
+
+	if resp, err = t.md.get(ctx, "/latest/meta-data/iam/security-credentials/"); err != nil {
+		return v, err
+	}
+	raw, err = io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return v, errors.WithStack(err)
+	}
+
+	role := strings.TrimSpace(strings.SplitN(string(raw), "\n", 2)[0])
+	if role == "" {
+		return v, errors.New("no instance profile role found")
+	}
+
+	if resp, err = t.md.get(ctx, "/latest/meta-data/iam/security-credentials/"+role); err != nil {
+		return v, err
+	}
+	defer resp.Body.Close()
+
+	if err = json.NewDecoder(resp.Body).Decode(&creds); err != nil {
+		return v, errors.Wrap(err, "unable to decode instance profile credentials")
+	}
+
+	if creds.Code != "Success" {
+		return v, errors.Errorf("unable to retrieve instance profile credentials: %s", creds.Code)
+	}
+
+	t.SetExpiration(creds.Expiration, time.Minute)
+
+	return credentials.Value{
+		AccessKeyID:     creds.AccessKeyID,
+		SecretAccessKey: creds.SecretAccessKey,
+		SessionToken:    creds.Token,
+		ProviderName:    "IMDSv2RoleProvider",
+	}, nil
+}
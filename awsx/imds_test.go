@@ -0,0 +1,97 @@
+package awsx_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/ec2metadata"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/james-lawrence/bw/awsx"
+)
+
+// imdsv2 stub metadata service that rejects any request without a session token.
+func imdsv2() *httptest.Server {
+	const token = "session-token"
+	mux := http.NewServeMux()
+	mux.HandleFunc("/latest/api/token", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.Header.Get("X-aws-ec2-metadata-token-ttl-seconds") == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte(token))
+	})
+	mux.HandleFunc("/latest/dynamic/instance-identity/document", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-aws-ec2-metadata-token") != token {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		json.NewEncoder(w).Encode(ec2metadata.EC2InstanceIdentityDocument{
+			Region:     "us-east-1",
+			InstanceID: "i-0123456789",
+		})
+	})
+	mux.HandleFunc("/latest/meta-data/iam/security-credentials/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-aws-ec2-metadata-token") != token {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		if r.URL.Path == "/latest/meta-data/iam/security-credentials/" {
+			w.Write([]byte("agent-role"))
+			return
+		}
+
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"Code":            "Success",
+			"AccessKeyId":     "access",
+			"SecretAccessKey": "secret",
+			"Token":           "token",
+			"Expiration":      time.Now().Add(time.Hour),
+		})
+	})
+
+	return httptest.NewServer(mux)
+}
+
+var _ = Describe("IMDS", func() {
+	It("should discover the instance identity using session tokens", func() {
+		srv := imdsv2()
+		defer srv.Close()
+
+		// sanity check the stub rejects v1 requests.
+		resp, err := http.Get(srv.URL + "/latest/dynamic/instance-identity/document")
+		Expect(err).ToNot(HaveOccurred())
+		resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusUnauthorized))
+
+		ident, err := NewIMDS(IMDSOptionEndpoint(srv.URL)).InstanceIdentityDocument(context.Background())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ident.Region).To(Equal("us-east-1"))
+		Expect(ident.InstanceID).To(Equal("i-0123456789"))
+	})
+
+	It("should retrieve instance profile credentials using session tokens", func() {
+		srv := imdsv2()
+		defer srv.Close()
+
+		v, err := NewIMDS(IMDSOptionEndpoint(srv.URL)).RoleCredentials().Retrieve()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(v.AccessKeyID).To(Equal("access"))
+		Expect(v.SecretAccessKey).To(Equal("secret"))
+		Expect(v.SessionToken).To(Equal("token"))
+	})
+
+	It("should return no peers when the metadata service is unreachable", func() {
+		srv := imdsv2()
+		srv.Close()
+
+		peers, err := AutoscalingPeersIMDSv2(context.Background(), NewIMDS(IMDSOptionEndpoint(srv.URL)))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(peers).To(BeEmpty())
+	})
+})
@@ -0,0 +1,202 @@
+// Package azurex provides the minimal azure clients used by the agents, the instance
+// metadata service and the compute resource manager api.
+package azurex
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultIMDSEndpoint default address of the instance metadata service.
+const DefaultIMDSEndpoint = "http://169.254.169.254"
+
+// DefaultManagementEndpoint default address of the azure resource manager.
+const DefaultManagementEndpoint = "https://management.azure.com"
+
+// Instance compute metadata of the instance.
+type Instance struct {
+	Name              string `json:"name"`
+	SubscriptionID    string `json:"subscriptionId"`
+	ResourceGroupName string `json:"resourceGroupName"`
+	VMScaleSetName    string `json:"vmScaleSetName"`
+}
+
+// IMDSOption options for the instance metadata service client.
+type IMDSOption func(*IMDS)
+
+// IMDSOptionEndpoint set the endpoint of the instance metadata service.
+func IMDSOptionEndpoint(s string) IMDSOption {
+	return func(i *IMDS) {
+		i.endpoint = strings.TrimSuffix(s, "/")
+	}
+}
+
+// NewIMDS instance metadata service client.
+func NewIMDS(options ...IMDSOption) IMDS {
+	md := IMDS{
+		endpoint: DefaultIMDSEndpoint,
+		client:   &http.Client{Timeout: time.Second},
+	}
+
+	for _, opt := range options {
+		opt(&md)
+	}
+
+	return md
+}
+
+// IMDS instance metadata service client.
+type IMDS struct {
+	endpoint string
+	client   *http.Client
+}
+
+// Instance retrieve the compute metadata of the instance.
+func (t IMDS) Instance(ctx context.Context) (inst Instance, err error) {
+	return inst, errors.Wrap(t.get(ctx, "/metadata/instance/compute", url.Values{"api-version": {"2021-02-01"}}, &inst), "unable to retrieve instance metadata")
+}
+
+// Token retrieve an access token for the resource manager using the managed identity
+// of the instance. fails when the instance has no managed identity.
+func (t IMDS) Token(ctx context.Context) (_ string, err error) {
+	var (
+		token struct {
+			AccessToken string `json:"access_token"`
+		}
+	)
+
+	q := url.Values{
+		"api-version": {"2018-02-01"},
+		"resource":    {DefaultManagementEndpoint + "/"},
+	}
+
+	if err = t.get(ctx, "/metadata/identity/oauth2/token", q, &token); err != nil {
+		return "", errors.Wrap(err, "unable to retrieve managed identity token")
+	}
+
+	if token.AccessToken == "" {
+		return "", errors.New("unable to retrieve managed identity token: missing access token")
+	}
+
+	return token.AccessToken, nil
+}
+
+func (t IMDS) get(ctx context.Context, path string, q url.Values, v interface{}) (err error) {
+	var (
+		req  *http.Request
+		resp *http.Response
+	)
+
+	if req, err = http.NewRequestWithContext(ctx, http.MethodGet, t.endpoint+path+"?"+q.Encode(), nil); err != nil {
+		return errors.WithStack(err)
+	}
+	req.Header.Set("Metadata", "true")
+
+	if resp, err = t.client.Do(req); err != nil {
+		return errors.WithStack(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("metadata request failed: %s", resp.Status)
+	}
+
+	return errors.WithStack(json.NewDecoder(resp.Body).Decode(v))
+}
+
+// ManagementOption options for the resource manager client.
+type ManagementOption func(*Management)
+
+// ManagementOptionEndpoint set the endpoint of the resource manager.
+func ManagementOptionEndpoint(s string) ManagementOption {
+	return func(m *Management) {
+		m.endpoint = strings.TrimSuffix(s, "/")
+	}
+}
+
+// NewManagement resource manager client.
+func NewManagement(options ...ManagementOption) Management {
+	m := Management{
+		endpoint: DefaultManagementEndpoint,
+		client:   &http.Client{Timeout: 10 * time.Second},
+	}
+
+	for _, opt := range options {
+		opt(&m)
+	}
+
+	return m
+}
+
+// Management azure resource manager client.
+type Management struct {
+	endpoint string
+	client   *http.Client
+}
+
+// ScaleSetPrivateIPs the private ip addresses of the instances within the scale set.
+func (t Management) ScaleSetPrivateIPs(ctx context.Context, token, subscription, group, scaleset string) (ips []string, err error) {
+	type page struct {
+		Value []struct {
+			Properties struct {
+				IPConfigurations []struct {
+					Properties struct {
+						PrivateIPAddress string `json:"privateIPAddress"`
+					} `json:"properties"`
+				} `json:"ipConfigurations"`
+			} `json:"properties"`
+		} `json:"value"`
+		NextLink string `json:"nextLink"`
+	}
+
+	next := t.endpoint + "/subscriptions/" + url.PathEscape(subscription) +
+		"/resourceGroups/" + url.PathEscape(group) +
+		"/providers/Microsoft.Compute/virtualMachineScaleSets/" + url.PathEscape(scaleset) +
+		"/networkInterfaces?api-version=2018-10-01"
+
+	for next != "" {
+		var (
+			req  *http.Request
+			resp *http.Response
+			p    page
+		)
+
+		if req, err = http.NewRequestWithContext(ctx, http.MethodGet, next, nil); err != nil {
+			return ips, errors.WithStack(err)
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+
+		if resp, err = t.client.Do(req); err != nil {
+			return ips, errors.Wrapf(err, "unable to list scale set network interfaces: %s", scaleset)
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return ips, errors.Errorf("unable to list scale set network interfaces: %s - %s", scaleset, resp.Status)
+		}
+
+		err = json.NewDecoder(resp.Body).Decode(&p)
+		resp.Body.Close()
+		if err != nil {
+			return ips, errors.Wrapf(err, "unable to decode scale set network interfaces: %s", scaleset)
+		}
+
+		for _, nic := range p.Value {
+			for _, ipc := range nic.Properties.IPConfigurations {
+				if ipc.Properties.PrivateIPAddress != "" {
+					ips = append(ips, ipc.Properties.PrivateIPAddress)
+				}
+			}
+		}
+
+		next = p.NextLink
+	}
+
+	return ips, nil
+}
@@ -90,10 +90,12 @@ func (t UntilSuccess) Run(ctx context.Context, c agent.Config, d deployer, dl st
 		d,
 		deployment.CoordinatorOptionRoot(c.Root),
 		deployment.CoordinatorOptionKeepN(c.KeepN),
+		deployment.CoordinatorOptionKeepLastSuccessful(c.Bootstrap.KeepLastSuccessful),
 		deployment.CoordinatorOptionStorage(
 			storage.New(storage.OptionProtocols(dl)),
 		),
 		deployment.CoordinatorOptionDispatcher(agentutil.LogDispatcher{}),
+		deployment.CoordinatorOptionVerifyArchives(c.VerifyArchiveWorkers),
 	)
 
 	for i := 0; i < t.maxAttempts; i++ {
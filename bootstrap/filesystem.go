@@ -111,7 +111,7 @@ func (t Filesystem) monitor() {
 		switch event := m.GetEvent().(type) {
 		case *agent.Message_DeployCommand:
 			dc := event.DeployCommand
-			if dc.Command == agent.DeployCommand_Done && dc.Archive != nil {
+			if dc.Command == agent.DeployCommand_Done && dc.Archive != nil && !dc.Options.GetSimulate() {
 				go func() {
 					if cause := errors.Wrap(t.clone(dc), "clone failed"); cause == nil {
 						log.Println("clone successful")
@@ -5,9 +5,12 @@ import (
 	"crypto/rand"
 	"encoding/base32"
 	"io"
+	"log"
+	"math"
 	"os"
 	"os/user"
 	"path/filepath"
+	"strconv"
 	"time"
 
 	"github.com/james-lawrence/bw/internal/stringsx"
@@ -41,12 +44,26 @@ const (
 	AuthKeysFile = "bw.auth.keys"
 	// DefaultDeployTimeout default timeout for a deployment.
 	DefaultDeployTimeout = time.Hour
+	// DefaultCertClockSkew default leeway applied to certificate validity windows.
+	DefaultCertClockSkew = 30 * time.Second
+	// DefaultMaxArchiveBytes default limit for the size of archives accepted by the agent.
+	DefaultMaxArchiveBytes = 4 << 30
+	// DefaultDrainTimeout default duration the agent has to drain on shutdown.
+	DefaultDrainTimeout = 30 * time.Second
+	// DefaultResolveTimeout upper bound for resolving a hostname, see EnvResolveTimeout.
+	DefaultResolveTimeout = 5 * time.Second
+	// DefaultGossipProbeInterval default interval between the failure detection probes of the peers.
+	DefaultGossipProbeInterval = 5 * time.Second
+	// DefaultGossipProbeTimeout default duration to wait for a probed peer to acknowledge the probe.
+	DefaultGossipProbeTimeout = 3 * time.Second
+	// DefaultGossipSuspicionMult default multiplier of the duration a peer is suspected before it's declared dead.
+	DefaultGossipSuspicionMult = 8
+	// DefaultRaftSnapshotRetain default number of raft snapshots retained on disk.
+	DefaultRaftSnapshotRetain = 5
 	// DeployLog filename for the logs of a given deployment.
 	DeployLog = "deploy.log"
 	// ArchiveFile name of the archive file stored on disk
 	ArchiveFile = "archive.tar.gz"
-	// DefaultP2PPort port which will replace all other ports
-	DefaultP2PPort = 2000
 	// DefaultDirAgentCredentials ...
 	DefaultDirAgentCredentials = "tls"
 	// DefaultNotaryKey rsa key used by clients to identify themselves.
@@ -59,6 +76,12 @@ const (
 	DefaultTLSCertCA = "tlsca.cert"
 )
 
+var (
+	// DefaultP2PPort port which will replace all other ports, overridden by
+	// the BW_DEFAULT_P2P_PORT environment variable for non-standard clusters.
+	DefaultP2PPort = defaultP2PPort(2000)
+)
+
 // The various protocols defined for bearded wookie
 const (
 	ProtocolProxy     = "bw.proxy"
@@ -70,6 +93,22 @@ const (
 	ProtocolTorrent   = "bw.torrent"
 )
 
+// read once at init, the override must be a valid tcp port otherwise the fallback is used.
+func defaultP2PPort(fallback int) int {
+	s := os.Getenv(EnvDefaultP2PPort)
+	if s == "" {
+		return fallback
+	}
+
+	port, err := strconv.Atoi(s)
+	if err != nil || port < 1 || port > math.MaxUint16 {
+		log.Printf("ignoring %s: invalid port %q, using %d\n", EnvDefaultP2PPort, s, fallback)
+		return fallback
+	}
+
+	return port
+}
+
 // DeployDir return the deploy directory under the given root.
 func DeployDir(root string) string {
 	return filepath.Join(root, DirDeploys)
@@ -116,6 +155,54 @@ func MustGenerateID() RandomID {
 	return id
 }
 
+// MinimumClusterTokenLength shortest cluster token considered strong.
+const MinimumClusterTokenLength = 16
+
+// cluster tokens with a lower estimated entropy (in bits) are considered weak.
+const minimumClusterTokenEntropy = 64
+
+// GenerateClusterToken generates a strong random cluster token, 32 bytes of
+// cryptographically secure random data encoded as base32.
+func GenerateClusterToken() string {
+	buf := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, buf); err != nil {
+		panic(errors.Wrap(err, "failed to generate cluster token"))
+	}
+
+	return base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(buf)
+}
+
+// ClusterTokenStrength returns an error describing why the token is weak, nil when
+// the token is sufficiently long and its estimated entropy sufficiently high.
+func ClusterTokenStrength(token string) error {
+	if len(token) < MinimumClusterTokenLength {
+		return errors.Errorf("cluster token is too short: %d characters, at least %d are required", len(token), MinimumClusterTokenLength)
+	}
+
+	if bits := entropy(token); bits < minimumClusterTokenEntropy {
+		return errors.Errorf("cluster token has low entropy: ~%.0f bits, at least %d are required", bits, minimumClusterTokenEntropy)
+	}
+
+	return nil
+}
+
+// entropy estimates the entropy of the string in bits using the frequency of its characters.
+func entropy(s string) (bits float64) {
+	freq := make(map[rune]float64, len(s))
+	n := 0.0
+	for _, r := range s {
+		freq[r]++
+		n++
+	}
+
+	for _, c := range freq {
+		p := c / n
+		bits -= p * math.Log2(p)
+	}
+
+	return bits * n
+}
+
 // DisplayName for the user
 func DisplayName() string {
 	u := systemx.CurrentUserOrDefault(user.User{Username: "unknown"})
@@ -22,4 +22,25 @@ var _ = Describe("bw", func() {
 			}
 		})
 	})
+
+	Describe("ClusterTokenStrength", func() {
+		DescribeTable("weak tokens", func(token string) {
+			Expect(ClusterTokenStrength(token)).ToNot(Succeed())
+		},
+			Entry("empty", ""),
+			Entry("short", "secret"),
+			Entry("repeated character", "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"),
+			Entry("repeated pattern", "abababababababababababababababab"),
+		)
+
+		It("should accept generated tokens", func() {
+			for i := 0; i < 100; i++ {
+				Expect(ClusterTokenStrength(GenerateClusterToken())).To(Succeed())
+			}
+		})
+
+		It("should generate different tokens", func() {
+			Expect(GenerateClusterToken()).ToNot(Equal(GenerateClusterToken()))
+		})
+	})
 })
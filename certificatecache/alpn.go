@@ -11,14 +11,45 @@ type cache interface {
 	GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error)
 }
 
-// NewALPN clones the provided TLS config and updates the GetCertificate method
+// NewALPN clones the provided TLS config and updates the GetCertificate method.
+// the tls-alpn-01 challenge is exempt from the client certificate policy of the
+// configuration since the acme validator never presents a certificate.
 func NewALPN(c *tls.Config, cc cache) *tls.Config {
 	updated := c.Clone()
 	updated.NextProtos = append(updated.NextProtos, tlsalpn01.ACMETLS1Protocol)
 	updated.GetCertificate = ALPN{cache: cc, fallback: c.GetCertificate}.GetCertificate
+
+	challenge := updated.Clone()
+	challenge.ClientAuth = tls.NoClientCert
+	challenge.VerifyConnection = nil
+
+	fallback := c.GetConfigForClient
+	updated.GetConfigForClient = func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+		if acmeChallenge(hello) {
+			return challenge, nil
+		}
+
+		if fallback == nil {
+			return nil, nil
+		}
+
+		return fallback(hello)
+	}
+
 	return updated
 }
 
+// acmeChallenge checks if the client is the acme validator of the tls-alpn-01 challenge.
+func acmeChallenge(hello *tls.ClientHelloInfo) bool {
+	for _, proto := range hello.SupportedProtos {
+		if proto == tlsalpn01.ACMETLS1Protocol {
+			return true
+		}
+	}
+
+	return false
+}
+
 // ALPN implements the alpn TLS certificate resolution strategy.
 type ALPN struct {
 	cache
@@ -27,10 +58,8 @@ type ALPN struct {
 
 // GetCertificate for use by tls.Config.
 func (t ALPN) GetCertificate(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
-	for _, proto := range hello.SupportedProtos {
-		if proto == tlsalpn01.ACMETLS1Protocol {
-			return t.cache.GetCertificate(hello)
-		}
+	if acmeChallenge(hello) {
+		return t.cache.GetCertificate(hello)
 	}
 
 	if t.fallback == nil {
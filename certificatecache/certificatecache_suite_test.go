@@ -0,0 +1,19 @@
+package certificatecache_test
+
+import (
+	"io"
+	"log"
+	"testing"
+
+	"github.com/james-lawrence/bw/internal/testingx"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestCertificatecache(t *testing.T) {
+	log.SetOutput(io.Discard)
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Certificatecache Suite")
+}
+
+var _ = SynchronizedAfterSuite(func() {}, testingx.Cleanup)
@@ -27,6 +27,7 @@ type Notary struct {
 	CertificateDir string
 	CA             string
 	Insecure       bool
+	Pin            string // hex encoded sha256 fingerprint of the agents' certificate, see tlsx.OptionPinSHA256.
 }
 
 // Refresh the current credentials
@@ -67,6 +68,10 @@ func (t Notary) Refresh() (err error) {
 		InsecureSkipVerify: t.Insecure,
 	}
 
+	if c, err = tlsx.Clone(c, tlsx.OptionPinSHA256(t.Pin)); err != nil {
+		return err
+	}
+
 	d, err := dialers.DefaultDialer(t.Address, tlsx.NewDialer(c), grpc.WithPerRPCCredentials(ss))
 	if err != nil {
 		return err
@@ -0,0 +1,92 @@
+package certificatecache
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"log"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultSessionTicketRotation default frequency the session ticket key files are reloaded.
+const DefaultSessionTicketRotation = time.Minute
+
+// NewSessionTicketKeys manages the session ticket keys of a server tls.Config
+// from the provided files. the first file is the active key used to issue new
+// tickets, the remaining keys are only used to resume sessions from previously issued tickets.
+func NewSessionTicketKeys(paths ...string) SessionTicketKeys {
+	return SessionTicketKeys{paths: paths}
+}
+
+// SessionTicketKeys loads session ticket keys from disk.
+type SessionTicketKeys struct {
+	paths []string
+}
+
+// Load the keys from disk, each key is derived from the contents of its file.
+func (t SessionTicketKeys) Load() (keys [][32]byte, err error) {
+	var (
+		encoded []byte
+	)
+
+	keys = make([][32]byte, 0, len(t.paths))
+	for _, path := range t.paths {
+		if encoded, err = os.ReadFile(path); err != nil {
+			return keys, errors.Wrapf(err, "unable to read session ticket key: %s", path)
+		}
+
+		if len(encoded) == 0 {
+			return keys, errors.Errorf("empty session ticket key: %s", path)
+		}
+
+		keys = append(keys, sha256.Sum256(encoded))
+	}
+
+	return keys, nil
+}
+
+// Rotate installs the current keys into the tls configuration.
+// when no keys are configured the tls configuration is left untouched
+// and go's automatically rotated random keys are used.
+func (t SessionTicketKeys) Rotate(c *tls.Config) (err error) {
+	var (
+		keys [][32]byte
+	)
+
+	if len(t.paths) == 0 {
+		return nil
+	}
+
+	if keys, err = t.Load(); err != nil {
+		return err
+	}
+
+	c.SetSessionTicketKeys(keys)
+
+	return nil
+}
+
+// Run periodically reload the keys until the context is cancelled.
+// on failure the previously installed keys remain active.
+func (t SessionTicketKeys) Run(ctx context.Context, c *tls.Config, d time.Duration) {
+	if len(t.paths) == 0 {
+		return
+	}
+
+	ticker := time.NewTicker(d)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := t.Rotate(c); err != nil {
+				log.Println("failed to rotate session ticket keys", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package certificatecache_test
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/james-lawrence/bw/certificatecache"
+	"github.com/james-lawrence/bw/internal/testingx"
+	"github.com/james-lawrence/bw/internal/tlsx"
+)
+
+func serverTLS() *tls.Config {
+	template, err := tlsx.X509Template(time.Hour, tlsx.X509OptionHosts("127.0.0.1"))
+	Expect(err).ToNot(HaveOccurred())
+	priv, derBytes, err := tlsx.SelfSignedRSAGen(2048, template)
+	Expect(err).ToNot(HaveOccurred())
+	cert, err := x509.ParseCertificate(derBytes)
+	Expect(err).ToNot(HaveOccurred())
+
+	return &tls.Config{
+		Certificates: []tls.Certificate{{
+			Certificate: [][]byte{derBytes},
+			PrivateKey:  priv,
+			Leaf:        cert,
+		}},
+	}
+}
+
+// serve accepts a single connection per dial and reports if the session was resumed.
+func serve(c *tls.Config) net.Addr {
+	l, err := tls.Listen("tcp", "127.0.0.1:0", c)
+	Expect(err).ToNot(HaveOccurred())
+	DeferCleanup(l.Close)
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+
+			_, _ = conn.Write([]byte{0})
+			_ = conn.Close()
+		}
+	}()
+
+	return l.Addr()
+}
+
+func resumed(addr net.Addr, cache tls.ClientSessionCache) bool {
+	conn, err := tls.Dial("tcp", addr.String(), &tls.Config{
+		InsecureSkipVerify: true,
+		ClientSessionCache: cache,
+	})
+	Expect(err).ToNot(HaveOccurred())
+	defer conn.Close()
+
+	// read to ensure the session ticket issued by the server is processed.
+	_, err = conn.Read(make([]byte, 1))
+	Expect(err).ToNot(HaveOccurred())
+
+	return conn.ConnectionState().DidResume
+}
+
+func writeKey(dir, name, content string) string {
+	path := filepath.Join(dir, name)
+	Expect(os.WriteFile(path, []byte(content), 0600)).To(Succeed())
+	return path
+}
+
+var _ = Describe("SessionTicketKeys", func() {
+	It("should leave the configuration untouched without keys", func() {
+		c := serverTLS()
+		Expect(NewSessionTicketKeys().Rotate(c)).To(Succeed())
+		cache := tls.NewLRUClientSessionCache(10)
+		addr := serve(c)
+		Expect(resumed(addr, cache)).To(BeFalse())
+		Expect(resumed(addr, cache)).To(BeTrue())
+	})
+
+	It("should fail to load missing keys", func() {
+		dir := testingx.TempDir()
+		Expect(NewSessionTicketKeys(filepath.Join(dir, "missing")).Rotate(serverTLS())).ToNot(Succeed())
+	})
+
+	It("should install the configured keys and replace them on rotation", func() {
+		var (
+			dir   = testingx.TempDir()
+			cache = tls.NewLRUClientSessionCache(10)
+			path  = writeKey(dir, "active", "key1")
+			keys  = NewSessionTicketKeys(path)
+			c1    = serverTLS()
+			c2    = serverTLS()
+		)
+
+		// two independent servers sharing the configured key can resume each others sessions.
+		Expect(keys.Rotate(c1)).To(Succeed())
+		Expect(keys.Rotate(c2)).To(Succeed())
+		addr1, addr2 := serve(c1), serve(c2)
+		Expect(resumed(addr1, cache)).To(BeFalse())
+		Expect(resumed(addr2, cache)).To(BeTrue())
+
+		// rotate the active key, previously issued tickets are no longer accepted.
+		writeKey(dir, "active", "key2")
+		Expect(keys.Rotate(c2)).To(Succeed())
+		Expect(resumed(addr2, cache)).To(BeFalse())
+		Expect(resumed(addr2, cache)).To(BeTrue())
+	})
+
+	It("should resume sessions from retired keys", func() {
+		var (
+			dir     = testingx.TempDir()
+			cache   = tls.NewLRUClientSessionCache(10)
+			retired = writeKey(dir, "retired", "key1")
+			c       = serverTLS()
+		)
+
+		Expect(NewSessionTicketKeys(retired).Rotate(c)).To(Succeed())
+		addr := serve(c)
+		Expect(resumed(addr, cache)).To(BeFalse())
+
+		Expect(NewSessionTicketKeys(writeKey(dir, "active", "key2"), retired).Rotate(c)).To(Succeed())
+		Expect(resumed(addr, cache)).To(BeTrue())
+	})
+})
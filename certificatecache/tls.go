@@ -4,10 +4,13 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"os"
+	"path"
+	"time"
 
 	"github.com/james-lawrence/bw/agent"
 	"github.com/james-lawrence/bw/internal/systemx"
 	"github.com/james-lawrence/bw/internal/tlsx"
+	"github.com/james-lawrence/bw/muxer"
 	"google.golang.org/grpc/credentials"
 
 	"github.com/pkg/errors"
@@ -16,7 +19,8 @@ import (
 // TLSGenServer generate tls config for the agent.
 func TLSGenServer(c agent.Config, options ...tlsx.Option) (creds *tls.Config, err error) {
 	var (
-		pool *x509.CertPool
+		pool         *x509.CertPool
+		restrictions []tlsx.Option
 	)
 
 	if err = os.MkdirAll(c.CredentialsDir, 0700); err != nil {
@@ -41,10 +45,275 @@ func TLSGenServer(c agent.Config, options ...tlsx.Option) (creds *tls.Config, er
 		GetClientCertificate: m.GetClientCertificate,
 		ClientCAs:            pool,
 		RootCAs:              pool,
-		NextProtos:           []string{"bw.mux"},
+		NextProtos:           []string{muxer.ALPNSession, "bw.mux"},
+	}
+
+	if restrictions, err = tlsRestrictions(c); err != nil {
+		return creds, err
+	}
+
+	// pinning is applied last to ensure inbound peers are still asked for their certificate.
+	return tlsx.Clone(creds, append(append(restrictions, options...), OptionPinPeerSANs(c.ExpectedPeerSANs...))...)
+}
+
+// tlsRestrictions the minimum version and cipher suites of the configuration.
+func tlsRestrictions(c agent.Config) (_ []tlsx.Option, err error) {
+	var (
+		version uint16
+		suites  []uint16
+	)
+
+	if version, err = tlsx.ParseVersion(c.TLSMinVersion); err != nil {
+		return nil, err
+	}
+
+	if suites, err = tlsx.ParseCipherSuites(c.TLSCipherSuites...); err != nil {
+		return nil, err
+	}
+
+	return []tlsx.Option{tlsx.OptionMinVersion(version), tlsx.OptionCipherSuites(suites...)}, nil
+}
+
+// ALPNGossip offered by the memberlist transport to request the gossip identity from the agent.
+const ALPNGossip = "bw.gossip"
+
+// TLSGenGossip generate tls config for the memberlist transport from the gossip credentials.
+// the gossip credentials are reloaded independently of the agent's credentials.
+func TLSGenGossip(c agent.Config, options ...tlsx.Option) (creds *tls.Config, err error) {
+	var (
+		pool         *x509.CertPool
+		restrictions []tlsx.Option
+	)
+
+	if err = os.MkdirAll(c.GossipCredentials.Directory, 0700); err != nil {
+		return creds, errors.WithStack(err)
+	}
+
+	if pool, err = x509.SystemCertPool(); err != nil {
+		return creds, errors.WithStack(err)
+	}
+
+	m := NewDirectory(
+		c.ServerName,
+		c.GossipCredentials.Directory,
+		c.GossipCredentials.CA,
+		pool,
+	)
+
+	creds = &tls.Config{
+		ServerName:           c.ServerName,
+		GetCertificate:       m.GetCertificate,
+		GetClientCertificate: m.GetClientCertificate,
+		ClientCAs:            pool,
+		RootCAs:              pool,
+		NextProtos:           []string{"bw.mux", ALPNGossip},
+	}
+
+	if restrictions, err = tlsRestrictions(c); err != nil {
+		return creds, err
+	}
+
+	return tlsx.Clone(creds, append(restrictions, options...)...)
+}
+
+// OptionGossipIdentity presents the certificate of the gossip configuration to
+// clients offering ALPNGossip, every other client is presented the existing certificate.
+func OptionGossipIdentity(gossip *tls.Config) tlsx.Option {
+	return func(c *tls.Config) error {
+		fallback := c.GetCertificate
+		c.GetCertificate = func(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+			for _, proto := range hello.SupportedProtos {
+				if proto == ALPNGossip {
+					return gossip.GetCertificate(hello)
+				}
+			}
+
+			if fallback == nil {
+				return nil, nil
+			}
+
+			return fallback(hello)
+		}
+
+		return nil
+	}
+}
+
+// ClientAuthType maps the client certificate policy to the tls.ClientAuthType,
+// see agent.ClientAuthRequire, agent.ClientAuthRequest, and agent.ClientAuthNone.
+func ClientAuthType(policy string) (tls.ClientAuthType, error) {
+	switch policy {
+	case agent.ClientAuthRequire:
+		return tls.RequireAndVerifyClientCert, nil
+	case agent.ClientAuthRequest:
+		return tls.VerifyClientCertIfGiven, nil
+	case agent.ClientAuthNone:
+		return tls.NoClientCert, nil
+	default:
+		return tls.NoClientCert, errors.Errorf("unknown client auth policy: %s", policy)
 	}
+}
+
+// OptionClientAuthPolicy sets the client certificate policy of the configuration.
+// a blank policy leaves the configuration untouched.
+func OptionClientAuthPolicy(policy string) tlsx.Option {
+	return func(c *tls.Config) (err error) {
+		if policy == "" {
+			return nil
+		}
+
+		c.ClientAuth, err = ClientAuthType(policy)
+		return err
+	}
+}
+
+// OptionPinPeerSANs pins the certificates presented by peers in both directions to the
+// allowed patterns, see VerifyPeerSANs. when acting as a server clients are asked for a
+// certificate, which is verified and pinned if presented. clients without a certificate
+// are left to the other authorization mechanisms. no patterns leaves the configuration untouched.
+func OptionPinPeerSANs(allowed ...string) tlsx.Option {
+	return func(c *tls.Config) error {
+		if len(allowed) == 0 {
+			return nil
+		}
+
+		switch c.ClientAuth {
+		case tls.NoClientCert, tls.RequestClientCert:
+			c.ClientAuth = tls.VerifyClientCertIfGiven
+		case tls.RequireAnyClientCert:
+			c.ClientAuth = tls.RequireAndVerifyClientCert
+		}
+
+		c.VerifyConnection = verifyAll(VerifyPeerSANs(allowed...), c.VerifyConnection)
+
+		return nil
+	}
+}
 
-	return tlsx.Clone(creds, options...)
+// OptionVerifyClockSkew replaces the standard verification of the server certificate
+// with one that allows the validity window to be off by the provided skew.
+// the standard verification has no leeway for the validity window.
+// configurations that already skip verification, or a skew <= 0, are left untouched.
+func OptionVerifyClockSkew(skew time.Duration) tlsx.Option {
+	return func(c *tls.Config) error {
+		if skew <= 0 || c.InsecureSkipVerify {
+			return nil
+		}
+
+		c.VerifyConnection = verifyAll(VerifyClockSkew(c.RootCAs, c.ServerName, skew), c.VerifyConnection)
+		c.InsecureSkipVerify = true
+
+		return nil
+	}
+}
+
+// VerifyClockSkew verifies the certificate chain presented by a server allowing
+// the validity window of the certificates to be off by the provided skew.
+// used in conjunction with InsecureSkipVerify to replace the standard verification.
+// the server name defaults to the name sent by the client when blank, if neither is
+// available verification fails. connections that have already been verified are ignored.
+func VerifyClockSkew(roots *x509.CertPool, servername string, skew time.Duration) func(tls.ConnectionState) error {
+	return func(cs tls.ConnectionState) (err error) {
+		if len(cs.VerifiedChains) > 0 {
+			return nil
+		}
+
+		if len(cs.PeerCertificates) == 0 {
+			return errors.New("server did not present a certificate")
+		}
+
+		name := servername
+		if name == "" {
+			name = cs.ServerName
+		}
+
+		if name == "" {
+			return errors.New("unable to verify server certificate, missing server name")
+		}
+
+		now := time.Now()
+		// the time the chain is verified at, clamped into the validity window of every certificate.
+		at := now
+		for _, cert := range cs.PeerCertificates {
+			if now.Add(skew).Before(cert.NotBefore) {
+				return errors.Errorf("certificate %s is not valid until %s, beyond the allowed clock skew %s", cert.Subject, cert.NotBefore.Format(time.RFC3339), skew)
+			}
+
+			if now.Add(-skew).After(cert.NotAfter) {
+				return errors.Errorf("certificate %s expired at %s, beyond the allowed clock skew %s", cert.Subject, cert.NotAfter.Format(time.RFC3339), skew)
+			}
+
+			if at.Before(cert.NotBefore) {
+				at = cert.NotBefore
+			}
+
+			if at.After(cert.NotAfter) {
+				at = cert.NotAfter
+			}
+		}
+
+		intermediates := x509.NewCertPool()
+		for _, cert := range cs.PeerCertificates[1:] {
+			intermediates.AddCert(cert)
+		}
+
+		_, err = cs.PeerCertificates[0].Verify(x509.VerifyOptions{
+			DNSName:       name,
+			Roots:         roots,
+			Intermediates: intermediates,
+			CurrentTime:   at,
+		})
+
+		return errors.WithStack(err)
+	}
+}
+
+func verifyAll(verifiers ...func(tls.ConnectionState) error) func(tls.ConnectionState) error {
+	return func(cs tls.ConnectionState) (err error) {
+		for _, v := range verifiers {
+			if v == nil {
+				continue
+			}
+
+			if err = v(cs); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
+
+// VerifyPeerSANs ensures the certificate presented by the peer has a subject alternative
+// name (dns, ip, or uri) matching one of the allowed patterns (see path.Match).
+// connections where the peer didn't present a certificate are left to the other
+// authorization mechanisms.
+func VerifyPeerSANs(allowed ...string) func(tls.ConnectionState) error {
+	return func(cs tls.ConnectionState) error {
+		if len(cs.PeerCertificates) == 0 {
+			return nil
+		}
+
+		leaf := cs.PeerCertificates[0]
+		sans := make([]string, 0, len(leaf.DNSNames)+len(leaf.IPAddresses)+len(leaf.URIs))
+		sans = append(sans, leaf.DNSNames...)
+		for _, ip := range leaf.IPAddresses {
+			sans = append(sans, ip.String())
+		}
+		for _, uri := range leaf.URIs {
+			sans = append(sans, uri.String())
+		}
+
+		for _, san := range sans {
+			for _, pattern := range allowed {
+				if matched, _ := path.Match(pattern, san); matched {
+					return nil
+				}
+			}
+		}
+
+		return errors.Errorf("peer certificate %s does not have an expected subject alternative name: %v", leaf.Subject, sans)
+	}
 }
 
 // GRPCGenServer generate grpc tls transport credentials for the server.
@@ -84,7 +353,7 @@ func TLSGenClient(c agent.ConfigClient, options ...tlsx.Option) (creds *tls.Conf
 		InsecureSkipVerify: c.Credentials.Insecure,
 	}
 
-	return tlsx.Clone(creds, options...)
+	return tlsx.Clone(creds, append([]tlsx.Option{tlsx.OptionPinSHA256(c.Credentials.Pin)}, options...)...)
 }
 
 // GRPCGenClient generate grpc tls transport credentials for a client.
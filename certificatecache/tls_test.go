@@ -0,0 +1,380 @@
+package certificatecache_test
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/go-acme/lego/v4/challenge/tlsalpn01"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/james-lawrence/bw/agent"
+	. "github.com/james-lawrence/bw/certificatecache"
+	"github.com/james-lawrence/bw/internal/testingx"
+	"github.com/james-lawrence/bw/internal/tlsx"
+)
+
+func certificate(hosts ...string) *x509.Certificate {
+	template, err := tlsx.X509Template(time.Hour, tlsx.X509OptionHosts(hosts...))
+	Expect(err).ToNot(HaveOccurred())
+	_, derBytes, err := tlsx.SelfSignedRSAGen(1024, template)
+	Expect(err).ToNot(HaveOccurred())
+	cert, err := x509.ParseCertificate(derBytes)
+	Expect(err).ToNot(HaveOccurred())
+	return cert
+}
+
+var _ = Describe("VerifyPeerSANs", func() {
+	DescribeTable("peer certificates", func(cert *x509.Certificate, matched bool, allowed ...string) {
+		err := VerifyPeerSANs(allowed...)(tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}})
+		if matched {
+			Expect(err).ToNot(HaveOccurred())
+		} else {
+			Expect(err).To(HaveOccurred())
+		}
+	},
+		Entry("matching dns name", certificate("node1.example.com"), true, "node2.example.com", "node1.example.com"),
+		Entry("matching wildcard", certificate("node1.example.com"), true, "*.example.com"),
+		Entry("matching ip", certificate("10.0.0.1"), true, "10.0.0.1"),
+		Entry("non matching dns name", certificate("node1.evil.com"), false, "*.example.com"),
+		Entry("non matching ip", certificate("10.0.0.2"), false, "10.0.0.1"),
+	)
+
+	It("should defer to other mechanisms when no certificate is presented", func() {
+		Expect(VerifyPeerSANs("*.example.com")(tls.ConnectionState{})).To(Succeed())
+	})
+
+	It("should reject handshakes with a non matching server", func() {
+		addr := serve(serverTLS())
+
+		conn, err := tls.Dial("tcp", addr.String(), &tls.Config{
+			InsecureSkipVerify: true,
+			VerifyConnection:   VerifyPeerSANs("127.0.0.1"),
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(conn.Close()).To(Succeed())
+
+		_, err = tls.Dial("tcp", addr.String(), &tls.Config{
+			InsecureSkipVerify: true,
+			VerifyConnection:   VerifyPeerSANs("*.example.com"),
+		})
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+type offsetclock time.Duration
+
+func (t offsetclock) Now() time.Time {
+	return time.Now().Add(time.Duration(t))
+}
+
+// skewedServer serves a self signed certificate that only becomes valid after the offset.
+func skewedServer(offset time.Duration) (net.Addr, *x509.CertPool) {
+	template, err := tlsx.X509Template(time.Hour, tlsx.X509OptionHosts("127.0.0.1"), tlsx.X509OptionTimeWindow(offsetclock(offset), time.Hour))
+	Expect(err).ToNot(HaveOccurred())
+	priv, derBytes, err := tlsx.SelfSignedRSAGen(2048, template)
+	Expect(err).ToNot(HaveOccurred())
+	cert, err := x509.ParseCertificate(derBytes)
+	Expect(err).ToNot(HaveOccurred())
+
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+
+	return serve(&tls.Config{
+		Certificates: []tls.Certificate{{
+			Certificate: [][]byte{derBytes},
+			PrivateKey:  priv,
+			Leaf:        cert,
+		}},
+	}), pool
+}
+
+var _ = Describe("VerifyClockSkew", func() {
+	It("should accept a certificate that becomes valid within the skew", func() {
+		addr, pool := skewedServer(10 * time.Second)
+
+		_, err := tls.Dial("tcp", addr.String(), &tls.Config{RootCAs: pool, ServerName: "127.0.0.1"})
+		Expect(err).To(HaveOccurred())
+
+		conn, err := tls.Dial("tcp", addr.String(), &tls.Config{
+			RootCAs:            pool,
+			ServerName:         "127.0.0.1",
+			InsecureSkipVerify: true,
+			VerifyConnection:   VerifyClockSkew(pool, "127.0.0.1", 30*time.Second),
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(conn.Close()).To(Succeed())
+	})
+
+	It("should reject a certificate that becomes valid beyond the skew", func() {
+		addr, pool := skewedServer(time.Minute)
+
+		_, err := tls.Dial("tcp", addr.String(), &tls.Config{
+			RootCAs:            pool,
+			ServerName:         "127.0.0.1",
+			InsecureSkipVerify: true,
+			VerifyConnection:   VerifyClockSkew(pool, "127.0.0.1", 30*time.Second),
+		})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should reject a certificate from an untrusted authority", func() {
+		addr, _ := skewedServer(10 * time.Second)
+
+		_, err := tls.Dial("tcp", addr.String(), &tls.Config{
+			RootCAs:            x509.NewCertPool(),
+			ServerName:         "127.0.0.1",
+			InsecureSkipVerify: true,
+			VerifyConnection:   VerifyClockSkew(x509.NewCertPool(), "127.0.0.1", 30*time.Second),
+		})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should verify against the configured server name", func() {
+		addr, pool := skewedServer(10 * time.Second)
+
+		_, err := tls.Dial("tcp", addr.String(), &tls.Config{
+			RootCAs:            pool,
+			InsecureSkipVerify: true,
+			VerifyConnection:   VerifyClockSkew(pool, "node1.example.com", 30*time.Second),
+		})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should apply the skew to configurations that verify the server", func() {
+		addr, pool := skewedServer(10 * time.Second)
+		c := tlsx.MustClone(&tls.Config{RootCAs: pool, ServerName: "127.0.0.1"}, OptionVerifyClockSkew(30*time.Second))
+
+		conn, err := tls.Dial("tcp", addr.String(), c)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(conn.Close()).To(Succeed())
+	})
+
+	It("should leave configurations that skip verification untouched", func() {
+		// an untrusted certificate that is beyond the skew.
+		addr, _ := skewedServer(time.Hour)
+		c := tlsx.MustClone(&tls.Config{ServerName: "127.0.0.1"}, tlsx.OptionInsecureSkipVerify, OptionVerifyClockSkew(30*time.Second))
+		Expect(c.VerifyConnection).To(BeNil())
+
+		conn, err := tls.Dial("tcp", addr.String(), c)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(conn.Close()).To(Succeed())
+	})
+})
+
+func keypair(hosts ...string) tls.Certificate {
+	template, err := tlsx.X509Template(time.Hour, tlsx.X509OptionHosts(hosts...))
+	Expect(err).ToNot(HaveOccurred())
+	priv, derBytes, err := tlsx.SelfSignedRSAGen(2048, template)
+	Expect(err).ToNot(HaveOccurred())
+	cert, err := x509.ParseCertificate(derBytes)
+	Expect(err).ToNot(HaveOccurred())
+
+	return tls.Certificate{Certificate: [][]byte{derBytes}, PrivateKey: priv, Leaf: cert}
+}
+
+var _ = Describe("OptionPinPeerSANs", func() {
+	// handshake with the server presenting the certificates, the server only completes
+	// the handshake when it writes, so a read is required to observe a rejection.
+	handshake := func(addr net.Addr, certs ...tls.Certificate) error {
+		conn, err := tls.Dial("tcp", addr.String(), &tls.Config{InsecureSkipVerify: true, Certificates: certs})
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+
+		_, err = conn.Read(make([]byte, 1))
+		return err
+	}
+
+	pinned := func(trusted ...tls.Certificate) net.Addr {
+		pool := x509.NewCertPool()
+		for _, c := range trusted {
+			pool.AddCert(c.Leaf)
+		}
+
+		c := serverTLS()
+		c.ClientCAs = pool
+		return serve(tlsx.MustClone(c, tlsx.OptionNoClientCert, OptionPinPeerSANs("*.example.com")))
+	}
+
+	It("should accept inbound peers presenting a matching certificate", func() {
+		peer := keypair("node1.example.com")
+		Expect(handshake(pinned(peer), peer)).To(Succeed())
+	})
+
+	It("should reject inbound peers presenting a non matching certificate", func() {
+		peer := keypair("node1.evil.com")
+		Expect(handshake(pinned(peer), peer)).ToNot(Succeed())
+	})
+
+	It("should reject inbound peers presenting an untrusted certificate", func() {
+		peer := keypair("node1.example.com")
+		Expect(handshake(pinned(), peer)).ToNot(Succeed())
+	})
+
+	It("should defer inbound clients without a certificate to other mechanisms", func() {
+		Expect(handshake(pinned())).To(Succeed())
+	})
+
+	It("should leave the configuration untouched without patterns", func() {
+		c := tlsx.MustClone(&tls.Config{}, tlsx.OptionNoClientCert, OptionPinPeerSANs())
+		Expect(c.ClientAuth).To(Equal(tls.NoClientCert))
+		Expect(c.VerifyConnection).To(BeNil())
+	})
+})
+
+var _ = Describe("OptionGossipIdentity", func() {
+	credentials := func(seed string) string {
+		dir := testingx.TempDir()
+		Expect(AutomaticTLSAgent([]byte(seed), "localhost", dir)).To(Succeed())
+		return dir
+	}
+
+	leaf := func(addr net.Addr, protocols ...string) *x509.Certificate {
+		conn, err := tls.Dial("tcp", addr.String(), &tls.Config{InsecureSkipVerify: true, NextProtos: protocols})
+		Expect(err).ToNot(HaveOccurred())
+		defer conn.Close()
+		return conn.ConnectionState().PeerCertificates[0]
+	}
+
+	servercert := func(dir string) *x509.Certificate {
+		encoded, err := os.ReadFile(filepath.Join(dir, DefaultTLSCertServer))
+		Expect(err).ToNot(HaveOccurred())
+		cert, err := tlsx.DecodePEMCertificate(encoded)
+		Expect(err).ToNot(HaveOccurred())
+		return cert
+	}
+
+	It("should present each subsystem its configured identity", func() {
+		rpcdir, gossipdir := credentials("rpc"), credentials("gossip")
+		c := agent.NewConfig()
+		c.CredentialsDir, c.CA = rpcdir, filepath.Join(rpcdir, DefaultTLSCertCA)
+		c.GossipCredentials.Directory = gossipdir
+		c.GossipCredentials.CA = filepath.Join(gossipdir, DefaultTLSCertCA)
+		Expect(c.DistinctGossipCredentials()).To(BeTrue())
+
+		gossip, err := TLSGenGossip(c)
+		Expect(err).ToNot(HaveOccurred())
+		server, err := TLSGenServer(c, tlsx.OptionNoClientCert, OptionGossipIdentity(gossip))
+		Expect(err).ToNot(HaveOccurred())
+		addr := serve(server)
+
+		rpccert, gossipcert := servercert(rpcdir), servercert(gossipdir)
+		Expect(rpccert.Equal(gossipcert)).To(BeFalse())
+
+		Expect(leaf(addr, "bw.mux").Equal(rpccert)).To(BeTrue())
+		Expect(leaf(addr, "bw.mux", ALPNGossip).Equal(gossipcert)).To(BeTrue())
+	})
+
+	It("should default the gossip identity to the agent credentials", func() {
+		c := agent.NewConfig()
+		c.CredentialsDir, c.CA = credentials("rpc"), ""
+		c = c.EnsureDefaults()
+		Expect(c.GossipCredentials.Directory).To(Equal(c.CredentialsDir))
+		Expect(c.GossipCredentials.CA).To(Equal(c.CA))
+		Expect(c.DistinctGossipCredentials()).To(BeFalse())
+	})
+})
+
+// staticCertificate serves the certificate for the acme challenge.
+type staticCertificate tls.Certificate
+
+func (t staticCertificate) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	c := tls.Certificate(t)
+	return &c, nil
+}
+
+var _ = Describe("OptionClientAuthPolicy", func() {
+	// anonymous reports if a client without a certificate completed an exchange with the server.
+	anonymous := func(addr net.Addr) error {
+		conn, err := tls.Dial("tcp", addr.String(), &tls.Config{InsecureSkipVerify: true})
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+
+		_, err = conn.Read(make([]byte, 1))
+		return err
+	}
+
+	listener := func(policy string) net.Addr {
+		c, err := tlsx.Clone(serverTLS(), OptionClientAuthPolicy(policy))
+		Expect(err).ToNot(HaveOccurred())
+		c.ClientCAs = x509.NewCertPool()
+		return serve(c)
+	}
+
+	It("should reject clients without a certificate on the require listener", func() {
+		Expect(anonymous(listener(agent.ClientAuthRequire))).To(HaveOccurred())
+	})
+
+	It("should accept clients without a certificate on the request listener", func() {
+		Expect(anonymous(listener(agent.ClientAuthRequest))).To(Succeed())
+	})
+
+	It("should accept clients without a certificate on the none listener", func() {
+		Expect(anonymous(listener(agent.ClientAuthNone))).To(Succeed())
+	})
+
+	It("should exempt the acme tls-alpn-01 challenge from the require listener", func() {
+		challenge := serverTLS().Certificates[0]
+		c, err := tlsx.Clone(NewALPN(serverTLS(), staticCertificate(challenge)), OptionClientAuthPolicy(agent.ClientAuthRequire))
+		Expect(err).ToNot(HaveOccurred())
+		c.ClientCAs = x509.NewCertPool()
+		addr := serve(c)
+
+		Expect(anonymous(addr)).To(HaveOccurred())
+
+		// the validator presents the domain being validated.
+		conn, err := tls.Dial("tcp", addr.String(), &tls.Config{InsecureSkipVerify: true, ServerName: "example.com", NextProtos: []string{tlsalpn01.ACMETLS1Protocol}})
+		Expect(err).ToNot(HaveOccurred())
+		defer conn.Close()
+		Expect(conn.ConnectionState().PeerCertificates[0].Equal(challenge.Leaf)).To(BeTrue())
+	})
+
+	It("should leave the configuration untouched with a blank policy", func() {
+		c, err := tlsx.Clone(&tls.Config{ClientAuth: tls.VerifyClientCertIfGiven}, OptionClientAuthPolicy(""))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(c.ClientAuth).To(Equal(tls.VerifyClientCertIfGiven))
+	})
+
+	It("should error on unknown policies", func() {
+		_, err := tlsx.Clone(&tls.Config{}, OptionClientAuthPolicy("optional"))
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("TLSGenServer", func() {
+	It("should retain the default version and cipher suites", func() {
+		c := agent.NewConfig()
+		c.CredentialsDir = testingx.TempDir()
+		server, err := TLSGenServer(c, tlsx.OptionNoClientCert)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(server.MinVersion).To(BeZero())
+		Expect(server.CipherSuites).To(BeNil())
+	})
+
+	It("should pin the configured version and cipher suites", func() {
+		c := agent.NewConfig()
+		c.CredentialsDir = testingx.TempDir()
+		c.TLSMinVersion = "1.3"
+		c.TLSCipherSuites = []string{"TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384"}
+		server, err := TLSGenServer(c, tlsx.OptionNoClientCert)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(server.MinVersion).To(Equal(uint16(tls.VersionTLS13)))
+		Expect(server.CipherSuites).To(Equal([]uint16{tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384}))
+	})
+
+	It("should reject an unknown cipher suite", func() {
+		c := agent.NewConfig()
+		c.CredentialsDir = testingx.TempDir()
+		c.TLSCipherSuites = []string{"TLS_RSA_WITH_RC4_128_SHA"}
+		_, err := TLSGenServer(c)
+		Expect(err).To(MatchError(ContainSubstring("unknown or insecure cipher suite")))
+	})
+})
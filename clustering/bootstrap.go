@@ -103,11 +103,13 @@ func BootstrapOptionBanned(b ...string) BootstrapOption {
 }
 
 type bootstrap struct {
-	Backoff      backoff
-	AllowRetry   allowRetry
-	JoinStrategy joinStrategy
-	Peering      []Source
-	Banned       map[string]struct{}
+	Backoff       backoff
+	AllowRetry    allowRetry
+	JoinStrategy  joinStrategy
+	Peering       []Source
+	Banned        map[string]struct{}
+	MaxClockDrift time.Duration
+	ClockDrift    ClockDrift
 }
 
 func (t bootstrap) retrieve(ctx context.Context, s Source) (peers []string, err error) {
@@ -167,6 +169,25 @@ func newBootstrap(options ...BootstrapOption) bootstrap {
 	return b
 }
 
+// wait the backoff for the attempt, or until the context is done. waits for at least
+// the minimum, e.g.) the retry after hint of a throttled join.
+func (t bootstrap) wait(ctx context.Context, attempt int, minimum time.Duration) error {
+	d := t.Backoff.Backoff(attempt)
+	if d < minimum {
+		d = minimum
+	}
+
+	delay := time.NewTimer(d)
+	defer delay.Stop()
+
+	select {
+	case <-ctx.Done():
+		return errors.WithStack(ctx.Err())
+	case <-delay.C:
+		return nil
+	}
+}
+
 // Bootstrap - bootstraps the provided cluster using the options provided.
 func Bootstrap(ctx context.Context, c Joiner, options ...BootstrapOption) (err error) {
 	var (
@@ -188,8 +209,17 @@ func Bootstrap(ctx context.Context, c Joiner, options ...BootstrapOption) (err e
 
 		log.Printf("located %d peers: %s\n", len(peers), spew.Sdump(peers))
 
+		peers = b.synchronized(ctx, peers...)
+
+		metricJoinAttempts.Inc()
+		metricPeersLocated.Observe(float64(len(peers)))
 		if joined, err = c.Join(peers...); err != nil {
+			metricJoinFailures.Inc()
 			log.Println(errors.Wrap(err, "failed to join peers"))
+			hint, _ := RetryAfter(err)
+			if err = b.wait(ctx, attempts, hint); err != nil {
+				return err
+			}
 			continue
 		}
 
@@ -215,7 +245,9 @@ func Bootstrap(ctx context.Context, c Joiner, options ...BootstrapOption) (err e
 			break
 		}
 
-		time.Sleep(b.Backoff.Backoff(attempts))
+		if err = b.wait(ctx, attempts, 0); err != nil {
+			return err
+		}
 	}
 
 	if joined == 0 {
@@ -0,0 +1,95 @@
+package clustering_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/memberlist"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/james-lawrence/bw/backoff"
+	"github.com/james-lawrence/bw/clustering"
+)
+
+// failingJoiner fails the first n joins.
+type failingJoiner struct {
+	failures int
+	joins    int
+}
+
+func (t *failingJoiner) Join(peers ...string) (int, error) {
+	t.joins++
+	if t.joins <= t.failures {
+		return 0, errors.New("join failed")
+	}
+
+	return len(peers), nil
+}
+
+func (t *failingJoiner) Members() []*memberlist.Node {
+	return nil
+}
+
+// recordingBackoff records the attempts it is consulted for.
+type recordingBackoff struct {
+	m        sync.Mutex
+	attempts []int
+}
+
+func (t *recordingBackoff) Backoff(attempt int) time.Duration {
+	t.m.Lock()
+	defer t.m.Unlock()
+	t.attempts = append(t.attempts, attempt)
+	return time.Millisecond
+}
+
+var _ = Describe("Bootstrap", func() {
+	peers := &mutableSource{peers: []string{"127.0.0.1:2000"}}
+
+	It("should backoff between failed joins", func() {
+		b := &recordingBackoff{}
+		j := &failingJoiner{failures: 3}
+		err := clustering.Bootstrap(
+			context.Background(),
+			j,
+			clustering.BootstrapOptionPeeringStrategies(peers),
+			clustering.BootstrapOptionJoinStrategy(clustering.MinimumPeers(1)),
+			clustering.BootstrapOptionBackoff(b),
+		)
+		Expect(err).To(Succeed())
+		Expect(j.joins).To(Equal(4))
+		Expect(b.attempts).To(Equal([]int{0, 1, 2}))
+	})
+
+	It("should retain the maximum attempts", func() {
+		b := &recordingBackoff{}
+		j := &failingJoiner{}
+		err := clustering.Bootstrap(
+			context.Background(),
+			j,
+			clustering.BootstrapOptionPeeringStrategies(peers),
+			clustering.BootstrapOptionJoinStrategy(clustering.MinimumPeers(2)),
+			clustering.BootstrapOptionAllowRetry(clustering.MaximumAttempts(3)),
+			clustering.BootstrapOptionBackoff(b),
+		)
+		Expect(err).To(Succeed())
+		Expect(j.joins).To(Equal(4))
+		Expect(b.attempts).To(Equal([]int{0, 1, 2}))
+	})
+
+	It("should stop waiting when the context is done", func() {
+		ctx, done := context.WithCancel(context.Background())
+		done()
+
+		err := clustering.Bootstrap(
+			ctx,
+			&failingJoiner{failures: 1},
+			clustering.BootstrapOptionPeeringStrategies(peers),
+			clustering.BootstrapOptionBackoff(backoff.Constant(time.Hour)),
+		)
+		Expect(errors.Is(err, context.Canceled)).To(BeTrue())
+	})
+})
@@ -0,0 +1,56 @@
+package clustering
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// ClockDrift measures the drift between the local clock and the clock of the peer at the address.
+type ClockDrift func(ctx context.Context, address string) (time.Duration, error)
+
+// BootstrapOptionMaxClockDrift refuse to join peers whose clock drifts from the local clock by more than max,
+// catches misconfigured time synchronization before it causes credential failures. peers whose clock is
+// unable to be measured are joined. max <= 0 disables the check.
+func BootstrapOptionMaxClockDrift(max time.Duration, measure ClockDrift) BootstrapOption {
+	return func(b *bootstrap) {
+		b.MaxClockDrift = max
+		b.ClockDrift = measure
+	}
+}
+
+// synchronized filters the peers whose clock drifts beyond the maximum.
+func (t bootstrap) synchronized(ctx context.Context, peers ...string) []string {
+	if t.MaxClockDrift <= 0 || t.ClockDrift == nil {
+		return peers
+	}
+
+	allowed := make([]string, 0, len(peers))
+	for _, p := range peers {
+		drift, err := t.measure(ctx, p)
+		if err != nil {
+			log.Println("WARNING: unable to measure the clock drift of peer", p, err)
+			allowed = append(allowed, p)
+			continue
+		}
+
+		if drift < 0 {
+			drift = -drift
+		}
+
+		if drift > t.MaxClockDrift {
+			log.Println("refusing to join peer", p, "clock drift", drift, "exceeds maximum", t.MaxClockDrift)
+			continue
+		}
+
+		allowed = append(allowed, p)
+	}
+
+	return allowed
+}
+
+func (t bootstrap) measure(ctx context.Context, address string) (time.Duration, error) {
+	mctx, done := context.WithTimeout(ctx, 5*time.Second)
+	defer done()
+	return t.ClockDrift(mctx, address)
+}
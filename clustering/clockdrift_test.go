@@ -0,0 +1,71 @@
+package clustering_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/james-lawrence/bw/clustering"
+)
+
+// skewedClocks reports the drift of the peers, unknown peers are unreachable.
+func skewedClocks(drift map[string]time.Duration) clustering.ClockDrift {
+	return func(ctx context.Context, address string) (time.Duration, error) {
+		if d, ok := drift[address]; ok {
+			return d, nil
+		}
+
+		return 0, errors.New("unreachable")
+	}
+}
+
+var _ = Describe("BootstrapOptionMaxClockDrift", func() {
+	bootstrap := func(j clustering.Joiner, max time.Duration, drift map[string]time.Duration, peers ...string) error {
+		return clustering.Bootstrap(
+			context.Background(),
+			j,
+			clustering.BootstrapOptionPeeringStrategies(&mutableSource{peers: peers}),
+			clustering.BootstrapOptionAllowRetry(clustering.MaximumAttempts(0)),
+			clustering.BootstrapOptionMaxClockDrift(max, skewedClocks(drift)),
+		)
+	}
+
+	It("should refuse peers whose clock drifts beyond the maximum", func() {
+		j := &recordingJoiner{}
+		drift := map[string]time.Duration{
+			"127.0.0.1:2000": 10 * time.Second,
+			"127.0.0.2:2000": -time.Minute,
+			"127.0.0.3:2000": time.Minute,
+		}
+
+		Expect(bootstrap(j, 30*time.Second, drift, "127.0.0.1:2000", "127.0.0.2:2000", "127.0.0.3:2000")).To(Succeed())
+		Expect(j.Joins()).To(ConsistOf(ConsistOf("127.0.0.1:2000")))
+	})
+
+	It("should allow peers within the maximum", func() {
+		j := &recordingJoiner{}
+		drift := map[string]time.Duration{
+			"127.0.0.1:2000": 10 * time.Second,
+			"127.0.0.2:2000": -29 * time.Second,
+		}
+
+		Expect(bootstrap(j, 30*time.Second, drift, "127.0.0.1:2000", "127.0.0.2:2000")).To(Succeed())
+		Expect(j.Joins()).To(ConsistOf(ConsistOf("127.0.0.1:2000", "127.0.0.2:2000")))
+	})
+
+	It("should join peers whose clock is unable to be measured", func() {
+		j := &recordingJoiner{}
+		Expect(bootstrap(j, 30*time.Second, nil, "127.0.0.1:2000")).To(Succeed())
+		Expect(j.Joins()).To(ConsistOf(ConsistOf("127.0.0.1:2000")))
+	})
+
+	It("should ignore the drift when disabled", func() {
+		j := &recordingJoiner{}
+		drift := map[string]time.Duration{"127.0.0.1:2000": time.Hour}
+		Expect(bootstrap(j, 0, drift, "127.0.0.1:2000")).To(Succeed())
+		Expect(j.Joins()).To(ConsistOf(ConsistOf("127.0.0.1:2000")))
+	})
+})
@@ -0,0 +1,16 @@
+package clustering_test
+
+import (
+	"io"
+	"log"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestClustering(t *testing.T) {
+	log.SetOutput(io.Discard)
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Clustering Suite")
+}
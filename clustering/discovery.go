@@ -0,0 +1,57 @@
+package clustering
+
+import (
+	"context"
+	"log"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Discover periodically re-queries the peering strategies at the given interval, joining
+// the discovered peers that are not already members of the cluster. allows the cluster to
+// pick up new peers during scaling events without waiting for a snapshot. blocking.
+func Discover(ctx context.Context, c Joiner, interval time.Duration, options ...BootstrapOption) {
+	b := newBootstrap(options...)
+
+	tick := time.NewTicker(interval)
+	defer tick.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-tick.C:
+			discover(ctx, c, b)
+		}
+	}
+}
+
+func discover(ctx context.Context, c Joiner, b bootstrap) {
+	peers, _ := b.collect(ctx, b.Peering...)
+
+	members := make(map[string]struct{}, len(c.Members()))
+	for _, m := range c.Members() {
+		members[net.JoinHostPort(m.Addr.String(), strconv.Itoa(int(m.Port)))] = struct{}{}
+	}
+
+	discovered := make([]string, 0, len(peers))
+	for _, p := range peers {
+		if _, ok := members[p]; ok {
+			continue
+		}
+
+		discovered = append(discovered, p)
+	}
+
+	if discovered = b.synchronized(ctx, discovered...); len(discovered) == 0 {
+		return
+	}
+
+	log.Println("discovered", len(discovered), "new peers", discovered)
+	if _, err := c.Join(discovered...); err != nil {
+		log.Println(errors.Wrap(err, "failed to join discovered peers"))
+	}
+}
@@ -0,0 +1,107 @@
+package clustering_test
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/memberlist"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/james-lawrence/bw/clustering"
+)
+
+// recordingJoiner members are the peers it has joined.
+type recordingJoiner struct {
+	m       sync.Mutex
+	members []*memberlist.Node
+	joins   [][]string
+}
+
+func (t *recordingJoiner) Join(peers ...string) (int, error) {
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	t.joins = append(t.joins, peers)
+	for _, p := range peers {
+		host, port, _ := net.SplitHostPort(p)
+		n, _ := strconv.Atoi(port)
+		t.members = append(t.members, &memberlist.Node{Name: p, Addr: net.ParseIP(host), Port: uint16(n)})
+	}
+
+	return len(t.members), nil
+}
+
+func (t *recordingJoiner) Members() []*memberlist.Node {
+	t.m.Lock()
+	defer t.m.Unlock()
+	return append([]*memberlist.Node(nil), t.members...)
+}
+
+func (t *recordingJoiner) Joins() [][]string {
+	t.m.Lock()
+	defer t.m.Unlock()
+	return append([][]string(nil), t.joins...)
+}
+
+// mutableSource peers can be changed while discovery is running.
+type mutableSource struct {
+	m     sync.Mutex
+	peers []string
+}
+
+func (t *mutableSource) Add(peers ...string) {
+	t.m.Lock()
+	defer t.m.Unlock()
+	t.peers = append(t.peers, peers...)
+}
+
+func (t *mutableSource) Peers(context.Context) ([]string, error) {
+	t.m.Lock()
+	defer t.m.Unlock()
+	return append([]string(nil), t.peers...), nil
+}
+
+var _ = Describe("Discover", func() {
+	It("should join peers added to the sources within the interval", func() {
+		const interval = 50 * time.Millisecond
+		ctx, done := context.WithCancel(context.Background())
+		defer done()
+
+		source := &mutableSource{peers: []string{"127.0.0.1:2000"}}
+		c := &recordingJoiner{}
+		_, err := c.Join("127.0.0.1:2000")
+		Expect(err).To(Succeed())
+
+		go clustering.Discover(ctx, c, interval, clustering.BootstrapOptionPeeringStrategies(source))
+
+		// existing members are never rejoined.
+		Consistently(c.Joins, 3*interval).Should(HaveLen(1))
+
+		source.Add("127.0.0.2:2000")
+		Eventually(c.Joins, 3*interval).Should(ContainElement([]string{"127.0.0.2:2000"}))
+		Consistently(c.Joins, 3*interval).Should(HaveLen(2))
+	})
+
+	It("should not join banned peers", func() {
+		const interval = 20 * time.Millisecond
+		ctx, done := context.WithCancel(context.Background())
+		defer done()
+
+		source := &mutableSource{peers: []string{"127.0.0.1:2000"}}
+		c := &recordingJoiner{}
+
+		go clustering.Discover(
+			ctx,
+			c,
+			interval,
+			clustering.BootstrapOptionPeeringStrategies(source),
+			clustering.BootstrapOptionBanned("127.0.0.1:2000"),
+		)
+
+		Consistently(c.Joins, 5*interval).Should(BeEmpty())
+	})
+})
@@ -0,0 +1,182 @@
+package clustering
+
+import (
+	"context"
+	"sync"
+
+	"github.com/hashicorp/memberlist"
+)
+
+// MembershipEventType the kind of change to the membership of the cluster.
+type MembershipEventType int
+
+// the changes to the membership of the cluster.
+const (
+	MembershipJoined MembershipEventType = iota
+	// the node left the cluster, memberlist doesn't distinguish nodes that
+	// gracefully left from nodes that failed.
+	MembershipLeft
+	MembershipUpdated
+)
+
+func (t MembershipEventType) String() string {
+	switch t {
+	case MembershipJoined:
+		return "joined"
+	case MembershipLeft:
+		return "left"
+	default:
+		return "updated"
+	}
+}
+
+// MembershipEvent a change to the membership of the cluster.
+type MembershipEvent struct {
+	Type MembershipEventType
+	Node *memberlist.Node
+}
+
+// NewEventDelegates fans out the memberlist events to each of the delegates in order.
+func NewEventDelegates(delegates ...memberlist.EventDelegate) memberlist.EventDelegate {
+	return eventDelegates(delegates)
+}
+
+type eventDelegates []memberlist.EventDelegate
+
+func (t eventDelegates) NotifyJoin(n *memberlist.Node) {
+	for _, d := range t {
+		d.NotifyJoin(n)
+	}
+}
+
+func (t eventDelegates) NotifyLeave(n *memberlist.Node) {
+	for _, d := range t {
+		d.NotifyLeave(n)
+	}
+}
+
+func (t eventDelegates) NotifyUpdate(n *memberlist.Node) {
+	for _, d := range t {
+		d.NotifyUpdate(n)
+	}
+}
+
+// NewEventBus delivers the membership changes of the cluster to subscribers,
+// fed by the memberlist event delegate.
+func NewEventBus() *EventBus {
+	return &EventBus{
+		subscriptions: make(map[*Subscription]struct{}),
+	}
+}
+
+// EventBus delivers the membership changes of the cluster to subscribers. events are
+// delivered at least once, a change that occurs while subscribing is delivered in addition
+// to being reflected in the snapshot of the subscription.
+type EventBus struct {
+	m             sync.Mutex
+	subscriptions map[*Subscription]struct{}
+}
+
+// NotifyJoin implements memberlist.EventDelegate.
+func (t *EventBus) NotifyJoin(n *memberlist.Node) {
+	t.publish(MembershipEvent{Type: MembershipJoined, Node: n})
+}
+
+// NotifyLeave implements memberlist.EventDelegate.
+func (t *EventBus) NotifyLeave(n *memberlist.Node) {
+	t.publish(MembershipEvent{Type: MembershipLeft, Node: n})
+}
+
+// NotifyUpdate implements memberlist.EventDelegate.
+func (t *EventBus) NotifyUpdate(n *memberlist.Node) {
+	t.publish(MembershipEvent{Type: MembershipUpdated, Node: n})
+}
+
+// memberlist forbids modifying the node, publishing a copy allows the
+// subscribers to retain the events.
+func (t *EventBus) publish(evt MembershipEvent) {
+	dup := *evt.Node
+	evt.Node = &dup
+
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	for s := range t.subscriptions {
+		s.push(evt)
+	}
+}
+
+// Subscribe to the membership changes of the cluster, the subscription begins
+// with a snapshot of the members. the subscription must be closed.
+func (t *EventBus) Subscribe(c interface{ Members() []*memberlist.Node }) *Subscription {
+	s := &Subscription{
+		bus:    t,
+		notify: make(chan struct{}, 1),
+	}
+
+	// subscribe before taking the snapshot so changes made in between are never missed.
+	t.m.Lock()
+	t.subscriptions[s] = struct{}{}
+	t.m.Unlock()
+
+	s.snapshot = c.Members()
+
+	return s
+}
+
+func (t *EventBus) unsubscribe(s *Subscription) {
+	t.m.Lock()
+	defer t.m.Unlock()
+	delete(t.subscriptions, s)
+}
+
+// Subscription to the membership changes of the cluster.
+type Subscription struct {
+	bus      *EventBus
+	snapshot []*memberlist.Node
+	notify   chan struct{}
+	m        sync.Mutex
+	pending  []MembershipEvent
+}
+
+// Snapshot the members of the cluster when the subscription began.
+func (t *Subscription) Snapshot() []*memberlist.Node {
+	return t.snapshot
+}
+
+// Next change to the membership of the cluster, blocks until a change occurs
+// or the context is done.
+func (t *Subscription) Next(ctx context.Context) (evt MembershipEvent, err error) {
+	for {
+		t.m.Lock()
+		if len(t.pending) > 0 {
+			evt, t.pending = t.pending[0], t.pending[1:]
+			t.m.Unlock()
+			return evt, nil
+		}
+		t.m.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return evt, ctx.Err()
+		case <-t.notify:
+		}
+	}
+}
+
+// Close the subscription, no further changes are delivered.
+func (t *Subscription) Close() {
+	t.bus.unsubscribe(t)
+}
+
+// push the event, changes are never dropped while subscribed.
+func (t *Subscription) push(evt MembershipEvent) {
+	t.m.Lock()
+	t.pending = append(t.pending, evt)
+	t.m.Unlock()
+
+	select {
+	case t.notify <- struct{}{}:
+	default:
+	}
+}
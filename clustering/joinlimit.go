@@ -0,0 +1,339 @@
+package clustering
+
+import (
+	"bytes"
+	"compress/lzw"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-msgpack/codec"
+	"github.com/hashicorp/memberlist"
+	"golang.org/x/time/rate"
+)
+
+// JoinThrottled reported to peers joining while the seed is over its join rate.
+// delivered to the joining peer as a remote error by memberlist, see RetryAfter.
+type JoinThrottled struct {
+	RetryAfter time.Duration
+}
+
+func (t JoinThrottled) Error() string {
+	return fmt.Sprintf("join throttled, retry after %s", t.RetryAfter)
+}
+
+var retryAfterPattern = regexp.MustCompile(`join throttled, retry after ([0-9.]+[a-zµ]+)`)
+
+// RetryAfter the largest retry after hint within the join error, false if the join wasn't throttled.
+func RetryAfter(err error) (d time.Duration, ok bool) {
+	if err == nil {
+		return 0, false
+	}
+
+	for _, m := range retryAfterPattern.FindAllStringSubmatch(err.Error(), -1) {
+		if hint, perr := time.ParseDuration(m[1]); perr == nil && hint >= d {
+			d, ok = hint, true
+		}
+	}
+
+	return d, ok
+}
+
+type memberset interface {
+	Members() []*memberlist.Node
+}
+
+// NewJoinLimiter token bucket limiting the joins accepted per second by a seed node,
+// joins beyond the rate are rejected with a retry after hint. the keyring decrypts the
+// gossip of the joining peers, nil when the gossip is unencrypted. returns nil when the
+// rate is <= 0, a nil limiter accepts every join.
+func NewJoinLimiter(perSecond int, keyring *memberlist.Keyring) *JoinLimiter {
+	if perSecond <= 0 {
+		return nil
+	}
+
+	return &JoinLimiter{
+		limiter: rate.NewLimiter(rate.Limit(perSecond), perSecond),
+		keyring: keyring,
+	}
+}
+
+// JoinLimiter limits the rate peers join through the local node.
+type JoinLimiter struct {
+	limiter *rate.Limiter
+	keyring *memberlist.Keyring
+	m       sync.RWMutex
+	members memberset
+}
+
+// Track the members of the cluster, connections from members are never throttled.
+func (t *JoinLimiter) Track(c memberset) {
+	if t == nil {
+		return
+	}
+
+	t.m.Lock()
+	defer t.m.Unlock()
+	t.members = c
+}
+
+// Allow a join at the given time, returns the duration to wait when throttled.
+func (t *JoinLimiter) Allow(now time.Time) (time.Duration, bool) {
+	if t == nil {
+		return 0, true
+	}
+
+	r := t.limiter.ReserveN(now, 1)
+	if delay := r.DelayFrom(now); delay > 0 {
+		r.CancelAt(now)
+		return delay, false
+	}
+
+	return 0, true
+}
+
+// Filter the incoming gossip streams, join requests from nodes that aren't members of the cluster
+// consume the rate. members are matched by the names of the nodes within the join request, their
+// address is irrelevant. throttled streams fail with JoinThrottled, memberlist returns the error
+// to the joining peer. the stream is inspected once memberlist reads from it.
+func (t *JoinLimiter) Filter(conn net.Conn) net.Conn {
+	if t == nil {
+		return conn
+	}
+
+	return &joinConn{Conn: conn, limiter: t}
+}
+
+// inspect the join request at the start of the stream, returns the stream to hand to memberlist.
+func (t *JoinLimiter) inspect(conn net.Conn) io.Reader {
+	var (
+		keys [][]byte
+	)
+
+	if t.keyring != nil {
+		keys = t.keyring.GetKeys()
+	}
+
+	recorded := bytes.NewBuffer(nil)
+	names, join := joining(io.TeeReader(conn, recorded), keys)
+	replay := io.MultiReader(recorded, conn)
+
+	if !join || t.member(names...) {
+		return replay
+	}
+
+	delay, ok := t.Allow(time.Now())
+	if ok {
+		return replay
+	}
+
+	log.Println("throttling join", names, conn.RemoteAddr(), "retry after", delay)
+
+	// memberlist only reports errors reading the message to the peer, the label and type
+	// of the message are replayed before failing.
+	prefix := recorded.Bytes()[:1]
+	if prefix[0] == msgLabel {
+		prefix = recorded.Bytes()[:3+int(recorded.Bytes()[1])]
+	}
+
+	return io.MultiReader(bytes.NewReader(prefix), failedReader{cause: JoinThrottled{RetryAfter: delay}})
+}
+
+// member checks if every one of the named nodes is a member of the cluster.
+func (t *JoinLimiter) member(names ...string) bool {
+	t.m.RLock()
+	defer t.m.RUnlock()
+
+	if t.members == nil {
+		return false
+	}
+
+	members := map[string]bool{}
+	for _, n := range t.members.Members() {
+		members[n.Name] = true
+	}
+
+	for _, name := range names {
+		if !members[name] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// joinConn inspects the join request of the stream on the first read, reads of throttled
+// streams fail and the error is written back to the peer by memberlist.
+type joinConn struct {
+	net.Conn
+	limiter *JoinLimiter
+	once    sync.Once
+	r       io.Reader
+}
+
+func (t *joinConn) Read(b []byte) (int, error) {
+	t.once.Do(func() {
+		t.r = t.limiter.inspect(t.Conn)
+	})
+
+	return t.r.Read(b)
+}
+
+type failedReader struct {
+	cause error
+}
+
+func (t failedReader) Read([]byte) (int, error) {
+	return 0, t.cause
+}
+
+// memberlist wire format, see github.com/hashicorp/memberlist/net.go.
+const (
+	msgPushPull   = 6
+	msgCompress   = 9
+	msgEncrypt    = 10
+	msgLabel      = 244
+	maxStateBytes = 20 * 1024 * 1024
+)
+
+// joining decodes the names of the nodes within the memberlist push/pull at the start of the stream,
+// returns false when the stream isn't a join request or can't be decoded.
+func joining(r io.Reader, keys [][]byte) (names []string, join bool) {
+	var (
+		header struct {
+			Nodes int
+			Join  bool
+		}
+		state struct {
+			Name string
+		}
+		label []byte
+		buf   = make([]byte, 2)
+	)
+
+	if _, err := io.ReadFull(r, buf[:1]); err != nil {
+		return nil, false
+	}
+
+	if buf[0] == msgLabel {
+		if _, err := io.ReadFull(r, buf[1:2]); err != nil {
+			return nil, false
+		}
+
+		label = make([]byte, buf[1])
+		if _, err := io.ReadFull(r, label); err != nil {
+			return nil, false
+		}
+
+		if _, err := io.ReadFull(r, buf[:1]); err != nil {
+			return nil, false
+		}
+	}
+
+	msgType, plain := buf[0], r
+	if msgType == msgEncrypt {
+		decrypted, err := decryptState(r, label, keys)
+		if err != nil || len(decrypted) == 0 {
+			return nil, false
+		}
+
+		msgType, plain = decrypted[0], bytes.NewReader(decrypted[1:])
+	}
+
+	h := codec.MsgpackHandle{}
+	dec := codec.NewDecoder(plain, &h)
+
+	if msgType == msgCompress {
+		var c struct {
+			Algo int
+			Buf  []byte
+		}
+
+		if err := dec.Decode(&c); err != nil || c.Algo != 0 {
+			return nil, false
+		}
+
+		decompressed, err := io.ReadAll(io.LimitReader(lzw.NewReader(bytes.NewReader(c.Buf), lzw.LSB, 8), maxStateBytes))
+		if err != nil || len(decompressed) == 0 {
+			return nil, false
+		}
+
+		msgType = decompressed[0]
+		dec = codec.NewDecoder(bytes.NewReader(decompressed[1:]), &h)
+	}
+
+	if msgType != msgPushPull {
+		return nil, false
+	}
+
+	if err := dec.Decode(&header); err != nil || !header.Join {
+		return nil, false
+	}
+
+	for i := 0; i < header.Nodes; i++ {
+		if err := dec.Decode(&state); err != nil {
+			return nil, false
+		}
+
+		names = append(names, state.Name)
+	}
+
+	return names, true
+}
+
+// decryptState decrypts the encrypted memberlist state following the message type.
+func decryptState(r io.Reader, label []byte, keys [][]byte) ([]byte, error) {
+	data := make([]byte, 5)
+	data[0] = msgEncrypt
+	if _, err := io.ReadFull(r, data[1:]); err != nil {
+		return nil, err
+	}
+
+	size := binary.BigEndian.Uint32(data[1:])
+	if size > maxStateBytes {
+		return nil, fmt.Errorf("remote state is larger than limit: %d", size)
+	}
+
+	msg := make([]byte, size)
+	if _, err := io.ReadFull(r, msg); err != nil {
+		return nil, err
+	}
+
+	// version, nonce, and the tag of the ciphertext.
+	if len(msg) < 1+12+16 {
+		return nil, fmt.Errorf("remote state is too small to decrypt: %d", len(msg))
+	}
+
+	for _, key := range keys {
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			continue
+		}
+
+		gcm, err := cipher.NewGCM(block)
+		if err != nil {
+			continue
+		}
+
+		plain, err := gcm.Open(nil, msg[1:13], msg[13:], append(data, label...))
+		if err != nil {
+			continue
+		}
+
+		// version 0 pads the plaintext, see PKCS7.
+		if msg[0] == 0 && len(plain) > 0 && int(plain[len(plain)-1]) <= len(plain) {
+			plain = plain[:len(plain)-int(plain[len(plain)-1])]
+		}
+
+		return plain, nil
+	}
+
+	return nil, fmt.Errorf("no installed keys could decrypt the remote state")
+}
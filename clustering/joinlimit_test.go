@@ -0,0 +1,187 @@
+package clustering_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/hashicorp/memberlist"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/james-lawrence/bw/backoff"
+	"github.com/james-lawrence/bw/clustering"
+	"github.com/james-lawrence/bw/internal/memberlistx"
+)
+
+// limitedSeed seed node accepting joins at the rate of the limiter, throttled joins
+// fail the same way memberlist reports the remote error.
+type limitedSeed struct {
+	limiter   *clustering.JoinLimiter
+	accepted  int32
+	throttled int32
+}
+
+func (t *limitedSeed) joiner() clustering.Joiner {
+	return seedJoiner{seed: t}
+}
+
+type seedJoiner struct {
+	seed *limitedSeed
+}
+
+func (t seedJoiner) Join(peers ...string) (int, error) {
+	if delay, ok := t.seed.limiter.Allow(time.Now()); !ok {
+		atomic.AddInt32(&t.seed.throttled, 1)
+		return 0, fmt.Errorf("Failed to join %s: remote error: %v", peers[0], clustering.JoinThrottled{RetryAfter: delay})
+	}
+
+	atomic.AddInt32(&t.seed.accepted, 1)
+	return 1, nil
+}
+
+func (t seedJoiner) Members() []*memberlist.Node {
+	return nil
+}
+
+type staticMembers []*memberlist.Node
+
+func (t staticMembers) Members() []*memberlist.Node {
+	return t
+}
+
+// gossipNode member of a cluster on the loopback, the joins accepted by the node are limited.
+func gossipNode(name string, keyring *memberlist.Keyring, limiter *clustering.JoinLimiter) clustering.Memberlist {
+	streams, err := net.Listen("tcp", "127.0.0.1:0")
+	Expect(err).To(Succeed())
+
+	packets, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.ParseIP("127.0.0.1"), Port: streams.Addr().(*net.TCPAddr).Port})
+	Expect(err).To(Succeed())
+
+	transport, err := memberlistx.NewSWIMTransport(
+		&net.Dialer{},
+		memberlistx.SWIMStreams(streams),
+		memberlistx.SWIMPackets(packets),
+		memberlistx.SWIMStreamFilter(limiter.Filter),
+	)
+	Expect(err).To(Succeed())
+
+	c, err := clustering.NewCluster(
+		clustering.OptionNodeID(name),
+		clustering.OptionKeyring(keyring),
+		clustering.OptionTransport(transport),
+		clustering.OptionLogOutput(io.Discard),
+	)
+	Expect(err).To(Succeed())
+	DeferCleanup(c.ForceShutdown)
+	limiter.Track(c)
+
+	return c
+}
+
+var _ = Describe("JoinLimiter", func() {
+	It("should enforce the rate", func() {
+		now := time.Now()
+		l := clustering.NewJoinLimiter(5, nil)
+
+		for i := 0; i < 5; i++ {
+			_, ok := l.Allow(now)
+			Expect(ok).To(BeTrue())
+		}
+
+		delay, ok := l.Allow(now)
+		Expect(ok).To(BeFalse())
+		Expect(delay).To(BeNumerically("~", 200*time.Millisecond, time.Millisecond))
+
+		// throttled joins don't consume the rate.
+		delay, ok = l.Allow(now)
+		Expect(ok).To(BeFalse())
+		Expect(delay).To(BeNumerically("~", 200*time.Millisecond, time.Millisecond))
+
+		_, ok = l.Allow(now.Add(200 * time.Millisecond))
+		Expect(ok).To(BeTrue())
+	})
+
+	It("should accept every join when disabled", func() {
+		l := clustering.NewJoinLimiter(0, nil)
+		for i := 0; i < 100; i++ {
+			_, ok := l.Allow(time.Now())
+			Expect(ok).To(BeTrue())
+		}
+	})
+
+	It("should parse the retry after hint from the join error", func() {
+		err := fmt.Errorf("1 error occurred:\n\t* Failed to join 10.0.0.1:2000: remote error: %v\n\t* Failed to join 10.0.0.2:2000: remote error: %v",
+			clustering.JoinThrottled{RetryAfter: 200 * time.Millisecond},
+			clustering.JoinThrottled{RetryAfter: 1500 * time.Millisecond},
+		)
+
+		d, ok := clustering.RetryAfter(err)
+		Expect(ok).To(BeTrue())
+		Expect(d).To(Equal(1500 * time.Millisecond))
+
+		_, ok = clustering.RetryAfter(fmt.Errorf("connection refused"))
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should throttle the joins of nodes that aren't members by their name", func() {
+		keyring, err := memberlist.NewKeyring(nil, make([]byte, 32))
+		Expect(err).To(Succeed())
+
+		seed := gossipNode("seed", keyring, clustering.NewJoinLimiter(1, keyring))
+		address := seed.LocalNode().Address()
+
+		// every node shares the address of the seed, only their names distinguish them.
+		node1 := gossipNode("node1", keyring, nil)
+		_, err = node1.Join(address)
+		Expect(err).To(Succeed())
+
+		_, err = gossipNode("node2", keyring, nil).Join(address)
+		Expect(err).To(MatchError(ContainSubstring("join throttled")))
+		_, throttled := clustering.RetryAfter(err)
+		Expect(throttled).To(BeTrue())
+
+		// members rejoin regardless of the rate.
+		for i := 0; i < 3; i++ {
+			_, err = node1.Join(address)
+			Expect(err).To(Succeed())
+		}
+	})
+
+	It("should respect the retry after hint when a burst of peers join", func() {
+		const (
+			rate    = 5
+			joiners = 10
+		)
+
+		seed := &limitedSeed{limiter: clustering.NewJoinLimiter(rate, nil)}
+		started := time.Now()
+
+		var wg sync.WaitGroup
+		for i := 0; i < joiners; i++ {
+			wg.Add(1)
+			go func() {
+				defer GinkgoRecover()
+				defer wg.Done()
+
+				Expect(clustering.Bootstrap(
+					context.Background(),
+					seed.joiner(),
+					clustering.BootstrapOptionPeeringStrategies(&mutableSource{peers: []string{"127.0.0.1:2000"}}),
+					clustering.BootstrapOptionBackoff(backoff.Constant(time.Millisecond)),
+				)).To(Succeed())
+			}()
+		}
+		wg.Wait()
+
+		Expect(atomic.LoadInt32(&seed.accepted)).To(Equal(int32(joiners)))
+		// the burst is accepted immediately, the remainder at the rate.
+		Expect(time.Since(started)).To(BeNumerically(">=", (joiners-rate-1)*time.Second/rate))
+		// without the hint each joiner would retry every millisecond.
+		Expect(atomic.LoadInt32(&seed.throttled)).To(BeNumerically("<", 4*joiners))
+	})
+})
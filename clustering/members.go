@@ -0,0 +1,94 @@
+package clustering
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/memberlist"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/james-lawrence/bw/agent"
+)
+
+// MemberInfo diagnostic representation of a member of the cluster.
+type MemberInfo struct {
+	Name     string            `json:"name"`
+	Address  string            `json:"address"`
+	Status   string            `json:"status"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// Members describe the members of the cluster.
+func Members(c Rendezvous) []MemberInfo {
+	nodes := c.Members()
+	members := make([]MemberInfo, 0, len(nodes))
+	for _, n := range nodes {
+		members = append(members, NewMemberInfo(n))
+	}
+
+	return members
+}
+
+// NewMemberInfo from the memberlist node, the labels and metadata are decoded
+// from the agent metadata the node gossips. nodes with undecodable metadata are
+// reported without them.
+func NewMemberInfo(n *memberlist.Node) MemberInfo {
+	var (
+		m agent.PeerMetadata
+	)
+
+	info := MemberInfo{
+		Name:    n.Name,
+		Address: n.Address(),
+		Status:  NodeStatus(n.State),
+	}
+
+	if len(n.Meta) == 0 {
+		return info
+	}
+
+	if err := proto.Unmarshal(n.Meta, &m); err != nil {
+		return info
+	}
+
+	info.Labels = map[string]string{
+		"role": strings.ToLower(agent.Peer_State(m.Status).String()),
+	}
+	info.Metadata = map[string]string{
+		"p2pPort": strconv.FormatUint(uint64(m.P2PPort), 10),
+	}
+
+	if len(m.Capability) > 0 {
+		info.Metadata["capability"] = hex.EncodeToString(m.Capability)
+	}
+
+	return info
+}
+
+// NodeStatus human readable status of the node.
+func NodeStatus(s memberlist.NodeStateType) string {
+	switch s {
+	case memberlist.StateAlive:
+		return "alive"
+	case memberlist.StateSuspect:
+		return "suspect"
+	case memberlist.StateDead:
+		return "dead"
+	case memberlist.StateLeft:
+		return "left"
+	default:
+		return "unknown"
+	}
+}
+
+// WriteMembersJSON write the members of the cluster as json.
+func WriteMembersJSON(dst io.Writer, c Rendezvous) error {
+	enc := json.NewEncoder(dst)
+	enc.SetIndent("", "  ")
+	return errors.WithStack(enc.Encode(Members(c)))
+}
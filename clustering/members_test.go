@@ -0,0 +1,52 @@
+package clustering_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net"
+
+	"github.com/hashicorp/memberlist"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/james-lawrence/bw/agent"
+	"github.com/james-lawrence/bw/clustering"
+)
+
+var _ = Describe("Members", func() {
+	It("should serialize each member of the cluster", func() {
+		var (
+			buf     bytes.Buffer
+			decoded []clustering.MemberInfo
+			local   = agent.PeerToNode(&agent.Peer{Name: "local", Ip: "127.0.0.1", P2PPort: 2000})
+			client  = agent.PeerToNode(&agent.Peer{Name: "client", Ip: "10.0.0.3", P2PPort: 2002, Status: agent.Peer_Client})
+			peer    = &memberlist.Node{Name: "peer", Addr: net.ParseIP("10.0.0.2"), Port: 2001, State: memberlist.StateSuspect}
+		)
+
+		client.State = memberlist.StateLeft
+
+		Expect(clustering.WriteMembersJSON(&buf, clustering.NewMock(local, peer, client))).To(Succeed())
+		Expect(json.Unmarshal(buf.Bytes(), &decoded)).To(Succeed())
+		Expect(decoded).To(Equal([]clustering.MemberInfo{
+			{
+				Name:    "peer",
+				Address: "10.0.0.2:2001",
+				Status:  "suspect",
+			},
+			{
+				Name:     "client",
+				Address:  "10.0.0.3:2002",
+				Status:   "left",
+				Labels:   map[string]string{"role": "client"},
+				Metadata: map[string]string{"p2pPort": "2002"},
+			},
+			{
+				Name:     "local",
+				Address:  "127.0.0.1:2000",
+				Status:   "alive",
+				Labels:   map[string]string{"role": "node"},
+				Metadata: map[string]string{"p2pPort": "2000"},
+			},
+		}))
+	})
+})
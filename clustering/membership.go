@@ -0,0 +1,90 @@
+package clustering
+
+import (
+	"context"
+	"log"
+
+	"github.com/hashicorp/memberlist"
+	"google.golang.org/grpc"
+
+	"github.com/james-lawrence/bw/agent"
+)
+
+type auth interface {
+	Deploy(ctx context.Context) error
+}
+
+// NewMembershipService streams the membership changes of the cluster to subscribers,
+// e.g.) dashboards and load balancers.
+func NewMembershipService(a auth, c interface{ Members() []*memberlist.Node }, bus *EventBus) MembershipService {
+	return MembershipService{
+		auth: a,
+		c:    c,
+		bus:  bus,
+	}
+}
+
+// MembershipService implements agent.MembershipServer.
+type MembershipService struct {
+	agent.UnimplementedMembershipServer
+	auth auth
+	c    interface{ Members() []*memberlist.Node }
+	bus  *EventBus
+}
+
+// Bind to a grpc server.
+func (t MembershipService) Bind(srv *grpc.Server) {
+	agent.RegisterMembershipServer(srv, t)
+}
+
+// Watch the membership of the cluster, the snapshot of the members is sent before the changes.
+func (t MembershipService) Watch(req *agent.MembershipRequest, stream agent.Membership_WatchServer) (err error) {
+	if err = t.auth.Deploy(stream.Context()); err != nil {
+		return err
+	}
+
+	s := t.bus.Subscribe(t.c)
+	defer s.Close()
+
+	if err = stream.Send(&agent.MembershipEvent{Type: agent.MembershipEvent_Snapshot, Peers: peers(s.Snapshot()...)}); err != nil {
+		return err
+	}
+
+	for {
+		evt, err := s.Next(stream.Context())
+		if err != nil {
+			return err
+		}
+
+		if err = stream.Send(&agent.MembershipEvent{Type: membershipType(evt.Type), Peers: peers(evt.Node)}); err != nil {
+			return err
+		}
+	}
+}
+
+func membershipType(t MembershipEventType) agent.MembershipEvent_Type {
+	switch t {
+	case MembershipJoined:
+		return agent.MembershipEvent_Joined
+	case MembershipLeft:
+		return agent.MembershipEvent_Left
+	default:
+		return agent.MembershipEvent_Updated
+	}
+}
+
+// peers converts the nodes, nodes that aren't agents are logged and skipped.
+func peers(nodes ...*memberlist.Node) []*agent.Peer {
+	r := make([]*agent.Peer, 0, len(nodes))
+	for _, n := range nodes {
+		p, err := agent.NodeToPeer(n)
+		if err != nil {
+			log.Println("unable to convert node to peer", n.Name, err)
+			continue
+		}
+
+		r = append(r, p)
+	}
+
+	return r
+}
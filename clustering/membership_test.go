@@ -0,0 +1,105 @@
+package clustering_test
+
+import (
+	"context"
+	"net"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/james-lawrence/bw/agent"
+	"github.com/james-lawrence/bw/clustering"
+)
+
+type allowauth struct{}
+
+func (allowauth) Deploy(context.Context) error { return nil }
+
+var _ = Describe("MembershipService", func() {
+	names := func(evt *agent.MembershipEvent) (r []string) {
+		for _, p := range evt.Peers {
+			r = append(r, p.Name)
+		}
+		return r
+	}
+
+	It("should stream the snapshot followed by the membership changes", func() {
+		var (
+			local  = agent.NewPeer("node1")
+			joined = agent.PeerToNode(agent.NewPeer("node3"))
+			bus    = clustering.NewEventBus()
+		)
+
+		c := clustering.NewMock(agent.PeerToNode(local), agent.PeerToNode(agent.NewPeer("node2")))
+
+		socket, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).To(Succeed())
+
+		srv := grpc.NewServer()
+		DeferCleanup(srv.Stop)
+		clustering.NewMembershipService(allowauth{}, c, bus).Bind(srv)
+		go srv.Serve(socket)
+
+		conn, err := grpc.Dial(socket.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+		Expect(err).To(Succeed())
+		DeferCleanup(conn.Close)
+
+		ctx, done := context.WithCancel(context.Background())
+		DeferCleanup(done)
+
+		events := make(chan *agent.MembershipEvent, 10)
+		go agent.NewConn(conn).Membership(ctx, events)
+
+		var snapshot *agent.MembershipEvent
+		Eventually(events).Should(Receive(&snapshot))
+		Expect(snapshot.Type).To(Equal(agent.MembershipEvent_Snapshot))
+		Expect(names(snapshot)).To(ConsistOf("node1", "node2"))
+
+		bus.NotifyJoin(joined)
+		bus.NotifyUpdate(joined)
+		bus.NotifyLeave(joined)
+
+		for _, expected := range []agent.MembershipEvent_Type{agent.MembershipEvent_Joined, agent.MembershipEvent_Updated, agent.MembershipEvent_Left} {
+			var evt *agent.MembershipEvent
+			Eventually(events).Should(Receive(&evt))
+			Expect(evt.Type).To(Equal(expected))
+			Expect(names(evt)).To(Equal([]string{"node3"}))
+		}
+	})
+})
+
+var _ = Describe("EventBus", func() {
+	It("should retain the changes until they're consumed", func() {
+		bus := clustering.NewEventBus()
+		s := bus.Subscribe(clustering.NewMock(agent.PeerToNode(agent.NewPeer("node1"))))
+		defer s.Close()
+
+		Expect(s.Snapshot()).To(HaveLen(1))
+
+		for _, name := range []string{"node2", "node3", "node4"} {
+			bus.NotifyJoin(agent.PeerToNode(agent.NewPeer(name)))
+		}
+
+		for _, name := range []string{"node2", "node3", "node4"} {
+			evt, err := s.Next(context.Background())
+			Expect(err).To(Succeed())
+			Expect(evt.Type).To(Equal(clustering.MembershipJoined))
+			Expect(evt.Node.Name).To(Equal(name))
+		}
+	})
+
+	It("should stop delivering changes once closed", func() {
+		bus := clustering.NewEventBus()
+		s := bus.Subscribe(clustering.NewMock(agent.PeerToNode(agent.NewPeer("node1"))))
+		s.Close()
+
+		bus.NotifyJoin(agent.PeerToNode(agent.NewPeer("node2")))
+
+		ctx, done := context.WithCancel(context.Background())
+		done()
+		_, err := s.Next(ctx)
+		Expect(err).To(MatchError(context.Canceled))
+	})
+})
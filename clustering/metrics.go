@@ -0,0 +1,47 @@
+package clustering
+
+import (
+	"github.com/hashicorp/memberlist"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	metricPeersJoined = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "bw_peers_joined_total",
+		Help: "peers that joined the cluster.",
+	})
+	metricPeersLeft = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "bw_peers_left_total",
+		Help: "peers that left or failed out of the cluster.",
+	})
+	metricJoinAttempts = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "bw_bootstrap_join_attempts_total",
+		Help: "attempts to join the cluster while bootstrapping.",
+	})
+	metricJoinFailures = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "bw_bootstrap_join_failures_total",
+		Help: "attempts to join the cluster while bootstrapping that failed.",
+	})
+	metricPeersLocated = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "bw_bootstrap_peers_located",
+		Help:    "peers located by the bootstrap sources for each join attempt.",
+		Buckets: []float64{0, 1, 3, 5, 10, 25, 50, 100},
+	})
+)
+
+// EventMetrics records the membership changes of the cluster, see NewEventDelegates.
+type EventMetrics struct{}
+
+// NotifyJoin implements memberlist.EventDelegate.
+func (EventMetrics) NotifyJoin(*memberlist.Node) {
+	metricPeersJoined.Inc()
+}
+
+// NotifyLeave implements memberlist.EventDelegate.
+func (EventMetrics) NotifyLeave(*memberlist.Node) {
+	metricPeersLeft.Inc()
+}
+
+// NotifyUpdate implements memberlist.EventDelegate.
+func (EventMetrics) NotifyUpdate(*memberlist.Node) {}
@@ -121,6 +121,35 @@ func OptionTransport(transport memberlist.Transport) Option {
 	}
 }
 
+// OptionProbeInterval specify the interval between the failure detection probes of the peers, <= 0 is ignored.
+func OptionProbeInterval(d time.Duration) Option {
+	return func(opts *Options) {
+		if d > 0 {
+			opts.Config.ProbeInterval = d
+		}
+	}
+}
+
+// OptionProbeTimeout specify the duration to wait for a probed peer to acknowledge the probe before
+// falling back to indirect probes. high latency links require a larger timeout, <= 0 is ignored.
+func OptionProbeTimeout(d time.Duration) Option {
+	return func(opts *Options) {
+		if d > 0 {
+			opts.Config.ProbeTimeout = d
+		}
+	}
+}
+
+// OptionSuspicionMult specify the multiplier of the duration a peer is suspected before
+// it's declared dead, see memberlist.Config.SuspicionMult. <= 0 is ignored.
+func OptionSuspicionMult(n int) Option {
+	return func(opts *Options) {
+		if n > 0 {
+			opts.Config.SuspicionMult = n
+		}
+	}
+}
+
 // NewOptionsFromConfig ...
 func NewOptionsFromConfig(c *memberlist.Config, options ...Option) Options {
 	opt := Options{
@@ -138,6 +167,8 @@ func NewOptionsFromConfig(c *memberlist.Config, options ...Option) Options {
 		log.Println("IndirectChecks:", opt.Config.IndirectChecks)
 		log.Println("RetransmitMult:", opt.Config.RetransmitMult)
 		log.Println("SuspicionMult:", opt.Config.SuspicionMult)
+		log.Println("ProbeInterval:", opt.Config.ProbeInterval)
+		log.Println("ProbeTimeout:", opt.Config.ProbeTimeout)
 		log.Println("GossipNodes:", opt.Config.GossipNodes)
 		log.Println("GossipInterval:", opt.Config.GossipInterval)
 		log.Println("disable tcp pings:", opt.Config.DisableTcpPings)
@@ -155,7 +186,9 @@ func NewOptionsFromConfig(c *memberlist.Config, options ...Option) Options {
 func NewOptions(options ...Option) Options {
 	c := memberlist.DefaultWANConfig()
 	c.TCPTimeout = 5 * time.Second
-	c.SuspicionMult = 8
+	c.ProbeInterval = bw.DefaultGossipProbeInterval
+	c.ProbeTimeout = bw.DefaultGossipProbeTimeout
+	c.SuspicionMult = bw.DefaultGossipSuspicionMult
 	c.GossipInterval = 2 * time.Second
 	c.GossipToTheDeadTime = 240 * time.Second
 
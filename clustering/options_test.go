@@ -0,0 +1,42 @@
+package clustering_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/james-lawrence/bw"
+	"github.com/james-lawrence/bw/clustering"
+)
+
+var _ = Describe("Options", func() {
+	It("should default the failure detection timing", func() {
+		c := clustering.NewOptions().Config
+		Expect(c.ProbeInterval).To(Equal(bw.DefaultGossipProbeInterval))
+		Expect(c.ProbeTimeout).To(Equal(bw.DefaultGossipProbeTimeout))
+		Expect(c.SuspicionMult).To(Equal(bw.DefaultGossipSuspicionMult))
+	})
+
+	It("should override the failure detection timing", func() {
+		c := clustering.NewOptions(
+			clustering.OptionProbeInterval(10*time.Second),
+			clustering.OptionProbeTimeout(7*time.Second),
+			clustering.OptionSuspicionMult(12),
+		).Config
+		Expect(c.ProbeInterval).To(Equal(10 * time.Second))
+		Expect(c.ProbeTimeout).To(Equal(7 * time.Second))
+		Expect(c.SuspicionMult).To(Equal(12))
+	})
+
+	It("should ignore unset failure detection timing", func() {
+		c := clustering.NewOptions(
+			clustering.OptionProbeInterval(0),
+			clustering.OptionProbeTimeout(-time.Second),
+			clustering.OptionSuspicionMult(0),
+		).Config
+		Expect(c.ProbeInterval).To(Equal(bw.DefaultGossipProbeInterval))
+		Expect(c.ProbeTimeout).To(Equal(bw.DefaultGossipProbeTimeout))
+		Expect(c.SuspicionMult).To(Equal(bw.DefaultGossipSuspicionMult))
+	})
+})
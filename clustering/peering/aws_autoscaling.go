@@ -5,6 +5,7 @@ import (
 	"net"
 	"strconv"
 
+	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/james-lawrence/bw/awsx"
 )
 
@@ -12,11 +13,21 @@ import (
 type AWSAutoscaling struct {
 	Port               int      // port to connect to.
 	SupplimentalGroups []string // additional autoscaling group names to check
+	RequireIMDSv2      bool     // exclusively use the token based instance metadata service.
 }
 
 // Peers - reads peers from aws Autoscaling groups.
 func (t AWSAutoscaling) Peers(ctx context.Context) (results []string, err error) {
-	instances, err := awsx.AutoscalingPeers(ctx, t.SupplimentalGroups...)
+	var (
+		instances []ec2.Instance
+	)
+
+	if t.RequireIMDSv2 {
+		instances, err = awsx.AutoscalingPeersIMDSv2(ctx, awsx.NewIMDS(), t.SupplimentalGroups...)
+	} else {
+		instances, err = awsx.AutoscalingPeers(ctx, t.SupplimentalGroups...)
+	}
+
 	if err != nil {
 		return []string(nil), err
 	}
@@ -0,0 +1,80 @@
+package peering
+
+import (
+	"context"
+	"net"
+	"strconv"
+
+	"github.com/james-lawrence/bw/azurex"
+	"github.com/james-lawrence/bw/internal/errorsx"
+)
+
+// NewAzureScaleSet peering using the azure scale sets, the scale set of the instance
+// is always checked.
+func NewAzureScaleSet(port int, scalesets ...string) AzureScaleSet {
+	return AzureScaleSet{
+		Port:       port,
+		ScaleSets:  scalesets,
+		Metadata:   azurex.NewIMDS(),
+		Management: azurex.NewManagement(),
+	}
+}
+
+// AzureScaleSet based peering
+type AzureScaleSet struct {
+	Port       int      // port to connect to.
+	ScaleSets  []string // additional scale sets within the resource group of the instance to check.
+	Metadata   azurex.IMDS
+	Management azurex.Management
+}
+
+// Authorized ensures the instance has the credentials required to enumerate the scale sets.
+func (t AzureScaleSet) Authorized(ctx context.Context) (err error) {
+	_, err = t.Metadata.Token(ctx)
+	return err
+}
+
+// Peers - reads peers from the azure scale sets.
+func (t AzureScaleSet) Peers(ctx context.Context) (results []string, err error) {
+	var (
+		inst  azurex.Instance
+		token string
+	)
+
+	if inst, err = t.Metadata.Instance(ctx); err != nil {
+		return results, err
+	}
+
+	if token, err = t.Metadata.Token(ctx); err != nil {
+		return results, err
+	}
+
+	scalesets := t.ScaleSets
+	if inst.VMScaleSetName != "" {
+		scalesets = append([]string{inst.VMScaleSetName}, scalesets...)
+	}
+
+	checked := make(map[string]struct{}, len(scalesets))
+	for _, scaleset := range scalesets {
+		if _, ok := checked[scaleset]; ok {
+			continue
+		}
+		checked[scaleset] = struct{}{}
+
+		ips, cause := t.Management.ScaleSetPrivateIPs(ctx, token, inst.SubscriptionID, inst.ResourceGroupName, scaleset)
+		if cause != nil {
+			err = errorsx.Compact(err, cause)
+			continue
+		}
+
+		for _, ip := range ips {
+			results = append(results, net.JoinHostPort(ip, strconv.Itoa(t.Port)))
+		}
+	}
+
+	if len(results) > 0 {
+		err = nil
+	}
+
+	return results, err
+}
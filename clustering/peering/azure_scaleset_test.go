@@ -0,0 +1,127 @@
+package peering_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/james-lawrence/bw/azurex"
+	. "github.com/james-lawrence/bw/clustering/peering"
+)
+
+// azure stub of the instance metadata service and resource manager.
+// when identity is false the instance has no managed identity.
+func azure(identity bool, scalesets map[string][]string) *httptest.Server {
+	const token = "access-token"
+	nics := func(ips ...string) map[string]interface{} {
+		values := make([]interface{}, 0, len(ips))
+		for _, ip := range ips {
+			values = append(values, map[string]interface{}{
+				"properties": map[string]interface{}{
+					"ipConfigurations": []interface{}{
+						map[string]interface{}{"properties": map[string]interface{}{"privateIPAddress": ip}},
+					},
+				},
+			})
+		}
+		return map[string]interface{}{"value": values}
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metadata/instance/compute", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Metadata") != "true" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		json.NewEncoder(w).Encode(azurex.Instance{
+			Name:              "agent_0",
+			SubscriptionID:    "sub",
+			ResourceGroupName: "group",
+			VMScaleSetName:    "agents",
+		})
+	})
+	mux.HandleFunc("/metadata/identity/oauth2/token", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Metadata") != "true" || !identity {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"access_token": token})
+	})
+	mux.HandleFunc("/subscriptions/sub/resourceGroups/group/providers/Microsoft.Compute/virtualMachineScaleSets/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+token {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		for name, ips := range scalesets {
+			if r.URL.Path == "/subscriptions/sub/resourceGroups/group/providers/Microsoft.Compute/virtualMachineScaleSets/"+name+"/networkInterfaces" {
+				json.NewEncoder(w).Encode(nics(ips...))
+				return
+			}
+		}
+
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	return httptest.NewServer(mux)
+}
+
+var _ = Describe("AzureScaleSet", func() {
+	scaleset := func(srv *httptest.Server, scalesets ...string) AzureScaleSet {
+		return AzureScaleSet{
+			Port:       2000,
+			ScaleSets:  scalesets,
+			Metadata:   azurex.NewIMDS(azurex.IMDSOptionEndpoint(srv.URL)),
+			Management: azurex.NewManagement(azurex.ManagementOptionEndpoint(srv.URL)),
+		}
+	}
+
+	It("should return the private ips of the agent's scale set", func() {
+		srv := azure(true, map[string][]string{
+			"agents": {"10.0.0.1", "10.0.0.2"},
+		})
+		defer srv.Close()
+
+		peers, err := scaleset(srv).Peers(context.Background())
+		Expect(err).To(Succeed())
+		Expect(peers).To(ConsistOf("10.0.0.1:2000", "10.0.0.2:2000"))
+	})
+
+	It("should include the additional scale sets once", func() {
+		srv := azure(true, map[string][]string{
+			"agents": {"10.0.0.1"},
+			"canary": {"10.0.1.1"},
+		})
+		defer srv.Close()
+
+		peers, err := scaleset(srv, "canary", "agents").Peers(context.Background())
+		Expect(err).To(Succeed())
+		Expect(peers).To(ConsistOf("10.0.0.1:2000", "10.0.1.1:2000"))
+	})
+
+	It("should return the peers of the readable scale sets", func() {
+		srv := azure(true, map[string][]string{
+			"agents": {"10.0.0.1"},
+		})
+		defer srv.Close()
+
+		peers, err := scaleset(srv, "missing").Peers(context.Background())
+		Expect(err).To(Succeed())
+		Expect(peers).To(ConsistOf("10.0.0.1:2000"))
+	})
+
+	It("should fail when the instance has no managed identity", func() {
+		srv := azure(false, map[string][]string{
+			"agents": {"10.0.0.1"},
+		})
+		defer srv.Close()
+
+		Expect(scaleset(srv).Authorized(context.Background())).ToNot(Succeed())
+		_, err := scaleset(srv).Peers(context.Background())
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,131 @@
+package peering
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// environment variables respected by the consul peering, see the consul documentation.
+const (
+	EnvConsulHTTPAddr  = "CONSUL_HTTP_ADDR"
+	EnvConsulHTTPToken = "CONSUL_HTTP_TOKEN"
+)
+
+// DefaultConsulService default name of the service registered by the agents.
+const DefaultConsulService = "bw"
+
+// NewConsul peering using the healthy instances of the consul service, the address of the consul
+// agent and its token are read from the environment (CONSUL_HTTP_ADDR and CONSUL_HTTP_TOKEN).
+func NewConsul(port int, service, datacenter string) Consul {
+	address := os.Getenv(EnvConsulHTTPAddr)
+	if address == "" {
+		address = "127.0.0.1:8500"
+	}
+
+	if !strings.Contains(address, "://") {
+		address = "http://" + address
+	}
+
+	if service == "" {
+		service = DefaultConsulService
+	}
+
+	return Consul{
+		Port:       port,
+		Service:    service,
+		Datacenter: datacenter,
+		Address:    strings.TrimSuffix(address, "/"),
+		Token:      os.Getenv(EnvConsulHTTPToken),
+		Client:     &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+// Consul based peering
+type Consul struct {
+	Port       int    // port to connect to.
+	Service    string // name of the consul service.
+	Datacenter string // datacenter to query, blank uses the datacenter of the consul agent.
+	Address    string // address of the consul http api.
+	Token      string // acl token used for requests.
+	Client     *http.Client
+}
+
+// Reachable ensures the consul agent is reachable.
+func (t Consul) Reachable(ctx context.Context) (err error) {
+	var leader string
+	return t.get(ctx, "/v1/status/leader", url.Values{}, &leader)
+}
+
+// Peers - reads peers from the healthy instances of the consul service.
+func (t Consul) Peers(ctx context.Context) (results []string, err error) {
+	var (
+		instances []struct {
+			Node struct {
+				Address string
+			}
+			Service struct {
+				Address string
+			}
+		}
+	)
+
+	q := url.Values{"passing": {"1"}}
+	if t.Datacenter != "" {
+		q.Set("dc", t.Datacenter)
+	}
+
+	if err = t.get(ctx, "/v1/health/service/"+url.PathEscape(t.Service), q, &instances); err != nil {
+		return results, err
+	}
+
+	port := strconv.Itoa(t.Port)
+	for _, i := range instances {
+		address := i.Service.Address
+		if address == "" {
+			address = i.Node.Address
+		}
+
+		if address == "" {
+			continue
+		}
+
+		results = append(results, net.JoinHostPort(address, port))
+	}
+
+	return results, nil
+}
+
+func (t Consul) get(ctx context.Context, path string, q url.Values, v interface{}) (err error) {
+	var (
+		req  *http.Request
+		resp *http.Response
+	)
+
+	if req, err = http.NewRequestWithContext(ctx, http.MethodGet, t.Address+path+"?"+q.Encode(), nil); err != nil {
+		return errors.WithStack(err)
+	}
+
+	if t.Token != "" {
+		req.Header.Set("X-Consul-Token", t.Token)
+	}
+
+	if resp, err = t.Client.Do(req); err != nil {
+		return errors.Wrapf(err, "unable to reach consul: %s", t.Address)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("consul request failed: %s - %s", path, resp.Status)
+	}
+
+	return errors.Wrap(json.NewDecoder(resp.Body).Decode(v), "unable to decode consul response")
+}
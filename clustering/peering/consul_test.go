@@ -0,0 +1,98 @@
+package peering_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/james-lawrence/bw/clustering/peering"
+)
+
+// consul stub of the consul http api, only returns the passing instances of the bw service
+// for the dc1 datacenter when the request presents the token.
+func consul(token string) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/status/leader", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode("10.0.0.100:8300")
+	})
+	mux.HandleFunc("/v1/health/service/bw", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Consul-Token") != token {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		if r.URL.Query().Get("passing") == "" || r.URL.Query().Get("dc") != "dc1" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		json.NewEncoder(w).Encode([]interface{}{
+			map[string]interface{}{
+				"Node":    map[string]string{"Address": "10.0.0.1"},
+				"Service": map[string]string{"Address": ""},
+			},
+			map[string]interface{}{
+				"Node":    map[string]string{"Address": "10.0.0.2"},
+				"Service": map[string]string{"Address": "10.0.1.2"},
+			},
+		})
+	})
+
+	return httptest.NewServer(mux)
+}
+
+var _ = Describe("Consul", func() {
+	It("should return the healthy instances of the service", func() {
+		srv := consul("token")
+		defer srv.Close()
+
+		c := NewConsul(2000, "", "dc1")
+		c.Address = srv.URL
+		c.Token = "token"
+
+		Expect(c.Reachable(context.Background())).To(Succeed())
+		peers, err := c.Peers(context.Background())
+		Expect(err).To(Succeed())
+		Expect(peers).To(ConsistOf("10.0.0.1:2000", "10.0.1.2:2000"))
+	})
+
+	It("should respect the consul environment variables", func() {
+		srv := consul("token")
+		defer srv.Close()
+
+		os.Setenv(EnvConsulHTTPAddr, srv.Listener.Addr().String())
+		os.Setenv(EnvConsulHTTPToken, "token")
+		defer os.Unsetenv(EnvConsulHTTPAddr)
+		defer os.Unsetenv(EnvConsulHTTPToken)
+
+		peers, err := NewConsul(2000, "bw", "dc1").Peers(context.Background())
+		Expect(err).To(Succeed())
+		Expect(peers).To(ConsistOf("10.0.0.1:2000", "10.0.1.2:2000"))
+	})
+
+	It("should fail when the token is rejected", func() {
+		srv := consul("token")
+		defer srv.Close()
+
+		c := NewConsul(2000, "bw", "dc1")
+		c.Address = srv.URL
+
+		_, err := c.Peers(context.Background())
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should fail when consul is unreachable", func() {
+		srv := consul("token")
+		srv.Close()
+
+		c := NewConsul(2000, "bw", "dc1")
+		c.Address = srv.URL
+
+		Expect(c.Reachable(context.Background())).ToNot(Succeed())
+	})
+})
@@ -0,0 +1,142 @@
+package peering
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// environment variables holding the digitalocean api token, checked in order.
+const (
+	EnvDigitalOceanAccessToken = "DIGITALOCEAN_ACCESS_TOKEN"
+	EnvDigitalOceanToken       = "DIGITALOCEAN_TOKEN"
+)
+
+// DefaultDigitalOceanTag default tag of the droplets running the agents.
+const DefaultDigitalOceanTag = "bw"
+
+// NewDigitalOcean peering using the private addresses of the droplets with the tag, the api token
+// is read from the environment (DIGITALOCEAN_ACCESS_TOKEN or DIGITALOCEAN_TOKEN).
+func NewDigitalOcean(port int, tag, region string) DigitalOcean {
+	token := os.Getenv(EnvDigitalOceanAccessToken)
+	if token == "" {
+		token = os.Getenv(EnvDigitalOceanToken)
+	}
+
+	if tag == "" {
+		tag = DefaultDigitalOceanTag
+	}
+
+	return DigitalOcean{
+		Port:    port,
+		Tag:     tag,
+		Region:  region,
+		Address: "https://api.digitalocean.com",
+		Token:   token,
+		Client:  &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+// DigitalOcean based peering
+type DigitalOcean struct {
+	Port    int    // port to connect to.
+	Tag     string // tag of the droplets.
+	Region  string // region slug of the droplets, blank uses droplets from every region.
+	Address string // address of the digitalocean api.
+	Token   string // api token used for requests.
+	Client  *http.Client
+}
+
+// Authorized ensures the api token is present and accepted.
+func (t DigitalOcean) Authorized(ctx context.Context) (err error) {
+	var account struct{}
+
+	if t.Token == "" {
+		return errors.Errorf("missing digitalocean api token: %s or %s", EnvDigitalOceanAccessToken, EnvDigitalOceanToken)
+	}
+
+	return t.get(ctx, "/v2/account", url.Values{}, &account)
+}
+
+// Peers - reads peers from the private addresses of the droplets with the tag.
+func (t DigitalOcean) Peers(ctx context.Context) (results []string, err error) {
+	port := strconv.Itoa(t.Port)
+	q := url.Values{"tag_name": {t.Tag}, "per_page": {"200"}}
+
+	for page := 1; ; page++ {
+		var (
+			resp struct {
+				Droplets []struct {
+					Region struct {
+						Slug string `json:"slug"`
+					} `json:"region"`
+					Networks struct {
+						V4 []struct {
+							IPAddress string `json:"ip_address"`
+							Type      string `json:"type"`
+						} `json:"v4"`
+					} `json:"networks"`
+				} `json:"droplets"`
+				Links struct {
+					Pages struct {
+						Next string `json:"next"`
+					} `json:"pages"`
+				} `json:"links"`
+			}
+		)
+
+		q.Set("page", strconv.Itoa(page))
+		if err = t.get(ctx, "/v2/droplets", q, &resp); err != nil {
+			return results, err
+		}
+
+		for _, d := range resp.Droplets {
+			if t.Region != "" && d.Region.Slug != t.Region {
+				continue
+			}
+
+			for _, n := range d.Networks.V4 {
+				if n.Type != "private" || n.IPAddress == "" {
+					continue
+				}
+
+				results = append(results, net.JoinHostPort(n.IPAddress, port))
+			}
+		}
+
+		if resp.Links.Pages.Next == "" {
+			return results, nil
+		}
+	}
+}
+
+func (t DigitalOcean) get(ctx context.Context, path string, q url.Values, v interface{}) (err error) {
+	var (
+		req  *http.Request
+		resp *http.Response
+	)
+
+	if req, err = http.NewRequestWithContext(ctx, http.MethodGet, t.Address+path+"?"+q.Encode(), nil); err != nil {
+		return errors.WithStack(err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+t.Token)
+
+	if resp, err = t.Client.Do(req); err != nil {
+		return errors.Wrapf(err, "unable to reach digitalocean: %s", t.Address)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("digitalocean request failed: %s - %s", path, resp.Status)
+	}
+
+	return errors.Wrap(json.NewDecoder(resp.Body).Decode(v), "unable to decode digitalocean response")
+}
@@ -0,0 +1,122 @@
+package peering_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/james-lawrence/bw/clustering/peering"
+)
+
+// digitalocean stub of the digitalocean api, returns the droplets tagged bw across two pages
+// when the request presents the token.
+func digitalocean(token string) *httptest.Server {
+	droplet := func(region string, private, public string) map[string]interface{} {
+		return map[string]interface{}{
+			"region": map[string]string{"slug": region},
+			"networks": map[string]interface{}{
+				"v4": []map[string]string{
+					{"ip_address": public, "type": "public"},
+					{"ip_address": private, "type": "private"},
+				},
+			},
+		}
+	}
+
+	mux := http.NewServeMux()
+	authorized := func(w http.ResponseWriter, r *http.Request) bool {
+		if r.Header.Get("Authorization") != "Bearer "+token {
+			w.WriteHeader(http.StatusUnauthorized)
+			return false
+		}
+		return true
+	}
+
+	mux.HandleFunc("/v2/account", func(w http.ResponseWriter, r *http.Request) {
+		if authorized(w, r) {
+			json.NewEncoder(w).Encode(map[string]interface{}{"account": map[string]string{"status": "active"}})
+		}
+	})
+	mux.HandleFunc("/v2/droplets", func(w http.ResponseWriter, r *http.Request) {
+		if !authorized(w, r) {
+			return
+		}
+
+		if r.URL.Query().Get("tag_name") != "bw" {
+			json.NewEncoder(w).Encode(map[string]interface{}{"droplets": []interface{}{}})
+			return
+		}
+
+		switch r.URL.Query().Get("page") {
+		case "1":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"droplets": []interface{}{droplet("nyc3", "10.0.0.1", "203.0.113.1")},
+				"links":    map[string]interface{}{"pages": map[string]string{"next": "page=2"}},
+			})
+		default:
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"droplets": []interface{}{droplet("sfo3", "10.0.0.2", "203.0.113.2")},
+			})
+		}
+	})
+
+	return httptest.NewServer(mux)
+}
+
+var _ = Describe("DigitalOcean", func() {
+	It("should return the private addresses of the tagged droplets", func() {
+		srv := digitalocean("token")
+		defer srv.Close()
+
+		d := NewDigitalOcean(2000, "", "")
+		d.Address = srv.URL
+		d.Token = "token"
+
+		Expect(d.Authorized(context.Background())).To(Succeed())
+		peers, err := d.Peers(context.Background())
+		Expect(err).To(Succeed())
+		Expect(peers).To(ConsistOf("10.0.0.1:2000", "10.0.0.2:2000"))
+	})
+
+	It("should only return the droplets within the region", func() {
+		srv := digitalocean("token")
+		defer srv.Close()
+
+		d := NewDigitalOcean(2000, "bw", "sfo3")
+		d.Address = srv.URL
+		d.Token = "token"
+
+		peers, err := d.Peers(context.Background())
+		Expect(err).To(Succeed())
+		Expect(peers).To(ConsistOf("10.0.0.2:2000"))
+	})
+
+	It("should read the token from the environment", func() {
+		GinkgoT().Setenv(EnvDigitalOceanAccessToken, "")
+		GinkgoT().Setenv(EnvDigitalOceanToken, "token")
+		Expect(NewDigitalOcean(2000, "bw", "").Token).To(Equal("token"))
+	})
+
+	It("should fail without a token", func() {
+		d := NewDigitalOcean(2000, "bw", "")
+		d.Token = ""
+		Expect(d.Authorized(context.Background())).To(MatchError(ContainSubstring("missing digitalocean api token")))
+	})
+
+	It("should fail when the token is rejected", func() {
+		srv := digitalocean("token")
+		defer srv.Close()
+
+		d := NewDigitalOcean(2000, "bw", "")
+		d.Address = srv.URL
+		d.Token = "invalid"
+
+		Expect(d.Authorized(context.Background())).ToNot(Succeed())
+		_, err := d.Peers(context.Background())
+		Expect(err).To(HaveOccurred())
+	})
+})
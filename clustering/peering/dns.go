@@ -6,6 +6,8 @@ import (
 	"strconv"
 
 	"github.com/pkg/errors"
+
+	"github.com/james-lawrence/bw/internal/netx"
 )
 
 // NewDNS create a new DNS peering strategy
@@ -18,8 +20,9 @@ func NewDNS(p int, hosts ...string) DNS {
 
 // DNS based peering
 type DNS struct {
-	Port  int // port to connect to.
-	Hosts []string
+	Port   int // port to connect to.
+	Hosts  []string
+	Family string // address family of the resolved peers, see netx.FamilyAuto.
 }
 
 // Peers - reads peers from a dns record.
@@ -34,7 +37,7 @@ func (t DNS) Peers(context.Context) (results []string, err error) {
 		}
 
 		ps := strconv.Itoa(t.Port)
-		for _, ip := range ips {
+		for _, ip := range netx.FilterFamily(t.Family, ips...) {
 			results = append(results, net.JoinHostPort(ip.String(), ps))
 		}
 	}
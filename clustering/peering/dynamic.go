@@ -0,0 +1,54 @@
+package peering
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/james-lawrence/bw/clustering"
+	"github.com/james-lawrence/bw/internal/errorsx"
+	"github.com/james-lawrence/bw/internal/logx"
+)
+
+// NewDynamic peering strategy whose sources can be replaced at runtime.
+func NewDynamic(sources ...clustering.Source) *Dynamic {
+	return &Dynamic{sources: sources}
+}
+
+// Dynamic combines a set of sources that can be swapped while in use.
+type Dynamic struct {
+	Log     logx.Leveled
+	m       sync.RWMutex
+	sources []clustering.Source
+}
+
+// Swap replace the sources.
+func (t *Dynamic) Swap(sources ...clustering.Source) {
+	t.m.Lock()
+	defer t.m.Unlock()
+	t.sources = sources
+}
+
+// Sources currently in use.
+func (t *Dynamic) Sources() []clustering.Source {
+	t.m.RLock()
+	defer t.m.RUnlock()
+	return append([]clustering.Source(nil), t.sources...)
+}
+
+// Peers - returns the peers from each of the current sources, a failing source
+// does not prevent the peers from the remaining sources from being returned.
+func (t *Dynamic) Peers(ctx context.Context) (peers []string, err error) {
+	for _, s := range t.Sources() {
+		found, cause := s.Peers(ctx)
+		if cause != nil {
+			t.Log.Warn("failed to load peers", "source", fmt.Sprintf("%T", s), "error", cause)
+			err = errorsx.Compact(err, cause)
+			continue
+		}
+
+		peers = append(peers, found...)
+	}
+
+	return peers, err
+}
@@ -3,15 +3,13 @@ package peering
 import (
 	"context"
 	"fmt"
-	"log"
 	"strconv"
 
 	"cloud.google.com/go/compute/metadata"
 	"github.com/davecgh/go-spew/spew"
-	"github.com/james-lawrence/bw"
-	"github.com/james-lawrence/bw/internal/envx"
 	"github.com/james-lawrence/bw/internal/errorsx"
 	"github.com/james-lawrence/bw/internal/gcloudx"
+	"github.com/james-lawrence/bw/internal/logx"
 	"github.com/pkg/errors"
 	"google.golang.org/api/compute/v1"
 )
@@ -20,6 +18,7 @@ import (
 type GCloudTargetPool struct {
 	Port    int // port to connect to.
 	Maximum int
+	Log     logx.Leveled
 }
 
 // Peers - reads peers from aws Autoscaling groups.
@@ -51,25 +50,19 @@ func (t GCloudTargetPool) Peers(ctx context.Context) (results []string, err erro
 		return results, err
 	}
 
-	if envx.Boolean(false, bw.EnvLogsVerbose) {
-		log.Println("instance created by", createdBy)
-	}
+	t.Log.Debug("instance created by", "manager", createdBy)
 
 	if peers, cause := t.standard(ctx, c, project, zone, createdBy); cause == nil {
 		results = append(results, peers...)
 	} else {
-		if envx.Boolean(false, bw.EnvLogsVerbose) {
-			log.Println("instance group peers failed", cause)
-		}
+		t.Log.Debug("instance group peers failed", "error", cause)
 		err = errorsx.Compact(err, cause)
 	}
 
 	if peers, cause := t.region(ctx, c, project, zone, createdBy); cause == nil {
 		results = append(results, peers...)
 	} else {
-		if envx.Boolean(false, bw.EnvLogsVerbose) {
-			log.Println("region instance group peers failed", cause)
-		}
+		t.Log.Debug("region instance group peers failed", "error", cause)
 		err = errorsx.Compact(err, cause)
 	}
 
@@ -89,7 +82,7 @@ func (t GCloudTargetPool) region(ctx context.Context, c *compute.Service, projec
 		resp   *compute.RegionInstanceGroupManagersListInstancesResponse
 	)
 	if prefix := gcloudx.ZonalRegion(zone, "-"); prefix == "" {
-		log.Println("cannot convert zone to region", zone)
+		t.Log.Warn("cannot convert zone to region", "zone", zone)
 		return results, nil
 	} else {
 		region = prefix
@@ -111,9 +104,7 @@ func (t GCloudTargetPool) region(ctx context.Context, c *compute.Service, projec
 		maximum = len(resp.ManagedInstances)
 	}
 
-	if envx.Boolean(false, bw.EnvLogsVerbose) {
-		log.Println("located gcloud instance group manager")
-	}
+	t.Log.Debug("located gcloud instance group manager")
 
 	for _, inst := range resp.ManagedInstances {
 		if ip := t.ip(c, project, inst, zones...); len(ip) > 0 {
@@ -144,9 +135,7 @@ func (t GCloudTargetPool) standard(ctx context.Context, c *compute.Service, proj
 		maximum = len(resp.ManagedInstances)
 	}
 
-	if envx.Boolean(false, bw.EnvLogsVerbose) {
-		log.Println("located gcloud instance group manager")
-	}
+	t.Log.Debug("located gcloud instance group manager")
 
 	for _, inst := range resp.ManagedInstances {
 		if ip := t.ip(c, project, inst, zone); len(ip) > 0 {
@@ -178,9 +167,7 @@ func (t GCloudTargetPool) ip(c *compute.Service, project string, mi *compute.Man
 			continue
 		}
 
-		if envx.Boolean(false, bw.EnvLogsVerbose) {
-			log.Println("gcloud peer info", spew.Sdump(instance))
-		}
+		t.Log.Debug("gcloud peer info", "instance", spew.Sdump(instance))
 
 		// return first IP found.
 		for _, n := range instance.NetworkInterfaces {
@@ -189,7 +176,7 @@ func (t GCloudTargetPool) ip(c *compute.Service, project string, mi *compute.Man
 	}
 
 	if err != nil {
-		log.Println("failed to retrieve instance", strconv.FormatUint(mi.Id, 10), err)
+		t.Log.Warn("failed to retrieve instance", "instance", strconv.FormatUint(mi.Id, 10), "error", err)
 	}
 
 	return ""
@@ -0,0 +1,395 @@
+package peering
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// environment variables respected by the kubernetes peering, see the kubernetes documentation.
+const (
+	EnvKubernetesServiceHost = "KUBERNETES_SERVICE_HOST"
+	EnvKubernetesServicePort = "KUBERNETES_SERVICE_PORT"
+	EnvKubeconfig            = "KUBECONFIG"
+)
+
+// DefaultKubernetesServiceAccount directory the service account credentials are mounted within pods.
+const DefaultKubernetesServiceAccount = "/var/run/secrets/kubernetes.io/serviceaccount"
+
+// NewKubernetes peering using the endpoints of the headless service, or the pods matching the selector
+// when one is provided. within a cluster the pod's service account is used, otherwise the kubeconfig
+// (defaults to KUBECONFIG, then ~/.kube/config) is used. a blank namespace uses the namespace of the
+// service account or the kubeconfig context.
+func NewKubernetes(port int, namespace, service, selector, kubeconfig string) (k Kubernetes, err error) {
+	k = Kubernetes{
+		Port:      port,
+		Namespace: namespace,
+		Service:   service,
+		Selector:  selector,
+	}
+
+	if service == "" && selector == "" {
+		return k, errors.New("kubernetes peering requires a service or a label selector")
+	}
+
+	if host := os.Getenv(EnvKubernetesServiceHost); host != "" && kubeconfig == "" {
+		err = k.incluster(DefaultKubernetesServiceAccount, net.JoinHostPort(host, os.Getenv(EnvKubernetesServicePort)))
+	} else {
+		err = k.kubeconfig(kubeconfig)
+	}
+
+	if err != nil {
+		return k, err
+	}
+
+	if k.Namespace == "" {
+		k.Namespace = "default"
+	}
+
+	return k, nil
+}
+
+// Kubernetes based peering
+type Kubernetes struct {
+	Port      int    // port to connect to.
+	Namespace string // namespace of the service or pods.
+	Service   string // name of the headless service whose endpoints are the peers.
+	Selector  string // label selector of the pods, takes precedence over the service.
+	Address   string // address of the kubernetes api.
+	Token     string // bearer token used for requests.
+	Client    *http.Client
+}
+
+// Reachable ensures the kubernetes api is reachable and the credentials are authorized to list the peers.
+func (t Kubernetes) Reachable(ctx context.Context) (err error) {
+	_, err = t.Peers(ctx)
+	return err
+}
+
+// Peers - reads peers from the ready endpoints of the service or the running pods matching the selector.
+func (t Kubernetes) Peers(ctx context.Context) (results []string, err error) {
+	var (
+		addresses []string
+	)
+
+	if t.Selector != "" {
+		addresses, err = t.pods(ctx)
+	} else {
+		addresses, err = t.endpoints(ctx)
+	}
+
+	if err != nil {
+		return results, err
+	}
+
+	port := strconv.Itoa(t.Port)
+	for _, address := range addresses {
+		if address == "" {
+			continue
+		}
+
+		results = append(results, net.JoinHostPort(address, port))
+	}
+
+	return results, nil
+}
+
+func (t Kubernetes) endpoints(ctx context.Context) (results []string, err error) {
+	var (
+		endpoints struct {
+			Subsets []struct {
+				Addresses []struct {
+					IP string `json:"ip"`
+				} `json:"addresses"`
+			} `json:"subsets"`
+		}
+	)
+
+	path := "/api/v1/namespaces/" + url.PathEscape(t.Namespace) + "/endpoints/" + url.PathEscape(t.Service)
+	if err = t.get(ctx, path, url.Values{}, &endpoints); err != nil {
+		return results, err
+	}
+
+	// only the ready addresses are listed by addresses, unready addresses are listed separately.
+	for _, s := range endpoints.Subsets {
+		for _, a := range s.Addresses {
+			results = append(results, a.IP)
+		}
+	}
+
+	return results, nil
+}
+
+func (t Kubernetes) pods(ctx context.Context) (results []string, err error) {
+	var (
+		pods struct {
+			Items []struct {
+				Status struct {
+					Phase string `json:"phase"`
+					PodIP string `json:"podIP"`
+				} `json:"status"`
+			} `json:"items"`
+		}
+	)
+
+	path := "/api/v1/namespaces/" + url.PathEscape(t.Namespace) + "/pods"
+	if err = t.get(ctx, path, url.Values{"labelSelector": {t.Selector}}, &pods); err != nil {
+		return results, err
+	}
+
+	for _, p := range pods.Items {
+		if p.Status.Phase != "Running" {
+			continue
+		}
+
+		results = append(results, p.Status.PodIP)
+	}
+
+	return results, nil
+}
+
+func (t Kubernetes) get(ctx context.Context, path string, q url.Values, v interface{}) (err error) {
+	var (
+		req  *http.Request
+		resp *http.Response
+	)
+
+	if req, err = http.NewRequestWithContext(ctx, http.MethodGet, t.Address+path+"?"+q.Encode(), nil); err != nil {
+		return errors.WithStack(err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+	if t.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+t.Token)
+	}
+
+	if resp, err = t.Client.Do(req); err != nil {
+		return errors.Wrapf(err, "unable to reach kubernetes: %s", t.Address)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("kubernetes request failed: %s - %s", path, resp.Status)
+	}
+
+	return errors.Wrap(json.NewDecoder(resp.Body).Decode(v), "unable to decode kubernetes response")
+}
+
+// incluster configures the peering from the pod's service account.
+func (t *Kubernetes) incluster(dir string, address string) (err error) {
+	var (
+		token []byte
+		ca    []byte
+	)
+
+	if token, err = os.ReadFile(filepath.Join(dir, "token")); err != nil {
+		return errors.Wrap(err, "unable to read the service account token")
+	}
+
+	if ca, err = os.ReadFile(filepath.Join(dir, "ca.crt")); err != nil {
+		return errors.Wrap(err, "unable to read the service account certificate authority")
+	}
+
+	if t.Namespace == "" {
+		if ns, err := os.ReadFile(filepath.Join(dir, "namespace")); err == nil {
+			t.Namespace = strings.TrimSpace(string(ns))
+		}
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(ca) {
+		return errors.New("invalid service account certificate authority")
+	}
+
+	t.Address = "https://" + address
+	t.Token = strings.TrimSpace(string(token))
+	t.Client = kubernetesClient(&tls.Config{RootCAs: pool})
+
+	return nil
+}
+
+// kubeconfig configures the peering from the current context of the kubeconfig.
+// supports bearer tokens and client certificates, embedded or as files.
+func (t *Kubernetes) kubeconfig(path string) (err error) {
+	type cluster struct {
+		Server                   string `yaml:"server"`
+		CertificateAuthority     string `yaml:"certificate-authority"`
+		CertificateAuthorityData string `yaml:"certificate-authority-data"`
+		InsecureSkipTLSVerify    bool   `yaml:"insecure-skip-tls-verify"`
+	}
+
+	type user struct {
+		Token                 string `yaml:"token"`
+		TokenFile             string `yaml:"tokenFile"`
+		ClientCertificate     string `yaml:"client-certificate"`
+		ClientCertificateData string `yaml:"client-certificate-data"`
+		ClientKey             string `yaml:"client-key"`
+		ClientKeyData         string `yaml:"client-key-data"`
+	}
+
+	var (
+		encoded []byte
+		config  struct {
+			CurrentContext string `yaml:"current-context"`
+			Clusters       []struct {
+				Name    string  `yaml:"name"`
+				Cluster cluster `yaml:"cluster"`
+			} `yaml:"clusters"`
+			Users []struct {
+				Name string `yaml:"name"`
+				User user   `yaml:"user"`
+			} `yaml:"users"`
+			Contexts []struct {
+				Name    string `yaml:"name"`
+				Context struct {
+					Cluster   string `yaml:"cluster"`
+					User      string `yaml:"user"`
+					Namespace string `yaml:"namespace"`
+				} `yaml:"context"`
+			} `yaml:"contexts"`
+		}
+		c        cluster
+		u        user
+		tc       = &tls.Config{}
+		ok       bool
+		selected = -1
+	)
+
+	if path == "" {
+		path = os.Getenv(EnvKubeconfig)
+	}
+
+	if path == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return errors.Wrap(err, "unable to locate the kubeconfig")
+		}
+
+		path = filepath.Join(home, ".kube", "config")
+	}
+
+	if encoded, err = os.ReadFile(path); err != nil {
+		return errors.Wrap(err, "unable to read the kubeconfig")
+	}
+
+	if err = yaml.Unmarshal(encoded, &config); err != nil {
+		return errors.Wrap(err, "unable to decode the kubeconfig")
+	}
+
+	// resolve relative file references from the kubeconfig's directory.
+	resolve := func(p string) string {
+		if p == "" || filepath.IsAbs(p) {
+			return p
+		}
+
+		return filepath.Join(filepath.Dir(path), p)
+	}
+
+	// base64 encoded data takes precedence over the file.
+	load := func(data, file string) ([]byte, error) {
+		if data != "" {
+			return base64.StdEncoding.DecodeString(data)
+		}
+
+		if file != "" {
+			return os.ReadFile(resolve(file))
+		}
+
+		return nil, nil
+	}
+
+	for i, candidate := range config.Contexts {
+		if candidate.Name == config.CurrentContext {
+			selected = i
+		}
+	}
+
+	if selected == -1 {
+		return errors.Errorf("kubeconfig context not found: %s", config.CurrentContext)
+	}
+
+	current := config.Contexts[selected].Context
+	for _, candidate := range config.Clusters {
+		if candidate.Name == current.Cluster {
+			c, ok = candidate.Cluster, true
+		}
+	}
+
+	if !ok {
+		return errors.Errorf("kubeconfig cluster not found: %s", current.Cluster)
+	}
+
+	for _, candidate := range config.Users {
+		if candidate.Name == current.User {
+			u = candidate.User
+		}
+	}
+
+	if t.Namespace == "" {
+		t.Namespace = current.Namespace
+	}
+
+	if ca, err := load(c.CertificateAuthorityData, c.CertificateAuthority); err != nil {
+		return errors.Wrap(err, "unable to read the kubeconfig certificate authority")
+	} else if ca != nil {
+		tc.RootCAs = x509.NewCertPool()
+		if !tc.RootCAs.AppendCertsFromPEM(ca) {
+			return errors.New("invalid kubeconfig certificate authority")
+		}
+	}
+	tc.InsecureSkipVerify = c.InsecureSkipTLSVerify
+
+	cert, err := load(u.ClientCertificateData, u.ClientCertificate)
+	if err != nil {
+		return errors.Wrap(err, "unable to read the kubeconfig client certificate")
+	}
+
+	key, err := load(u.ClientKeyData, u.ClientKey)
+	if err != nil {
+		return errors.Wrap(err, "unable to read the kubeconfig client key")
+	}
+
+	if cert != nil && key != nil {
+		pair, err := tls.X509KeyPair(cert, key)
+		if err != nil {
+			return errors.Wrap(err, "invalid kubeconfig client certificate")
+		}
+
+		tc.Certificates = []tls.Certificate{pair}
+	}
+
+	t.Token = u.Token
+	if u.TokenFile != "" && t.Token == "" {
+		token, err := os.ReadFile(resolve(u.TokenFile))
+		if err != nil {
+			return errors.Wrap(err, "unable to read the kubeconfig token")
+		}
+
+		t.Token = strings.TrimSpace(string(token))
+	}
+
+	t.Address = strings.TrimSuffix(c.Server, "/")
+	t.Client = kubernetesClient(tc)
+
+	return nil
+}
+
+func kubernetesClient(c *tls.Config) *http.Client {
+	return &http.Client{
+		Timeout:   5 * time.Second,
+		Transport: &http.Transport{TLSClientConfig: c, Proxy: http.ProxyFromEnvironment},
+	}
+}
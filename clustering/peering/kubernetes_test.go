@@ -0,0 +1,151 @@
+package peering_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/james-lawrence/bw/internal/testingx"
+
+	. "github.com/james-lawrence/bw/clustering/peering"
+)
+
+// kubernetes stub of the kubernetes api, returns the endpoints of the bw service and the pods
+// labelled app=bw within the bw namespace when the request presents the token.
+func kubernetes(token string) *httptest.Server {
+	authorized := func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "Bearer "+token {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+
+			next(w, r)
+		}
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/namespaces/bw/endpoints/bw", authorized(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"subsets": []interface{}{
+				map[string]interface{}{
+					"addresses":         []interface{}{map[string]string{"ip": "10.0.0.1"}, map[string]string{"ip": "10.0.0.2"}},
+					"notReadyAddresses": []interface{}{map[string]string{"ip": "10.0.0.3"}},
+				},
+			},
+		})
+	}))
+	mux.HandleFunc("/api/v1/namespaces/bw/pods", authorized(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("labelSelector") != "app=bw" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"items": []interface{}{
+				map[string]interface{}{"status": map[string]string{"phase": "Running", "podIP": "10.0.1.1"}},
+				map[string]interface{}{"status": map[string]string{"phase": "Pending", "podIP": ""}},
+			},
+		})
+	}))
+
+	return httptest.NewTLSServer(mux)
+}
+
+// kubeconfig writes a kubeconfig for the stub server into the directory.
+func kubeconfig(dir string, srv *httptest.Server, token string) string {
+	ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	path := filepath.Join(dir, "kubeconfig")
+	config := fmt.Sprintf(`
+current-context: bw
+clusters:
+- name: bw
+  cluster:
+    server: %s
+    certificate-authority-data: %s
+users:
+- name: bw
+  user:
+    token: %s
+contexts:
+- name: bw
+  context:
+    cluster: bw
+    user: bw
+    namespace: bw
+`, srv.URL, base64.StdEncoding.EncodeToString(ca), token)
+
+	Expect(os.WriteFile(path, []byte(config), 0600)).To(Succeed())
+	return path
+}
+
+var _ = Describe("Kubernetes", func() {
+	It("should return the ready endpoints of the service", func() {
+		srv := kubernetes("token")
+		defer srv.Close()
+
+		k, err := NewKubernetes(2000, "", "bw", "", kubeconfig(testingx.TempDir(), srv, "token"))
+		Expect(err).To(Succeed())
+		Expect(k.Namespace).To(Equal("bw"))
+		Expect(k.Reachable(context.Background())).To(Succeed())
+
+		peers, err := k.Peers(context.Background())
+		Expect(err).To(Succeed())
+		Expect(peers).To(ConsistOf("10.0.0.1:2000", "10.0.0.2:2000"))
+	})
+
+	It("should return the running pods matching the selector", func() {
+		srv := kubernetes("token")
+		defer srv.Close()
+
+		k, err := NewKubernetes(2000, "bw", "bw", "app=bw", kubeconfig(testingx.TempDir(), srv, "token"))
+		Expect(err).To(Succeed())
+
+		peers, err := k.Peers(context.Background())
+		Expect(err).To(Succeed())
+		Expect(peers).To(ConsistOf("10.0.1.1:2000"))
+	})
+
+	It("should respect the KUBECONFIG environment variable", func() {
+		srv := kubernetes("token")
+		defer srv.Close()
+
+		os.Setenv(EnvKubeconfig, kubeconfig(testingx.TempDir(), srv, "token"))
+		defer os.Unsetenv(EnvKubeconfig)
+
+		k, err := NewKubernetes(2000, "", "bw", "", "")
+		Expect(err).To(Succeed())
+
+		peers, err := k.Peers(context.Background())
+		Expect(err).To(Succeed())
+		Expect(peers).To(ConsistOf("10.0.0.1:2000", "10.0.0.2:2000"))
+	})
+
+	It("should fail when the token is rejected", func() {
+		srv := kubernetes("token")
+		defer srv.Close()
+
+		k, err := NewKubernetes(2000, "", "bw", "", kubeconfig(testingx.TempDir(), srv, "invalid"))
+		Expect(err).To(Succeed())
+		Expect(k.Reachable(context.Background())).ToNot(Succeed())
+	})
+
+	It("should fail without a service or selector", func() {
+		_, err := NewKubernetes(2000, "bw", "", "", "")
+		Expect(err).ToNot(Succeed())
+	})
+
+	It("should fail when the kubeconfig is missing", func() {
+		_, err := NewKubernetes(2000, "bw", "bw", "", filepath.Join(testingx.TempDir(), "missing"))
+		Expect(err).ToNot(Succeed())
+	})
+})
@@ -0,0 +1,118 @@
+package peering
+
+import (
+	"context"
+	"math/rand"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+type srvresolver interface {
+	LookupSRV(ctx context.Context, service, proto, name string) (string, []*net.SRV, error)
+}
+
+// NewDNSSRV create a new DNS SRV peering strategy, the resolved peers are reused for the ttl.
+func NewDNSSRV(name string, ttl time.Duration) *DNSSRV {
+	return &DNSSRV{
+		Name:     name,
+		TTL:      ttl,
+		Resolver: net.DefaultResolver,
+	}
+}
+
+// DNSSRV peering using the targets of a SRV record, e.g.) _bw._tcp.example.com. unlike DNS
+// the port of each peer is provided by the record.
+type DNSSRV struct {
+	Name     string        // srv record to resolve.
+	TTL      time.Duration // duration the resolved peers are reused before the record is resolved again, <= 0 always resolves.
+	Resolver srvresolver   // defaults to net.DefaultResolver.
+	m        sync.Mutex
+	cached   []string
+	expires  time.Time
+}
+
+// Peers the targets of the SRV record ordered by their priority, records with the
+// same priority are randomized by their weight. a record that doesn't exist has no peers.
+func (t *DNSSRV) Peers(ctx context.Context) (results []string, err error) {
+	var (
+		records []*net.SRV
+		dnserr  *net.DNSError
+	)
+
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	if time.Now().Before(t.expires) {
+		return t.cached, nil
+	}
+
+	resolver := t.Resolver
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+
+	// blank service and proto resolves the name as is.
+	if _, records, err = resolver.LookupSRV(ctx, "", "", t.Name); errors.As(err, &dnserr) && dnserr.IsNotFound {
+		return results, nil
+	} else if err != nil {
+		return results, errors.Wrapf(err, "unable to resolve srv record: %s", t.Name)
+	}
+
+	// the standard resolver orders the records, other resolvers may not.
+	weighted(records)
+
+	results = make([]string, 0, len(records))
+	for _, r := range records {
+		results = append(results, net.JoinHostPort(strings.TrimSuffix(r.Target, "."), strconv.Itoa(int(r.Port))))
+	}
+
+	t.cached = results
+	t.expires = time.Now().Add(t.TTL)
+
+	return results, nil
+}
+
+// weighted orders the records by their priority, records with the same priority are
+// shuffled by their weight, see RFC 2782.
+func weighted(records []*net.SRV) {
+	sort.SliceStable(records, func(i, j int) bool { return records[i].Priority < records[j].Priority })
+
+	for i := 0; i < len(records); {
+		j := i + 1
+		for j < len(records) && records[j].Priority == records[i].Priority {
+			j++
+		}
+
+		shuffle(records[i:j])
+		i = j
+	}
+}
+
+// shuffle the records by their weight, the likelihood of a record being selected next
+// is proportional to its weight. records without a weight retain their order after the
+// weighted records.
+func shuffle(records []*net.SRV) {
+	sum := 0
+	for _, r := range records {
+		sum += int(r.Weight)
+	}
+
+	for sum > 0 && len(records) > 1 {
+		n, s := rand.Intn(sum), 0
+		for i := range records {
+			if s += int(records[i].Weight); s > n {
+				records[0], records[i] = records[i], records[0]
+				break
+			}
+		}
+
+		sum -= int(records[0].Weight)
+		records = records[1:]
+	}
+}
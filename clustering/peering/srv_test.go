@@ -0,0 +1,91 @@
+package peering_test
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/james-lawrence/bw/clustering/peering"
+)
+
+// fakeSRV resolves every name to the records, counting the lookups.
+type fakeSRV struct {
+	records []*net.SRV
+	err     error
+	lookups int64
+}
+
+func (t *fakeSRV) LookupSRV(ctx context.Context, service, proto, name string) (string, []*net.SRV, error) {
+	atomic.AddInt64(&t.lookups, 1)
+	return name, t.records, t.err
+}
+
+var _ = Describe("DNSSRV", func() {
+	It("should use the ports and priorities of the records", func() {
+		r := &fakeSRV{records: []*net.SRV{
+			{Target: "node2.example.com.", Port: 2001, Priority: 20},
+			{Target: "node1.example.com.", Port: 2000, Priority: 10},
+		}}
+
+		srv := NewDNSSRV("_bw._tcp.example.com", 0)
+		srv.Resolver = r
+
+		Expect(srv.Peers(context.Background())).To(Equal([]string{"node1.example.com:2000", "node2.example.com:2001"}))
+	})
+
+	It("should shuffle the records of the same priority by their weight", func() {
+		r := &fakeSRV{records: []*net.SRV{
+			{Target: "node3.example.com.", Port: 2000, Priority: 20, Weight: 100},
+			{Target: "node1.example.com.", Port: 2000, Priority: 10, Weight: 1},
+			{Target: "node2.example.com.", Port: 2000, Priority: 10, Weight: 3},
+			{Target: "node4.example.com.", Port: 2000, Priority: 10},
+		}}
+
+		srv := NewDNSSRV("_bw._tcp.example.com", 0)
+		srv.Resolver = r
+
+		first := map[string]int{}
+		for i := 0; i < 400; i++ {
+			peers, err := srv.Peers(context.Background())
+			Expect(err).To(Succeed())
+			Expect(peers).To(ConsistOf("node1.example.com:2000", "node2.example.com:2000", "node3.example.com:2000", "node4.example.com:2000"))
+			// records without a weight follow the weighted records of their priority.
+			Expect(peers[2:]).To(Equal([]string{"node4.example.com:2000", "node3.example.com:2000"}))
+			first[peers[0]]++
+		}
+
+		Expect(first["node1.example.com:2000"]).To(BeNumerically(">", 50))
+		Expect(first["node2.example.com:2000"]).To(BeNumerically(">", 250))
+	})
+
+	It("should reuse the peers until the ttl expires", func() {
+		r := &fakeSRV{records: []*net.SRV{{Target: "node1.example.com.", Port: 2000}}}
+
+		srv := NewDNSSRV("_bw._tcp.example.com", time.Hour)
+		srv.Resolver = r
+
+		for i := 0; i < 3; i++ {
+			Expect(srv.Peers(context.Background())).To(HaveLen(1))
+		}
+		Expect(r.lookups).To(Equal(int64(1)))
+	})
+
+	It("should have no peers when the record doesn't exist", func() {
+		srv := NewDNSSRV("_bw._tcp.example.com", 0)
+		srv.Resolver = &fakeSRV{err: &net.DNSError{Err: "no such host", Name: "_bw._tcp.example.com", IsNotFound: true}}
+
+		Expect(srv.Peers(context.Background())).To(BeEmpty())
+	})
+
+	It("should fail when the record can't be resolved", func() {
+		srv := NewDNSSRV("_bw._tcp.example.com", 0)
+		srv.Resolver = &fakeSRV{err: &net.DNSError{Err: "server misbehaving", Name: "_bw._tcp.example.com", IsTemporary: true}}
+
+		_, err := srv.Peers(context.Background())
+		Expect(err).To(HaveOccurred())
+	})
+})
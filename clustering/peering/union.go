@@ -0,0 +1,64 @@
+package peering
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	"github.com/james-lawrence/bw/clustering"
+	"github.com/james-lawrence/bw/internal/errorsx"
+)
+
+// Union queries the sources concurrently and merges their peers, deduplicated by
+// address in the order of the sources. failing sources are logged and skipped, an
+// error is only returned when every source fails.
+func Union(sources ...clustering.Source) clustering.Source {
+	return union(sources)
+}
+
+type union []clustering.Source
+
+// Peers - returns the deduplicated peers of the sources.
+func (t union) Peers(ctx context.Context) (peers []string, err error) {
+	var (
+		wg      sync.WaitGroup
+		found   = make([][]string, len(t))
+		errs    = make([]error, len(t))
+		failed  int
+		deduped = make(map[string]struct{})
+	)
+
+	for idx, s := range t {
+		wg.Add(1)
+		go func(idx int, s clustering.Source) {
+			defer wg.Done()
+			found[idx], errs[idx] = s.Peers(ctx)
+		}(idx, s)
+	}
+
+	wg.Wait()
+
+	for idx, s := range t {
+		if errs[idx] != nil {
+			log.Printf("failed to load peers: %T: %s\n", s, errs[idx])
+			err = errorsx.Compact(err, errs[idx])
+			failed++
+			continue
+		}
+
+		for _, p := range found[idx] {
+			if _, ok := deduped[p]; ok {
+				continue
+			}
+
+			deduped[p] = struct{}{}
+			peers = append(peers, p)
+		}
+	}
+
+	if failed < len(t) {
+		return peers, nil
+	}
+
+	return peers, err
+}
@@ -0,0 +1,67 @@
+package peering_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/james-lawrence/bw/clustering/peering"
+	"github.com/james-lawrence/bw/internal/errorsx"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Union", func() {
+	failing := func(cause string) Closure {
+		return Closure(func(context.Context) ([]string, error) {
+			return nil, errorsx.String(cause)
+		})
+	}
+
+	It("should merge and deduplicate the peers of the sources", func() {
+		u := Union(
+			NewStatic("127.0.0.1:2000", "127.0.0.2:2000"),
+			NewStatic("127.0.0.2:2000", "127.0.0.3:2000", "127.0.0.1:2000"),
+		)
+		Expect(u.Peers(context.Background())).To(Equal([]string{"127.0.0.1:2000", "127.0.0.2:2000", "127.0.0.3:2000"}))
+	})
+
+	It("should return the peers of the remaining sources when a source fails", func() {
+		u := Union(
+			failing("boom"),
+			NewStatic("127.0.0.1:2000"),
+			NewStatic("127.0.0.1:2000", "127.0.0.2:2000"),
+		)
+		Expect(u.Peers(context.Background())).To(Equal([]string{"127.0.0.1:2000", "127.0.0.2:2000"}))
+	})
+
+	It("should fail when every source fails", func() {
+		_, err := Union(failing("boom"), failing("bang")).Peers(context.Background())
+		Expect(err).To(MatchError(ContainSubstring("boom")))
+	})
+
+	It("should query the sources concurrently", func() {
+		release := make(chan struct{})
+		blocked := Closure(func(ctx context.Context) ([]string, error) {
+			select {
+			case <-release:
+				return []string{"127.0.0.1:2000"}, nil
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		})
+		releasing := Closure(func(context.Context) ([]string, error) {
+			close(release)
+			return []string{"127.0.0.2:2000"}, nil
+		})
+
+		ctx, done := context.WithTimeout(context.Background(), 5*time.Second)
+		defer done()
+
+		Expect(Union(blocked, releasing).Peers(ctx)).To(Equal([]string{"127.0.0.1:2000", "127.0.0.2:2000"}))
+	})
+
+	It("should succeed without sources", func() {
+		Expect(Union().Peers(context.Background())).To(BeEmpty())
+	})
+})
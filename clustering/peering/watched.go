@@ -0,0 +1,88 @@
+package peering
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/james-lawrence/bw/internal/logx"
+	"github.com/pkg/errors"
+)
+
+// NewWatchedFile peering strategy reading the bootstrap addresses from a newline delimited file.
+func NewWatchedFile(path string) *WatchedFile {
+	return &WatchedFile{Path: path}
+}
+
+// WatchedFile reads a newline delimited list of bootstrap addresses from a file, the file is
+// re-read whenever it changes allowing an external process to update the peers without restarting
+// the agent. blank lines and lines starting with # are ignored. when the file fails to load the last
+// good set of peers continues to be served.
+type WatchedFile struct {
+	Path     string
+	Log      logx.Leveled
+	m        sync.Mutex
+	modified time.Time
+	size     int64
+	peers    []string
+}
+
+// Peers - returns the peers from the file, re-reading the file if it changed.
+func (t *WatchedFile) Peers(context.Context) ([]string, error) {
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	info, err := os.Stat(t.Path)
+	if err != nil {
+		t.Log.Warn("failed to stat bootstrap file, using the last known peers", "path", t.Path, "error", err)
+		return t.peers, nil
+	}
+
+	if info.ModTime().Equal(t.modified) && info.Size() == t.size {
+		return t.peers, nil
+	}
+
+	// record the change regardless of the result, the file is only re-read once it changes again.
+	t.modified, t.size = info.ModTime(), info.Size()
+
+	peers, err := t.read()
+	if err != nil {
+		t.Log.Warn("failed to load bootstrap file, using the last known peers", "path", t.Path, "error", err)
+		return t.peers, nil
+	}
+
+	t.peers = peers
+
+	return t.peers, nil
+}
+
+func (t *WatchedFile) read() (peers []string, err error) {
+	var (
+		data []byte
+	)
+
+	if data, err = os.ReadFile(t.Path); err != nil {
+		return peers, errors.Wrapf(err, "failed to read peers from file: %s", t.Path)
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		address := strings.TrimSpace(scanner.Text())
+		if address == "" || strings.HasPrefix(address, "#") {
+			continue
+		}
+
+		if _, _, err = net.SplitHostPort(address); err != nil {
+			return peers, errors.Wrapf(err, "invalid peer address %s:%d", t.Path, line)
+		}
+
+		peers = append(peers, address)
+	}
+
+	return peers, errors.Wrapf(scanner.Err(), "failed to read peers from file: %s", t.Path)
+}
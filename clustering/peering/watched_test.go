@@ -0,0 +1,65 @@
+package peering_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/james-lawrence/bw/clustering/peering"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("WatchedFile", func() {
+	var (
+		err    error
+		tmpdir string
+	)
+
+	BeforeEach(func() {
+		tmpdir, err = os.MkdirTemp(".", "watched-peering")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tmpdir)
+	})
+
+	write := func(path string, content string, modified time.Time) {
+		Expect(os.WriteFile(path, []byte(content), 0600)).To(Succeed())
+		Expect(os.Chtimes(path, modified, modified)).To(Succeed())
+	}
+
+	It("should re-read the peers when the file changes", func() {
+		path := filepath.Join(tmpdir, "peers")
+		write(path, "# bootstrap\n127.0.0.1:2000\n\n127.0.0.2:2000\n", time.Now().Add(-time.Minute))
+
+		w := NewWatchedFile(path)
+		Expect(w.Peers(context.Background())).To(Equal([]string{"127.0.0.1:2000", "127.0.0.2:2000"}))
+
+		write(path, "127.0.0.3:2000\n", time.Now())
+		Expect(w.Peers(context.Background())).To(Equal([]string{"127.0.0.3:2000"}))
+	})
+
+	It("should serve the last good set of peers when the file is invalid", func() {
+		path := filepath.Join(tmpdir, "peers")
+		write(path, "127.0.0.1:2000\n", time.Now().Add(-time.Minute))
+
+		w := NewWatchedFile(path)
+		Expect(w.Peers(context.Background())).To(Equal([]string{"127.0.0.1:2000"}))
+
+		write(path, "127.0.0.1:2000\nnot an address\n", time.Now())
+		Expect(w.Peers(context.Background())).To(Equal([]string{"127.0.0.1:2000"}))
+
+		Expect(os.Remove(path)).To(Succeed())
+		Expect(w.Peers(context.Background())).To(Equal([]string{"127.0.0.1:2000"}))
+	})
+
+	It("should return no peers when the file is missing", func() {
+		peers, err := NewWatchedFile(filepath.Join(tmpdir, "missing")).Peers(context.Background())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(peers).To(BeEmpty())
+	})
+})
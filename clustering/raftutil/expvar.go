@@ -0,0 +1,128 @@
+package raftutil
+
+import (
+	"encoding/json"
+	"expvar"
+	"strconv"
+	"sync"
+
+	"github.com/hashicorp/raft"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ExpvarKey namespace of the raft metrics published via expvar.
+const ExpvarKey = "bw.raft"
+
+// guards the publication of the expvar.
+var publishing sync.Mutex
+
+var (
+	descAppliedIndex      = prometheus.NewDesc("bw_raft_applied_index", "index of the last log applied by the local raft instance.", nil, nil)
+	descCommitIndex       = prometheus.NewDesc("bw_raft_commit_index", "index of the last log committed by the quorum.", nil, nil)
+	descLastSnapshotIndex = prometheus.NewDesc("bw_raft_last_snapshot_index", "index of the last snapshot of the local raft instance.", nil, nil)
+	descTerm              = prometheus.NewDesc("bw_raft_term", "current term of the local raft instance.", nil, nil)
+	descLeader            = prometheus.NewDesc("bw_raft_leader", "leader of the quorum observed by the local raft instance.", []string{"leader"}, nil)
+)
+
+// metrics source, implemented by *raft.Raft.
+type metrics interface {
+	Stats() map[string]string
+	Leader() raft.ServerAddress
+}
+
+// ExpvarMetrics snapshot of the raft metrics published via expvar.
+type ExpvarMetrics struct {
+	AppliedIndex      uint64 `json:"appliedIndex"`
+	CommitIndex       uint64 `json:"commitIndex"`
+	LastSnapshotIndex uint64 `json:"lastSnapshotIndex"`
+	Term              uint64 `json:"term"`
+	Leader            string `json:"leader"`
+}
+
+// Expvar publishes the metrics of the active raft instance, the raft instance is replaced
+// each time the protocol reconnects.
+type Expvar struct {
+	m       sync.RWMutex
+	current metrics
+}
+
+// PublishExpvar publishes the raft metrics under ExpvarKey and registers them with the
+// prometheus metrics of the agent, safe to call multiple times.
+func PublishExpvar() *Expvar {
+	publishing.Lock()
+	defer publishing.Unlock()
+
+	if v, ok := expvar.Get(ExpvarKey).(*Expvar); ok {
+		return v
+	}
+
+	v := &Expvar{}
+	expvar.Publish(ExpvarKey, v)
+	prometheus.MustRegister(v)
+	return v
+}
+
+// Track the metrics of the provided raft instance.
+func (t *Expvar) Track(r metrics) {
+	t.m.Lock()
+	defer t.m.Unlock()
+	t.current = r
+}
+
+// Untrack the raft instance, noop if a different instance is being tracked.
+func (t *Expvar) Untrack(r metrics) {
+	t.m.Lock()
+	defer t.m.Unlock()
+	if t.current == r {
+		t.current = nil
+	}
+}
+
+// Metrics of the tracked raft instance, zero values when nothing is tracked.
+func (t *Expvar) Metrics() (m ExpvarMetrics) {
+	t.m.RLock()
+	defer t.m.RUnlock()
+
+	if t.current == nil {
+		return m
+	}
+
+	stats := t.current.Stats()
+	parse := func(k string) uint64 {
+		v, _ := strconv.ParseUint(stats[k], 10, 64)
+		return v
+	}
+
+	return ExpvarMetrics{
+		AppliedIndex:      parse("applied_index"),
+		CommitIndex:       parse("commit_index"),
+		LastSnapshotIndex: parse("last_snapshot_index"),
+		Term:              parse("term"),
+		Leader:            string(t.current.Leader()),
+	}
+}
+
+// Describe implements prometheus.Collector
+func (t *Expvar) Describe(ch chan<- *prometheus.Desc) {
+	ch <- descAppliedIndex
+	ch <- descCommitIndex
+	ch <- descLastSnapshotIndex
+	ch <- descTerm
+	ch <- descLeader
+}
+
+// Collect implements prometheus.Collector
+func (t *Expvar) Collect(ch chan<- prometheus.Metric) {
+	m := t.Metrics()
+	ch <- prometheus.MustNewConstMetric(descAppliedIndex, prometheus.GaugeValue, float64(m.AppliedIndex))
+	ch <- prometheus.MustNewConstMetric(descCommitIndex, prometheus.GaugeValue, float64(m.CommitIndex))
+	ch <- prometheus.MustNewConstMetric(descLastSnapshotIndex, prometheus.GaugeValue, float64(m.LastSnapshotIndex))
+	ch <- prometheus.MustNewConstMetric(descTerm, prometheus.GaugeValue, float64(m.Term))
+	ch <- prometheus.MustNewConstMetric(descLeader, prometheus.GaugeValue, 1, m.Leader)
+}
+
+// String implements expvar.Var
+func (t *Expvar) String() string {
+	encoded, _ := json.Marshal(t.Metrics())
+	return string(encoded)
+}
@@ -0,0 +1,86 @@
+package raftutil_test
+
+import (
+	"encoding/json"
+	"expvar"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/raft"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	. "github.com/james-lawrence/bw/clustering/raftutil"
+)
+
+// fakeRaft minimal raft state for the metrics.
+type fakeRaft struct {
+	applied, commit, snapshot, term uint64
+	leader                          raft.ServerAddress
+}
+
+func (t *fakeRaft) Stats() map[string]string {
+	return map[string]string{
+		"applied_index":       strconv.FormatUint(t.applied, 10),
+		"commit_index":        strconv.FormatUint(t.commit, 10),
+		"last_snapshot_index": strconv.FormatUint(t.snapshot, 10),
+		"term":                strconv.FormatUint(t.term, 10),
+	}
+}
+
+func (t *fakeRaft) Leader() raft.ServerAddress {
+	return t.leader
+}
+
+var _ = Describe("Expvar", func() {
+	read := func() (m ExpvarMetrics) {
+		Expect(json.Unmarshal([]byte(expvar.Get(ExpvarKey).String()), &m)).To(Succeed())
+		return m
+	}
+
+	It("should publish the metrics of the tracked raft instance", func() {
+		v := PublishExpvar()
+		Expect(PublishExpvar()).To(BeIdenticalTo(v))
+
+		r := &fakeRaft{term: 1}
+		v.Track(r)
+		Expect(read()).To(Equal(ExpvarMetrics{Term: 1}))
+
+		r.leader = "127.0.0.1:2000"
+		r.term, r.commit, r.applied = 2, 10, 9
+		Expect(read()).To(Equal(ExpvarMetrics{AppliedIndex: 9, CommitIndex: 10, Term: 2, Leader: "127.0.0.1:2000"}))
+
+		r.snapshot, r.applied = 8, 10
+		Expect(read()).To(Equal(ExpvarMetrics{AppliedIndex: 10, CommitIndex: 10, LastSnapshotIndex: 8, Term: 2, Leader: "127.0.0.1:2000"}))
+
+		replacement := &fakeRaft{term: 3}
+		v.Track(replacement)
+		v.Untrack(r)
+		Expect(read()).To(Equal(ExpvarMetrics{Term: 3}))
+
+		v.Untrack(replacement)
+		Expect(read()).To(Equal(ExpvarMetrics{}))
+	})
+
+	It("should register the metrics of the tracked raft instance with prometheus", func() {
+		v := PublishExpvar()
+		r := &fakeRaft{term: 4, commit: 12, applied: 11, leader: "127.0.0.1:2000"}
+		v.Track(r)
+		defer v.Untrack(r)
+
+		expected := `
+# HELP bw_raft_commit_index index of the last log committed by the quorum.
+# TYPE bw_raft_commit_index gauge
+bw_raft_commit_index 12
+# HELP bw_raft_leader leader of the quorum observed by the local raft instance.
+# TYPE bw_raft_leader gauge
+bw_raft_leader{leader="127.0.0.1:2000"} 1
+# HELP bw_raft_term current term of the local raft instance.
+# TYPE bw_raft_term gauge
+bw_raft_term 4
+`
+		Expect(testutil.GatherAndCompare(prometheus.DefaultGatherer, strings.NewReader(expected), "bw_raft_commit_index", "bw_raft_leader", "bw_raft_term")).To(Succeed())
+	})
+})
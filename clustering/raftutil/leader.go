@@ -24,7 +24,7 @@ func (t leader) Update(c rendezvous) state {
 	log.Printf("leader update invoked: %p - %s\n", t.r, t.protocol.PassiveCheckin)
 	switch t.r.State() {
 	case raft.Leader:
-		if t.cleanupPeers(t.protocol.LocalNode, agent.QuorumNodes(c)...) {
+		if t.cleanupPeers(t.protocol.LocalNode, agent.QuorumNodes(c), agent.NonVoterNodes(c)...) {
 			refresh := time.Second
 			log.Println("peers unstable, will refresh in", refresh)
 			return delayed(t, t.protocol.ClusterChange, refresh)
@@ -38,7 +38,7 @@ func (t leader) Update(c rendezvous) state {
 }
 
 // cleanupPeers returns true if the peer set was unstable.
-func (t leader) cleanupPeers(local *memberlist.Node, candidates ...*memberlist.Node) (unstable bool) {
+func (t leader) cleanupPeers(local *memberlist.Node, candidates []*memberlist.Node, nonvoters ...*memberlist.Node) (unstable bool) {
 	const (
 		commitTimeout = 10 * time.Second
 	)
@@ -86,6 +86,31 @@ func (t leader) cleanupPeers(local *memberlist.Node, candidates ...*memberlist.N
 		}
 	}
 
+	for _, peer := range nonvoters {
+		if rs, err = NodeToServer(peer); err != nil {
+			log.Println("failed to lookup peer", err)
+			return true
+		}
+
+		peers = removePeer(rs.ID, peers...)
+
+		if rs.ID == raft.ServerID(local.Name) || len(strings.TrimSpace(string(rs.Address))) == 0 {
+			continue
+		}
+
+		if suffrage(rs.ID, unmodifiedPeers...) == raft.Voter {
+			err = t.r.DemoteVoter(rs.ID, 0, commitTimeout).Error()
+		} else {
+			err = t.r.AddNonvoter(rs.ID, rs.Address, 0, commitTimeout).Error()
+		}
+
+		if err != nil {
+			log.Println("failed to add non-voter", spew.Sdump(rs), spew.Sdump(unmodifiedPeers), err)
+			unstable = true
+			continue
+		}
+	}
+
 	for _, peer := range peers {
 		if peer.ID == raft.ServerID(local.Name) {
 			continue
@@ -125,6 +150,17 @@ func voterCount(peers []raft.Server) (c int) {
 	return c
 }
 
+// suffrage of the peer within the configuration, peers missing from the configuration are staging.
+func suffrage(id raft.ServerID, peers ...raft.Server) raft.ServerSuffrage {
+	for _, peer := range peers {
+		if peer.ID == id {
+			return peer.Suffrage
+		}
+	}
+
+	return raft.Staging
+}
+
 func removePeer(id raft.ServerID, peers ...raft.Server) []raft.Server {
 	result := make([]raft.Server, 0, len(peers))
 	for _, peer := range peers {
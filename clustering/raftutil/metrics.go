@@ -0,0 +1,28 @@
+package raftutil
+
+import (
+	"github.com/hashicorp/raft"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	metricStateTransitions = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "bw_raft_state_transitions_total",
+		Help: "transitions of the local raft instance into the state.",
+	}, []string{"state"})
+	metricLeadershipChange = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "bw_raft_leadership_changes_total",
+		Help: "changes in the leader of the quorum observed by this agent.",
+	})
+)
+
+// observeMetrics records the raft observation, e.g.) state transitions and leadership changes.
+func observeMetrics(o *raft.Observation) {
+	switch evt := o.Data.(type) {
+	case raft.RaftState:
+		metricStateTransitions.WithLabelValues(evt.String()).Inc()
+	case raft.LeaderObservation:
+		metricLeadershipChange.Inc()
+	}
+}
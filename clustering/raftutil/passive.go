@@ -76,7 +76,7 @@ func (t passive) Update(c rendezvous) state {
 		done:        done,
 	}
 
-	if r.LastIndex() == 0 {
+	if r.LastIndex() == 0 && !t.protocol.nonvoter {
 		if err = r.BootstrapCluster(configuration(c)).Error(); err != nil {
 			log.Println("raft bootstrap failed", r.LastIndex(), err)
 			sm.cleanShutdown()
@@ -1,8 +1,14 @@
 package raftutil
 
 import (
+	"fmt"
+	"net"
 	"sync"
 
+	"github.com/hashicorp/memberlist"
+	"github.com/james-lawrence/bw/agent"
+	"github.com/james-lawrence/bw/clustering"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
@@ -34,3 +40,36 @@ var _ = Describe("passive state", func() {
 		})
 	})
 })
+
+var _ = Describe("non-voter membership", func() {
+	cluster := func(voters, nonvoters int) (c clustering.Mock, nonvoter *memberlist.Node) {
+		var nodes []*memberlist.Node
+		for i := 0; i < voters+nonvoters; i++ {
+			nodes = append(nodes, agent.PeerToNode(agent.NewPeer(
+				fmt.Sprintf("node-%d", i),
+				agent.PeerOptionIP(net.IPv4(127, 0, 0, byte(i+1))),
+				agent.PeerOptionNonVoter(i >= voters),
+			)))
+		}
+
+		return clustering.NewMock(nodes[len(nodes)-1], nodes[:len(nodes)-1]...), nodes[len(nodes)-1]
+	}
+
+	It("should wait for the voters to satisfy the minimum quorum", func() {
+		c, local := cluster(2, 1)
+		Expect(Protocol{LocalNode: local, nonvoter: true, minQuorum: agent.QuorumDefault}.isMember(c)).To(BeFalse())
+		Expect(Protocol{LocalNode: local, nonvoter: true, minQuorum: 2}.isMember(c)).To(BeTrue())
+	})
+
+	It("should never count towards the quorum", func() {
+		c, local := cluster(0, 1)
+		Expect(configuration(c).Servers).To(BeEmpty())
+		Expect(Protocol{LocalNode: local, nonvoter: true, minQuorum: 1}.isMember(c)).To(BeFalse())
+	})
+
+	It("should join a single node cluster", func() {
+		c, local := cluster(1, 1)
+		Expect(configuration(c).Servers).To(HaveLen(1))
+		Expect(Protocol{LocalNode: local, nonvoter: true, minQuorum: 1}.isMember(c)).To(BeTrue())
+	})
+})
@@ -119,6 +119,13 @@ func ProtocolOptionPassiveReset(reset func() (Storage, raft.SnapshotStore, error
 	}
 }
 
+// ProtocolOptionExpvar publish the metrics of the raft instance via the expvar.
+func ProtocolOptionExpvar(v *Expvar) ProtocolOption {
+	return func(p *Protocol) {
+		p.expvar = v
+	}
+}
+
 // ProtocolOptionEnableSingleNode operation
 func ProtocolOptionEnableSingleNode(b bool) ProtocolOption {
 	return func(p *Protocol) {
@@ -145,6 +152,15 @@ func ProtocolOptionQuorumMinimum(min int) ProtocolOption {
 	}
 }
 
+// ProtocolOptionNonVoter join the raft protocol as a non-voter, non-voters replicate the log
+// but never vote, bootstrap the cluster, or count towards the minimum quorum. they join once
+// the voters are able to satisfy the minimum quorum.
+func ProtocolOptionNonVoter(b bool) ProtocolOption {
+	return func(p *Protocol) {
+		p.nonvoter = b
+	}
+}
+
 // NewProtocol ...
 func NewProtocol(ctx context.Context, local *memberlist.Node, q *grpc.ClientConn, options ...ProtocolOption) (_ignored Protocol, err error) {
 	p := Protocol{
@@ -226,6 +242,10 @@ func (t *stateMeta) waitShutdown(c rendezvous) {
 
 func (t *stateMeta) cleanShutdown() {
 	if t.r != nil {
+		if t.protocol.expvar != nil {
+			t.protocol.expvar.Untrack(t.r)
+		}
+
 		if err := t.r.Shutdown().Error(); err != nil {
 			log.Println(t.protocol.LocalNode.Name, "failed to shutdown raft", err)
 		}
@@ -277,6 +297,7 @@ type Storage interface {
 // It cannot be instantiated directly, instead use NewProtocol.
 type Protocol struct {
 	minQuorum        int
+	nonvoter         bool
 	LocalNode        *memberlist.Node
 	Context          context.Context
 	StabilityQueue   *grpc.ClientConn
@@ -287,6 +308,7 @@ type Protocol struct {
 	getTransport     func() (raft.Transport, error)
 	observers        []*raft.Observer
 	clusterObserver  clusterObserver
+	expvar           *Expvar
 	config           *raft.Config
 	lastContactGrace time.Duration // how long to wait before a missing leader triggers a reset
 }
@@ -367,6 +389,7 @@ func (t *Protocol) connect(c rendezvous) (network raft.Transport, r *raft.Raft,
 	}
 
 	r.RegisterObserver(raft.NewObserver(nil, false, func(o *raft.Observation) bool {
+		observeMetrics(o)
 		if envx.Boolean(false, bw.EnvLogsRaft, bw.EnvLogsVerbose) {
 			switch evt := o.Data.(type) {
 			case raft.RaftState:
@@ -383,7 +406,11 @@ func (t *Protocol) connect(c rendezvous) (network raft.Transport, r *raft.Raft,
 		r.RegisterObserver(o)
 	}
 
-	if idx := r.LastIndex(); idx == 0 {
+	if t.expvar != nil {
+		t.expvar.Track(r)
+	}
+
+	if idx := r.LastIndex(); idx == 0 && !t.nonvoter {
 		if err = r.BootstrapCluster(quorum).Error(); err != nil {
 			return network, r, errorsx.Compact(
 				errors.Wrapf(err, "raft bootstrap failed: %d", idx),
@@ -481,8 +508,12 @@ func (t Protocol) MaybeLeave(c rendezvous) bool {
 }
 
 // isMember utility function for checking if the local node of the cluster is a member
-// of the possiblePeers set.
+// of the possiblePeers set. non-voters are members whenever the voters can form a quorum.
 func (t Protocol) isMember(c rendezvous) bool {
+	if t.nonvoter {
+		return len(agent.QuorumNodes(c)) >= t.minQuorum
+	}
+
 	return isPossiblePeer(t.LocalNode, agent.QuorumNodes(c)...)
 }
 
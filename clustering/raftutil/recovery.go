@@ -0,0 +1,51 @@
+package raftutil
+
+import (
+	"log"
+
+	"github.com/hashicorp/raft"
+	"github.com/pkg/errors"
+)
+
+// RecoverLogs best effort recovery of a partially corrupt log store. the intact prefix
+// of the log is retained and every entry from the first unreadable entry onwards is removed,
+// allowing raft to replay the retained entries on top of the latest valid snapshot.
+// returns an error when the store itself is unusable.
+func RecoverLogs(store raft.LogStore) (err error) {
+	var (
+		first, last uint64
+		entry       raft.Log
+	)
+
+	if first, err = store.FirstIndex(); err != nil {
+		return errors.Wrap(err, "failed to read the first log index")
+	}
+
+	if last, err = store.LastIndex(); err != nil {
+		return errors.Wrap(err, "failed to read the last log index")
+	}
+
+	// empty log, nothing to recover.
+	if last == 0 {
+		return nil
+	}
+
+	for idx := first; idx <= last; idx++ {
+		cause := store.GetLog(idx, &entry)
+		if cause == nil && entry.Index == idx {
+			continue
+		}
+
+		log.Println("raft log corrupted, discarding entries", idx, "-", last, cause)
+		return errors.Wrapf(store.DeleteRange(idx, last), "failed to discard corrupt log entries %d - %d", idx, last)
+	}
+
+	return nil
+}
+
+// RecoverSnapshots ensures the snapshot store is readable, raft restores from the
+// latest snapshot it is able to open.
+func RecoverSnapshots(snapshots raft.SnapshotStore) (err error) {
+	_, err = snapshots.List()
+	return errors.Wrap(err, "failed to list raft snapshots")
+}
@@ -0,0 +1,69 @@
+package raftutil_test
+
+import (
+	"github.com/hashicorp/raft"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+
+	"github.com/james-lawrence/bw/clustering/raftutil"
+)
+
+// corrupted fails to decode the entries at or beyond the index.
+type corrupted struct {
+	*raft.InmemStore
+	index uint64
+}
+
+func (t corrupted) GetLog(idx uint64, log *raft.Log) error {
+	if idx >= t.index {
+		return errors.New("msgpack decode error")
+	}
+
+	return t.InmemStore.GetLog(idx, log)
+}
+
+var _ = Describe("RecoverLogs", func() {
+	store := func(n uint64) *raft.InmemStore {
+		s := raft.NewInmemStore()
+		for idx := uint64(1); idx <= n; idx++ {
+			Expect(s.StoreLog(&raft.Log{Index: idx, Term: 1, Type: raft.LogCommand, Data: []byte{byte(idx)}})).To(Succeed())
+		}
+		return s
+	}
+
+	It("should discard a corrupt tail entry retaining the intact entries", func() {
+		s := corrupted{InmemStore: store(5), index: 5}
+		Expect(raftutil.RecoverLogs(s)).To(Succeed())
+
+		last, err := s.LastIndex()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(last).To(Equal(uint64(4)))
+
+		for idx := uint64(1); idx <= last; idx++ {
+			var entry raft.Log
+			Expect(s.GetLog(idx, &entry)).To(Succeed())
+			Expect(entry.Data).To(Equal([]byte{byte(idx)}))
+		}
+	})
+
+	It("should discard every entry following the first corrupt entry", func() {
+		s := corrupted{InmemStore: store(5), index: 3}
+		Expect(raftutil.RecoverLogs(s)).To(Succeed())
+		last, err := s.LastIndex()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(last).To(Equal(uint64(2)))
+	})
+
+	It("should leave an intact log untouched", func() {
+		s := store(5)
+		Expect(raftutil.RecoverLogs(s)).To(Succeed())
+		last, err := s.LastIndex()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(last).To(Equal(uint64(5)))
+	})
+
+	It("should succeed on an empty log", func() {
+		Expect(raftutil.RecoverLogs(raft.NewInmemStore())).To(Succeed())
+	})
+})
@@ -0,0 +1,99 @@
+package clustering
+
+import (
+	"context"
+	"net"
+	"sync"
+
+	"github.com/james-lawrence/bw/agent"
+	"github.com/james-lawrence/bw/internal/errorsx"
+	"github.com/pkg/errors"
+)
+
+type dialer interface {
+	DialContext(ctx context.Context, network, address string) (net.Conn, error)
+}
+
+// NewRouter routes connections to the members of the cluster via the advertised address
+// preferred by the networks of the local interfaces, see agent.LocalRoutes. the addresses
+// of the members (memberlist node address and raft address) are the primary advertised
+// address of the member. until the members are tracked addresses are used as is.
+func NewRouter() *Router {
+	return &Router{}
+}
+
+// Router see NewRouter.
+type Router struct {
+	m       sync.RWMutex
+	members memberset
+}
+
+// Track the members of the cluster.
+func (t *Router) Track(c memberset) {
+	t.m.Lock()
+	defer t.m.Unlock()
+	t.members = c
+}
+
+// Routes to the address in order of preference, addresses that don't belong to
+// a member only route to themselves.
+func (t *Router) Routes(address string) []string {
+	t.m.RLock()
+	members := t.members
+	t.m.RUnlock()
+
+	if members == nil {
+		return []string{address}
+	}
+
+	for _, n := range members.Members() {
+		if n.Address() != address {
+			continue
+		}
+
+		p, err := agent.NodeToPeer(n)
+		if err != nil || len(p.Alternates) == 0 {
+			return []string{address}
+		}
+
+		routes := agent.LocalRoutes(p)
+		addresses := make([]string, 0, len(routes))
+		for _, r := range routes {
+			addresses = append(addresses, agent.RaftAddress(r))
+		}
+
+		return addresses
+	}
+
+	return []string{address}
+}
+
+// Dialer dials the routes to the address in order until one succeeds.
+func (t *Router) Dialer(d dialer) RouteDialer {
+	return RouteDialer{router: t, d: d}
+}
+
+// RouteDialer see Router.Dialer.
+type RouteDialer struct {
+	router *Router
+	d      dialer
+}
+
+// DialContext the routes to the address.
+func (t RouteDialer) DialContext(ctx context.Context, network, address string) (conn net.Conn, err error) {
+	var cause error
+
+	for _, route := range t.router.Routes(address) {
+		if conn, cause = t.d.DialContext(ctx, network, route); cause == nil {
+			return conn, nil
+		}
+
+		err = errorsx.Compact(err, errors.Wrapf(cause, "route %s", route))
+
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	return nil, err
+}
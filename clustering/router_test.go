@@ -0,0 +1,75 @@
+package clustering_test
+
+import (
+	"context"
+	"net"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/james-lawrence/bw/agent"
+	"github.com/james-lawrence/bw/clustering"
+	"github.com/james-lawrence/bw/internal/errorsx"
+)
+
+// recordingDialer records the dialed addresses, failing every address except the accepted one.
+type recordingDialer struct {
+	accepted string
+	dialed   *[]string
+}
+
+func (t recordingDialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	*t.dialed = append(*t.dialed, address)
+	if address != t.accepted {
+		return nil, errorsx.String("connection refused")
+	}
+
+	c, _ := net.Pipe()
+	return c, nil
+}
+
+var _ = Describe("Router", func() {
+	// the alternate resides within the loopback network of the local interfaces.
+	split := agent.NewPeer(
+		"node1",
+		agent.PeerOptionIP(net.ParseIP("198.51.100.1")),
+		agent.PeerOptionAlternates("127.0.0.2:2001"),
+	)
+
+	It("should prefer the route within the local networks", func() {
+		r := clustering.NewRouter()
+		r.Track(staticMembers{agent.PeerToNode(split)})
+		Expect(r.Routes(agent.RaftAddress(split))).To(Equal([]string{"127.0.0.2:2001", agent.RaftAddress(split)}))
+	})
+
+	It("should route addresses as is until the members are tracked", func() {
+		Expect(clustering.NewRouter().Routes("198.51.100.1:2000")).To(Equal([]string{"198.51.100.1:2000"}))
+	})
+
+	It("should route unknown addresses as is", func() {
+		r := clustering.NewRouter()
+		r.Track(staticMembers{agent.PeerToNode(split)})
+		Expect(r.Routes("198.51.100.2:2000")).To(Equal([]string{"198.51.100.2:2000"}))
+	})
+
+	It("should dial the remaining routes when the preferred route fails", func() {
+		var dialed []string
+		r := clustering.NewRouter()
+		r.Track(staticMembers{agent.PeerToNode(split)})
+
+		conn, err := r.Dialer(recordingDialer{accepted: agent.RaftAddress(split), dialed: &dialed}).DialContext(context.Background(), "tcp", agent.RaftAddress(split))
+		Expect(err).To(Succeed())
+		Expect(conn.Close()).To(Succeed())
+		Expect(dialed).To(Equal([]string{"127.0.0.2:2001", agent.RaftAddress(split)}))
+	})
+
+	It("should fail when every route fails", func() {
+		var dialed []string
+		r := clustering.NewRouter()
+		r.Track(staticMembers{agent.PeerToNode(split)})
+
+		_, err := r.Dialer(recordingDialer{dialed: &dialed}).DialContext(context.Background(), "tcp", agent.RaftAddress(split))
+		Expect(err).To(MatchError(ContainSubstring("connection refused")))
+		Expect(dialed).To(HaveLen(2))
+	})
+})
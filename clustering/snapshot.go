@@ -3,6 +3,7 @@ package clustering
 import (
 	"context"
 	"log"
+	"reflect"
 	"time"
 )
 
@@ -16,16 +17,25 @@ func SnapshotOptionContext(ctx context.Context) SnapshotOption {
 	}
 }
 
-// SnapshotOptionFrequency specify how often a snapshot should be taken.
+// SnapshotOptionFrequency specify how often a snapshot should be taken, <= 0 disables snapshots.
 func SnapshotOptionFrequency(freq time.Duration) SnapshotOption {
 	return func(s *snapshot) {
 		s.Frequency = freq
 	}
 }
 
+// SnapshotOptionWarmup delays the initial snapshot until the peers of the cluster
+// have been stable for the warmup period. <= 0 takes the initial snapshot immediately.
+func SnapshotOptionWarmup(d time.Duration) SnapshotOption {
+	return func(s *snapshot) {
+		s.Warmup = d
+	}
+}
+
 type snapshot struct {
 	Context   context.Context
 	Frequency time.Duration
+	Warmup    time.Duration
 }
 
 func newSnapshot(options ...SnapshotOption) (snapper snapshot) {
@@ -39,19 +49,34 @@ func newSnapshot(options ...SnapshotOption) (snapper snapshot) {
 	return snapper
 }
 
-// Snapshot - performs a periodic snapshot of the cluster. blocking.
+// SnapshotEnabled reports if the options result in periodic snapshots of the cluster.
+func SnapshotEnabled(options ...SnapshotOption) bool {
+	return newSnapshot(options...).Frequency > 0
+}
+
+// Snapshot - performs a periodic snapshot of the cluster. blocking, returns
+// immediately when snapshots are disabled.
 func Snapshot(c Rendezvous, s snapshotter, options ...SnapshotOption) {
 	var (
 		err     error
 		snapper = newSnapshot(options...)
 	)
+
+	if snapper.Frequency <= 0 {
+		return
+	}
+
 	take := func() {
 		log.Println("taking snapshot of the cluster")
 		if err = s.Snapshot(Peers(c)); err != nil {
 			log.Println("failed to snapshot cluster", err)
 		}
 	}
-	// take an initial snapshot immediately.
+	if !warmup(snapper.Context, c, snapper.Warmup) {
+		return
+	}
+
+	// take an initial snapshot.
 	take()
 	// then take a snapshot every period.
 	tick := time.NewTicker(snapper.Frequency)
@@ -65,3 +90,27 @@ func Snapshot(c Rendezvous, s snapshotter, options ...SnapshotOption) {
 		}
 	}
 }
+
+// warmup blocks until the peers of the cluster are unchanged for the duration.
+// returns false if the context is done before the cluster stabilized.
+func warmup(ctx context.Context, c Rendezvous, d time.Duration) bool {
+	if d <= 0 {
+		return true
+	}
+
+	for previous := Peers(c); ; {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(d):
+		}
+
+		current := Peers(c)
+		if reflect.DeepEqual(previous, current) {
+			return true
+		}
+
+		log.Println("cluster membership changed during snapshot warmup, delaying initial snapshot")
+		previous = current
+	}
+}
@@ -0,0 +1,89 @@
+package clustering_test
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/memberlist"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/james-lawrence/bw/clustering"
+)
+
+type recordingSnapshotter struct {
+	m     sync.Mutex
+	taken int
+}
+
+func (t *recordingSnapshotter) Snapshot([]string) error {
+	t.m.Lock()
+	defer t.m.Unlock()
+	t.taken++
+	return nil
+}
+
+func (t *recordingSnapshotter) Taken() int {
+	t.m.Lock()
+	defer t.m.Unlock()
+	return t.taken
+}
+
+var _ = Describe("Snapshot", func() {
+	c := clustering.NewMock(
+		&memberlist.Node{Name: "node1", Addr: net.ParseIP("127.0.0.1"), Port: 2000},
+		&memberlist.Node{Name: "node2", Addr: net.ParseIP("127.0.0.2"), Port: 2000},
+	)
+
+	It("should take the initial snapshot immediately without a warmup", func() {
+		ctx, done := context.WithCancel(context.Background())
+		defer done()
+
+		s := &recordingSnapshotter{}
+		go clustering.Snapshot(c, s, clustering.SnapshotOptionContext(ctx))
+		Eventually(s.Taken).Should(Equal(1))
+	})
+
+	It("should delay the initial snapshot until the warmup elapses", func() {
+		const warmup = 200 * time.Millisecond
+		ctx, done := context.WithCancel(context.Background())
+		defer done()
+
+		s := &recordingSnapshotter{}
+		go clustering.Snapshot(
+			c,
+			s,
+			clustering.SnapshotOptionContext(ctx),
+			clustering.SnapshotOptionWarmup(warmup),
+			clustering.SnapshotOptionFrequency(time.Hour),
+		)
+
+		Consistently(s.Taken, warmup/2, 10*time.Millisecond).Should(Equal(0))
+		Eventually(s.Taken, time.Second).Should(Equal(1))
+	})
+
+	It("should not snapshot when the context is done during the warmup", func() {
+		ctx, done := context.WithCancel(context.Background())
+		s := &recordingSnapshotter{}
+		finished := make(chan struct{})
+		go func() {
+			defer close(finished)
+			clustering.Snapshot(c, s, clustering.SnapshotOptionContext(ctx), clustering.SnapshotOptionWarmup(time.Hour))
+		}()
+
+		done()
+		Eventually(finished).Should(BeClosed())
+		Expect(s.Taken()).To(Equal(0))
+	})
+
+	It("should return immediately when snapshots are disabled", func() {
+		s := &recordingSnapshotter{}
+		Expect(clustering.SnapshotEnabled(clustering.SnapshotOptionFrequency(0))).To(BeFalse())
+		Expect(clustering.SnapshotEnabled()).To(BeTrue())
+
+		clustering.Snapshot(c, s, clustering.SnapshotOptionFrequency(-time.Second))
+		Expect(s.Taken()).To(Equal(0))
+	})
+})
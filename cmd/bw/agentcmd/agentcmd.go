@@ -7,10 +7,12 @@ import (
 )
 
 type Config struct {
-	Location       string         `name:"agent-config" help:"configuration file to load" default:"${vars_bw_default_agent_configuration_location}"`
-	Address        *net.TCPAddr   `name:"agent-address" alias:"agent-p2p" help:"address for the agent to bind" default:"${vars_bw_default_agent_address}" env:"${env_bw_agent_bind_primary}"`
-	P2PAdvertised  *net.TCPAddr   `name:"agent-address-advertised" alias:"agent-p2p-advertised" help:"ip address to advertise" env:"${env_bw_agent_bind_advertised}"`
-	AlternateBinds []*net.TCPAddr `name:"agent-address-bindings" alias:"agent-p2p-alternates" help:"additional ip/port for the server to bind" placeholder:"127.0.0.1:2000" env:"${env_bw_agent_bind_secondary}"`
+	Location            string         `name:"agent-config" help:"configuration file to load" default:"${vars_bw_default_agent_configuration_location}"`
+	Address             *net.TCPAddr   `name:"agent-address" alias:"agent-p2p" help:"address for the agent to bind" default:"${vars_bw_default_agent_address}" env:"${env_bw_agent_bind_primary}"`
+	AddressCIDR         *net.IPNet     `name:"agent-address-cidr" help:"bind the first local address within the network using the port of the agent address, e.g.) 10.0.0.0/8" env:"${env_bw_agent_bind_cidr}"`
+	P2PAdvertised       *net.TCPAddr   `name:"agent-address-advertised" alias:"agent-p2p-advertised" help:"ip address to advertise" env:"${env_bw_agent_bind_advertised}"`
+	AlternateAdvertised []*net.TCPAddr `name:"agent-address-advertised-alternates" help:"additional ip/port to advertise for split-horizon networks, peers prefer the address within their local networks" placeholder:"10.0.0.1:2000" env:"${env_bw_agent_bind_advertised_alternates}"`
+	AlternateBinds      []*net.TCPAddr `name:"agent-address-bindings" alias:"agent-p2p-alternates" help:"additional ip/port for the server to bind" placeholder:"127.0.0.1:2000" env:"${env_bw_agent_bind_secondary}"`
 }
 
 func (t Config) AfterApply(config *agent.Config) (err error) {
@@ -18,11 +20,21 @@ func (t Config) AfterApply(config *agent.Config) (err error) {
 		t.Address = config.P2PBind
 	}
 
-	*config = config.Clone(
+	options := []agent.ConfigOption{
 		agent.ConfigOptionP2P(t.Address),
 		agent.ConfigOptionAdvertised(t.P2PAdvertised),
 		agent.ConfigOptionSecondaryBindings(t.AlternateBinds...),
-	).EnsureDefaults()
+	}
+
+	if len(t.AlternateAdvertised) > 0 {
+		options = append(options, agent.ConfigOptionAlternateAdvertised(t.AlternateAdvertised...))
+	}
+
+	if t.AddressCIDR != nil {
+		options = append(options, agent.ConfigOptionBindFromCIDR(t.AddressCIDR))
+	}
+
+	*config = config.Clone(options...).EnsureDefaults()
 
 	return nil
 }
@@ -1,10 +1,12 @@
 package agentcmd
 
 import (
+	"context"
 	"crypto/tls"
 	"log"
 	"net"
 	"path/filepath"
+	"syscall"
 	"time"
 
 	"github.com/james-lawrence/bw"
@@ -13,11 +15,13 @@ import (
 	"github.com/james-lawrence/bw/agent/dialers"
 	"github.com/james-lawrence/bw/certificatecache"
 	"github.com/james-lawrence/bw/cluster"
+	"github.com/james-lawrence/bw/clustering"
 	"github.com/james-lawrence/bw/cmd/bw/cmdopts"
 	"github.com/james-lawrence/bw/cmd/commandutils"
 	"github.com/james-lawrence/bw/daemons"
 	"github.com/james-lawrence/bw/deployment"
 	"github.com/james-lawrence/bw/directives/shell"
+	"github.com/james-lawrence/bw/internal/contextx"
 	"github.com/james-lawrence/bw/internal/envx"
 	"github.com/james-lawrence/bw/internal/errorsx"
 	"github.com/james-lawrence/bw/internal/rsax"
@@ -55,7 +59,7 @@ func (t CmdRuntime) Run(ctx *cmdopts.Global, aconfig *agent.Config) (err error)
 		return err
 	}
 
-	deployer := deployment.NewDirective(
+	deployer := deployment.NewExecutors(
 		deployment.DirectiveOptionShellContext(sctx),
 	)
 
@@ -77,16 +81,21 @@ type daemon struct {
 
 func (t *daemon) bind(ctx *cmdopts.Global, config agent.Config, deployer daemons.Deployer) (err error) {
 	var (
-		ring      *memberlist.Keyring
-		l         net.Listener
-		bound     []net.Listener
-		localpriv []byte
-		localpub  []byte
-		tc        storage.TorrentConfig
-		tlscreds  *tls.Config
-		ns        notary.Composite
-		ss        notary.Signer
-		acmesvc   acme.DiskCache
+		defaults    = config
+		ring        *memberlist.Keyring
+		l           net.Listener
+		altbound    []net.Listener
+		localpriv   []byte
+		localpub    []byte
+		tc          storage.TorrentConfig
+		tlscreds    *tls.Config
+		gossipcreds *tls.Config
+		primary     *tls.Config
+		alternates  *tls.Config
+		ns          notary.Composite
+		ss          notary.Signer
+		agentsigner *notary.ReloadingSigner
+		acmesvc     acme.DiskCache
 	)
 
 	if config, err = commandutils.LoadAgentConfig(t.Location, config); err != nil {
@@ -132,7 +141,11 @@ func (t *daemon) bind(ctx *cmdopts.Global, config agent.Config, deployer daemons
 		return err
 	}
 
-	if tlscreds, err = certificatecache.TLSGenServer(config, tlsx.OptionNoClientCert); err != nil {
+	if gossipcreds, err = certificatecache.TLSGenGossip(config); err != nil {
+		return err
+	}
+
+	if tlscreds, err = certificatecache.TLSGenServer(config, tlsx.OptionNoClientCert, certificatecache.OptionGossipIdentity(gossipcreds)); err != nil {
 		return err
 	}
 
@@ -145,7 +158,6 @@ func (t *daemon) bind(ctx *cmdopts.Global, config agent.Config, deployer daemons
 	if l, err = net.ListenTCP("tcp", config.P2PBind); err != nil {
 		return err
 	}
-	bound = append(bound, l)
 
 	log.Println("alternate bindings", len(config.AlternateBinds))
 	for _, alt := range config.AlternateBinds {
@@ -157,12 +169,22 @@ func (t *daemon) bind(ctx *cmdopts.Global, config agent.Config, deployer daemons
 			return err
 		}
 
-		bound = append(bound, l2)
+		altbound = append(altbound, l2)
+	}
+
+	tlsdialer := dialers.NewBreaker(
+		tlsx.NewDialer(tlsx.MustClone(tlscreds, certificatecache.OptionVerifyClockSkew(config.CertClockSkew)), tlsx.DialerOptionTimeout(config.TLSHandshakeTimeout)),
+		dialers.BreakerOptionConfig(config),
+	)
+	muxed := dialers.WithMuxer(tlsdialer, l.Addr())
+	if config.Multiplex {
+		log.Println("multiplexing connections to each peer over a single connection")
+		muxed = dialers.WithMuxer(muxer.NewSessions(tlsdialer), l.Addr())
 	}
 
 	// grpc can be insecure because the socket itself has tls.
 	dialer := dialers.NewDefaults(
-		dialers.WithMuxer(tlsx.NewDialer(tlscreds), l.Addr()),
+		muxed,
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
 		grpc.WithPerRPCCredentials(ss),
 	)
@@ -171,21 +193,29 @@ func (t *daemon) bind(ctx *cmdopts.Global, config agent.Config, deployer daemons
 		return err
 	}
 
+	// the daemons outlive the shutdown signal until the agent has drained, see Config.DrainTimeout.
+	running, stop := context.WithCancel(contextx.WithWaitGroup(context.Background(), ctx.Cleanup))
+	shutdown := func() {
+		stop()
+		ctx.Shutdown()
+	}
+
 	dctx := daemons.Context{
 		Deploys:           deployer,
 		Local:             local,
 		Listener:          l,
 		Dialer:            dialer,
-		Muxer:             muxer.New(),
+		Muxer:             muxer.New(muxer.OptionHandshakeTimeout(config.TLSHandshakeTimeout)),
 		ConfigurationFile: t.Location,
 		Config:            config,
-		Context:           ctx.Context,
-		Shutdown:          ctx.Shutdown,
+		Context:           running,
+		Shutdown:          shutdown,
 		Cleanup:           ctx.Cleanup,
 		DebugLog:          commandutils.DebugLog(envx.Boolean(false, bw.EnvLogsGossip)),
 		NotaryStorage:     ns,
 		NotaryAuth:        notary.NewAuth(ns),
 		RPCCredentials:    tlscreds,
+		GossipCredentials: gossipcreds,
 		RPCKeepalivePolicy: keepalive.EnforcementPolicy{
 			MinTime:             10 * time.Second,
 			PermitWithoutStream: true,
@@ -197,9 +227,17 @@ func (t *daemon) bind(ctx *cmdopts.Global, config agent.Config, deployer daemons
 		Results:       make(chan *deployment.DeployResult, 100),
 		PeeringEvents: clusterevents,
 		ACMECache:     acmesvc,
+		Drainer:       daemons.NewDrainer(),
+		Membership:    clustering.NewEventBus(),
+	}
+
+	daemons.DrainOnShutdown(ctx.Context, dctx)
+
+	if err = daemons.Metrics(dctx); err != nil {
+		return errors.Wrap(err, "failed to initialize metrics service")
 	}
 
-	if dctx, err = daemons.Proxy(dctx, tlsx.NewDialer(tlscreds)); err != nil {
+	if dctx, err = daemons.Proxy(dctx, tlsx.NewDialer(tlsx.MustClone(tlscreds, certificatecache.OptionVerifyClockSkew(config.CertClockSkew)), tlsx.DialerOptionTimeout(config.TLSHandshakeTimeout))); err != nil {
 		return errors.Wrap(err, "failed to initialize proxy connection service")
 	}
 
@@ -211,24 +249,57 @@ func (t *daemon) bind(ctx *cmdopts.Global, config agent.Config, deployer daemons
 		return errors.Wrap(err, "failed to initialize peering service")
 	}
 
+	if err = daemons.DNS(dctx); err != nil {
+		return errors.Wrap(err, "failed to initialize dns service")
+	}
+
 	alpn := certificatecache.NewALPN(
 		tlscreds,
 		acme.NewALPNCertCache(acme.NewResolver(config.Peer(), dctx.Cluster, acmesvc, dialer)),
 	)
 
-	for idx, b := range bound {
-		bound[idx] = tls.NewListener(
-			b,
-			alpn,
-		)
+	if primary, err = tlsx.Clone(alpn, certificatecache.OptionClientAuthPolicy(config.ClientAuthPolicy.P2P)); err != nil {
+		return errors.Wrap(err, "invalid p2p client auth policy")
 	}
 
-	dctx.MuxerListen(ctx.Context, bound...)
+	if alternates, err = tlsx.Clone(alpn, certificatecache.OptionClientAuthPolicy(config.ClientAuthPolicy.Alternates)); err != nil {
+		return errors.Wrap(err, "invalid alternates client auth policy")
+	}
+
+	tickets := certificatecache.NewSessionTicketKeys(config.SessionTicketKeys...)
+	for _, c := range []*tls.Config{primary, alternates} {
+		if err = tickets.Rotate(c); err != nil {
+			return errors.Wrap(err, "failed to load session ticket keys")
+		}
+		go tickets.Run(running, c, certificatecache.DefaultSessionTicketRotation)
+	}
+
+	// the client certificate policy is determined by the role of the listener.
+	bound := []net.Listener{tls.NewListener(l, primary)}
+	for _, b := range altbound {
+		bound = append(bound, tls.NewListener(b, alternates))
+	}
+
+	dctx.MuxerListen(running, bound...)
 
 	if err = daemons.Discovery(dctx); err != nil {
 		return errors.Wrap(err, "failed to initialize discovery service")
 	}
 
+	t.Peering.Log = ctx.Logger()
+
+	// reloads the agent's key on change, e.g.) once a rotation commits the replacement.
+	if agentsigner, err = notary.NewReloadingAgentSigner(config.Root); err != nil {
+		return errors.Wrap(err, "failed to load the agent's signing key")
+	}
+	agentsigner.ReloadOnSignal(running, syscall.SIGHUP)
+	t.Peering.Signer = agentsigner
+
+	// allows operators to switch bootstrap sources without restarting the agent.
+	t.Peering.ReloadOnSignal(running, func() (agent.Config, error) {
+		return commandutils.LoadAgentConfig(t.Location, defaults)
+	}, syscall.SIGHUP)
+
 	if dctx, err = daemons.Peered(dctx, &t.Peering); err != nil {
 		return errors.Wrap(err, "failed to initialize peering service")
 	}
@@ -267,6 +338,13 @@ func (t *daemon) bind(ctx *cmdopts.Global, config agent.Config, deployer daemons
 		return errors.Wrap(err, "failed to bootstrap node shutting down")
 	}
 
+	// bootstrapping requires the agent to have joined the cluster and the quorum to be available,
+	// signal systemd only now so units ordered after the agent observe a bootstrapped node.
+	errorsx.MaybeLog(agent.NotifyReady())
+	go func() {
+		errorsx.MaybeLog(agent.NotifyWatchdog(running))
+	}()
+
 	return nil
 }
 
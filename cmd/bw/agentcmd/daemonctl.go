@@ -38,7 +38,9 @@ import (
 
 type CmdControl struct {
 	Restart    CmdControlRestart       `cmd:"" help:"restart all the nodes within the cluster"`
+	Drain      CmdControlDrain         `cmd:"" help:"gracefully remove the matching nodes from the cluster, intended to be invoked by autoscaling lifecycle hooks"`
 	Quorum     CmdControlQuorum        `cmd:"" help:"print information about the quorum members of the cluster"`
+	Members    CmdControlMembers       `cmd:"" help:"print the members of the cluster as json"`
 	Stacktrace CmdControlStacktrace    `cmd:"" help:"print stack trace from each node"`
 	CPU        CmdControlProfileCPU    `cmd:"" help:"run a cpu profile against agents"`
 	Memory     CmdControlProfileMemory `cmd:"" help:"run a memory profile against agents"`
@@ -159,6 +161,41 @@ func (t CmdControlRestart) shutdown(ctx context.Context, filter deployment.Filte
 	return agentutil.Shutdown(ctx, peers, d)
 }
 
+type CmdControlDrain struct {
+	controlConnection
+	Timeout time.Duration `name:"timeout" help:"maximum amount of time to wait for the drain to complete" default:"5m"`
+	Enabled bool          `name:"force" help:"must be specified in order for the command to actual be sent" default:"false"`
+}
+
+func (t CmdControlDrain) Run(ctx *cmdopts.Global) (err error) {
+	var (
+		d dialers.Defaults
+		c clustering.Rendezvous
+	)
+
+	local := &agent.Peer{
+		Name: bw.MustGenerateID().String(),
+		Ip:   systemx.HostnameOrLocalhost(),
+	}
+
+	if d, c, err = t.connect(); err != nil {
+		return err
+	}
+
+	cx := cluster.New(local, c)
+
+	peers := agentutil.PeerSet(deployment.ApplyFilter(deployment.Or(t.filters()...), cx.Peers()...))
+	if !t.Enabled {
+		log.Println("force not specified, not executing for the following agents:")
+		for _, p := range peers.Peers() {
+			log.Println(p.Name, p.Ip)
+		}
+		return nil
+	}
+
+	return agentutil.Drain(ctx.Context, peers, d, t.Timeout)
+}
+
 type CmdControlQuorum struct {
 	controlConnection
 }
@@ -190,6 +227,22 @@ func (t CmdControlQuorum) Run(ctx *cmdopts.Global) (err error) {
 	return nil
 }
 
+type CmdControlMembers struct {
+	controlConnection
+}
+
+func (t CmdControlMembers) Run(ctx *cmdopts.Global) (err error) {
+	var (
+		c clustering.Rendezvous
+	)
+
+	if _, c, err = t.connect(); err != nil {
+		return err
+	}
+
+	return clustering.WriteMembersJSON(os.Stdout, t.filtered(c))
+}
+
 type CmdControlStacktrace struct {
 	controlConnection
 }
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"log"
+
+	"github.com/james-lawrence/bw/agent"
+	"github.com/james-lawrence/bw/agent/dialers"
+	"github.com/james-lawrence/bw/agentutil"
+	"github.com/james-lawrence/bw/cluster"
+	"github.com/james-lawrence/bw/clustering"
+	"github.com/james-lawrence/bw/cmd/bw/cmdopts"
+	"github.com/james-lawrence/bw/cmd/commandutils"
+	"github.com/james-lawrence/bw/daemons"
+	"github.com/james-lawrence/bw/notary"
+	"github.com/james-lawrence/bw/uxterm"
+	"github.com/james-lawrence/bw/vcsinfo"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+)
+
+type cmdCluster struct {
+	ConfigCheck cmdClusterConfigCheck `cmd:"" name:"config-check" help:"flag agents running a configuration different from the rest of the cluster"`
+}
+
+type cmdClusterConfigCheck struct {
+	cmdopts.BeardedWookieEnv
+	Insecure bool `help:"skip tls verification"`
+}
+
+func (t cmdClusterConfigCheck) Run(ctx *cmdopts.Global) (err error) {
+	var (
+		c            clustering.Rendezvous
+		d            dialers.Defaults
+		config       agent.ConfigClient
+		ss           notary.Signer
+		fingerprints []agentutil.ConfigFingerprint
+	)
+	defer ctx.Shutdown()
+
+	if config, err = commandutils.LoadConfiguration(t.Environment, agent.CCOptionInsecure(t.Insecure)); err != nil {
+		return err
+	}
+
+	if ss, err = notary.NewAutoSigner(vcsinfo.CurrentUserDisplay(config.WorkDir())); err != nil {
+		return err
+	}
+
+	if d, c, err = daemons.Connect(config, ss, grpc.WithPerRPCCredentials(ss)); err != nil {
+		return err
+	}
+
+	cx := cluster.New(commandutils.NewClientPeer(), c)
+	if fingerprints, err = agentutil.ConfigFingerprints(ctx.Context, cx, d); err != nil {
+		return err
+	}
+
+	report := agentutil.NewConfigReport(fingerprints...)
+	log.Println("configuration", report.Fingerprint, "consistent agents", len(report.Consistent))
+
+	for _, f := range report.Unknown {
+		log.Println("unknown configuration", uxterm.PeerString(f.Peer))
+	}
+
+	for _, f := range report.Divergent {
+		log.Println("divergent configuration", f.Fingerprint, uxterm.PeerString(f.Peer))
+	}
+
+	if report.Diverged() {
+		return errors.Errorf("%d agents are running a divergent configuration", len(report.Divergent))
+	}
+
+	return nil
+}
@@ -6,15 +6,19 @@ import (
 	"log"
 	"net"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"strconv"
 	"sync"
+	"time"
 
 	"github.com/hashicorp/memberlist"
 	"github.com/hashicorp/raft"
+	raftboltdb "github.com/hashicorp/raft-boltdb"
 	"github.com/james-lawrence/bw"
 	"github.com/james-lawrence/bw/agent"
 	"github.com/james-lawrence/bw/agent/dialers"
+	"github.com/james-lawrence/bw/agent/discovery"
 	"github.com/james-lawrence/bw/certificatecache"
 	"github.com/james-lawrence/bw/clustering"
 	"github.com/james-lawrence/bw/clustering/peering"
@@ -22,14 +26,18 @@ import (
 	"github.com/james-lawrence/bw/cmd/commandutils"
 	"github.com/james-lawrence/bw/internal/envx"
 	"github.com/james-lawrence/bw/internal/errorsx"
+	"github.com/james-lawrence/bw/internal/logx"
 	"github.com/james-lawrence/bw/internal/tlsx"
 	"github.com/james-lawrence/bw/notary"
 	"github.com/pkg/errors"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 )
 
 type Global struct {
-	Verbosity int                `help:"increase verbosity of logging" short:"v" type:"counter" default:"0"`
+	Verbosity int                `help:"increase verbosity of logging" short:"v" type:"counter" default:"0"` // verbosity at startup, see Level for the current verbosity.
+	LogFormat string             `name:"log-format" help:"format of the log output" enum:"text,json" default:"text"`
+	Level     *logx.Verbosity    `kong:"-"` // verbosity shared with the loggers, see SetVerbosity.
 	Context   context.Context    `kong:"-"`
 	Shutdown  context.CancelFunc `kong:"-"`
 	Cleanup   *sync.WaitGroup    `kong:"-"`
@@ -40,52 +48,291 @@ func (t Global) BeforeApply() error {
 	return nil
 }
 
+// Logger leveled logger for the verbosity and log format, at verbosity 0 only warnings and
+// errors are emitted, each additional level enables info and then debug messages.
+// the logger observes adjustments to the verbosity, see SetVerbosity.
+func (t Global) Logger() logx.Leveled {
+	if t.Level == nil {
+		return logx.NewLeveled(t.Verbosity, t.LogFormat)
+	}
+
+	return logx.NewShared(t.Level, t.LogFormat)
+}
+
+// RouteStandardLog when emitting json the output of the standard logger is routed through
+// the leveled logger, ensuring every line emitted is a json object. lines without a level
+// tag are always emitted, matching the text format.
+func (t Global) RouteStandardLog() {
+	if t.LogFormat != logx.FormatJSON {
+		return
+	}
+
+	l := t.Logger()
+	l.Output = log.Writer()
+	// the json messages include the timestamp.
+	log.SetFlags(log.Flags() &^ (log.Ldate | log.Ltime | log.Lmicroseconds))
+	log.SetOutput(l.Writer(logx.LevelWarn))
+}
+
+// SetVerbosity adjust the logging verbosity without restarting, safe to call concurrently
+// once the level is initialized.
+func (t *Global) SetVerbosity(n int) {
+	if t.Level == nil {
+		t.Level = logx.NewVerbosity(n)
+	}
+
+	setVerbosity(t.Level, n)
+}
+
+func setVerbosity(level *logx.Verbosity, n int) {
+	log.Println("logging verbosity set to", n)
+	level.Set(n)
+	commandutils.SetLogVerbosity(n)
+}
+
+// VerbosityOnSignal each signal raises the logging verbosity by one, once the maximum
+// verbosity is exceeded it reverts to the verbosity provided at startup.
+// the signals are registered before returning, the adjustments happen in the background
+// until the context is cancelled.
+func (t *Global) VerbosityOnSignal(ctx context.Context, sigs ...os.Signal) {
+	if t.Level == nil {
+		t.Level = logx.NewVerbosity(t.Verbosity)
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, sigs...)
+
+	go func(level *logx.Verbosity, startup int) {
+		defer signal.Stop(signals)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-signals:
+				current := int(level.Level()) + 1
+				if current > commandutils.MaximumVerbosity {
+					current = startup
+				}
+
+				setVerbosity(level, current)
+			}
+		}
+	}(t.Level, t.Verbosity)
+}
+
 type Peering struct {
-	Bootstrap     []*net.TCPAddr `name:"bootstrap-static-addresses" help:"addresses of the cluster to bootstrap from" env:"${env_bw_agent_bootstrap_static}"`
-	DNSEnabled    bool           `name:"bootstrap-dns-enable" alias:"cluster-dns-enable" help:"enable dns peering" env:"${env_bw_agent_bootstrap_dns_enabled}"`
-	AWSEnabled    bool           `name:"bootstrap-aws-enable" alias:"cluster-aws-enable" help:"enable aws autoscaling group peering" env:"${env_bw_agent_bootstrap_aws_autoscaling_enabled}"`
-	GCloudEnabled bool           `name:"bootstrap-gcloud-enable" alias:"cluster-gcloud-enable" help:"enable gcloud target pools peering" env:"${env_bw_agent_bootstrap_gcloud_taget_pool_enabled}"`
+	Bootstrap         []*net.TCPAddr                `name:"bootstrap-static-addresses" help:"addresses of the cluster to bootstrap from" env:"${env_bw_agent_bootstrap_static}"`
+	BootstrapFile     string                        `name:"bootstrap-file" help:"newline delimited file of addresses of the cluster to bootstrap from, re-read when the file changes" env:"${env_bw_agent_bootstrap_file}"`
+	DNSEnabled        bool                          `name:"bootstrap-dns-enable" alias:"cluster-dns-enable" help:"enable dns peering" env:"${env_bw_agent_bootstrap_dns_enabled}"`
+	DNSSRV            string                        `name:"bootstrap-dns-srv" help:"srv record to bootstrap from, e.g.) _bw._tcp.example.com" env:"${env_bw_agent_bootstrap_dns_srv}"`
+	AWSEnabled        bool                          `name:"bootstrap-aws-enable" alias:"cluster-aws-enable" help:"enable aws autoscaling group peering" env:"${env_bw_agent_bootstrap_aws_autoscaling_enabled}"`
+	GCloudEnabled     bool                          `name:"bootstrap-gcloud-enable" alias:"cluster-gcloud-enable" help:"enable gcloud target pools peering" env:"${env_bw_agent_bootstrap_gcloud_taget_pool_enabled}"`
+	AzureEnabled      bool                          `name:"bootstrap-azure-enable" alias:"cluster-azure-enable" help:"enable azure scale set peering" env:"${env_bw_agent_bootstrap_azure_scale_sets_enabled}"`
+	ConsulEnabled     bool                          `name:"bootstrap-consul-enable" alias:"cluster-consul-enable" help:"enable consul service peering" env:"${env_bw_agent_bootstrap_consul_enabled}"`
+	KubernetesEnabled bool                          `name:"bootstrap-kubernetes-enable" alias:"cluster-kubernetes-enable" help:"enable kubernetes endpoints peering" env:"${env_bw_agent_bootstrap_kubernetes_enabled}"`
+	DOEnabled         bool                          `name:"bootstrap-digitalocean-enable" alias:"cluster-digitalocean-enable" help:"enable digitalocean droplet tag peering" env:"${env_bw_agent_bootstrap_digitalocean_enabled}"`
+	Log               logx.Leveled                  `kong:"-"`
+	Signer            credentials.PerRPCCredentials `kong:"-"` // signs the discovery requests, defaults to the agent's key.
+	sources           *peering.Dynamic              `kong:"-"`
 }
 
 func (t *Peering) Join(ctx context.Context, config agent.Config, c clustering.Joiner, snap peering.File) (err error) {
 	var (
-		p2ppeers    clustering.Source
-		clipeers    clustering.Source = peering.NewStaticTCP(t.Bootstrap...)
-		awspeers    clustering.Source = peering.NewStaticTCP()
-		gcloudpeers clustering.Source = peering.NewStaticTCP()
-		dnspeers    clustering.Source = peering.NewStaticTCP()
+		p2ppeers  clustering.Source
+		clipeers  clustering.Source = peering.NewStaticTCP(t.Bootstrap...)
+		filepeers clustering.Source = peering.NewStaticTCP()
 	)
 
-	if p2ppeers, err = p2ppeering(config); err != nil {
-		log.Println("WARNING: P2P discovery disabled", err)
+	if t.BootstrapFile != "" {
+		t.Log.Info("bootstrap file peering enabled", "path", t.BootstrapFile)
+		watched := peering.NewWatchedFile(t.BootstrapFile)
+		watched.Log = t.Log
+		filepeers = watched
+	}
+
+	if t.Signer == nil {
+		if t.Signer, err = notary.NewReloadingAgentSigner(config.Root); err != nil {
+			return err
+		}
+	}
+
+	if p2ppeers, err = p2ppeering(config, t.Signer); err != nil {
+		t.Log.Warn("P2P discovery disabled", "error", err)
 		p2ppeers = peering.NewStaticTCP()
 	}
 
-	if t.DNSEnabled {
-		log.Println("dns peering enabled")
-		dnspeers = peering.NewDNS(config.P2PBind.Port, append(config.DNSBootstrap, config.ServerName)...)
+	drift, err := clockdrift(config, t.Signer)
+	if err != nil {
+		t.Log.Warn("clock drift check disabled", "error", err)
+	}
+
+	t.Reload(config)
+
+	discovered := peering.Union(clipeers, filepeers, p2ppeers, t.sources)
+
+	if err = commandutils.ClusterJoin(ctx, config, c, drift, discovered, snap); err != nil {
+		return err
+	}
+
+	commandutils.ClusterDiscover(ctx, config, c, drift, discovered)
+
+	return nil
+}
+
+// Reload rebuilds the enabled bootstrap sources from the configuration,
+// the bootstrap sources of the configuration override the command line.
+func (t *Peering) Reload(config agent.Config) {
+	if t.sources == nil {
+		t.sources = peering.NewDynamic()
+	}
+
+	t.sources.Log = t.Log
+	t.sources.Swap(t.bootstrap(config)...)
+}
+
+// Sources the bootstrap sources currently in use.
+func (t *Peering) Sources() []clustering.Source {
+	if t.sources == nil {
+		return nil
+	}
+
+	return t.sources.Sources()
+}
+
+// ReloadOnSignal reloads the bootstrap sources using the configuration returned by load
+// each time one of the signals is received. the signals are registered before returning,
+// the reloads happen in the background until the context is cancelled.
+func (t *Peering) ReloadOnSignal(ctx context.Context, load func() (agent.Config, error), sigs ...os.Signal) {
+	if t.sources == nil {
+		t.sources = peering.NewDynamic()
+	}
+
+	t.sources.Log = t.Log
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, sigs...)
+
+	go func() {
+		defer signal.Stop(signals)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-signals:
+				config, err := load()
+				if err != nil {
+					t.Log.Error("failed to reload bootstrap sources", "error", err)
+					continue
+				}
+
+				t.Log.Info("reloading bootstrap sources")
+				t.Reload(config)
+			}
+		}
+	}()
+}
+
+func (t *Peering) bootstrap(config agent.Config) (sources []clustering.Source) {
+	enabled := func(flag bool, override *bool) bool {
+		if override != nil {
+			return *override
+		}
+
+		return flag
+	}
+
+	if enabled(t.DNSEnabled, config.BootstrapSources.DNS) {
+		t.Log.Info("dns peering enabled")
+		dns := peering.NewDNS(config.P2PBind.Port, append(config.DNSBootstrap, config.ServerName)...)
+		dns.Family = config.AddressFamily
+		sources = append(sources, dns)
 	}
 
-	if t.AWSEnabled {
-		log.Println("aws autoscale groups peering enabled")
-		awspeers = peering.AWSAutoscaling{
+	if t.DNSSRV != "" {
+		t.Log.Info("dns srv peering enabled", "record", t.DNSSRV)
+		ttl := time.Duration(config.DNSBind.TTL) * time.Second
+		if ttl == 0 {
+			ttl = config.DNSBind.Frequency
+		}
+		sources = append(sources, peering.NewDNSSRV(t.DNSSRV, ttl))
+	}
+
+	if enabled(t.AWSEnabled, config.BootstrapSources.AWS) {
+		t.Log.Info("aws autoscale groups peering enabled")
+		sources = append(sources, peering.AWSAutoscaling{
 			Port:               config.P2PBind.Port,
 			SupplimentalGroups: config.AWSBootstrap.AutoscalingGroups,
-		}
+			RequireIMDSv2:      config.AWSBootstrap.RequireIMDSv2,
+		})
 	}
 
-	if t.GCloudEnabled {
-		log.Println("gcloud target pool peering enabled")
-		gcloudpeers = peering.GCloudTargetPool{
+	if enabled(t.GCloudEnabled, config.BootstrapSources.GCloud) {
+		t.Log.Info("gcloud target pool peering enabled")
+		sources = append(sources, peering.GCloudTargetPool{
 			Port:    config.P2PBind.Port,
 			Maximum: config.MinimumNodes,
+			Log:     t.Log,
+		})
+	}
+
+	if enabled(t.AzureEnabled, config.BootstrapSources.Azure) {
+		t.Log.Info("azure scale set peering enabled")
+		azure := peering.NewAzureScaleSet(config.P2PBind.Port, config.AzureBootstrap.ScaleSets...)
+		if err := azure.Authorized(context.Background()); err != nil {
+			t.Log.Warn("azure scale set peering disabled", "error", err)
+			sources = append(sources, peering.NewStaticTCP())
+		} else {
+			sources = append(sources, azure)
+		}
+	}
+
+	if enabled(t.ConsulEnabled, config.BootstrapSources.Consul) {
+		t.Log.Info("consul service peering enabled")
+		consul := peering.NewConsul(config.P2PBind.Port, config.ConsulBootstrap.Service, config.ConsulBootstrap.Datacenter)
+		if err := consul.Reachable(context.Background()); err != nil {
+			t.Log.Warn("consul service peering disabled", "error", err)
+			sources = append(sources, peering.NewStaticTCP())
+		} else {
+			sources = append(sources, consul)
+		}
+	}
+
+	if enabled(t.KubernetesEnabled, config.BootstrapSources.Kubernetes) {
+		t.Log.Info("kubernetes endpoints peering enabled")
+		kb := config.KubernetesBootstrap
+		if k8s, err := peering.NewKubernetes(config.P2PBind.Port, kb.Namespace, kb.Service, kb.Selector, kb.Kubeconfig); err != nil {
+			t.Log.Warn("kubernetes endpoints peering disabled", "error", err)
+			sources = append(sources, peering.NewStaticTCP())
+		} else if err = k8s.Reachable(context.Background()); err != nil {
+			t.Log.Warn("kubernetes endpoints peering disabled", "error", err)
+			sources = append(sources, peering.NewStaticTCP())
+		} else {
+			sources = append(sources, k8s)
+		}
+	}
+
+	if enabled(t.DOEnabled, config.BootstrapSources.DigitalOcean) {
+		t.Log.Info("digitalocean droplet tag peering enabled")
+		do := peering.NewDigitalOcean(config.P2PBind.Port, config.DigitalOceanBootstrap.Tag, config.DigitalOceanBootstrap.Region)
+		if err := do.Authorized(context.Background()); err != nil {
+			t.Log.Warn("digitalocean droplet tag peering disabled", "error", err)
+			sources = append(sources, peering.NewStaticTCP())
+		} else {
+			sources = append(sources, do)
 		}
 	}
 
-	return commandutils.ClusterJoin(ctx, config, c, clipeers, p2ppeers, awspeers, gcloudpeers, snap, dnspeers)
+	return sources
 }
 
 func (t *Peering) Snapshot(c clustering.Rendezvous, fssnapshot peering.File, options ...clustering.SnapshotOption) {
+	if !clustering.SnapshotEnabled(options...) {
+		log.Println("cluster snapshots are disabled")
+		return
+	}
+
 	go clustering.Snapshot(
 		c,
 		fssnapshot,
@@ -96,32 +343,75 @@ func (t *Peering) Snapshot(c clustering.Rendezvous, fssnapshot peering.File, opt
 func (t *Peering) Raft(ctx context.Context, conf agent.Config, node *memberlist.Node, eq *grpc.ClientConn, options ...raftutil.ProtocolOption) (p raftutil.Protocol, err error) {
 	var (
 		dir = filepath.Join(conf.Root, "raft.d")
+		// best effort recovery only applies to the state present on startup.
+		recovery = conf.RaftRecoveryMode == agent.RaftRecoveryBestEffort
 	)
 
 	if err = os.MkdirAll(dir, 0700); err != nil {
 		return p, err
 	}
 
+	open := func() (s *raftboltdb.BoltStore, ss raft.SnapshotStore, err error) {
+		if s, err = commandutils.RaftStoreFilepath(filepath.Join(dir, "state.bin")); err != nil {
+			return s, ss, errors.WithStack(err)
+		}
+
+		if ss, err = raft.NewFileSnapshotStore(dir, conf.RaftSnapshotRetain, t.Log.Writer(logx.LevelInfo)); err != nil {
+			return nil, ss, errorsx.Compact(errors.WithStack(err), s.Close())
+		}
+
+		return s, ss, nil
+	}
+
+	// attempts to retain the intact raft state, raft restores the latest valid snapshot
+	// and replays the retained log entries on top of it.
+	salvage := func() (s *raftboltdb.BoltStore, ss raft.SnapshotStore, err error) {
+		if s, ss, err = open(); err != nil {
+			return s, ss, err
+		}
+
+		if err = errorsx.Compact(raftutil.RecoverSnapshots(ss), raftutil.RecoverLogs(s)); err != nil {
+			return s, ss, errorsx.Compact(err, s.Close())
+		}
+
+		return s, ss, nil
+	}
+
 	defaultOptions := []raftutil.ProtocolOption{
 		raftutil.ProtocolOptionQuorumMinimum(conf.MinimumNodes),
 		raftutil.ProtocolOptionEnableSingleNode(conf.MinimumNodes <= 1),
-		raftutil.ProtocolOptionPassiveReset(func() (s raftutil.Storage, ss raft.SnapshotStore, err error) {
-			if err = errorsx.Compact(os.RemoveAll(dir), os.MkdirAll(dir, 0700)); err != nil {
-				return s, ss, errors.WithStack(err)
+		raftutil.ProtocolOptionNonVoter(conf.Bootstrap.NonVoter),
+		raftutil.ProtocolOptionPassiveReset(func() (_ raftutil.Storage, ss raft.SnapshotStore, err error) {
+			var (
+				s *raftboltdb.BoltStore
+			)
+
+			if recovery {
+				recovery = false
+				if s, ss, err = salvage(); err == nil {
+					log.Println("recovered raft state")
+					return s, ss, nil
+				}
+
+				log.Println("best effort raft recovery failed, wiping raft state", err)
 			}
 
-			if s, err = commandutils.RaftStoreFilepath(filepath.Join(dir, "state.bin")); err != nil {
-				return s, ss, errors.WithStack(err)
+			if err = errorsx.Compact(os.RemoveAll(dir), os.MkdirAll(dir, 0700)); err != nil {
+				return nil, ss, errors.WithStack(err)
 			}
 
-			if ss, err = raft.NewFileSnapshotStore(dir, 5, os.Stderr); err != nil {
-				return s, ss, errors.WithStack(err)
+			if s, ss, err = open(); err != nil {
+				return nil, ss, err
 			}
 
 			return s, ss, nil
 		}),
 	}
 
+	if conf.EnableExpvar {
+		defaultOptions = append(defaultOptions, raftutil.ProtocolOptionExpvar(raftutil.PublishExpvar()))
+	}
+
 	return raftutil.NewProtocol(
 		ctx,
 		node,
@@ -140,29 +430,49 @@ func BootstrapPeers(peers ...*agent.Peer) peering.Static {
 	return peering.NewStatic(speers...)
 }
 
-func p2ppeering(c agent.Config) (s clustering.Source, err error) {
+func p2ppeering(c agent.Config, ss credentials.PerRPCCredentials) (s clustering.Source, err error) {
 	var (
-		tlsconfig *tls.Config
-		ss        notary.Signer
-		d         dialers.Defaults
-		address   = net.JoinHostPort(c.ServerName, envx.String(strconv.Itoa(c.P2PBind.Port), bw.EnvAgentClusterP2PDiscoveryPort))
+		d       dialers.Defaults
+		address = net.JoinHostPort(c.ServerName, envx.String(strconv.Itoa(c.P2PBind.Port), bw.EnvAgentClusterP2PDiscoveryPort))
 	)
 
-	if ss, err = notary.NewAgentSigner(c.Root); err != nil {
+	if d, err = discoveryDialer(c, address, ss); err != nil {
 		return nil, err
 	}
 
-	if tlsconfig, err = certificatecache.TLSGenServer(c, tlsx.OptionNoClientCert); err != nil {
-		return nil, err
+	return peering.P2P{
+		Address: agent.URIDiscovery(address),
+		Dialer:  d,
+	}, nil
+}
+
+// clockdrift measures the clock drift of peers using their discovery service. nil when the check is disabled.
+func clockdrift(c agent.Config, ss credentials.PerRPCCredentials) (_ clustering.ClockDrift, err error) {
+	var (
+		d dialers.Defaults
+	)
+
+	if c.MaxClockDrift <= 0 {
+		return nil, nil
 	}
 
-	d, err = dialers.DefaultDialer(address, tlsx.NewDialer(tlsconfig), grpc.WithPerRPCCredentials(ss))
-	if err != nil {
+	if d, err = discoveryDialer(c, c.P2PBind.String(), ss); err != nil {
 		return nil, err
 	}
 
-	return peering.P2P{
-		Address: agent.URIDiscovery(address),
-		Dialer:  d,
+	return func(ctx context.Context, address string) (time.Duration, error) {
+		return discovery.ClockDrift(ctx, agent.URIDiscovery(address), d.Defaults()...)
 	}, nil
 }
+
+func discoveryDialer(c agent.Config, address string, ss credentials.PerRPCCredentials) (d dialers.Defaults, err error) {
+	var (
+		tlsconfig *tls.Config
+	)
+
+	if tlsconfig, err = certificatecache.TLSGenServer(c, tlsx.OptionNoClientCert); err != nil {
+		return d, err
+	}
+
+	return dialers.DefaultDialer(address, tlsx.NewDialer(tlsconfig), grpc.WithPerRPCCredentials(ss))
+}
@@ -0,0 +1,253 @@
+package cmdopts_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/hashicorp/memberlist"
+	"github.com/hashicorp/raft"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+
+	"github.com/james-lawrence/bw"
+	"github.com/james-lawrence/bw/agent"
+	"github.com/james-lawrence/bw/clustering"
+	"github.com/james-lawrence/bw/clustering/peering"
+	. "github.com/james-lawrence/bw/cmd/bw/cmdopts"
+	"github.com/james-lawrence/bw/cmd/commandutils"
+	"github.com/james-lawrence/bw/internal/logx"
+	"github.com/james-lawrence/bw/internal/testingx"
+)
+
+var _ = Describe("Global", func() {
+	AfterEach(func() {
+		commandutils.SetLogVerbosity(0)
+		log.SetOutput(io.Discard)
+	})
+
+	It("should change log output when the verbosity is adjusted", func() {
+		var (
+			buf   bytes.Buffer
+			cause = errors.New("boom")
+			g     = &Global{}
+		)
+
+		log.SetOutput(&buf)
+
+		commandutils.LogCause(cause)
+		Expect(buf.String()).ToNot(ContainSubstring("cmdopts_test.go"))
+
+		buf.Reset()
+		g.SetVerbosity(2)
+		commandutils.LogCause(cause)
+		Expect(buf.String()).To(ContainSubstring("cmdopts_test.go"))
+
+		buf.Reset()
+		g.SetVerbosity(0)
+		commandutils.LogCause(cause)
+		Expect(buf.String()).ToNot(ContainSubstring("cmdopts_test.go"))
+		Expect(g.Level.Level()).To(Equal(logx.Level(0)))
+	})
+
+	It("should adjust the verbosity of the loggers", func() {
+		var buf bytes.Buffer
+		log.SetOutput(&buf)
+
+		g := &Global{Level: logx.NewVerbosity(0)}
+		l := g.Logger()
+
+		l.Info("hidden message")
+		Expect(buf.String()).ToNot(ContainSubstring("hidden message"))
+
+		g.SetVerbosity(1)
+		l.Info("info message")
+		Expect(buf.String()).To(ContainSubstring("INFO: info message"))
+	})
+
+	It("should route the standard logger through the json logger", func() {
+		var buf bytes.Buffer
+		flags := log.Flags()
+		log.SetOutput(&buf)
+		defer log.SetFlags(flags)
+
+		Global{Level: logx.NewVerbosity(0), LogFormat: logx.FormatJSON}.RouteStandardLog()
+		log.Println("standard message")
+
+		var decoded map[string]interface{}
+		Expect(json.Unmarshal(buf.Bytes(), &decoded)).To(Succeed())
+		Expect(decoded).To(HaveKeyWithValue("msg", "standard message"))
+	})
+
+	It("should enable grpc logging at the highest verbosity", func() {
+		g := &Global{}
+
+		g.SetVerbosity(commandutils.MaximumVerbosity)
+		Expect(g.Level.Level()).To(Equal(logx.Level(commandutils.MaximumVerbosity)))
+		Expect(os.Getenv("GRPC_GO_LOG_SEVERITY_LEVEL")).To(Equal("info"))
+
+		g.SetVerbosity(0)
+		_, ok := os.LookupEnv("GRPC_GO_LOG_SEVERITY_LEVEL")
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should raise the verbosity on signal and revert once the maximum is exceeded", func() {
+		ctx, done := context.WithCancel(context.Background())
+		defer done()
+
+		g := &Global{Verbosity: 1}
+		g.VerbosityOnSignal(ctx, syscall.SIGUSR1)
+
+		enabled := func(key string) func() bool {
+			return func() bool {
+				_, ok := os.LookupEnv(key)
+				return ok
+			}
+		}
+
+		signal := func() {
+			Expect(syscall.Kill(os.Getpid(), syscall.SIGUSR1)).To(Succeed())
+		}
+
+		Expect(enabled(bw.EnvLogsVerbose)()).To(BeFalse())
+
+		signal()
+		Eventually(enabled(bw.EnvLogsVerbose)).Should(BeTrue())
+		Expect(enabled(bw.EnvLogsRaft)()).To(BeFalse())
+
+		signal()
+		Eventually(enabled(bw.EnvLogsRaft)).Should(BeTrue())
+
+		// exceeding the maximum reverts to the startup verbosity.
+		signal()
+		Eventually(enabled(bw.EnvLogsVerbose)).Should(BeFalse())
+		Expect(enabled(bw.EnvLogsRaft)()).To(BeFalse())
+		Expect(enabled(bw.EnvLogsConfiguration)()).To(BeTrue())
+		Expect(g.Level.Level()).To(Equal(logx.Level(1)))
+		// the startup verbosity is never modified.
+		Expect(g.Verbosity).To(Equal(1))
+	})
+})
+
+var _ = Describe("Peering", func() {
+	newConfig := func() agent.Config {
+		return agent.NewConfig(agent.ConfigOptionP2P(&net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 2000}))
+	}
+
+	types := func(p *Peering) func() []string {
+		return func() (results []string) {
+			for _, s := range p.Sources() {
+				results = append(results, fmt.Sprintf("%T", s))
+			}
+			return results
+		}
+	}
+
+	It("should rebuild the bootstrap sources when reloaded", func() {
+		p := &Peering{AWSEnabled: true}
+
+		p.Reload(newConfig())
+		Expect(types(p)()).To(Equal([]string{"peering.AWSAutoscaling"}))
+
+		p.AWSEnabled = false
+		p.GCloudEnabled = true
+		p.Reload(newConfig())
+		Expect(types(p)()).To(Equal([]string{"peering.GCloudTargetPool"}))
+	})
+
+	It("should prefer the bootstrap sources of the configuration", func() {
+		p := &Peering{AWSEnabled: true}
+		config := newConfig()
+		config.BootstrapSources.AWS = boolptr(false)
+		config.BootstrapSources.GCloud = boolptr(true)
+
+		p.Reload(config)
+		Expect(types(p)()).To(Equal([]string{"peering.GCloudTargetPool"}))
+	})
+
+	It("should reload the bootstrap sources on signal", func() {
+		ctx, done := context.WithCancel(context.Background())
+		defer done()
+
+		p := &Peering{AWSEnabled: true}
+		config := newConfig()
+		reloaded := make(chan agent.Config, 1)
+
+		p.ReloadOnSignal(ctx, func() (agent.Config, error) {
+			return <-reloaded, nil
+		}, syscall.SIGHUP)
+		p.Reload(config)
+		Expect(types(p)()).To(Equal([]string{"peering.AWSAutoscaling"}))
+
+		config.BootstrapSources.AWS = boolptr(false)
+		config.BootstrapSources.GCloud = boolptr(true)
+		reloaded <- config
+		Expect(syscall.Kill(os.Getpid(), syscall.SIGHUP)).To(Succeed())
+		Eventually(types(p)).Should(Equal([]string{"peering.GCloudTargetPool"}))
+	})
+
+	It("should not start snapshotting when snapshots are disabled", func() {
+		ctx, done := context.WithCancel(context.Background())
+		defer done()
+
+		var (
+			c       = clustering.NewMock(&memberlist.Node{Name: "node1", Addr: net.ParseIP("127.0.0.1"), Port: 2000})
+			enabled = peering.File{Path: filepath.Join(GinkgoT().TempDir(), "enabled.snapshot")}
+			disable = peering.File{Path: filepath.Join(GinkgoT().TempDir(), "disabled.snapshot")}
+			exists  = func(path string) func() bool {
+				return func() bool {
+					_, err := os.Stat(path)
+					return err == nil
+				}
+			}
+		)
+
+		(&Peering{}).Snapshot(c, disable, clustering.SnapshotOptionContext(ctx), clustering.SnapshotOptionFrequency(0))
+		(&Peering{}).Snapshot(c, enabled, clustering.SnapshotOptionContext(ctx), clustering.SnapshotOptionFrequency(time.Hour))
+
+		Eventually(exists(enabled.Path)).Should(BeTrue())
+		Consistently(exists(disable.Path), 100*time.Millisecond).Should(BeFalse())
+	})
+
+	It("should retain the configured number of raft snapshots", func() {
+		ctx, done := context.WithCancel(context.Background())
+		defer done()
+
+		conn, server := testingx.NewGRPCServer(func(s *grpc.Server) {})
+		defer testingx.GRPCCleanup(conn, server)
+
+		conf := agent.NewConfig(agent.ConfigOptionRaftSnapshotRetain(2))
+		conf.Root = GinkgoT().TempDir()
+
+		protocol, err := (&Peering{}).Raft(ctx, conf, &memberlist.Node{Name: "node1"}, conn)
+		Expect(err).To(Succeed())
+
+		_, ss, err := protocol.PassiveReset()
+		Expect(err).To(Succeed())
+
+		for idx := uint64(1); idx <= 4; idx++ {
+			sink, err := ss.Create(raft.SnapshotVersionMax, idx, 1, raft.Configuration{}, 1, nil)
+			Expect(err).To(Succeed())
+			Expect(sink.Close()).To(Succeed())
+		}
+
+		snapshots, err := os.ReadDir(filepath.Join(conf.Root, "raft.d", "snapshots"))
+		Expect(err).To(Succeed())
+		Expect(snapshots).To(HaveLen(2))
+	})
+})
+
+func boolptr(b bool) *bool {
+	return &b
+}
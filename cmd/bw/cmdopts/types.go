@@ -1,21 +1,50 @@
 package cmdopts
 
 import (
+	"context"
 	"net"
 	"reflect"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/alecthomas/kong"
 	"github.com/davecgh/go-spew/spew"
 	"github.com/pkg/errors"
+
+	"github.com/james-lawrence/bw"
+	"github.com/james-lawrence/bw/internal/envx"
 )
 
+// DefaultResolveTimeout upper bound for resolving the hostname of an address, see bw.EnvResolveTimeout.
+const DefaultResolveTimeout = bw.DefaultResolveTimeout
+
 // ParseIP addresses
 func ParseIP(ctx *kong.DecodeContext, target reflect.Value) (err error) {
 	target.Set(reflect.ValueOf(net.ParseIP(ctx.Scan.Pop().String())))
 	return nil
 }
 
+// ParseCIDR networks, e.g.) 10.0.0.0/8
+func ParseCIDR(ctx *kong.DecodeContext, target reflect.Value) (err error) {
+	if ctx.Scan.Len() == 0 {
+		return nil
+	}
+
+	var (
+		n     *net.IPNet
+		token = ctx.Scan.Pop().String()
+	)
+
+	if _, n, err = net.ParseCIDR(token); err != nil {
+		return errors.Wrapf(err, "invalid cidr %s, expected a network (e.g. 10.0.0.0/8)", token)
+	}
+
+	target.Set(reflect.ValueOf(n))
+
+	return nil
+}
+
 func ParseTCPAddr(ctx *kong.DecodeContext, target reflect.Value) (err error) {
 	if ctx.Scan.Len() == 0 {
 		return nil
@@ -29,7 +58,7 @@ func ParseTCPAddr(ctx *kong.DecodeContext, target reflect.Value) (err error) {
 		addr *net.TCPAddr
 	)
 
-	if addr, err = net.ResolveTCPAddr("tcp", saddr); err != nil {
+	if addr, err = resolveTCPAddr(saddr); err != nil {
 		return errors.Wrapf(err, "unable to resolve tcp address %s - %s", saddr, spew.Sdump(ctx))
 	}
 
@@ -38,6 +67,31 @@ func ParseTCPAddr(ctx *kong.DecodeContext, target reflect.Value) (err error) {
 	return nil
 }
 
+// ParseDuration durations, e.g.) 1h30m or 90m, bare integers are seconds.
+func ParseDuration(ctx *kong.DecodeContext, target reflect.Value) (err error) {
+	if ctx.Scan.Len() == 0 {
+		return nil
+	}
+
+	var (
+		d     time.Duration
+		token = ctx.Scan.Pop().String()
+	)
+
+	if seconds, err := strconv.ParseInt(token, 10, 64); err == nil {
+		target.Set(reflect.ValueOf(time.Duration(seconds) * time.Second))
+		return nil
+	}
+
+	if d, err = time.ParseDuration(token); err != nil {
+		return errors.Wrapf(err, "invalid duration %s, expected a duration (e.g. 1h30m) or seconds", token)
+	}
+
+	target.Set(reflect.ValueOf(d))
+
+	return nil
+}
+
 func ParseTCPAddrArray(ctx *kong.DecodeContext, target reflect.Value) (err error) {
 
 	if ctx.Scan.Len() == 0 {
@@ -49,14 +103,13 @@ func ParseTCPAddrArray(ctx *kong.DecodeContext, target reflect.Value) (err error
 		token   = ctx.Scan.Pop().String()
 	)
 
-	token = strings.ReplaceAll(token, "\n", " ")
 	token = strings.ReplaceAll(token, ",", " ")
-	for _, saddr := range strings.Split(token, " ") {
+	for _, saddr := range strings.Fields(token) {
 		var (
 			addr *net.TCPAddr
 		)
 
-		if addr, err = net.ResolveTCPAddr("tcp", saddr); err != nil {
+		if addr, err = resolveTCPAddr(saddr); err != nil {
 			return errors.Wrapf(err, "unable to resolve tcp address %s : %s", saddr, token)
 		}
 
@@ -66,3 +119,74 @@ func ParseTCPAddrArray(ctx *kong.DecodeContext, target reflect.Value) (err error
 	target.Set(reflect.ValueOf(results))
 	return nil
 }
+
+// resolve the address, the resolution is bound by the resolve timeout (see bw.EnvResolveTimeout)
+// so a broken dns server fails fast instead of hanging startup.
+func resolveTCPAddr(saddr string) (*net.TCPAddr, error) {
+	timeout := envx.Duration(DefaultResolveTimeout, bw.EnvResolveTimeout)
+	ctx, done := context.WithTimeout(context.Background(), timeout)
+	defer done()
+
+	addr, err := ResolveTCPAddr(ctx, net.DefaultResolver, saddr)
+	if errors.Is(err, context.DeadlineExceeded) {
+		return nil, errors.Errorf("timed out resolving %s after %s, the timeout can be adjusted using %s", saddr, timeout, bw.EnvResolveTimeout)
+	}
+
+	return addr, err
+}
+
+// ResolveTCPAddr resolves the address using the resolver, the resolution is bound by the context.
+// addresses without a port use the default p2p port. ipv6 addresses must be bracketed, e.g.) [::1]
+// or [::1]:2000, since a bare ipv6 address is ambiguous when the last group could be a port.
+// hostnames resolve to their first ipv4 address when present.
+func ResolveTCPAddr(ctx context.Context, r *net.Resolver, saddr string) (*net.TCPAddr, error) {
+	if net.ParseIP(saddr) != nil && strings.Contains(saddr, ":") {
+		return nil, errors.Errorf("ambiguous ipv6 address %s, ipv6 addresses must be bracketed: [%s] or [%s]:port", saddr, saddr, saddr)
+	}
+
+	host := strings.TrimSuffix(strings.TrimPrefix(saddr, "["), "]")
+	if net.ParseIP(host) != nil {
+		return net.ResolveTCPAddr("tcp", net.JoinHostPort(host, strconv.Itoa(bw.DefaultP2PPort)))
+	}
+
+	if _, _, err := net.SplitHostPort(saddr); err != nil {
+		if aerr, ok := err.(*net.AddrError); ok && aerr.Err == "missing port in address" {
+			saddr = net.JoinHostPort(saddr, strconv.Itoa(bw.DefaultP2PPort))
+		}
+	}
+
+	host, sport, err := net.SplitHostPort(saddr)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	port, err := r.LookupPort(ctx, "tcp", sport)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	if ip := net.ParseIP(host); ip != nil || host == "" {
+		return &net.TCPAddr{IP: ip, Port: port}, nil
+	}
+
+	ips, err := r.LookupIPAddr(ctx, host)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, errors.WithStack(ctx.Err())
+		}
+
+		return nil, errors.WithStack(err)
+	}
+
+	for _, ip := range ips {
+		if ip.IP.To4() != nil {
+			return &net.TCPAddr{IP: ip.IP, Port: port, Zone: ip.Zone}, nil
+		}
+	}
+
+	if len(ips) == 0 {
+		return nil, errors.Errorf("no addresses found for %s", host)
+	}
+
+	return &net.TCPAddr{IP: ips[0].IP, Port: port, Zone: ips[0].Zone}, nil
+}
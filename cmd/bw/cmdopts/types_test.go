@@ -0,0 +1,188 @@
+package cmdopts_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"reflect"
+	"time"
+
+	"github.com/alecthomas/kong"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/james-lawrence/bw"
+	"github.com/james-lawrence/bw/agent"
+	. "github.com/james-lawrence/bw/cmd/bw/cmdopts"
+)
+
+type addresses struct {
+	Address   *net.TCPAddr   `name:"address"`
+	Addresses []*net.TCPAddr `name:"addresses"`
+}
+
+func parseAddresses(args ...string) (cli addresses) {
+	cli, err := tryParseAddresses(args...)
+	Expect(err).To(Succeed())
+	return cli
+}
+
+func tryParseAddresses(args ...string) (cli addresses, err error) {
+	parser, err := kong.New(
+		&cli,
+		kong.TypeMapper(reflect.TypeOf(&net.TCPAddr{}), kong.MapperFunc(ParseTCPAddr)),
+		kong.TypeMapper(reflect.TypeOf([]*net.TCPAddr(nil)), kong.MapperFunc(ParseTCPAddrArray)),
+	)
+	Expect(err).To(Succeed())
+	_, err = parser.Parse(args)
+	return cli, err
+}
+
+var _ = Describe("ParseTCPAddr", func() {
+	It("should retain an explicit port", func() {
+		cli := parseAddresses("--address", "127.0.0.1:3000", "--addresses", "127.0.0.1:3001,[::1]:3002")
+		Expect(cli.Address.Port).To(Equal(3000))
+		Expect(cli.Addresses).To(HaveLen(2))
+		Expect(cli.Addresses[0].Port).To(Equal(3001))
+		Expect(cli.Addresses[1].Port).To(Equal(3002))
+	})
+
+	It("should default the port of addresses without one", func() {
+		cli := parseAddresses("--address", "127.0.0.1", "--addresses", "127.0.0.1,[::1]")
+		Expect(cli.Address.Port).To(Equal(bw.DefaultP2PPort))
+		Expect(cli.Addresses).To(HaveLen(2))
+		Expect(cli.Addresses[0].Port).To(Equal(bw.DefaultP2PPort))
+		Expect(cli.Addresses[1].IP.Equal(net.IPv6loopback)).To(BeTrue())
+		Expect(cli.Addresses[1].Port).To(Equal(bw.DefaultP2PPort))
+	})
+
+	DescribeTable("should parse a mix of ipv4, ipv6, and hostnames",
+		func(arg string, expected ...string) {
+			cli := parseAddresses("--addresses", arg)
+			actual := make([]string, 0, len(cli.Addresses))
+			for _, addr := range cli.Addresses {
+				actual = append(actual, addr.String())
+			}
+			Expect(actual).To(Equal(expected))
+		},
+		Entry("ipv4 and ipv6 with ports", "127.0.0.1:3000,[::1]:3001", "127.0.0.1:3000", "[::1]:3001"),
+		Entry("ipv6 without a port", "[::1],[2001:db8::1]", fmt.Sprintf("[::1]:%d", bw.DefaultP2PPort), fmt.Sprintf("[2001:db8::1]:%d", bw.DefaultP2PPort)),
+		Entry("hostname with ipv6", "localhost:3000, [2001:db8::1]:2000", "127.0.0.1:3000", "[2001:db8::1]:2000"),
+		Entry("mixed delimiters", "127.0.0.1:3000 [::1]:3001\n[::ffff:10.0.0.1]:3002", "127.0.0.1:3000", "[::1]:3001", "10.0.0.1:3002"),
+	)
+
+	DescribeTable("should reject bare ipv6 addresses",
+		func(arg string) {
+			_, err := tryParseAddresses("--addresses", arg)
+			Expect(err).To(MatchError(ContainSubstring("ambiguous ipv6 address")))
+		},
+		Entry("loopback", "127.0.0.1:3000,::1"),
+		Entry("trailing group resembling a port", "[::1]:3000,2001:db8::1:2000"),
+		Entry("ipv4 mapped", "::ffff:10.0.0.1"),
+	)
+
+	It("should reject a bare ipv6 address", func() {
+		_, err := tryParseAddresses("--address", "2001:db8::1")
+		Expect(err).To(MatchError(ContainSubstring("ambiguous ipv6 address")))
+	})
+
+	// the override is read once at init, so the assertions are made by a fresh test process.
+	It("should default every port using the environment override", func() {
+		const override = 2100
+
+		if os.Getenv(bw.EnvDefaultP2PPort) == "" {
+			cmd := exec.Command(os.Args[0], "-ginkgo.focus=should default every port using the environment override")
+			cmd.Env = append(os.Environ(), bw.EnvDefaultP2PPort+"=2100")
+			out, err := cmd.CombinedOutput()
+			Expect(err).To(Succeed(), string(out))
+			return
+		}
+
+		Expect(bw.DefaultP2PPort).To(Equal(override))
+		Expect(agent.NewConfig(agent.ConfigOptionDefaultBind(net.ParseIP("127.0.0.1"))).P2PBind.Port).To(Equal(override))
+		Expect(agent.NewConfigClient(agent.ConfigClient{}, agent.CCOptionAddress("localhost")).Address).To(Equal("localhost:2100"))
+		Expect(agent.NewPeer("node").P2PPort).To(Equal(uint32(override)))
+		Expect(parseAddresses("--address", "127.0.0.1").Address.Port).To(Equal(override))
+	})
+})
+
+var _ = Describe("ResolveTCPAddr", func() {
+	// resolver whose dns server never responds.
+	stalled := &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+	}
+
+	It("should fail once the deadline passes when the dns server stalls", func() {
+		ctx, done := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer done()
+
+		started := time.Now()
+		_, err := ResolveTCPAddr(ctx, stalled, "bootstrap.example.com:2000")
+		Expect(errors.Is(err, context.DeadlineExceeded)).To(BeTrue())
+		Expect(time.Since(started)).To(BeNumerically("<", time.Second))
+	})
+
+	It("should not consult the dns server for ip addresses", func() {
+		ctx, done := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer done()
+
+		addr, err := ResolveTCPAddr(ctx, stalled, "127.0.0.1:2000")
+		Expect(err).To(Succeed())
+		Expect(addr.String()).To(Equal("127.0.0.1:2000"))
+	})
+
+	It("should prefer ipv4 addresses of hostnames", func() {
+		addr, err := ResolveTCPAddr(context.Background(), net.DefaultResolver, "localhost")
+		Expect(err).To(Succeed())
+		Expect(addr.String()).To(Equal(fmt.Sprintf("127.0.0.1:%d", bw.DefaultP2PPort)))
+	})
+})
+
+type durations struct {
+	Timeout  time.Duration `name:"timeout" default:"10s"`
+	Interval time.Duration `name:"interval"`
+}
+
+func parseDurations(args ...string) (cli durations, err error) {
+	parser, err := kong.New(
+		&cli,
+		kong.TypeMapper(reflect.TypeOf(time.Duration(0)), kong.MapperFunc(ParseDuration)),
+	)
+	Expect(err).To(Succeed())
+	_, err = parser.Parse(args)
+	return cli, err
+}
+
+var _ = Describe("ParseDuration", func() {
+	DescribeTable("should parse durations and seconds",
+		func(arg string, expected time.Duration) {
+			cli, err := parseDurations("--timeout", arg)
+			Expect(err).To(Succeed())
+			Expect(cli.Timeout).To(Equal(expected))
+		},
+		Entry("hours and minutes", "1h30m", 90*time.Minute),
+		Entry("minutes", "90m", 90*time.Minute),
+		Entry("seconds", "45", 45*time.Second),
+		Entry("zero", "0", time.Duration(0)),
+		Entry("fractional", "1.5s", 1500*time.Millisecond),
+	)
+
+	It("should use the default", func() {
+		cli, err := parseDurations()
+		Expect(err).To(Succeed())
+		Expect(cli.Timeout).To(Equal(10 * time.Second))
+		Expect(cli.Interval).To(Equal(time.Duration(0)))
+	})
+
+	It("should name the invalid token", func() {
+		_, err := parseDurations("--interval", "ten minutes")
+		Expect(err).To(MatchError(ContainSubstring("invalid duration ten minutes")))
+	})
+})
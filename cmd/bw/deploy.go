@@ -6,6 +6,7 @@ import (
 	"regexp"
 	"time"
 
+	"github.com/james-lawrence/bw/agent"
 	"github.com/james-lawrence/bw/cmd/bw/cmdopts"
 	"github.com/james-lawrence/bw/cmd/deploy"
 	"github.com/james-lawrence/bw/deployment"
@@ -16,6 +17,7 @@ type cmdDeploy struct {
 	Locally  cmdDeployLocal       `cmd:"" name:"locally" aliases:"local" help:"deploy to the local system"`
 	Snapshot cmdDeploySnapshot    `cmd:"" name:"snapshot" help:"generate a deployment archive without uploading it anywhere"`
 	Redeploy cmdDeployRedeploy    `cmd:"" name:"archive" help:"redeploy an archive to nodes within the cluster of the specified environment"`
+	Rollback cmdDeployRollback    `cmd:"" name:"rollback" help:"redeploy a previously deployed archive to nodes within the cluster of the specified environment"`
 	Cancel   cmdDeployCancel      `cmd:"" name:"cancel" help:"cancel any current deploy"`
 }
 
@@ -26,9 +28,17 @@ type DeployCluster struct {
 	Lenient     bool             `name:"ignore-failures" help:"ignore failed deploys"`
 	Canary      bool             `name:"canary" help:"deploy to the canary server" default:"false"`
 	Heartbeat   time.Duration    `name:"heartbeat" help:"frequency at which the deploy should emit a heartbeat" default:"10s"`
+	Timeout     time.Duration    `name:"timeout" help:"timeout of the deploy on each node, overrides the environment's configuration" placeholder:"DURATION"`
 	Names       []*regexp.Regexp `name:"name" help:"regex to match names against"`
 	IPs         []net.IP         `name:"ip" help:"match against the provided IP addresses"`
 	Concurrency int64            `name:"concurrency" help:"number of nodes allowed to deploy simultaneously"`
+	Progress    string           `name:"progress" help:"format of the deploy progress: human, json, or ndjson, overrides the environment's configuration" enum:"human,json,ndjson," default:"" placeholder:"FORMAT"`
+	Simulate    bool             `name:"simulate" help:"exercise the deploy against the nodes without executing it or changing their state"`
+	Zone        string           `name:"zone" help:"zone the client resides within, peers within the zone are preferred, overrides the environment's configuration" placeholder:"ZONE"`
+	DryRun      bool             `name:"dry-run" help:"plan the deploy without initiating it, reporting the nodes and partitions it would deploy to"`
+	Follow      bool             `name:"follow" help:"stream the deploy output of each node while the deploy is in progress"`
+	WritePlan   string           `name:"write-plan" help:"write the plan of the deploy to the json file before it is initiated" placeholder:"PATH"`
+	Output      string           `name:"output" help:"format of the summary reported once the deploy concludes: human or json" enum:"human,json" default:"human" placeholder:"FORMAT"`
 }
 
 type cmdDeployEnvironment struct {
@@ -58,12 +68,20 @@ func (t cmdDeployEnvironment) Run(ctx *cmdopts.Global) error {
 		Verbose:     ctx.Verbosity > 0,
 		Environment: t.Environment,
 		Concurrency: t.Concurrency,
+		Progress:    t.Progress,
+		Zone:        t.Zone,
 		Insecure:    t.Insecure,
 		Heartbeat:   t.Heartbeat,
+		Timeout:     t.Timeout,
 		Lenient:     t.Lenient,
 		Silent:      t.Silent,
 		Canary:      t.Canary,
 		Debug:       t.Debug,
+		Simulate:    t.Simulate,
+		DryRun:      t.DryRun,
+		Follow:      t.Follow,
+		WritePlan:   t.WritePlan,
+		Output:      t.Output,
 		Filter:      deployment.Or(filters...),
 		AllowEmpty:  len(filters) == 0,
 	})
@@ -97,17 +115,77 @@ func (t cmdDeployRedeploy) Run(ctx *cmdopts.Global) error {
 		Verbose:     ctx.Verbosity > 0,
 		Environment: t.Environment,
 		Concurrency: t.Concurrency,
+		Progress:    t.Progress,
+		Zone:        t.Zone,
 		Insecure:    t.Insecure,
 		Lenient:     t.Lenient,
 		Heartbeat:   t.Heartbeat,
+		Timeout:     t.Timeout,
 		Silent:      t.Silent,
 		Canary:      t.Canary,
 		Debug:       t.Debug,
+		Simulate:    t.Simulate,
+		DryRun:      t.DryRun,
+		Follow:      t.Follow,
+		WritePlan:   t.WritePlan,
+		Output:      t.Output,
 		Filter:      deployment.Or(filters...),
 		AllowEmpty:  len(filters) == 0,
 	}, t.DeploymentID)
 }
 
+type cmdDeployRollback struct {
+	DeployCluster
+	cmdopts.BeardedWookieEnvRequired
+	Ref string `arg:"" name:"ref" optional:"" help:"commit or deployment id of a retained archive, defaults to the deploy prior to the latest"`
+}
+
+func (t cmdDeployRollback) Run(ctx *cmdopts.Global) error {
+	filters := make([]deployment.Filter, 0, len(t.Names))
+	for _, n := range t.Names {
+		filters = append(filters, deployment.Named(n))
+	}
+
+	for _, n := range t.IPs {
+		filters = append(filters, deployment.IP(n))
+	}
+
+	// need a filter to be present for the canary to work.
+	if t.Canary {
+		filters = append(filters, deployment.AlwaysMatch)
+	}
+
+	option := agent.CCOptionRollback()
+	if t.Ref != "" {
+		option = agent.CCOptionRollbackTo(t.Ref)
+	}
+
+	return deploy.Rollback(&deploy.Context{
+		Context:     ctx.Context,
+		CancelFunc:  ctx.Shutdown,
+		WaitGroup:   ctx.Cleanup,
+		Verbose:     ctx.Verbosity > 0,
+		Environment: t.Environment,
+		Concurrency: t.Concurrency,
+		Progress:    t.Progress,
+		Zone:        t.Zone,
+		Insecure:    t.Insecure,
+		Lenient:     t.Lenient,
+		Heartbeat:   t.Heartbeat,
+		Timeout:     t.Timeout,
+		Silent:      t.Silent,
+		Canary:      t.Canary,
+		Debug:       t.Debug,
+		Simulate:    t.Simulate,
+		DryRun:      t.DryRun,
+		Follow:      t.Follow,
+		WritePlan:   t.WritePlan,
+		Output:      t.Output,
+		Filter:      deployment.Or(filters...),
+		AllowEmpty:  len(filters) == 0,
+	}, option)
+}
+
 type cmdDeployLocal struct {
 	DeployCluster
 	cmdopts.BeardedWookieEnv
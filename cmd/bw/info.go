@@ -36,10 +36,11 @@ import (
 )
 
 type cmdInfo struct {
-	Watch cmdInfoWatch `cmd:"" help:"watch cluster activity"`
-	Nodes cmdInfoNodes `cmd:"" help:"retrieve nodes within the cluster"`
-	Logs  cmdInfoLogs  `cmd:"" help:"log retrieval for the latest deployment"`
-	Check cmdInfoCheck `cmd:"" help:"check connectivity with the discovery service"`
+	Watch    cmdInfoWatch    `cmd:"" help:"watch cluster activity"`
+	Nodes    cmdInfoNodes    `cmd:"" help:"retrieve nodes within the cluster"`
+	Logs     cmdInfoLogs     `cmd:"" help:"log retrieval for the latest deployment"`
+	Check    cmdInfoCheck    `cmd:"" help:"check connectivity with the discovery service"`
+	Deployed cmdInfoDeployed `cmd:"" help:"commit deployed to each node, highlighting the nodes not running the expected commit"`
 }
 
 type cmdInfoWatch struct {
@@ -73,7 +74,7 @@ func (t cmdInfoWatch) Run(ctx *cmdopts.Global) (err error) {
 		return err
 	}
 
-	qd := dialers.NewQuorum(c, d.Defaults()...)
+	qd := dialers.NewQuorum(c, d.Defaults()...).PreferZone(config.Zone)
 
 	if conn, err = qd.DialContext(ctx.Context); err != nil {
 		return err
@@ -140,7 +141,7 @@ func (t cmdInfoNodes) Run(ctx *cmdopts.Global) (err error) {
 		return err
 	}
 
-	qd := dialers.NewQuorum(c, d.Defaults()...)
+	qd := dialers.NewQuorum(c, d.Defaults()...).PreferZone(config.Zone)
 
 	if conn, err = qd.DialContext(ctx.Context); err != nil {
 		return err
@@ -205,6 +206,61 @@ func (t cmdInfoLogs) Run(ctx *cmdopts.Global) (err error) {
 	return iox.Error(io.Copy(os.Stderr, logs))
 }
 
+type cmdInfoDeployed struct {
+	cmdopts.BeardedWookieEnv
+	Insecure bool   `help:"skip tls verification"`
+	Ref      string `help:"commit the nodes are expected to be running, defaults to the commit deployed to the majority of the nodes"`
+}
+
+func (t cmdInfoDeployed) Run(ctx *cmdopts.Global) (err error) {
+	var (
+		c      clustering.Rendezvous
+		d      dialers.Defaults
+		config agent.ConfigClient
+		ss     notary.Signer
+		refs   []agentutil.DeployedRef
+	)
+	defer ctx.Shutdown()
+
+	if config, err = commandutils.LoadConfiguration(t.Environment, agent.CCOptionInsecure(t.Insecure)); err != nil {
+		return err
+	}
+
+	if ss, err = notary.NewAutoSigner(vcsinfo.CurrentUserDisplay(config.WorkDir())); err != nil {
+		return err
+	}
+
+	if d, c, err = daemons.Connect(config, ss, grpc.WithPerRPCCredentials(ss)); err != nil {
+		return err
+	}
+
+	cx := cluster.New(commandutils.NewClientPeer(), c)
+	if refs, err = agentutil.DeployedRefs(ctx.Context, cx, d); err != nil {
+		return err
+	}
+
+	report := agentutil.NewRefReport(t.Ref, refs...)
+	log.Println("expected commit", report.Expected, "in sync nodes", len(report.InSync))
+
+	for _, r := range report.InSync {
+		log.Println("in sync", r.Commit, r.Deployed.Format(time.RFC3339), uxterm.PeerString(r.Peer))
+	}
+
+	for _, r := range report.Unknown {
+		log.Println("unknown commit", uxterm.PeerString(r.Peer))
+	}
+
+	for _, r := range report.OutOfSync {
+		log.Println("OUT OF SYNC", r.Commit, r.Deployed.Format(time.RFC3339), uxterm.PeerString(r.Peer))
+	}
+
+	if !report.Converged() {
+		return errors.Errorf("%d nodes are not running the expected commit %s", len(report.OutOfSync)+len(report.Unknown), report.Expected)
+	}
+
+	return nil
+}
+
 type cmdInfoCheck struct {
 	Insecure bool   `help:"skip tls verification"`
 	Address  string `help:"address to check" arg:""`
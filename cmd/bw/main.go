@@ -10,6 +10,7 @@ import (
 	"reflect"
 	"sync"
 	"syscall"
+	"time"
 
 	"github.com/alecthomas/kong"
 	"github.com/james-lawrence/bw"
@@ -20,7 +21,9 @@ import (
 	"github.com/james-lawrence/bw/cmd/commandutils"
 	"github.com/james-lawrence/bw/internal/contextx"
 	"github.com/james-lawrence/bw/internal/debugx"
+	"github.com/james-lawrence/bw/internal/logx"
 	"github.com/james-lawrence/bw/internal/systemx"
+	"github.com/pkg/errors"
 	"github.com/posener/complete"
 	"github.com/willabides/kongplete"
 )
@@ -34,6 +37,7 @@ func main() {
 		Redeploy           cmdDeployRedeploy            `cmd:"" name:"redeploy" help:"redeploy an archive to nodes within the cluster of the specified environment"`
 		Me                 cmdMe                        `cmd:"" help:"commands for managing the user's profile"`
 		Info               cmdInfo                      `cmd:"" help:"retrieve information from an environment"`
+		Cluster            cmdCluster                   `cmd:"" help:"diagnose the cluster of an environment"`
 		Notary             cmdNotary                    `cmd:"" help:"retrieve and manage permissions"`
 		Workspace          cmdWorkspace                 `cmd:"" help:"workspace related commands"`
 		InstallCompletions kongplete.InstallCompletions `cmd:"" help:"install shell completions"`
@@ -49,13 +53,14 @@ func main() {
 		agentconfigdefaults = agent.NewConfig(agent.ConfigOptionDefaultBind(systemip))
 	)
 
+	shellCli.Level = logx.NewVerbosity(0)
 	shellCli.Cleanup = &sync.WaitGroup{}
 	shellCli.Context = contextx.WithWaitGroup(context.Background(), shellCli.Cleanup)
 	shellCli.Context, shellCli.Shutdown = context.WithCancel(shellCli.Context)
 
 	log.SetFlags(log.Flags() | log.Lshortfile)
 	go debugx.DumpOnSignal(shellCli.Context, syscall.SIGUSR2)
-	go systemx.Cleanup(shellCli.Context, shellCli.Shutdown, shellCli.Cleanup, os.Kill, os.Interrupt)(func() {
+	go systemx.Cleanup(shellCli.Context, shellCli.Shutdown, shellCli.Cleanup, os.Kill, os.Interrupt, syscall.SIGTERM)(func() {
 		log.Println("waiting for systems to shutdown")
 	})
 
@@ -71,18 +76,28 @@ func main() {
 			"vars_bw_default_agent_address":                    agentconfigdefaults.P2PBind.String(),
 			"env_bw_agent_bind_primary":                        bw.EnvAgentP2PBind,
 			"env_bw_agent_bind_advertised":                     bw.EnvAgentP2PAdvertised,
+			"env_bw_agent_bind_cidr":                           bw.EnvAgentP2PBindCIDR,
 			"env_bw_agent_bind_secondary":                      bw.EnvAgentP2PAlternatesBind,
+			"env_bw_agent_bind_advertised_alternates":          bw.EnvAgentP2PAlternatesAdvertised,
 			"env_bw_agent_bootstrap_static":                    bw.EnvAgentClusterBootstrap,
+			"env_bw_agent_bootstrap_file":                      bw.EnvAgentClusterBootstrapFile,
 			"env_bw_agent_bootstrap_dns_enabled":               bw.EnvAgentClusterEnableDNS,
+			"env_bw_agent_bootstrap_dns_srv":                   bw.EnvAgentClusterDNSSRV,
 			"env_bw_agent_bootstrap_aws_autoscaling_enabled":   bw.EnvAgentClusterEnableAWSAutoscaling,
 			"env_bw_agent_bootstrap_gcloud_taget_pool_enabled": bw.EnvAgentClusterEnableGoogleCloudPool,
+			"env_bw_agent_bootstrap_azure_scale_sets_enabled":  bw.EnvAgentClusterEnableAzureScaleSet,
+			"env_bw_agent_bootstrap_consul_enabled":            bw.EnvAgentClusterEnableConsul,
+			"env_bw_agent_bootstrap_kubernetes_enabled":        bw.EnvAgentClusterEnableKubernetes,
+			"env_bw_agent_bootstrap_digitalocean_enabled":      bw.EnvAgentClusterEnableDigitalOcean,
 		},
 		kong.UsageOnError(),
 		kong.Bind(&shellCli.Global),
 		kong.Bind(&agentconfigdefaults),
 		kong.TypeMapper(reflect.TypeOf(&net.IP{}), kong.MapperFunc(cmdopts.ParseIP)),
 		kong.TypeMapper(reflect.TypeOf(&net.TCPAddr{}), kong.MapperFunc(cmdopts.ParseTCPAddr)),
+		kong.TypeMapper(reflect.TypeOf(&net.IPNet{}), kong.MapperFunc(cmdopts.ParseCIDR)),
 		kong.TypeMapper(reflect.TypeOf([]*net.TCPAddr(nil)), kong.MapperFunc(cmdopts.ParseTCPAddrArray)),
+		kong.TypeMapper(reflect.TypeOf(time.Duration(0)), kong.MapperFunc(cmdopts.ParseDuration)),
 	)
 
 	// Run kongplete.Complete to handle completion requests
@@ -95,12 +110,27 @@ func main() {
 		os.Exit(1)
 	}
 
+	shellCli.Level.Set(shellCli.Verbosity)
+	shellCli.RouteStandardLog()
+	shellCli.Global.VerbosityOnSignal(shellCli.Context, syscall.SIGUSR1)
+
 	if err = commandutils.LogCause(ctx.Run()); err != nil {
 		shellCli.Shutdown()
 	}
 
 	shellCli.Cleanup.Wait()
 	if err != nil {
-		os.Exit(1)
+		os.Exit(exitCode(err))
 	}
 }
+
+// exitCode of the command, errors carrying an exit code determine it, e.g.) the summary of a deploy.
+func exitCode(err error) int {
+	var coded interface{ ExitCode() int }
+
+	if errors.As(err, &coded) {
+		return coded.ExitCode()
+	}
+
+	return 1
+}
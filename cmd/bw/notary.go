@@ -2,6 +2,7 @@ package main
 
 import (
 	"log"
+	"time"
 
 	"github.com/bits-and-blooms/bloom/v3"
 	"github.com/davecgh/go-spew/spew"
@@ -20,6 +21,7 @@ import (
 type cmdNotary struct {
 	Search cmdNotarySearch `cmd:"" help:"search users"`
 	Print  cmdNotaryPrint  `cmd:"" help:"list the fingerprints and their permissions in a file"`
+	Rotate cmdNotaryRotate `cmd:"" help:"rotate a signing key across the cluster, both keys are valid during the grace window"`
 }
 
 type cmdNotarySearch struct {
@@ -52,7 +54,7 @@ func (t cmdNotarySearch) Run(ctx *cmdopts.Global) (err error) {
 		return err
 	}
 
-	client := notary.NewClient(dialers.NewQuorum(c, d.Defaults()...))
+	client := notary.NewClient(dialers.NewQuorum(c, d.Defaults()...).PreferZone(config.Zone))
 
 	if s, err = client.Search(ctx.Context, &notary.SearchRequest{}); err != nil {
 		return err
@@ -100,3 +102,58 @@ func (t *cmdNotaryPrint) Run(ctx *cmdopts.Global) (err error) {
 		}
 	}
 }
+
+type cmdNotaryRotate struct {
+	cmdopts.BeardedWookieEnv
+	Insecure bool          `help:"skip tls verification"`
+	Agent    string        `help:"root directory of an agent, rotates the signing key of the agent instead of the user's key" placeholder:"/var/cache/bearded-wookie"`
+	Grace    time.Duration `help:"duration both keys are valid before the current key is revoked, processes using the key must be restarted within the window" default:"1m"`
+}
+
+func (t cmdNotaryRotate) Run(ctx *cmdopts.Global) (err error) {
+	var (
+		d        dialers.Direct
+		config   agent.ConfigClient
+		ss       notary.Signer
+		c        clustering.Rendezvous
+		rotation notary.Rotation
+	)
+	defer ctx.Shutdown()
+
+	if config, err = commandutils.LoadConfiguration(t.Environment, agent.CCOptionInsecure(t.Insecure)); err != nil {
+		return err
+	}
+
+	displayname := vcsinfo.CurrentUserDisplay(config.WorkDir())
+
+	if ss, err = notary.NewAutoSigner(displayname); err != nil {
+		return err
+	}
+
+	location, comment := notary.PrivateKeyPath(), displayname
+	if t.Agent != "" {
+		location, comment = notary.AgentSignerPath(t.Agent), ""
+	}
+
+	if rotation, err = notary.NewRotation(location, comment); err != nil {
+		return err
+	}
+
+	if d, c, err = daemons.Connect(config, ss, grpc.WithPerRPCCredentials(ss)); err != nil {
+		return err
+	}
+
+	client := notary.NewClient(dialers.NewQuorum(c, d.Defaults()...).PreferZone(config.Zone))
+
+	current, _, _ := rotation.Current.AutoSignerInfo()
+	next, _, _ := rotation.Next.AutoSignerInfo()
+	log.Println("rotating", location, "from", current, "to", next)
+
+	if err = rotation.Rotate(ctx.Context, client, t.Grace); err != nil {
+		return err
+	}
+
+	log.Println("rotation completed", current, "revoked")
+
+	return nil
+}
@@ -95,7 +95,8 @@ func (t *cmdDNS) exec(ctx *kingpin.ParseContext) (err error) {
 			t.region,
 			dns.Route53OptionCommon(
 				dns.OptionTTL(t.config.DNSBind.TTL),
-				dns.OptionFQDN(stringsx.DefaultIfBlank(t.hostname, t.config.ServerName)),
+				dns.OptionHealthyOnly(t.config.DNSBind.HealthyOnly),
+				dns.OptionFQDN(stringsx.DefaultIfBlank(t.hostname, t.config.DNSBind.Name(t.config.ServerName))),
 				dns.OptionMaximumNodes(t.config.MinimumNodes),
 			),
 		),
@@ -101,7 +101,8 @@ func (t *cmdDNS) exec(ctx *kingpin.ParseContext) (err error) {
 		t.zoneID,
 		dns.GCloudDNSOptionCommon(
 			dns.OptionTTL(t.config.DNSBind.TTL),
-			dns.OptionFQDN(t.config.ServerName),
+			dns.OptionHealthyOnly(t.config.DNSBind.HealthyOnly),
+			dns.OptionFQDN(t.config.DNSBind.Name(t.config.ServerName)),
 			dns.OptionMaximumNodes(t.config.MinimumNodes),
 		),
 	).Sample(cx)
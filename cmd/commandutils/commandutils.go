@@ -62,7 +62,14 @@ func LoadAgentConfig(path string, proto agent.Config) (c agent.Config, err error
 	if err = bw.ExpandAndDecodeFile(path, &proto); err != nil {
 		return c, err
 	}
-	return proto.EnsureDefaults(), nil
+
+	for _, warning := range proto.Lint() {
+		log.Println("WARNING:", warning)
+	}
+
+	proto = proto.EnsureDefaults()
+
+	return proto, proto.Validate()
 }
 
 // LoadConfiguration loads the configuration for the given environment.
@@ -89,7 +96,7 @@ func LoadConfiguration(environment string, options ...agent.ConfigClientOption)
 		log.Println("loading configuration", path, bw.DefaultCacheDirectory())
 	}
 
-	if config, err = agent.DefaultConfigClient(append(options, agent.CCOptionTLSConfig(environment))...).LoadConfig(path); err != nil {
+	if config, err = agent.DefaultConfigClient(append(options, agent.CCOptionTLSConfig(environment), agent.CCOptionEnvironmentName(environment))...).LoadConfig(path); err != nil {
 		return config, errors.Wrap(err, "configuration load failed")
 	}
 
@@ -126,15 +133,18 @@ func LoadConfiguration(environment string, options ...agent.ConfigClientOption)
 		)))
 	}
 
+	refresh := cc.NewRefreshClient(
+		config.Credentials.Directory,
+		config.Credentials.Insecure,
+	)
+	refresh.Pin = config.Credentials.Pin
+
 	// load or create credentials.
 	if err = cc.FromConfig(
 		config.Credentials.Directory,
 		config.Credentials.Mode,
 		path,
-		cc.NewRefreshClient(
-			config.Credentials.Directory,
-			config.Credentials.Insecure,
-		),
+		refresh,
 	); err != nil {
 		return config, errors.Wrapf(
 			err,
@@ -193,28 +203,52 @@ func ReadConfiguration(environment string) (config agent.ConfigClient, err error
 	return config, err
 }
 
-// ClusterJoin connects to a cluster.
-func ClusterJoin(ctx context.Context, conf agent.Config, c clustering.Joiner, defaultPeers ...clustering.Source) (err error) {
+// ClusterJoin connects to a cluster, peers whose clock drifts beyond the maximum of the configuration are refused.
+func ClusterJoin(ctx context.Context, conf agent.Config, c clustering.Joiner, drift clustering.ClockDrift, defaultPeers ...clustering.Source) (err error) {
 	if envx.Boolean(false, bw.EnvLogsVerbose) {
 		log.Println("connecting to cluster")
 		defer log.Println("connection to cluster complete")
 	}
 
+	policy := conf.BootstrapPolicy()
 	joins := clustering.BootstrapOptionJoinStrategy(clustering.MinimumPeers(conf.MinimumNodes))
-	attempts := clustering.BootstrapOptionAllowRetry(clustering.MaximumAttempts(conf.Bootstrap.Attempts))
+	attempts := clustering.BootstrapOptionAllowRetry(clustering.MaximumAttempts(policy.Attempts))
+	delay := clustering.BootstrapOptionBackoff(policy.Retry())
 	peerings := clustering.BootstrapOptionPeeringStrategies(defaultPeers...)
-	banned := clustering.BootstrapOptionBanned(
+	clock := clustering.BootstrapOptionMaxClockDrift(conf.MaxClockDrift, drift)
+	if err = clustering.Bootstrap(ctx, c, peerings, joins, attempts, delay, banned(conf), clock); err != nil {
+		return errors.Wrap(err, "failed to bootstrap cluster")
+	}
+
+	return nil
+}
+
+// ClusterDiscover periodically joins newly discovered peers at the configured discovery
+// interval until the context is done. noop when the interval is disabled.
+func ClusterDiscover(ctx context.Context, conf agent.Config, c clustering.Joiner, drift clustering.ClockDrift, sources ...clustering.Source) {
+	if conf.DiscoveryInterval <= 0 {
+		return
+	}
+
+	go clustering.Discover(
+		ctx,
+		c,
+		conf.DiscoveryInterval,
+		clustering.BootstrapOptionPeeringStrategies(sources...),
+		banned(conf),
+		clustering.BootstrapOptionMaxClockDrift(conf.MaxClockDrift, drift),
+	)
+}
+
+// banned the addresses of the agent itself.
+func banned(conf agent.Config) clustering.BootstrapOption {
+	return clustering.BootstrapOptionBanned(
 		append(
-			netx.AddrToString(conf.AlternateBinds...),
+			append(netx.AddrToString(conf.AlternateBinds...), netx.AddrToString(conf.AlternateAdvertised...)...),
 			conf.P2PAdvertised.String(),
 			conf.P2PBind.String(),
 		)...,
 	)
-	if err = clustering.Bootstrap(ctx, c, peerings, joins, attempts, banned); err != nil {
-		return errors.Wrap(err, "failed to bootstrap cluster")
-	}
-
-	return nil
 }
 
 // DebugLog return a logger that is either enabled or disabled for debugging purposes.
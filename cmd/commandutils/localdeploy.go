@@ -40,7 +40,7 @@ func RunLocalDirectives(ctx context.Context, config agent.ConfigClient) (commiti
 	commitish = vcsinfo.Commitish(config.WorkDir(), config.Deployment.CommitRef)
 	log.Println("vcs.commit", config.Deployment.CommitRef, "->", commitish)
 
-	if err = os.WriteFile(filepath.Join(cdir, bw.EnvFile), []byte(config.Environment), 0600); err != nil {
+	if err = os.WriteFile(filepath.Join(cdir, bw.EnvFile), []byte(config.Environ()), 0600); err != nil {
 		return commitish, err
 	}
 
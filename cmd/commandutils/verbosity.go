@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"sync"
 
 	"github.com/james-lawrence/bw"
 	"github.com/james-lawrence/bw/internal/envx"
@@ -47,6 +48,94 @@ func LogCause(err error) error {
 	return err
 }
 
+// MaximumVerbosity highest supported verbosity level.
+const MaximumVerbosity = 3
+
+var runtimeVerbosity = struct {
+	m        sync.Mutex
+	baseline map[string]*string
+}{}
+
+// verbosity level at which each logging environment variable is enabled.
+var verbosityLevels = []struct {
+	key   string
+	level int
+}{
+	{key: bw.EnvLogsConfiguration, level: 1},
+	{key: bw.EnvLogsVerbose, level: 2},
+	{key: bw.EnvLogsGRPC, level: 3},
+	{key: bw.EnvLogsGossip, level: 3},
+	{key: bw.EnvLogsRaft, level: 3},
+}
+
+// grpc logging environment variables, restored when grpc logging is disabled.
+var grpcLogEnviron = []string{"GRPC_GO_LOG_VERBOSITY_LEVEL", "GRPC_GO_LOG_SEVERITY_LEVEL"}
+
+// SetLogVerbosity adjust the logging verbosity of a running process. lowering the
+// verbosity restores the logging environment as it was prior to the first adjustment.
+// the grpc logger is rebuilt to match, gossip and raft loggers constructed
+// at startup retain their initial configuration.
+func SetLogVerbosity(verbosity int) {
+	runtimeVerbosity.m.Lock()
+	defer runtimeVerbosity.m.Unlock()
+
+	if runtimeVerbosity.baseline == nil {
+		runtimeVerbosity.baseline = make(map[string]*string, len(verbosityLevels)+len(grpcLogEnviron))
+		for _, l := range verbosityLevels {
+			baseline(l.key)
+		}
+
+		for _, key := range grpcLogEnviron {
+			baseline(key)
+		}
+	}
+
+	for _, l := range verbosityLevels {
+		if verbosity >= l.level {
+			os.Setenv(l.key, "1")
+		} else {
+			restore(l.key)
+		}
+	}
+
+	if grpcLogging() {
+		return
+	}
+
+	for _, key := range grpcLogEnviron {
+		restore(key)
+	}
+
+	grpclog.SetLoggerV2(grpcx.NewLogger())
+}
+
+func baseline(key string) {
+	if v, ok := os.LookupEnv(key); ok {
+		runtimeVerbosity.baseline[key] = &v
+	}
+}
+
+func restore(key string) {
+	if v, ok := runtimeVerbosity.baseline[key]; ok {
+		os.Setenv(key, *v)
+	} else {
+		os.Unsetenv(key)
+	}
+}
+
+// grpcLogging enables grpc logging when requested by the environment.
+func grpcLogging() bool {
+	if !envx.Boolean(false, bw.EnvLogsGRPC, bw.EnvLogsVerbose) {
+		return false
+	}
+
+	os.Setenv("GRPC_GO_LOG_VERBOSITY_LEVEL", "99")
+	os.Setenv("GRPC_GO_LOG_SEVERITY_LEVEL", "info")
+	grpclog.SetLoggerV2(grpcx.NewLogger())
+
+	return true
+}
+
 func LogEnv(verbosity int) {
 	switch verbosity {
 	case 3:
@@ -63,9 +152,5 @@ func LogEnv(verbosity int) {
 	}
 
 	// enable GRPC logging
-	if envx.Boolean(false, bw.EnvLogsGRPC, bw.EnvLogsVerbose) {
-		os.Setenv("GRPC_GO_LOG_VERBOSITY_LEVEL", "99")
-		os.Setenv("GRPC_GO_LOG_SEVERITY_LEVEL", "info")
-		grpclog.SetLoggerV2(grpcx.NewLogger())
-	}
+	grpcLogging()
 }
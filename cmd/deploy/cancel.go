@@ -47,7 +47,7 @@ func Cancel(ctx *Context) (err error) {
 		return err
 	}
 
-	qd := dialers.NewQuorum(c, d.Defaults()...)
+	qd := dialers.NewQuorum(c, d.Defaults()...).PreferZone(config.Zone)
 
 	if conn, err = qd.DialContext(ctx.Context); err != nil {
 		return err
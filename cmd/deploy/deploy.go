@@ -13,6 +13,7 @@ import (
 	"github.com/james-lawrence/bw"
 	"github.com/james-lawrence/bw/agent"
 	"github.com/james-lawrence/bw/agent/dialers"
+	"github.com/james-lawrence/bw/agentutil"
 	"github.com/james-lawrence/bw/archive"
 	"github.com/james-lawrence/bw/clustering"
 	"github.com/james-lawrence/bw/clustering/rendezvous"
@@ -24,6 +25,7 @@ import (
 	"github.com/james-lawrence/bw/internal/errorsx"
 	"github.com/james-lawrence/bw/internal/grpcx"
 	"github.com/james-lawrence/bw/internal/iox"
+	"github.com/james-lawrence/bw/internal/tracex"
 	"github.com/james-lawrence/bw/notary"
 	"github.com/james-lawrence/bw/ux"
 	"github.com/james-lawrence/bw/vcsinfo"
@@ -36,24 +38,31 @@ import (
 type Context struct {
 	Environment string
 	Concurrency int64
+	Progress    string
+	Zone        string
 	Filter      deployment.Filter
 	Verbose     bool
 	Insecure    bool
 	Lenient     bool
 	Silent      bool
 	Heartbeat   time.Duration
+	Timeout     time.Duration // overrides the deploy timeout of the configuration when > 0.
 	AllowEmpty  bool
 	Canary      bool
 	Debug       bool
+	Simulate    bool
+	DryRun      bool
+	Follow      bool
+	WritePlan   string
+	Output      string // format of the deploy summary, see OutputHuman and OutputJSON.
 	context.Context
 	context.CancelFunc
 	*sync.WaitGroup
 }
 
 // Into deploy into the specified environment.
-func Into(ctx *Context) error {
+func Into(ctx *Context) (err error) {
 	var (
-		err       error
 		dst       *os.File
 		dstinfo   os.FileInfo
 		conn      *grpc.ClientConn
@@ -67,10 +76,35 @@ func Into(ctx *Context) error {
 		commitish string
 	)
 
-	if config, err = commandutils.LoadConfiguration(ctx.Environment, agent.CCOptionInsecure(ctx.Insecure)); err != nil {
+	if config, err = commandutils.LoadConfiguration(ctx.Environment, agent.CCOptionInsecure(ctx.Insecure), agent.CCOptionSimulate(ctx.Simulate)); err != nil {
 		return errors.Wrap(err, "unable to load configuration")
 	}
 
+	// the command line takes precedence over the environment's configuration.
+	if ctx.Progress != "" {
+		config = agent.NewConfigClient(config, agent.CCOptionProgressFormat(ctx.Progress))
+	}
+
+	if ctx.Zone != "" {
+		config = agent.NewConfigClient(config, agent.CCOptionZone(ctx.Zone))
+	}
+
+	if ctx.Timeout > 0 {
+		config = agent.NewConfigClient(config, agent.CCOptionTimeout(ctx.Timeout))
+	}
+
+	if ctx.DryRun {
+		config = agent.NewConfigClient(config, agent.CCOptionDryRun(true))
+	}
+
+	if ctx.Follow {
+		config = agent.NewConfigClient(config, agent.CCOptionFollow(true))
+	}
+
+	if ctx.WritePlan != "" {
+		config = agent.NewConfigClient(config, agent.CCOptionWritePlan(ctx.WritePlan))
+	}
+
 	displayname := vcsinfo.CurrentUserDisplay(config.WorkDir())
 
 	if ss, err = notary.NewAutoSigner(displayname); err != nil {
@@ -91,6 +125,14 @@ func Into(ctx *Context) error {
 		}
 	}
 
+	defer func() {
+		posthook(ctx.Context, config, err)
+	}()
+
+	if err = prehook(ctx.Context, config); err != nil {
+		return err
+	}
+
 	if commitish, err = commandutils.RunLocalDirectives(ctx.Context, config); err != nil {
 		return errors.Wrap(err, "failed to run local directives")
 	}
@@ -121,12 +163,14 @@ func Into(ctx *Context) error {
 		return errors.Wrap(err, "unable to connect to cluster")
 	}
 
-	qd := dialers.NewQuorum(c, d.Defaults()...)
+	qd := dialers.NewQuorum(c, d.Defaults()...).PreferZone(config.Zone)
 
-	termui.NewFromClientConfig(
+	result := NewDeployResult()
+	finished := termui.NewFromClientConfig(
 		ctx.Context, config, qd, local, events,
 		ux.OptionHeartbeat(ctx.Heartbeat),
 		ux.OptionDebug(ctx.Verbose),
+		ux.OptionObserver(result.Record),
 	)
 
 	conn = grpcx.UntilSuccess(ctx.Context, func(ictx context.Context) (*grpc.ClientConn, error) {
@@ -142,8 +186,14 @@ func Into(ctx *Context) error {
 
 	events <- agent.LogEvent(local, "connected to cluster")
 
+	if err = requireQuorum(ctx.Context, config, conn); err != nil {
+		events <- agent.LogError(local, err)
+		events <- agent.LogEvent(local, "deployment failed")
+		return err
+	}
+
 	deployspace := config.Deployspace()
-	if err = os.WriteFile(filepath.Join(deployspace, bw.EnvFile), []byte(config.Environment), 0600); err != nil {
+	if err = os.WriteFile(filepath.Join(deployspace, bw.EnvFile), []byte(config.Environ()), 0600); err != nil {
 		return err
 	}
 
@@ -154,29 +204,56 @@ func Into(ctx *Context) error {
 	}
 
 	if dst, err = os.CreateTemp("", "bwarchive"); err != nil {
-		events <- agent.LogError(local, errors.Wrap(err, "archive creation failed"))
+		err = errors.Wrap(err, "archive creation failed")
+		events <- agent.LogError(local, err)
 		events <- agent.LogEvent(local, "deployment failed")
-		return nil
+		return err
 	}
 	defer os.Remove(dst.Name())
 	defer dst.Close()
 
-	if err = archive.Pack(dst, deployspace); err != nil {
+	pack := archive.Pack
+	if config.Deployment.ReproducibleArchive {
+		pack = archive.PackReproducible
+	}
+
+	if err = pack(dst, deployspace); err != nil {
 		return err
 	}
 
 	if dstinfo, err = dst.Stat(); err != nil {
-		events <- agent.LogError(local, errors.Wrap(err, "archive creation failed"))
+		err = errors.Wrap(err, "archive creation failed")
+		events <- agent.LogError(local, err)
 		events <- agent.LogEvent(local, "deployment failed")
-		return nil
+		return err
+	}
+
+	// only consider the canary node.
+	if ctx.Canary {
+		peers = agent.NodesToPeers(c.Get(rendezvous.Auto()))
+	} else {
+		peers = agent.NodesToPeers(c.Members()...)
+	}
+
+	peers = selected(config.Deployment, deployment.ApplyFilter(ctx.Filter, peers...)...)
+
+	if config.Deployment.SkipIfUnchanged {
+		if skip, err := unchanged(ctx.Context, d, dst, commitish, peers...); err != nil {
+			events <- agent.LogError(local, errors.Wrap(err, "unable to determine if the archive changed, deploying"))
+		} else if skip {
+			events <- agent.LogEvent(local, fmt.Sprintf("deploy skipped: every node is running an identical archive of commit(%s)", commitish))
+			events <- agent.NewDeployCommand(local, agent.DeployCommandDone(displayname))
+			return nil
+		}
 	}
 
 	events <- agent.LogEvent(local, "archive upload initiated")
 	err = grpcx.Retry(func() error {
 		if _, err = dst.Seek(0, io.SeekStart); err != nil {
-			events <- agent.LogError(local, errors.Wrap(err, "archive creation failed"))
+			err = errors.Wrap(err, "archive creation failed")
+			events <- agent.LogError(local, err)
 			events <- agent.LogEvent(local, "deployment failed")
-			return nil
+			return err
 		}
 
 		meta := agent.UploadMetadata{
@@ -185,7 +262,8 @@ func Into(ctx *Context) error {
 		}
 
 		if darchive, err = client.Upload(ctx.Context, &meta, dst); err != nil {
-			events <- agent.LogError(local, errors.Wrap(err, "archive upload failed"))
+			err = errors.Wrap(err, "archive upload failed")
+			events <- agent.LogError(local, err)
 			events <- agent.LogEvent(local, "deployment failed")
 			return err
 		}
@@ -204,20 +282,21 @@ func Into(ctx *Context) error {
 		max = int64(config.Partitioner().Partition(len(c.Members())))
 	}
 
-	// only consider the canary node.
-	if ctx.Canary {
-		peers = agent.NodesToPeers(c.Get(rendezvous.Auto()))
-	} else {
-		peers = agent.NodesToPeers(c.Members()...)
-	}
-
-	peers = deployment.ApplyFilter(ctx.Filter, peers...)
 	dopts := agent.DeployOptions{
-		Concurrency:       max,
-		Timeout:           int64(config.Deployment.Timeout),
-		Heartbeat:         int64(ctx.Heartbeat),
-		IgnoreFailures:    ctx.Lenient,
-		SilenceDeployLogs: ctx.Silent,
+		Concurrency:        max,
+		Timeout:            int64(config.Deployment.Timeout),
+		Heartbeat:          int64(ctx.Heartbeat),
+		IgnoreFailures:     ctx.Lenient,
+		SilenceDeployLogs:  ctx.Silent,
+		Executor:           config.Deployment.Executor,
+		Simulate:           config.Deployment.Simulate,
+		MaxTotal:           int64(config.Deployment.MaxTotal),
+		ConcurrencyByLabel: config.Deployment.ConcurrencyByLabel,
+		PerNodeConcurrency: int64(config.Deployment.PerNodeConcurrency),
+		Tracing:            config.Deployment.Tracing,
+		RetryFailedOnce:    config.Deployment.RetryFailedOnce,
+		Retries:            int64(config.Deployment.Retries),
+		RetryBackoff:       int64(config.Deployment.RetryBackoff),
 	}
 
 	if len(peers) == 0 && !ctx.AllowEmpty {
@@ -226,12 +305,60 @@ func Into(ctx *Context) error {
 		return cause
 	}
 
+	if err = writePlan(config, displayname, &dopts, darchive, planned(c, peers)...); err != nil {
+		events <- agent.LogError(local, err)
+		events <- agent.LogEvent(local, "deployment failed")
+		return err
+	}
+
+	if config.Deployment.DryRun {
+		dryrun(events, local, displayname, &dopts, darchive, planned(c, peers)...)
+		return nil
+	}
+
 	events <- agent.LogEvent(local, fmt.Sprintf("deploy initiated: by(%s) concurrency(%d), deployID(%s)", displayname, max, bw.RandomID(darchive.DeploymentID)))
-	if cause := client.RemoteDeploy(ctx.Context, displayname, &dopts, darchive, peers...); cause != nil {
-		events <- agent.LogError(local, errors.Wrap(cause, "deploy failed"))
+	result.Initiated(darchive, peers...)
+	follow(ctx, config, client, darchive, peers...)
+	tctx, span := tracex.StartRoot(ctx.Context, "bw.deploy", dopts.Tracing)
+	defer span.End()
+
+	if cause := client.RemoteDeploy(tctx, displayname, &dopts, darchive, peers...); cause != nil {
+		events <- agent.LogError(local, errors.Wrap(agent.ExplainDeployRejection(cause), "deploy failed"))
 		events <- agent.DeployEventFailed(local, displayname, &dopts, darchive, cause)
 		events <- agent.NewDeployCommand(local, agent.DeployCommandFailed(displayname, darchive.DeployOption, dopts.DeployOption))
+		return errors.Wrap(agent.ExplainDeployRejection(cause), "deploy failed")
+	}
+
+	interrupted(ctx, finished, qd, displayname)
+
+	return concluded(ctx, finished, result)
+}
+
+// concluded waits for the deploy to finish and reports the summary of the deploy,
+// an interrupted deploy is cancelled instead and fails, see interrupted.
+func concluded(ctx *Context, finished context.Context, result *DeployResult) error {
+	<-finished.Done()
+
+	if ctx.Context.Err() != nil {
+		return result.Cancelled()
+	}
+
+	return report(ctx.Output, result)
+}
+
+// requireQuorum refuses the deploy when the configuration requires quorum and the cluster lacks it.
+func requireQuorum(ctx context.Context, config agent.ConfigClient, conn *grpc.ClientConn) (err error) {
+	var (
+		info *agent.InfoResponse
+	)
+
+	if !config.Deployment.RequireQuorum {
+		return nil
+	}
+
+	if info, err = agent.NewConn(conn).QuorumInfo(ctx); err != nil {
+		return errors.Wrap(err, "unable to determine the quorum of the cluster")
 	}
 
-	return err
+	return agentutil.RequireQuorum(info)
 }
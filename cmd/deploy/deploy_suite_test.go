@@ -0,0 +1,17 @@
+package deploy
+
+import (
+	"io"
+	"log"
+	"testing"
+
+	// the package Context conflicts with the dot import of ginkgo.
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestDeploy(t *testing.T) {
+	log.SetOutput(io.Discard)
+	RegisterFailHandler(ginkgo.Fail)
+	ginkgo.RunSpecs(t, "Deploy Suite")
+}
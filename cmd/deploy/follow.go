@@ -0,0 +1,18 @@
+package deploy
+
+import (
+	"os"
+
+	"github.com/james-lawrence/bw/agent"
+	"github.com/james-lawrence/bw/agentutil"
+)
+
+// follow streams the deploy output of the peers to stderr in the background when enabled.
+// the streams wait for the deploy to start on each peer and end with the deploy.
+func follow(ctx *Context, config agent.ConfigClient, c agent.DeployClient, archive *agent.Archive, peers ...*agent.Peer) {
+	if !config.Deployment.Follow {
+		return
+	}
+
+	go agentutil.FollowLogs(ctx.Context, c, archive.DeploymentID, os.Stderr, peers...)
+}
@@ -0,0 +1,101 @@
+package deploy
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+
+	"github.com/james-lawrence/bw/agent"
+	"github.com/james-lawrence/bw/vcsinfo"
+	"github.com/pkg/errors"
+)
+
+// environment variables provided to the deploy hooks, in addition to the environment
+// of the client and the env of the deployment.
+const (
+	EnvHookEnvironment = "BW_DEPLOY_ENVIRONMENT" // name of the environment being deployed.
+	EnvHookCommit      = "BW_DEPLOY_COMMIT"      // commit being deployed.
+	EnvHookDryRun      = "BW_DEPLOY_DRY_RUN"     // true when the deploy is a dry run, the cluster isn't deployed to.
+	EnvHookOutcome     = "BW_DEPLOY_OUTCOME"     // outcome of the deploy, only provided to the post hook: succeeded, failed, cancelled, or dry-run.
+	EnvHookError       = "BW_DEPLOY_ERROR"       // failure of the deploy, only provided to the post hook.
+)
+
+// outcomes of the deploy provided to the post hook.
+const (
+	HookSucceeded = "succeeded"
+	HookFailed    = "failed"
+	HookCancelled = "cancelled"
+	HookDryRun    = "dry-run" // the dry run completed without deploying.
+)
+
+// prehook runs the pre hook of the deployment, noop when the hook isn't configured.
+func prehook(ctx context.Context, config agent.ConfigClient) error {
+	if len(config.Deployment.PreHook) == 0 {
+		return nil
+	}
+
+	return errors.Wrap(hook(ctx, config, config.Deployment.PreHook), "pre-hook failed")
+}
+
+// posthook runs the post hook of the deployment with the outcome of the deploy, failures
+// are logged without altering the outcome. the hook runs to completion even when the
+// deploy was interrupted.
+func posthook(ctx context.Context, config agent.ConfigClient, cause error) {
+	if len(config.Deployment.PostHook) == 0 {
+		return
+	}
+
+	outcome := HookSucceeded
+	switch {
+	case ctx.Err() != nil:
+		outcome = HookCancelled
+	case cause != nil:
+		outcome = HookFailed
+	case config.Deployment.DryRun:
+		outcome = HookDryRun
+	}
+
+	environ := []string{EnvHookOutcome + "=" + outcome}
+	if cause != nil {
+		environ = append(environ, EnvHookError+"="+cause.Error())
+	}
+
+	if err := hook(context.Background(), config, config.Deployment.PostHook, environ...); err != nil {
+		log.Println("warning: post-hook failed", err)
+	}
+}
+
+// hook runs the command from the work directory of the deploy, the output is
+// written to stderr to keep stdout free for the deploy summary.
+func hook(ctx context.Context, config agent.ConfigClient, argv []string, environ ...string) error {
+	cmd := exec.CommandContext(ctx, argv[0], argv[1:]...)
+	cmd.Dir = config.WorkDir()
+	cmd.Env = append(hookEnviron(config), environ...)
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+
+	return cmd.Run()
+}
+
+func hookEnviron(config agent.ConfigClient) []string {
+	keys := make([]string, 0, len(config.Deployment.Env))
+	for k := range config.Deployment.Env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	environ := os.Environ()
+	for _, k := range keys {
+		environ = append(environ, k+"="+config.Deployment.Env[k])
+	}
+
+	return append(
+		environ,
+		EnvHookEnvironment+"="+config.EnvironmentName(),
+		EnvHookCommit+"="+vcsinfo.Commitish(config.WorkDir(), config.Deployment.CommitRef),
+		EnvHookDryRun+"="+strconv.FormatBool(config.Deployment.DryRun),
+	)
+}
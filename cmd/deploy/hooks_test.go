@@ -0,0 +1,97 @@
+package deploy
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/james-lawrence/bw/agent"
+	"github.com/james-lawrence/bw/internal/errorsx"
+	"github.com/james-lawrence/bw/internal/testingx"
+)
+
+var _ = ginkgo.Describe("hooks", func() {
+	var (
+		root string
+	)
+
+	// records the environment of the hook within the work directory.
+	record := func(names ...string) []string {
+		script := ""
+		for _, n := range names {
+			script += "echo \"" + n + "=${" + n + "}\" >> hook.env;"
+		}
+		return []string{"sh", "-c", script}
+	}
+
+	recorded := func() string {
+		encoded, err := os.ReadFile(filepath.Join(root, "hook.env"))
+		Expect(err).To(Succeed())
+		return string(encoded)
+	}
+
+	config := func(options ...agent.ConfigClientOption) agent.ConfigClient {
+		return agent.NewConfigClient(
+			agent.DefaultConfigClient(agent.CCOptionDeployRoot(root), agent.CCOptionEnvironmentName("production"), agent.CCOptionEnv(map[string]string{"FOO": "bar"})),
+			options...,
+		)
+	}
+
+	ginkgo.BeforeEach(func() {
+		root = testingx.TempDir()
+	})
+
+	ginkgo.It("should ignore unconfigured hooks", func() {
+		Expect(prehook(context.Background(), config())).To(Succeed())
+		posthook(context.Background(), config(), nil)
+	})
+
+	ginkgo.It("should run the pre hook with the environment of the deploy", func() {
+		Expect(prehook(context.Background(), config(agent.CCOptionPreHook(record("FOO", EnvHookEnvironment, EnvHookOutcome, EnvHookDryRun)...)))).To(Succeed())
+		Expect(recorded()).To(ContainSubstring("FOO=bar\n"))
+		Expect(recorded()).To(ContainSubstring(EnvHookEnvironment + "=production\n"))
+		Expect(recorded()).To(ContainSubstring(EnvHookOutcome + "=\n"))
+		Expect(recorded()).To(ContainSubstring(EnvHookDryRun + "=false\n"))
+	})
+
+	ginkgo.It("should abort when the pre hook fails", func() {
+		err := prehook(context.Background(), config(agent.CCOptionPreHook("sh", "-c", "exit 1")))
+		Expect(err).To(MatchError(ContainSubstring("pre-hook failed")))
+	})
+
+	ginkgo.It("should provide the outcome to the post hook", func() {
+		c := config(agent.CCOptionPostHook(record(EnvHookOutcome, EnvHookError)...))
+
+		posthook(context.Background(), c, nil)
+		Expect(recorded()).To(ContainSubstring(EnvHookOutcome + "=" + HookSucceeded + "\n"))
+
+		posthook(context.Background(), c, errorsx.String("boom"))
+		Expect(recorded()).To(ContainSubstring(EnvHookOutcome + "=" + HookFailed + "\n" + EnvHookError + "=boom\n"))
+
+		ctx, done := context.WithCancel(context.Background())
+		done()
+		posthook(ctx, c, nil)
+		Expect(recorded()).To(ContainSubstring(EnvHookOutcome + "=" + HookCancelled + "\n"))
+	})
+
+	ginkgo.It("should mark the outcome of a dry run", func() {
+		c := config(agent.CCOptionDryRun(true), agent.CCOptionPreHook(record(EnvHookDryRun)...), agent.CCOptionPostHook(record(EnvHookOutcome, EnvHookDryRun)...))
+
+		Expect(prehook(context.Background(), c)).To(Succeed())
+		Expect(recorded()).To(Equal(EnvHookDryRun + "=true\n"))
+
+		posthook(context.Background(), c, nil)
+		Expect(recorded()).To(HaveSuffix(EnvHookOutcome + "=" + HookDryRun + "\n" + EnvHookDryRun + "=true\n"))
+
+		posthook(context.Background(), c, errorsx.String("boom"))
+		Expect(recorded()).To(HaveSuffix(EnvHookOutcome + "=" + HookFailed + "\n" + EnvHookDryRun + "=true\n"))
+	})
+
+	ginkgo.It("should only warn when the post hook fails", func() {
+		posthook(context.Background(), config(agent.CCOptionPostHook("sh", "-c", "exit 1")), nil)
+		posthook(context.Background(), config(agent.CCOptionPostHook(filepath.Join(root, "missing"))), nil)
+	})
+})
@@ -0,0 +1,56 @@
+package deploy
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/james-lawrence/bw/agent"
+	"github.com/james-lawrence/bw/agent/dialers"
+	"github.com/james-lawrence/bw/internal/contextx"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+)
+
+// interrupted cancels the deploy when the client is interrupted before the deploy finishes, e.g.) ctrl-c.
+// the cleanup waits for the cluster to acknowledge the cancellation, completed nodes are left intact
+// and only the nodes currently deploying are halted.
+func interrupted(ctx *Context, finished context.Context, qd dialers.Quorum, displayname string) {
+	contextx.WaitGroupAdd(ctx.Context, 1)
+	go func() {
+		defer contextx.WaitGroupDone(ctx.Context)
+
+		<-finished.Done()
+
+		// the deploy finished without being interrupted.
+		if ctx.Context.Err() == nil {
+			return
+		}
+
+		log.Println("interrupted, cancelling the deploy")
+		if err := cancelDeploy(qd, displayname, 30*time.Second); err != nil {
+			log.Println("failed to cancel the deploy", err)
+			return
+		}
+
+		log.Println("deploy cancelled")
+	}()
+}
+
+// cancelDeploy the active deploy, the connection used by the deploy is closed
+// once interrupted so a new connection is established.
+func cancelDeploy(qd dialers.Quorum, displayname string, timeout time.Duration) (err error) {
+	var (
+		conn *grpc.ClientConn
+	)
+
+	ctx, done := context.WithTimeout(context.Background(), timeout)
+	defer done()
+
+	if conn, err = qd.DialContext(ctx); err != nil {
+		return errors.Wrap(err, "unable to connect to cluster")
+	}
+	defer conn.Close()
+
+	return agent.NewDeployConn(conn).Cancel(ctx, &agent.CancelRequest{Initiator: displayname})
+}
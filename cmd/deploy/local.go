@@ -35,7 +35,7 @@ func Locally(ctx *Context, debug bool) (err error) {
 
 	displayname := vcsinfo.CurrentUserDisplay(config.WorkDir())
 
-	if err = os.WriteFile(filepath.Join(config.WorkDir(), config.Deployment.DataDir, bw.EnvFile), []byte(config.Environment), 0600); err != nil {
+	if err = os.WriteFile(filepath.Join(config.WorkDir(), config.Deployment.DataDir, bw.EnvFile), []byte(config.Environ()), 0600); err != nil {
 		return errors.WithStack(err)
 	}
 
@@ -106,6 +106,7 @@ func Locally(ctx *Context, debug bool) (err error) {
 		&agent.DeployOptions{
 			Timeout:   int64(config.Deployment.Timeout),
 			Heartbeat: int64(ctx.Heartbeat),
+			Executor:  config.Deployment.Executor,
 		},
 		&agent.Archive{
 			Location: dst.Name(),
@@ -118,7 +119,7 @@ func Locally(ctx *Context, debug bool) (err error) {
 		return errors.Wrap(err, "failed to create deployment context")
 	}
 
-	deploy := deployment.NewDirective(
+	deploy := deployment.NewExecutors(
 		deployment.DirectiveOptionShellContext(sctx),
 	)
 	deploy.Deploy(dctx)
@@ -0,0 +1,130 @@
+package deploy
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/memberlist"
+	"github.com/pkg/errors"
+
+	"github.com/james-lawrence/bw"
+	"github.com/james-lawrence/bw/agent"
+	"github.com/james-lawrence/bw/deployment"
+)
+
+// plan the batches of peers deployed to simultaneously, peers are partitioned
+// by their labels the same way the agents partition them during the deploy.
+func plan(dopts *agent.DeployOptions, peers ...*agent.Peer) (batches [][]*agent.Peer) {
+	return agent.NewLabelPartitioner(bw.ConstantPartitioner(dopts.Concurrency), dopts.ConcurrencyByLabel).Partitions(peers...)
+}
+
+// selected the peers matching the include patterns of the deployment that aren't excluded,
+// include is applied before exclude.
+func selected(d agent.Deployment, peers ...*agent.Peer) []*agent.Peer {
+	if len(d.IncludePeers) > 0 {
+		peers = deployment.ApplyFilter(deployment.Glob(d.IncludePeers...), peers...)
+	}
+
+	if len(d.ExcludePeers) > 0 {
+		peers = deployment.ApplyFilter(deployment.Not(deployment.Glob(d.ExcludePeers...)), peers...)
+	}
+
+	return peers
+}
+
+// dryrun reports the plan of the deploy instead of initiating it.
+func dryrun(events chan<- *agent.Message, local *agent.Peer, by string, dopts *agent.DeployOptions, archive *agent.Archive, peers ...*agent.Peer) {
+	batches := plan(dopts, peers...)
+	events <- agent.LogEvent(local, fmt.Sprintf("dry run: by(%s) commit(%s) deployID(%s) nodes(%d) partitions(%d)", by, archive.Commit, bw.RandomID(archive.DeploymentID), len(peers), len(batches)))
+	for idx, batch := range batches {
+		names := make([]string, 0, len(batch))
+		for _, p := range batch {
+			names = append(names, p.Name)
+		}
+
+		events <- agent.LogEvent(local, fmt.Sprintf("dry run: partition(%d) %s", idx+1, strings.Join(names, ", ")))
+	}
+	events <- agent.LogEvent(local, "dry run completed, the deploy was not initiated")
+	// the deploy was never initiated, the client has to complete it.
+	events <- agent.NewDeployCommand(local, agent.DeployCommandDone(by, archive.DeployOption, dopts.DeployOption))
+}
+
+// planned the peers the deploy targets, when no peers are specified
+// the agents deploy to the entire cluster.
+func planned(c interface{ Members() []*memberlist.Node }, peers []*agent.Peer) []*agent.Peer {
+	if len(peers) > 0 {
+		return peers
+	}
+
+	return agent.NodesToPeers(c.Members()...)
+}
+
+// Plan of a deploy written for archival, see CCOptionWritePlan.
+type Plan struct {
+	By                 string             `json:"by"`
+	Environment        string             `json:"environment"`
+	Commit             string             `json:"commit"`
+	DeploymentID       string             `json:"deploymentID"`
+	Location           string             `json:"location"`
+	Concurrency        int64              `json:"concurrency"`
+	ConcurrencyByLabel map[string]float64 `json:"concurrencyByLabel,omitempty"`
+	Executor           string             `json:"executor,omitempty"`
+	Timeout            time.Duration      `json:"timeout"`
+	DryRun             bool               `json:"dryRun"`
+	Simulate           bool               `json:"simulate"`
+	Nodes              []string           `json:"nodes"`      // nodes targeted by the deploy.
+	Partitions         [][]string         `json:"partitions"` // nodes deployed to simultaneously, in the order they're deployed.
+}
+
+// newPlan of the deploy to the peers.
+func newPlan(config agent.ConfigClient, by string, dopts *agent.DeployOptions, archive *agent.Archive, peers ...*agent.Peer) Plan {
+	names := func(peers ...*agent.Peer) []string {
+		r := make([]string, 0, len(peers))
+		for _, p := range peers {
+			r = append(r, p.Name)
+		}
+		return r
+	}
+
+	p := Plan{
+		By:                 by,
+		Environment:        config.EnvironmentName(),
+		Commit:             archive.Commit,
+		DeploymentID:       bw.RandomID(archive.DeploymentID).String(),
+		Location:           archive.Location,
+		Concurrency:        dopts.Concurrency,
+		ConcurrencyByLabel: dopts.ConcurrencyByLabel,
+		Executor:           dopts.Executor,
+		Timeout:            time.Duration(dopts.Timeout),
+		DryRun:             config.Deployment.DryRun,
+		Simulate:           dopts.Simulate,
+		Nodes:              names(peers...),
+		Partitions:         [][]string{},
+	}
+
+	for _, batch := range plan(dopts, peers...) {
+		p.Partitions = append(p.Partitions, names(batch...))
+	}
+
+	return p
+}
+
+// writePlan of the deploy to the configured file, noop when the file isn't configured.
+func writePlan(config agent.ConfigClient, by string, dopts *agent.DeployOptions, archive *agent.Archive, peers ...*agent.Peer) (err error) {
+	var (
+		encoded []byte
+	)
+
+	if config.Deployment.WritePlan == "" {
+		return nil
+	}
+
+	if encoded, err = json.MarshalIndent(newPlan(config, by, dopts, archive, peers...), "", "  "); err != nil {
+		return errors.Wrap(err, "unable to encode the deploy plan")
+	}
+
+	return errors.Wrapf(os.WriteFile(config.Deployment.WritePlan, encoded, 0600), "unable to write the deploy plan: %s", config.Deployment.WritePlan)
+}
@@ -0,0 +1,115 @@
+package deploy
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/james-lawrence/bw"
+	"github.com/james-lawrence/bw/agent"
+	"github.com/james-lawrence/bw/internal/testingx"
+)
+
+var _ = ginkgo.Describe("writePlan", func() {
+	var (
+		dopts = &agent.DeployOptions{
+			Concurrency:        2,
+			Timeout:            int64(time.Minute),
+			ConcurrencyByLabel: map[string]float64{"canary": 1},
+		}
+		archive = &agent.Archive{
+			Commit:       "8a1f3c2",
+			DeploymentID: bw.MustGenerateID(),
+			Location:     "bw.agent1/archive.tar.gz",
+		}
+		peers = []*agent.Peer{
+			agent.NewPeer("node1"),
+			agent.NewPeer("node2", agent.PeerOptionLabels("canary")),
+			agent.NewPeer("node3"),
+			agent.NewPeer("node4", agent.PeerOptionLabels("canary")),
+			agent.NewPeer("node5"),
+		}
+	)
+
+	read := func(path string) (p Plan) {
+		encoded, err := os.ReadFile(path)
+		Expect(err).To(Succeed())
+		Expect(json.Unmarshal(encoded, &p)).To(Succeed())
+		return p
+	}
+
+	ginkgo.It("should write the executed plan", func() {
+		path := filepath.Join(testingx.TempDir(), "plan.json")
+		config := agent.NewConfigClient(agent.DefaultConfigClient(agent.CCOptionEnvironmentName("production")), agent.CCOptionWritePlan(path))
+
+		Expect(writePlan(config, "user", dopts, archive, peers...)).To(Succeed())
+
+		executed := [][]string{}
+		for _, batch := range plan(dopts, peers...) {
+			names := []string{}
+			for _, p := range batch {
+				names = append(names, p.Name)
+			}
+			executed = append(executed, names)
+		}
+
+		p := read(path)
+		Expect(p.Partitions).To(Equal(executed))
+		Expect(p.Partitions).To(Equal([][]string{{"node1", "node3"}, {"node5"}, {"node2", "node4"}}))
+		Expect(p.Nodes).To(Equal([]string{"node1", "node2", "node3", "node4", "node5"}))
+		Expect(p.Commit).To(Equal("8a1f3c2"))
+		Expect(p.DeploymentID).To(Equal(bw.RandomID(archive.DeploymentID).String()))
+		Expect(p.Concurrency).To(Equal(int64(2)))
+		Expect(p.ConcurrencyByLabel).To(Equal(map[string]float64{"canary": 1}))
+		Expect(p.Environment).To(Equal("production"))
+		Expect(p.By).To(Equal("user"))
+		Expect(p.Timeout).To(Equal(time.Minute))
+	})
+
+	ginkgo.It("should not write a plan when disabled", func() {
+		dir := testingx.TempDir()
+		Expect(writePlan(agent.DefaultConfigClient(), "user", dopts, archive, peers...)).To(Succeed())
+
+		entries, err := os.ReadDir(dir)
+		Expect(err).To(Succeed())
+		Expect(entries).To(BeEmpty())
+	})
+
+	ginkgo.It("should fail when the plan can't be written", func() {
+		config := agent.NewConfigClient(agent.DefaultConfigClient(), agent.CCOptionWritePlan(filepath.Join(testingx.TempDir(), "missing", "plan.json")))
+		Expect(writePlan(config, "user", dopts, archive, peers...)).ToNot(Succeed())
+	})
+})
+
+var _ = ginkgo.Describe("selected", func() {
+	peers := []*agent.Peer{
+		agent.NewPeer("canary-1"),
+		agent.NewPeer("canary-2"),
+		agent.NewPeer("web-1"),
+		agent.NewPeer("web-2"),
+		agent.NewPeer("worker-1"),
+	}
+
+	names := func(config agent.ConfigClient) (results []string) {
+		for _, p := range selected(config.Deployment, peers...) {
+			results = append(results, p.Name)
+		}
+		return results
+	}
+
+	ginkgo.DescribeTable("should include then exclude the peers by name", func(include, exclude, expected []string) {
+		config := agent.NewConfigClient(agent.DefaultConfigClient(), agent.CCOptionIncludePeers(include...), agent.CCOptionExcludePeers(exclude...))
+		Expect(names(config)).To(Equal(expected))
+	},
+		ginkgo.Entry("no patterns", nil, nil, []string{"canary-1", "canary-2", "web-1", "web-2", "worker-1"}),
+		ginkgo.Entry("include", []string{"web-*"}, nil, []string{"web-1", "web-2"}),
+		ginkgo.Entry("exclude", nil, []string{"canary-*"}, []string{"web-1", "web-2", "worker-1"}),
+		ginkgo.Entry("overlapping includes", []string{"w*", "web-?"}, nil, []string{"web-1", "web-2", "worker-1"}),
+		ginkgo.Entry("exclude overlapping the include", []string{"w*"}, []string{"web-*", "*-2"}, []string{"worker-1"}),
+		ginkgo.Entry("exclude every included peer", []string{"canary-*"}, []string{"*"}, nil),
+	)
+})
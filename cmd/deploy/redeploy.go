@@ -19,6 +19,7 @@ import (
 	"github.com/james-lawrence/bw/internal/envx"
 	"github.com/james-lawrence/bw/internal/errorsx"
 	"github.com/james-lawrence/bw/internal/grpcx"
+	"github.com/james-lawrence/bw/internal/tracex"
 	"github.com/james-lawrence/bw/notary"
 	"github.com/james-lawrence/bw/ux"
 	"github.com/james-lawrence/bw/vcsinfo"
@@ -27,9 +28,18 @@ import (
 	"google.golang.org/grpc"
 )
 
+// Redeploy the archive of the specified deployment.
 func Redeploy(ctx *Context, deploymentID string) error {
+	return redeploy(ctx, deploymentID)
+}
+
+// Rollback redeploy a previously recorded archive, see agent.CCOptionRollback and agent.CCOptionRollbackTo.
+func Rollback(ctx *Context, option agent.ConfigClientOption) error {
+	return redeploy(ctx, "", option)
+}
+
+func redeploy(ctx *Context, deploymentID string, options ...agent.ConfigClientOption) (err error) {
 	var (
-		err     error
 		conn    *grpc.ClientConn
 		d       dialers.Defaults
 		client  agent.DeployClient
@@ -42,10 +52,35 @@ func Redeploy(ctx *Context, deploymentID string) error {
 	)
 
 	log.Println("pid", os.Getpid())
-	if config, err = commandutils.LoadConfiguration(ctx.Environment, agent.CCOptionInsecure(ctx.Insecure)); err != nil {
+	if config, err = commandutils.LoadConfiguration(ctx.Environment, append([]agent.ConfigClientOption{agent.CCOptionInsecure(ctx.Insecure), agent.CCOptionSimulate(ctx.Simulate)}, options...)...); err != nil {
 		return err
 	}
 
+	// the command line takes precedence over the environment's configuration.
+	if ctx.Progress != "" {
+		config = agent.NewConfigClient(config, agent.CCOptionProgressFormat(ctx.Progress))
+	}
+
+	if ctx.Zone != "" {
+		config = agent.NewConfigClient(config, agent.CCOptionZone(ctx.Zone))
+	}
+
+	if ctx.Timeout > 0 {
+		config = agent.NewConfigClient(config, agent.CCOptionTimeout(ctx.Timeout))
+	}
+
+	if ctx.DryRun {
+		config = agent.NewConfigClient(config, agent.CCOptionDryRun(true))
+	}
+
+	if ctx.Follow {
+		config = agent.NewConfigClient(config, agent.CCOptionFollow(true))
+	}
+
+	if ctx.WritePlan != "" {
+		config = agent.NewConfigClient(config, agent.CCOptionWritePlan(ctx.WritePlan))
+	}
+
 	displayname := vcsinfo.CurrentUserDisplay(config.WorkDir())
 
 	if len(config.Deployment.Prompt) > 0 {
@@ -64,6 +99,14 @@ func Redeploy(ctx *Context, deploymentID string) error {
 		return err
 	}
 
+	defer func() {
+		posthook(ctx.Context, config, err)
+	}()
+
+	if err = prehook(ctx.Context, config); err != nil {
+		return err
+	}
+
 	events := make(chan *agent.Message, 100)
 	local := commandutils.NewClientPeer(
 		agent.PeerOptionName("local"),
@@ -82,7 +125,7 @@ func Redeploy(ctx *Context, deploymentID string) error {
 		return err
 	}
 
-	qd := dialers.NewQuorum(c, d.Defaults()...)
+	qd := dialers.NewQuorum(c, d.Defaults()...).PreferZone(config.Zone)
 
 	if conn, err = qd.DialContext(ctx.Context); err != nil {
 		return err
@@ -95,23 +138,39 @@ func Redeploy(ctx *Context, deploymentID string) error {
 
 	client = agent.NewDeployConn(conn)
 
-	termui.NewFromClientConfig(
+	result := NewDeployResult()
+	finished := termui.NewFromClientConfig(
 		ctx.Context, config, qd, local, events,
 		ux.OptionHeartbeat(ctx.Heartbeat),
 		ux.OptionDebug(ctx.Verbose),
+		ux.OptionObserver(result.Record),
 	)
 
 	events <- agent.LogEvent(local, "connected to cluster")
+
+	if err = requireQuorum(ctx.Context, config, conn); err != nil {
+		events <- agent.LogError(local, err)
+		events <- agent.LogEvent(local, "deployment failed")
+		return err
+	}
+
 	go func() {
 		<-ctx.Context.Done()
-		if err = client.Close(); err != nil {
+		if err := client.Close(); err != nil {
 			log.Println("failed to close client", err)
 		}
 	}()
 
 	cx := cluster.New(local, c)
-	if located, err = agentutil.LocateDeployment(cx, qd, agentutil.FilterDeployID(deploymentID)); err != nil {
-		events <- agent.LogError(local, errors.Wrap(err, "archive retrieval failed"))
+	if config.Deployment.Rollback.Enabled {
+		located, err = agentutil.LocateRollback(cx, qd, config.Deployment.Rollback.Ref)
+	} else {
+		located, err = agentutil.LocateDeployment(cx, qd, agentutil.FilterDeployID(deploymentID))
+	}
+
+	if err != nil {
+		err = errors.Wrap(err, "archive retrieval failed")
+		events <- agent.LogError(local, err)
 		events <- agent.LogEvent(local, "deployment failed")
 		return err
 	}
@@ -136,13 +195,22 @@ func Redeploy(ctx *Context, deploymentID string) error {
 		peers = cx.Peers()
 	}
 
-	peers = deployment.ApplyFilter(ctx.Filter, peers...)
+	peers = selected(config.Deployment, deployment.ApplyFilter(ctx.Filter, peers...)...)
 	dopts := agent.DeployOptions{
-		Concurrency:       max,
-		Timeout:           int64(config.Deployment.Timeout),
-		Heartbeat:         int64(ctx.Heartbeat),
-		IgnoreFailures:    ctx.Lenient,
-		SilenceDeployLogs: ctx.Silent,
+		Concurrency:        max,
+		Timeout:            int64(config.Deployment.Timeout),
+		Heartbeat:          int64(ctx.Heartbeat),
+		IgnoreFailures:     ctx.Lenient,
+		SilenceDeployLogs:  ctx.Silent,
+		Executor:           config.Deployment.Executor,
+		Simulate:           config.Deployment.Simulate,
+		MaxTotal:           int64(config.Deployment.MaxTotal),
+		ConcurrencyByLabel: config.Deployment.ConcurrencyByLabel,
+		PerNodeConcurrency: int64(config.Deployment.PerNodeConcurrency),
+		Tracing:            config.Deployment.Tracing,
+		RetryFailedOnce:    config.Deployment.RetryFailedOnce,
+		Retries:            int64(config.Deployment.Retries),
+		RetryBackoff:       int64(config.Deployment.RetryBackoff),
 	}
 
 	if len(peers) == 0 && !ctx.AllowEmpty {
@@ -151,10 +219,29 @@ func Redeploy(ctx *Context, deploymentID string) error {
 		return cause
 	}
 
+	if err = writePlan(config, displayname, &dopts, archive, planned(cx, peers)...); err != nil {
+		events <- agent.LogError(local, err)
+		events <- agent.LogEvent(local, "deployment failed")
+		return err
+	}
+
+	if config.Deployment.DryRun {
+		dryrun(events, local, displayname, &dopts, archive, planned(cx, peers)...)
+		return nil
+	}
+
 	events <- agent.LogEvent(local, fmt.Sprintf("initiating deploy: concurrency(%d), deployID(%s)", max, bw.RandomID(archive.DeploymentID)))
-	if cause := client.RemoteDeploy(ctx.Context, displayname, &dopts, archive, peers...); cause != nil {
-		events <- agent.LogEvent(local, fmt.Sprintln("deployment failed", cause))
+	result.Initiated(archive, peers...)
+	follow(ctx, config, client, archive, peers...)
+	tctx, span := tracex.StartRoot(ctx.Context, "bw.deploy", dopts.Tracing)
+	defer span.End()
+
+	if cause := client.RemoteDeploy(tctx, displayname, &dopts, archive, peers...); cause != nil {
+		events <- agent.LogEvent(local, fmt.Sprintln("deployment failed", agent.ExplainDeployRejection(cause)))
+		return errors.Wrap(agent.ExplainDeployRejection(cause), "deploy failed")
 	}
 
-	return err
+	interrupted(ctx, finished, qd, displayname)
+
+	return concluded(ctx, finished, result)
 }
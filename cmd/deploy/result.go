@@ -0,0 +1,254 @@
+package deploy
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/james-lawrence/bw"
+	"github.com/james-lawrence/bw/agent"
+	"github.com/pkg/errors"
+)
+
+// supported output formats of the deploy summary.
+const (
+	OutputHuman = "human"
+	OutputJSON  = "json"
+)
+
+// exit codes of a deploy.
+const (
+	ExitSucceeded = 0 // every node deployed successfully.
+	ExitFailed    = 1 // the deploy failed or was cancelled, or a node failed to deploy.
+	// the deploy never concluded, the client lost contact with the quorum mid-deploy.
+	ExitQuorumLost = 2
+)
+
+// PeerStatus the outcome of the deploy for a single node.
+type PeerStatus string
+
+// outcomes of the deploy for a node.
+const (
+	PeerSucceeded PeerStatus = "succeeded"
+	PeerFailed    PeerStatus = "failed"
+	PeerSkipped   PeerStatus = "skipped" // the node never reported the outcome of the deploy.
+)
+
+// PeerResult the outcome of the deploy for a single node.
+type PeerResult struct {
+	Name      string     `json:"name"`
+	IP        string     `json:"ip"`
+	Status    PeerStatus `json:"status"`
+	Error     string     `json:"error,omitempty"`
+	Recovered bool       `json:"recovered,omitempty"` // the node failed then succeeded once retried.
+}
+
+// NewDeployResult the result of a deploy, events are ignored until the deploy is initiated.
+func NewDeployResult() *DeployResult {
+	return &DeployResult{
+		index: make(map[string]int),
+	}
+}
+
+// DeployResult summary of a deploy, aggregated from the events of the deploy. safe for concurrent use.
+type DeployResult struct {
+	m         sync.Mutex
+	archive   []byte
+	index     map[string]int
+	peers     []PeerResult
+	concluded bool
+	failed    bool
+}
+
+// Initiated the deploy of the archive to the peers, the peers are skipped
+// until they report the outcome of the deploy.
+func (t *DeployResult) Initiated(archive *agent.Archive, peers ...*agent.Peer) {
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	t.archive = archive.DeploymentID
+	for _, p := range peers {
+		t.index[p.Name] = len(t.peers)
+		t.peers = append(t.peers, PeerResult{Name: p.Name, IP: p.Ip, Status: PeerSkipped})
+	}
+}
+
+// Record the event into the result, events of other deploys are ignored.
+func (t *DeployResult) Record(m *agent.Message) {
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	if t.archive == nil {
+		return
+	}
+
+	switch evt := m.Event.(type) {
+	case *agent.Message_DeployCommand:
+		if evt.DeployCommand.Archive != nil && len(evt.DeployCommand.Archive.DeploymentID) > 0 && !bytes.Equal(evt.DeployCommand.Archive.DeploymentID, t.archive) {
+			return
+		}
+
+		switch evt.DeployCommand.Command {
+		case agent.DeployCommand_Done:
+			t.concluded = true
+		case agent.DeployCommand_Failed, agent.DeployCommand_Cancel:
+			t.concluded, t.failed = true, true
+		}
+	case *agent.Message_Deploy:
+		if m.Peer == nil || evt.Deploy.Archive == nil || !bytes.Equal(evt.Deploy.Archive.DeploymentID, t.archive) {
+			return
+		}
+
+		idx, ok := t.index[m.Peer.Name]
+		if !ok {
+			return
+		}
+
+		// a node retried after failing reports the latest outcome.
+		switch evt.Deploy.Stage {
+		case agent.Deploy_Completed:
+			if t.peers[idx].Status == PeerFailed {
+				t.peers[idx].Recovered = true
+			}
+			t.peers[idx].Status, t.peers[idx].Error = PeerSucceeded, ""
+		case agent.Deploy_Failed:
+			t.peers[idx].Status, t.peers[idx].Error = PeerFailed, evt.Deploy.Error
+		}
+	}
+}
+
+// Peers the outcome of the deploy for each node ordered by name.
+func (t *DeployResult) Peers() []PeerResult {
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	dup := append([]PeerResult(nil), t.peers...)
+	sort.SliceStable(dup, func(i, j int) bool { return dup[i].Name < dup[j].Name })
+
+	return dup
+}
+
+// ExitCode of the deploy, see ExitSucceeded, ExitFailed, and ExitQuorumLost.
+func (t *DeployResult) ExitCode() int {
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	if !t.concluded {
+		return ExitQuorumLost
+	}
+
+	if t.failed {
+		return ExitFailed
+	}
+
+	for _, p := range t.peers {
+		if p.Status == PeerFailed {
+			return ExitFailed
+		}
+	}
+
+	return ExitSucceeded
+}
+
+// Err describes the failure of the deploy, nil when every node succeeded.
+func (t *DeployResult) Err() error {
+	if t.ExitCode() == ExitSucceeded {
+		return nil
+	}
+
+	return resultError{result: t}
+}
+
+func (t *DeployResult) String() string {
+	var succeeded, recovered, failed, skipped int
+
+	for _, p := range t.Peers() {
+		if p.Recovered {
+			recovered++
+		}
+
+		switch p.Status {
+		case PeerSucceeded:
+			succeeded++
+		case PeerFailed:
+			failed++
+		default:
+			skipped++
+		}
+	}
+
+	return fmt.Sprintf("deploy %s: succeeded(%d) recovered(%d) failed(%d) skipped(%d)", bw.RandomID(t.archive), succeeded, recovered, failed, skipped)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (t *DeployResult) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		DeploymentID string       `json:"deploymentID"`
+		ExitCode     int          `json:"exitCode"`
+		Peers        []PeerResult `json:"peers"`
+	}{
+		DeploymentID: bw.RandomID(t.archive).String(),
+		ExitCode:     t.ExitCode(),
+		Peers:        t.Peers(),
+	})
+}
+
+// Cancelled describes the deploy interrupted by the client, see ExitFailed.
+func (t *DeployResult) Cancelled() error {
+	return resultError{result: t, cancelled: true}
+}
+
+// resultError a deploy that didn't succeed, carries the exit code for the command line.
+type resultError struct {
+	result    *DeployResult
+	cancelled bool
+}
+
+func (t resultError) Error() string {
+	if t.cancelled {
+		return fmt.Sprintf("%s, deploy cancelled", t.result)
+	}
+
+	switch t.result.ExitCode() {
+	case ExitQuorumLost:
+		return fmt.Sprintf("%s, lost contact with the quorum before the deploy concluded", t.result)
+	default:
+		return fmt.Sprintf("%s, deploy failed", t.result)
+	}
+}
+
+func (t resultError) ExitCode() int {
+	if t.cancelled {
+		return ExitFailed
+	}
+
+	return t.result.ExitCode()
+}
+
+// report the summary of the deploy in the output format, the returned error carries the exit code.
+func report(format string, result *DeployResult) error {
+	return reportTo(os.Stdout, format, result)
+}
+
+func reportTo(dst io.Writer, format string, result *DeployResult) error {
+	switch format {
+	case OutputJSON:
+		encoded, err := json.Marshal(result)
+		if err != nil {
+			return errors.Wrap(err, "unable to encode the deploy summary")
+		}
+
+		if _, err = dst.Write(append(encoded, '\n')); err != nil {
+			return errors.Wrap(err, "unable to write the deploy summary")
+		}
+	default:
+		log.Println(result)
+	}
+
+	return result.Err()
+}
@@ -0,0 +1,148 @@
+package deploy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+
+	"github.com/james-lawrence/bw"
+	"github.com/james-lawrence/bw/agent"
+)
+
+var _ = ginkgo.Describe("DeployResult", func() {
+	var (
+		local   = agent.NewPeer("local")
+		node1   = agent.NewPeer("node1")
+		node2   = agent.NewPeer("node2")
+		node3   = agent.NewPeer("node3")
+		archive = &agent.Archive{DeploymentID: bw.MustGenerateID()}
+		dopts   = &agent.DeployOptions{}
+	)
+
+	completed := func(p *agent.Peer, a *agent.Archive) *agent.Message {
+		return agent.DeployEvent(p, &agent.Deploy{Stage: agent.Deploy_Completed, Archive: a})
+	}
+
+	failed := func(p *agent.Peer, a *agent.Archive) *agent.Message {
+		return agent.DeployEventFailed(p, "", dopts, a, errors.New("boom"))
+	}
+
+	record := func(messages ...*agent.Message) *DeployResult {
+		r := NewDeployResult()
+		r.Initiated(archive, node1, node2, node3)
+		for _, m := range messages {
+			r.Record(m)
+		}
+		return r
+	}
+
+	status := func(r *DeployResult) (statuses []PeerStatus) {
+		for _, p := range r.Peers() {
+			statuses = append(statuses, p.Status)
+		}
+		return statuses
+	}
+
+	ginkgo.It("should succeed when every node succeeded", func() {
+		r := record(
+			completed(node1, archive),
+			completed(node2, archive),
+			completed(node3, archive),
+			agent.NewDeployCommand(local, agent.DeployCommandDone("", archive.DeployOption)),
+		)
+		Expect(r.ExitCode()).To(Equal(ExitSucceeded))
+		Expect(r.Err()).To(Succeed())
+	})
+
+	ginkgo.It("should report each node that failed or never reported", func() {
+		r := record(
+			completed(node1, archive),
+			failed(node2, archive),
+			agent.NewDeployCommand(local, agent.DeployCommandFailed("", archive.DeployOption)),
+		)
+		Expect(status(r)).To(Equal([]PeerStatus{PeerSucceeded, PeerFailed, PeerSkipped}))
+		Expect(r.Peers()[1].Error).To(Equal("boom"))
+		Expect(r.ExitCode()).To(Equal(ExitFailed))
+	})
+
+	ginkgo.It("should fail when a node failed while ignoring failures", func() {
+		r := record(
+			failed(node1, archive),
+			completed(node2, archive),
+			completed(node3, archive),
+			agent.NewDeployCommand(local, agent.DeployCommandDone("", archive.DeployOption)),
+		)
+		Expect(r.ExitCode()).To(Equal(ExitFailed))
+	})
+
+	ginkgo.It("should use the latest outcome of a retried node", func() {
+		r := record(
+			failed(node1, archive),
+			completed(node1, archive),
+			completed(node2, archive),
+			completed(node3, archive),
+			agent.NewDeployCommand(local, agent.DeployCommandDone("", archive.DeployOption)),
+		)
+		Expect(r.ExitCode()).To(Equal(ExitSucceeded))
+		Expect(r.Peers()[0].Recovered).To(BeTrue())
+		Expect(r.Peers()[1].Recovered).To(BeFalse())
+		Expect(r.String()).To(HaveSuffix("succeeded(3) recovered(1) failed(0) skipped(0)"))
+	})
+
+	ginkgo.It("should ignore the events of other deploys", func() {
+		other := &agent.Archive{DeploymentID: bw.MustGenerateID()}
+		r := record(
+			failed(node1, other),
+			agent.NewDeployCommand(local, agent.DeployCommandFailed("", other.DeployOption)),
+		)
+		Expect(status(r)).To(Equal([]PeerStatus{PeerSkipped, PeerSkipped, PeerSkipped}))
+		Expect(r.ExitCode()).To(Equal(ExitQuorumLost))
+	})
+
+	ginkgo.It("should report the quorum lost when the deploy never concluded", func() {
+		r := record(completed(node1, archive))
+
+		var coded interface{ ExitCode() int }
+		Expect(errors.As(r.Err(), &coded)).To(BeTrue())
+		Expect(coded.ExitCode()).To(Equal(ExitQuorumLost))
+	})
+
+	ginkgo.It("should fail the interrupted deploy", func() {
+		r := record(completed(node1, archive))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		err := concluded(&Context{Context: ctx}, ctx, r)
+		Expect(err).To(MatchError(ContainSubstring("deploy cancelled")))
+
+		var coded interface{ ExitCode() int }
+		Expect(errors.As(err, &coded)).To(BeTrue())
+		Expect(coded.ExitCode()).To(Equal(ExitFailed))
+	})
+
+	ginkgo.It("should encode the summary as json", func() {
+		var (
+			buf     bytes.Buffer
+			decoded struct {
+				DeploymentID string       `json:"deploymentID"`
+				ExitCode     int          `json:"exitCode"`
+				Peers        []PeerResult `json:"peers"`
+			}
+		)
+
+		r := record(
+			completed(node1, archive),
+			failed(node2, archive),
+			agent.NewDeployCommand(local, agent.DeployCommandFailed("", archive.DeployOption)),
+		)
+		Expect(reportTo(&buf, OutputJSON, r)).ToNot(Succeed())
+		Expect(json.Unmarshal(buf.Bytes(), &decoded)).To(Succeed())
+		Expect(decoded.DeploymentID).To(Equal(bw.RandomID(archive.DeploymentID).String()))
+		Expect(decoded.ExitCode).To(Equal(ExitFailed))
+		Expect(decoded.Peers).To(HaveLen(3))
+	})
+})
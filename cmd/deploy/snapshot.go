@@ -25,7 +25,7 @@ func Snapshot(ctx Context, out io.WriteCloser) (err error) {
 
 	log.Println("pid", os.Getpid())
 
-	if err = os.WriteFile(filepath.Join(config.Deployment.DataDir, bw.EnvFile), []byte(config.Environment), 0600); err != nil {
+	if err = os.WriteFile(filepath.Join(config.Deployment.DataDir, bw.EnvFile), []byte(config.Environ()), 0600); err != nil {
 		return errors.Wrap(err, "failed to crreate bw.env")
 	}
 
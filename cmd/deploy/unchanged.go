@@ -0,0 +1,63 @@
+package deploy
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"io"
+
+	"github.com/pkg/errors"
+
+	"github.com/james-lawrence/bw/agent"
+	"github.com/james-lawrence/bw/agent/dialers"
+	"github.com/james-lawrence/bw/agentutil"
+)
+
+// checksum of the archive, identical to the deployment id the agents assign to the uploaded archive.
+func checksum(archive io.ReadSeeker) (_ []byte, err error) {
+	digest := sha256.New()
+
+	if _, err = archive.Seek(0, io.SeekStart); err != nil {
+		return nil, errors.Wrap(err, "unable to rewind the archive")
+	}
+
+	if _, err = io.Copy(digest, archive); err != nil {
+		return nil, errors.Wrap(err, "unable to checksum the archive")
+	}
+
+	return digest.Sum(nil), nil
+}
+
+// unchanged determines if every peer is already running an identical archive of the commit.
+func unchanged(ctx context.Context, d dialers.Defaults, archive io.ReadSeeker, commit string, peers ...*agent.Peer) (_ bool, err error) {
+	var (
+		digest []byte
+		refs   []agentutil.DeployedRef
+	)
+
+	if digest, err = checksum(archive); err != nil {
+		return false, err
+	}
+
+	if refs, err = agentutil.DeployedRefs(ctx, agentutil.PeerSet(peers), d); err != nil {
+		return false, err
+	}
+
+	return deployed(digest, commit, refs...), nil
+}
+
+// deployed returns true when every ref is the archive of the commit, unreachable peers
+// haven't deployed the archive.
+func deployed(digest []byte, commit string, refs ...agentutil.DeployedRef) bool {
+	if len(refs) == 0 {
+		return false
+	}
+
+	for _, ref := range refs {
+		if ref.Commit != commit || !bytes.Equal(ref.DeploymentID, digest) {
+			return false
+		}
+	}
+
+	return true
+}
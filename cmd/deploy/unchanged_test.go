@@ -0,0 +1,78 @@
+package deploy
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/james-lawrence/bw/agent"
+	"github.com/james-lawrence/bw/agentutil"
+	"github.com/james-lawrence/bw/archive"
+	"github.com/james-lawrence/bw/internal/testingx"
+)
+
+var _ = ginkgo.Describe("unchanged", func() {
+	var (
+		node1 = agent.NewPeer("node1")
+		node2 = agent.NewPeer("node2")
+	)
+
+	pack := func(deployspace string) *bytes.Reader {
+		var buf bytes.Buffer
+		Expect(archive.PackReproducible(&buf, deployspace)).To(Succeed())
+		return bytes.NewReader(buf.Bytes())
+	}
+
+	ginkgo.It("should skip a second deploy of an identical archive", func() {
+		deployspace := testingx.TempDir()
+		Expect(os.WriteFile(filepath.Join(deployspace, "bw.env"), []byte("FOO=bar\n"), 0600)).To(Succeed())
+
+		first, err := checksum(pack(deployspace))
+		Expect(err).To(Succeed())
+
+		// the files are rewritten between the deploys, reproducible archives ignore the timestamps.
+		now := time.Now().Add(time.Hour)
+		Expect(os.Chtimes(filepath.Join(deployspace, "bw.env"), now, now)).To(Succeed())
+
+		second, err := checksum(pack(deployspace))
+		Expect(err).To(Succeed())
+		Expect(second).To(Equal(first))
+
+		refs := []agentutil.DeployedRef{
+			{Peer: node1, Commit: "8a1f3c2", DeploymentID: first},
+			{Peer: node2, Commit: "8a1f3c2", DeploymentID: first},
+		}
+		Expect(deployed(second, "8a1f3c2", refs...)).To(BeTrue())
+	})
+
+	ginkgo.It("should deploy a changed archive", func() {
+		deployspace := testingx.TempDir()
+		Expect(os.WriteFile(filepath.Join(deployspace, "bw.env"), []byte("FOO=bar\n"), 0600)).To(Succeed())
+		first, err := checksum(pack(deployspace))
+		Expect(err).To(Succeed())
+
+		Expect(os.WriteFile(filepath.Join(deployspace, "bw.env"), []byte("FOO=baz\n"), 0600)).To(Succeed())
+		second, err := checksum(pack(deployspace))
+		Expect(err).To(Succeed())
+		Expect(second).ToNot(Equal(first))
+
+		Expect(deployed(second, "8a1f3c2", agentutil.DeployedRef{Peer: node1, Commit: "8a1f3c2", DeploymentID: first})).To(BeFalse())
+	})
+
+	ginkgo.It("should deploy when any node is running a different commit or is unreachable", func() {
+		digest := []byte("digest")
+		Expect(deployed(digest, "8a1f3c2",
+			agentutil.DeployedRef{Peer: node1, Commit: "8a1f3c2", DeploymentID: digest},
+			agentutil.DeployedRef{Peer: node2, Commit: "0bc41d7", DeploymentID: digest},
+		)).To(BeFalse())
+		Expect(deployed(digest, "8a1f3c2",
+			agentutil.DeployedRef{Peer: node1, Commit: "8a1f3c2", DeploymentID: digest},
+			agentutil.DeployedRef{Peer: node2},
+		)).To(BeFalse())
+		Expect(deployed(digest, "8a1f3c2")).To(BeFalse())
+	})
+})
@@ -11,9 +11,11 @@ import (
 	"github.com/james-lawrence/bw/ux"
 )
 
-func NewFromClientConfig(ctx context.Context, config agent.ConfigClient, d dialers.Quorum, local *agent.Peer, events chan *agent.Message, options ...ux.Option) {
+// NewFromClientConfig returns a context that is done once the ui stops displaying the deploy.
+func NewFromClientConfig(ctx context.Context, config agent.ConfigClient, d dialers.Quorum, local *agent.Peer, events chan *agent.Message, options ...ux.Option) context.Context {
 	dctx, ddone := context.WithTimeout(ctx, config.Deployment.Timeout+time.Minute)
-	New(dctx, ddone, d, local, events, options...)
+	New(dctx, ddone, d, local, events, append([]ux.Option{ux.OptionProgressFormat(config.ProgressFormat)}, options...)...)
+	return dctx
 }
 
 func New(ctx context.Context, shutdown context.CancelFunc, d dialers.Quorum, local *agent.Peer, events chan *agent.Message, options ...ux.Option) {
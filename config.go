@@ -155,6 +155,11 @@ func locateFile(name string, searchDirs ...string) (result string) {
 
 // ExpandAndDecodeFile ...
 func ExpandAndDecodeFile(path string, dst interface{}) (err error) {
+	return decodeFile(path, dst, ExpandAndDecode)
+}
+
+// decodeFile decodes the file at the specified path, missing files are ignored.
+func decodeFile(path string, dst interface{}, decode func([]byte, interface{}) error) (err error) {
 	var (
 		raw []byte
 	)
@@ -171,7 +176,7 @@ func ExpandAndDecodeFile(path string, dst interface{}) (err error) {
 		log.Println("loaded configuration", path)
 	}
 
-	return ExpandAndDecode(raw, dst)
+	return decode(raw, dst)
 }
 
 // ExpandAndDecode expands environment variables within the file at the specified
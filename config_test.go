@@ -20,6 +20,15 @@ func environmentSet1(k string) string {
 	}
 }
 
+func lookupSet1(k string) (string, bool) {
+	switch k {
+	case "FOO", "BIZZ", "MULTILINE", "EMPTY":
+		return environmentSet1(k), true
+	default:
+		return "", false
+	}
+}
+
 type xType struct {
 	Field1 string
 	Field2 string
@@ -50,4 +59,95 @@ field3: "YOINK"
 			},
 		),
 	)
+
+	It("should not resolve references when expanding the environment", func() {
+		out := xType{}
+		Expect(ExpandEnvironAndDecode([]byte("field1: \"${.field2}\"\nfield2: \"${UNDEFINED}\"\n"), &out, environmentSet1)).To(Succeed())
+		Expect(out).To(Equal(xType{}))
+	})
+
+	Describe("references", func() {
+		type bind struct {
+			Host string
+			Port int
+		}
+
+		type config struct {
+			Name       string
+			ServerName string `yaml:"serverName"`
+			Address    string
+			Bind       bind
+			Alternates []bind
+		}
+
+		decode := func(content string) (out config, err error) {
+			err = ExpandEnvironAndDecodeReferences([]byte(content), &out, lookupSet1)
+			return out, err
+		}
+
+		It("should resolve a reference to a top level field", func() {
+			out, err := decode(`
+name: "${FOO}"
+serverName: "${.name}.example.com"
+`)
+			Expect(err).To(Succeed())
+			Expect(out.ServerName).To(Equal("BAR.example.com"))
+		})
+
+		It("should resolve nested references retaining the type of the value", func() {
+			out, err := decode(`
+serverName: example.com
+address: "${bind.host}:${bind.port}"
+bind:
+  host: "${.serverName}"
+  port: 2000
+alternates:
+  - host: "${alternates.1.host}"
+    port: "${bind.port}"
+  - host: localhost
+    port: 2001
+`)
+			Expect(err).To(Succeed())
+			Expect(out.Address).To(Equal("example.com:2000"))
+			Expect(out.Bind).To(Equal(bind{Host: "example.com", Port: 2000}))
+			Expect(out.Alternates).To(Equal([]bind{{Host: "localhost", Port: 2000}, {Host: "localhost", Port: 2001}}))
+		})
+
+		It("should detect reference cycles", func() {
+			_, err := decode(`
+name: "${.address}"
+serverName: "${.name}"
+address: "${.serverName}:2000"
+`)
+			Expect(err).To(MatchError(ContainSubstring("config reference cycle")))
+		})
+
+		It("should detect self references", func() {
+			_, err := decode(`name: "${.name}"`)
+			Expect(err).To(MatchError(ContainSubstring("config reference cycle")))
+		})
+
+		It("should reject undefined references", func() {
+			_, err := decode(`serverName: "${bind.host}"`)
+			Expect(err).To(MatchError("undefined config reference: ${bind.host}"))
+		})
+
+		It("should reject undefined environment variables", func() {
+			_, err := decode(`serverName: "${serverName}"`)
+			Expect(err).To(MatchError("undefined environment variable: ${serverName}"))
+
+			out, err := decode(`serverName: "${EMPTY}example.com"`)
+			Expect(err).To(Succeed())
+			Expect(out.ServerName).To(Equal("example.com"))
+		})
+
+		It("should reject interpolating a collection", func() {
+			_, err := decode(`
+address: "${.bind}:2000"
+bind:
+  host: localhost
+`)
+			Expect(err).To(MatchError(ContainSubstring("not a scalar value")))
+		})
+	})
 })
@@ -1,7 +1,10 @@
 package daemons
 
 import (
+	"context"
 	"net"
+	"path/filepath"
+	"time"
 
 	"github.com/hashicorp/raft"
 	"github.com/pkg/errors"
@@ -17,7 +20,10 @@ import (
 	"github.com/james-lawrence/bw/agent/quorum"
 	"github.com/james-lawrence/bw/agentutil"
 	"github.com/james-lawrence/bw/certificatecache"
+	"github.com/james-lawrence/bw/clustering"
 	"github.com/james-lawrence/bw/deployment"
+	"github.com/james-lawrence/bw/internal/errorsx"
+	"github.com/james-lawrence/bw/internal/netx"
 	"github.com/james-lawrence/bw/notary"
 	"github.com/james-lawrence/bw/storage"
 )
@@ -26,7 +32,9 @@ import (
 func Agent(dctx Context, upload storage.UploadProtocol, download storage.DownloadProtocol) (err error) {
 	var (
 		bind         net.Listener
+		allowed      []*net.IPNet
 		observersmem observers.Memory
+		fingerprint  string
 		dlreg        = storage.New(storage.OptionProtocols(download))
 	)
 
@@ -34,10 +42,14 @@ func Agent(dctx Context, upload storage.UploadProtocol, download storage.Downloa
 		return err
 	}
 
+	if fingerprint, err = dctx.Config.Fingerprint(); err != nil {
+		return errors.Wrap(err, "failed to fingerprint the configuration")
+	}
+
 	qdialer := dialers.NewQuorum(
 		dctx.Cluster,
 		dctx.Dialer.Defaults()...,
-	)
+	).PreferZone(dctx.Config.Zone)
 	dispatcher := agentutil.NewDispatcher(qdialer)
 
 	coordinator := deployment.New(
@@ -46,8 +58,10 @@ func Agent(dctx Context, upload storage.UploadProtocol, download storage.Downloa
 		deployment.CoordinatorOptionDispatcher(dispatcher),
 		deployment.CoordinatorOptionRoot(dctx.Config.Root),
 		deployment.CoordinatorOptionKeepN(dctx.Config.KeepN),
+		deployment.CoordinatorOptionKeepLastSuccessful(dctx.Config.Bootstrap.KeepLastSuccessful),
 		deployment.CoordinatorOptionDeployResults(dctx.Results),
 		deployment.CoordinatorOptionStorage(dlreg),
+		deployment.CoordinatorOptionVerifyArchives(dctx.Config.VerifyArchiveWorkers),
 	)
 
 	server := grpc.NewServer(
@@ -57,13 +71,6 @@ func Agent(dctx Context, upload storage.UploadProtocol, download storage.Downloa
 		grpc.KeepaliveEnforcementPolicy(dctx.RPCKeepalivePolicy),
 	)
 
-	agent.NewServer(
-		dctx.Cluster,
-		agent.ServerOptionAuth(notary.NewAgentAuth(dctx.NotaryAuth)),
-		agent.ServerOptionDeployer(&coordinator),
-		agent.ServerOptionShutdown(dctx.Shutdown),
-	).Bind(server)
-
 	q := quorum.New(
 		observersmem,
 		dctx.Cluster,
@@ -73,9 +80,46 @@ func Agent(dctx Context, upload storage.UploadProtocol, download storage.Downloa
 		upload,
 		dctx.Raft,
 		quorum.OptionDialer(qdialer),
+		quorum.OptionApplyBatch(dctx.Config.RaftApplyBatchSize),
+		quorum.OptionMaxArchiveBytes(dctx.Config.MaxArchiveBytes),
+		quorum.OptionMinHealthyForDeploy(dctx.Config.MinHealthyForDeploy),
+		quorum.OptionMinimumNodes(dctx.Config.MinimumNodes),
+		quorum.OptionMaxConcurrency(dctx.Config.MaxEnvironmentConcurrency[dctx.Config.ServerName]),
 	)
 	go (&q).Observe(make(chan raft.Observation, 200))
 
+	if dctx.Config.StartupMaintenance > 0 {
+		maintenance := agent.NewMaintenance(&q, agent.MaintenanceOptionCluster(dctx.Cluster, &q))
+		errorsx.MaybeLog(maintenance.EnterMaintenance())
+		time.AfterFunc(dctx.Config.StartupMaintenance, maintenance.ExitMaintenance)
+		go maintenance.Run(dctx.Context)
+	}
+
+	if dctx.Config.StatusFile != "" {
+		go agent.StatusFile(
+			dctx.Context,
+			statusPath(dctx.Config),
+			dctx.Config.SnapshotFrequency,
+			status(dctx, fingerprint, &q, &coordinator),
+		)
+	}
+
+	gate := agent.NewDrainGate()
+	drain := []agent.DrainOption{
+		agent.DrainOptionLeadership(&q),
+		agent.DrainOptionCluster(dctx.Bootstrapper),
+	}
+	dctx.Drainer.Register(append(drain, agent.DrainOptionGate(gate), agent.DrainOptionDeployer(&coordinator))...)
+
+	agent.NewServer(
+		dctx.Cluster,
+		agent.ServerOptionAuth(notary.NewAgentAuth(dctx.NotaryAuth)),
+		agent.ServerOptionDeployer(&coordinator),
+		agent.ServerOptionShutdown(dctx.Shutdown),
+		agent.ServerOptionFingerprint(fingerprint),
+		agent.ServerOptionDrain(gate, drain...),
+	).Bind(server)
+
 	agent.NewQuorum(
 		&q,
 		notary.NewAgentAuth(dctx.NotaryAuth),
@@ -97,13 +141,64 @@ func Agent(dctx Context, upload storage.UploadProtocol, download storage.Downloa
 	).Bind(server)
 
 	proxy.NewDeployment(dctx.NotaryAuth, qdialer).Bind(server)
+
+	clustering.NewMembershipService(notary.NewAgentAuth(dctx.NotaryAuth), dctx.Cluster, dctx.Membership).Bind(server)
+
+	// standard grpc health service for load balancers and orchestrators, not serving once the agent shuts down.
+	health := agent.NewHealth(
+		agent.HealthCheckJoined(dctx.Cluster),
+		agent.HealthCheckQuorum(&q),
+		agent.HealthCheckIdle(&coordinator),
+	).Bind(server)
+	go health.Run(dctx.Context, time.Second)
 	acme.NewService(dctx.ACMECache, dctx.NotaryAuth).Bind(server)
 
+	if allowed, err = netx.ParseCIDRs(dctx.Config.AllowedClientCIDRs...); err != nil {
+		return errors.Wrap(err, "invalid allowed client cidrs")
+	}
+
 	if bind, err = dctx.Muxer.Bind(bw.ProtocolAgent, dctx.Listener.Addr()); err != nil {
 		return errors.Wrap(err, "failed to bind agent protocol")
 	}
 
+	// reject rpc connections from outside the allowed ranges before any requests are handled.
+	bind = netx.NewAllowListener(bind, allowed...)
+
 	dctx.grpc("agent", server, bind)
 
 	return nil
 }
+
+func statusPath(c agent.Config) string {
+	if filepath.IsAbs(c.StatusFile) {
+		return c.StatusFile
+	}
+
+	return filepath.Join(c.Root, c.StatusFile)
+}
+
+// status of the agent for the status file.
+func status(dctx Context, fingerprint string, q *quorum.Quorum, coordinator *deployment.Coordinator) func(ctx context.Context) agent.Status {
+	return func(ctx context.Context) agent.Status {
+		s := agent.Status{
+			Name:        dctx.Config.Name,
+			Peers:       len(dctx.Cluster.Peers()),
+			Quorum:      []string{},
+			Fingerprint: fingerprint,
+		}
+
+		for _, p := range dctx.Cluster.Quorum() {
+			s.Quorum = append(s.Quorum, p.Name)
+		}
+
+		if info, err := q.Info(ctx); err == nil && info.Leader != nil {
+			s.Leader = info.Leader.Name
+		}
+
+		if deploys, err := coordinator.Deployments(); err == nil && len(deploys) > 0 {
+			s.LastDeploy = agent.NewStatusDeploy(deploys[0])
+		}
+
+		return s
+	}
+}
@@ -0,0 +1,170 @@
+package daemons_test
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"math/big"
+	"net"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/james-lawrence/bw"
+	"github.com/james-lawrence/bw/agent"
+	"github.com/james-lawrence/bw/agent/dialers"
+	"github.com/james-lawrence/bw/cluster"
+	"github.com/james-lawrence/bw/clustering"
+	"github.com/james-lawrence/bw/clustering/raftutil"
+	"github.com/james-lawrence/bw/daemons"
+	"github.com/james-lawrence/bw/deployment"
+	"github.com/james-lawrence/bw/internal/testingx"
+	"github.com/james-lawrence/bw/internal/tlsx"
+	"github.com/james-lawrence/bw/muxer"
+	"github.com/james-lawrence/bw/notary"
+	"github.com/james-lawrence/bw/storage"
+)
+
+// noopDownloads download protocol of the agent, never used while checking the health.
+type noopDownloads struct{}
+
+func (noopDownloads) Protocol() string {
+	return "noop"
+}
+
+func (noopDownloads) New() storage.Downloader {
+	return nil
+}
+
+// credentials self signed tls credentials of the agent, clients trust the certificate.
+func credentials() (server *tls.Config, client *tls.Config) {
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+
+	priv, der, err := tlsx.SelfSignedRSAGen(2048, template)
+	Expect(err).To(Succeed())
+
+	cert, err := x509.ParseCertificate(der)
+	Expect(err).To(Succeed())
+
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+
+	server = &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: priv}},
+		NextProtos:   []string{"bw.mux"},
+	}
+
+	client = &tls.Config{
+		RootCAs:    pool,
+		ServerName: "127.0.0.1",
+		NextProtos: []string{"bw.mux"},
+	}
+
+	return server, client
+}
+
+var _ = Describe("Agent", func() {
+	var (
+		servertls *tls.Config
+		clienttls *tls.Config
+	)
+
+	BeforeEach(func() {
+		servertls, clienttls = credentials()
+	})
+
+	// run the agent daemon bound to the loopback until the spec completes.
+	run := func(allowed ...string) net.Addr {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).To(Succeed())
+
+		ctx, done := context.WithCancel(context.Background())
+		cleanup := new(sync.WaitGroup)
+		DeferCleanup(cleanup.Wait)
+		DeferCleanup(done)
+
+		root := testingx.TempDir()
+		config := agent.NewConfig(agent.ConfigOptionDefaultBind(net.ParseIP("127.0.0.1"))).EnsureDefaults()
+		config.AllowedClientCIDRs = allowed
+		config.Root = root
+		config.CredentialsDir = root
+		local := cluster.NewLocal(config.Peer())
+
+		dctx := daemons.Context{
+			Local:         local,
+			Listener:      l,
+			Dialer:        dialers.NewDefaults(),
+			Muxer:         muxer.New(),
+			Config:        config,
+			Context:       ctx,
+			Shutdown:      done,
+			Cleanup:       cleanup,
+			NotaryStorage: notary.NewComposite(root, notary.NewMem()),
+			Cluster:       cluster.New(local.Peer, clustering.NewMock(agent.PeerToNode(local.Peer))),
+			PeeringEvents: cluster.NewEventsQueue(local),
+			Results:       make(chan *deployment.DeployResult, 100),
+			Drainer:       daemons.NewDrainer(),
+			Membership:    clustering.NewEventBus(),
+		}
+		dctx.NotaryAuth = notary.NewAuth(dctx.NotaryStorage)
+
+		dctx, err = daemons.Inmem(dctx)
+		Expect(err).To(Succeed())
+
+		dctx.Raft, err = raftutil.NewProtocol(ctx, agent.PeerToNode(local.Peer), dctx.Inmem)
+		Expect(err).To(Succeed())
+
+		dctx.MuxerListen(ctx, tls.NewListener(l, servertls))
+		Expect(daemons.Agent(dctx, nil, noopDownloads{})).To(Succeed())
+
+		return l.Addr()
+	}
+
+	check := func(addr net.Addr) (*grpc_health_v1.HealthCheckResponse, error) {
+		ctx, done := context.WithTimeout(context.Background(), 5*time.Second)
+		defer done()
+
+		d := muxer.NewDialer(bw.ProtocolAgent, tlsx.NewDialer(clienttls))
+		conn, err := grpc.DialContext(
+			ctx,
+			addr.String(),
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+			grpc.WithContextDialer(func(ctx context.Context, address string) (net.Conn, error) {
+				return d.DialContext(ctx, "tcp", address)
+			}),
+		)
+		Expect(err).To(Succeed())
+		defer conn.Close()
+
+		return grpc_health_v1.NewHealthClient(conn).Check(ctx, &grpc_health_v1.HealthCheckRequest{})
+	}
+
+	It("should serve the health of the agent over tls", func() {
+		resp, err := check(run())
+		Expect(err).To(Succeed())
+		// the agent isn't serving until it participates in a quorum.
+		Expect(resp.Status).To(Equal(grpc_health_v1.HealthCheckResponse_NOT_SERVING))
+	})
+
+	It("should serve the health to the allowed client ranges", func() {
+		_, err := check(run("127.0.0.0/8"))
+		Expect(err).To(Succeed())
+	})
+
+	It("should reject clients outside of the allowed ranges", func() {
+		_, err := check(run("10.0.0.0/8"))
+		Expect(err).ToNot(Succeed())
+	})
+})
@@ -1,6 +1,8 @@
 package daemons
 
 import (
+	"log"
+
 	"github.com/james-lawrence/bw/agent"
 	"github.com/james-lawrence/bw/agent/dialers"
 	"github.com/james-lawrence/bw/bootstrap"
@@ -35,11 +37,17 @@ func Bootstrap(ctx Context, download storage.DownloadProtocol) (err error) {
 		return errors.Wrap(err, "failed to initialize filesystem bootstrap service")
 	}
 
+	policy := ctx.Config.BootstrapPolicy()
 	bus := bootstrap.NewUntilSuccess(
-		bootstrap.OptionMaxAttempts(ctx.Config.Bootstrap.Attempts),
+		bootstrap.OptionMaxAttempts(policy.Attempts),
 	)
 
 	if err = bus.Run(ctx.Context, ctx.Config, ctx.Deploys, download, ctx.Results); err != nil {
+		if !policy.Fatal() {
+			log.Println("failed to bootstrap node, continuing without the latest deploy", err)
+			return nil
+		}
+
 		// if bootstrapping fails shutdown the process.
 		return errors.Wrap(err, "failed to bootstrap node shutting down")
 	}
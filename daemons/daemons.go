@@ -8,6 +8,7 @@ import (
 	"log"
 	"net"
 	"sync"
+	"time"
 
 	"github.com/james-lawrence/bw/agent"
 	"github.com/james-lawrence/bw/agent/acme"
@@ -17,6 +18,7 @@ import (
 	"github.com/james-lawrence/bw/clustering/raftutil"
 	"github.com/james-lawrence/bw/deployment"
 	"github.com/james-lawrence/bw/internal/errorsx"
+	"github.com/james-lawrence/bw/internal/tlsx"
 	"github.com/james-lawrence/bw/muxer"
 	"github.com/james-lawrence/bw/notary"
 	"github.com/pkg/errors"
@@ -41,6 +43,11 @@ type cluster interface {
 	GetN(n int, key []byte) []*memberlist.Node
 }
 
+type bootstrapper interface {
+	clustering.Joiner
+	Leave(time.Duration) error
+}
+
 type dialer interface {
 	DialContext(ctx context.Context, network string, address string) (net.Conn, error)
 }
@@ -61,8 +68,9 @@ type Context struct {
 	NotaryAuth         notary.Auth
 	Raft               raftutil.Protocol
 	Cluster            cluster
-	Bootstrapper       clustering.Joiner
+	Bootstrapper       bootstrapper
 	RPCCredentials     *tls.Config
+	GossipCredentials  *tls.Config
 	RPCKeepalivePolicy keepalive.EnforcementPolicy
 	RPCKeepalive       keepalive.ServerParameters
 	PeeringEvents      *_cluster.EventsQueue
@@ -71,6 +79,8 @@ type Context struct {
 	ACMECache          acme.DiskCache
 	Inmem              *grpc.ClientConn
 	P2PPublicKey       []byte
+	Drainer            *Drainer
+	Membership         *clustering.EventBus
 }
 
 // MuxerListen ...
@@ -103,3 +113,15 @@ func (t *Context) shutdown(name string, listeners ...net.Listener) {
 		}
 	}()
 }
+
+// anonymous never presents a client certificate for transports authenticated by other means,
+// unless the agents' listeners require clients to present one.
+func anonymous(c agent.Config) tlsx.Option {
+	return func(tc *tls.Config) error {
+		if c.ClientAuthPolicy.P2P == agent.ClientAuthRequire || c.ClientAuthPolicy.Alternates == agent.ClientAuthRequire {
+			return nil
+		}
+
+		return tlsx.OptionNoClientCertificate(tc)
+	}
+}
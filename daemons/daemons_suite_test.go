@@ -0,0 +1,13 @@
+package daemons_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestDaemons(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Daemons Suite")
+}
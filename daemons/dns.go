@@ -0,0 +1,65 @@
+package daemons
+
+import (
+	"log"
+	"net"
+
+	"github.com/miekg/dns"
+	"github.com/pkg/errors"
+
+	_dns "github.com/james-lawrence/bw/dns"
+	"github.com/james-lawrence/bw/internal/errorsx"
+)
+
+// DNS serves the records of the cluster over udp and tcp, see dns.NewHandler.
+// noop unless Config.DNSBind.Address is set.
+func DNS(dctx Context) (err error) {
+	var (
+		udp net.PacketConn
+		tcp net.Listener
+	)
+
+	if dctx.Config.DNSBind.Address == nil {
+		return nil
+	}
+
+	if udp, err = net.ListenPacket("udp", dctx.Config.DNSBind.Address.String()); err != nil {
+		return errors.Wrapf(err, "failed to bind dns to udp %s", dctx.Config.DNSBind.Address)
+	}
+
+	if tcp, err = net.ListenTCP("tcp", dctx.Config.DNSBind.Address); err != nil {
+		return errorsx.Compact(errors.Wrapf(err, "failed to bind dns to tcp %s", dctx.Config.DNSBind.Address), udp.Close())
+	}
+
+	handler := _dns.NewHandler(
+		dctx.Cluster,
+		_dns.OptionTTL(dctx.Config.DNSBind.TTL),
+		_dns.OptionHealthyOnly(dctx.Config.DNSBind.HealthyOnly),
+		_dns.OptionFQDN(dctx.Config.DNSBind.Name(dctx.Config.ServerName)),
+	)
+
+	servers := []*dns.Server{
+		{PacketConn: udp, Handler: handler},
+		{Listener: tcp, Handler: handler},
+	}
+
+	dctx.Cleanup.Add(1)
+	go func() {
+		defer dctx.Cleanup.Done()
+		<-dctx.Context.Done()
+		for _, srv := range servers {
+			log.Println("dns shutdown", errorsx.Compact(errors.Wrap(srv.Shutdown(), "failed"), errorsx.String("complete")))
+		}
+	}()
+
+	log.Println("dns: listening", dctx.Config.DNSBind.Address.String(), "for", dctx.Config.DNSBind.Name(dctx.Config.ServerName))
+	for _, srv := range servers {
+		go func(srv *dns.Server) {
+			if cause := srv.ActivateAndServe(); cause != nil {
+				log.Println("dns: stopped", cause)
+			}
+		}(srv)
+	}
+
+	return nil
+}
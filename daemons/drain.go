@@ -0,0 +1,57 @@
+package daemons
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/james-lawrence/bw/agent"
+	"github.com/james-lawrence/bw/internal/errorsx"
+)
+
+// NewDrainer the components of the agent drained on shutdown.
+func NewDrainer() *Drainer {
+	return &Drainer{}
+}
+
+// Drainer the components of the agent drained on shutdown, the daemons register their
+// components as they're initialized.
+type Drainer struct {
+	m       sync.Mutex
+	options []agent.DrainOption
+}
+
+// Register the components to drain.
+func (t *Drainer) Register(options ...agent.DrainOption) {
+	if t == nil {
+		return
+	}
+
+	t.m.Lock()
+	defer t.m.Unlock()
+	t.options = append(t.options, options...)
+}
+
+func (t *Drainer) registered() []agent.DrainOption {
+	if t == nil {
+		return nil
+	}
+
+	t.m.Lock()
+	defer t.m.Unlock()
+	return append([]agent.DrainOption(nil), t.options...)
+}
+
+// DrainOnShutdown once the signal context is done the agent drains within the drain timeout
+// and then the daemons are shutdown. the cleanup is held until the daemons are shutdown.
+func DrainOnShutdown(signal context.Context, dctx Context) {
+	dctx.Cleanup.Add(1)
+	go func() {
+		defer dctx.Cleanup.Done()
+		defer dctx.Shutdown()
+
+		err := agent.DrainOnSignal(signal, dctx.Context, dctx.Config.DrainTimeout, dctx.Drainer.registered()...)
+		errorsx.MaybeLog(errors.Wrap(err, "drain failed"))
+	}()
+}
@@ -0,0 +1,55 @@
+package daemons
+
+import (
+	"expvar"
+	"log"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/james-lawrence/bw/internal/errorsx"
+)
+
+// Metrics serves the prometheus metrics of the agent at /metrics, including the process
+// and go runtime metrics, and the expvars at /debug/vars. noop unless Config.MetricsBind is set.
+func Metrics(dctx Context) (err error) {
+	var (
+		bind net.Listener
+	)
+
+	if dctx.Config.MetricsBind == nil {
+		return nil
+	}
+
+	if bind, err = net.ListenTCP("tcp", dctx.Config.MetricsBind); err != nil {
+		return errors.Wrapf(err, "failed to bind metrics to %s", dctx.Config.MetricsBind)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	mux.Handle("/debug/vars", expvar.Handler())
+
+	srv := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	dctx.Cleanup.Add(1)
+	go func() {
+		defer dctx.Cleanup.Done()
+		<-dctx.Context.Done()
+		log.Println("metrics shutdown", errorsx.Compact(errors.Wrap(srv.Close(), "failed"), errorsx.String("complete")))
+	}()
+
+	log.Println("metrics: listening", bind.Addr().String())
+	go func() {
+		if cause := srv.Serve(bind); cause != nil && !errors.Is(cause, http.ErrServerClosed) {
+			log.Println("metrics: stopped", cause)
+		}
+	}()
+
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/hashicorp/memberlist"
 	"github.com/james-lawrence/bw"
 	"github.com/james-lawrence/bw/agent"
+	"github.com/james-lawrence/bw/agent/dialers"
 	_cluster "github.com/james-lawrence/bw/cluster"
 	"github.com/james-lawrence/bw/clustering"
 	"github.com/james-lawrence/bw/clustering/peering"
@@ -57,11 +58,23 @@ func Peering(dctx Context) (_ Context, err error) {
 	}
 
 	// TLS verification doesn't matter for swim, since we use a secret key but we need to still
-	// pass through the TLS handshake.
+	// pass through the TLS handshake. no client certificate is presented for the same reason,
+	// unless the agents require one.
+	// distinct gossip credentials are verified against the gossip authority.
+	gossip := tlsx.MustClone(dctx.GossipCredentials, tlsx.OptionNoClientCert, anonymous(dctx.Config))
+	if !dctx.Config.DistinctGossipCredentials() {
+		gossip = tlsx.MustClone(gossip, tlsx.OptionInsecureSkipVerify)
+	}
+
+	joins := clustering.NewJoinLimiter(dctx.Config.JoinRateLimit, keyring)
+	router := clustering.NewRouter()
 	transport, err := memberlistx.NewSWIMTransport(
-		muxer.NewDialer(bw.ProtocolSWIM, tlsx.NewDialer(tlsx.MustClone(dctx.RPCCredentials, tlsx.OptionInsecureSkipVerify, tlsx.OptionNoClientCert))),
+		muxer.NewDialer(bw.ProtocolSWIM, dialers.NewBreaker(tlsx.NewDialer(gossip, tlsx.DialerOptionTimeout(dctx.Config.TLSHandshakeTimeout)), dialers.BreakerOptionConfig(dctx.Config))),
 		memberlistx.SWIMStreams(bindreliable),
 		memberlistx.SWIMPackets(bindpacket),
+		memberlistx.SWIMStreamFilter(joins.Filter),
+		memberlistx.SWIMFamily(dctx.Config.AddressFamily),
+		memberlistx.SWIMRoutes(router.Routes),
 	)
 
 	if err != nil {
@@ -75,15 +88,20 @@ func Peering(dctx Context) (_ Context, err error) {
 		clustering.OptionAdvertisePort(dctx.Config.P2PAdvertised.Port),
 		clustering.OptionDelegate(dctx.PeeringEvents),
 		clustering.OptionKeyring(keyring),
-		clustering.OptionEventDelegate(dctx.PeeringEvents),
+		clustering.OptionEventDelegate(clustering.NewEventDelegates(dctx.PeeringEvents, dctx.Membership, clustering.EventMetrics{})),
 		clustering.OptionAliveDelegate(_cluster.AliveDefault{}),
 		clustering.OptionLogger(dctx.DebugLog),
 		clustering.OptionTransport(transport),
+		clustering.OptionProbeInterval(dctx.Config.Gossip.ProbeInterval),
+		clustering.OptionProbeTimeout(dctx.Config.Gossip.ProbeTimeout),
+		clustering.OptionSuspicionMult(dctx.Config.Gossip.SuspicionMult),
 	)
 
 	if c, err = cdialer.Dial(); err != nil {
 		return dctx, errors.Wrap(err, "failed to create cluster")
 	}
+	joins.Track(c)
+	router.Track(c)
 
 	dctx.Cluster = _cluster.New(dctx.Local.Peer, c)
 	dctx.Bootstrapper = c
@@ -104,6 +122,7 @@ func Peered(dctx Context, cc connecter) (_ Context, err error) {
 		dctx.Cluster,
 		fssnapshot,
 		clustering.SnapshotOptionFrequency(dctx.Config.SnapshotFrequency),
+		clustering.SnapshotOptionWarmup(dctx.Config.SnapshotWarmup),
 		clustering.SnapshotOptionContext(dctx.Context),
 	)
 
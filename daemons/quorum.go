@@ -5,7 +5,9 @@ import (
 
 	"github.com/hashicorp/memberlist"
 	"github.com/james-lawrence/bw/agent"
+	"github.com/james-lawrence/bw/clustering"
 	"github.com/james-lawrence/bw/clustering/raftutil"
+	"github.com/james-lawrence/bw/muxer"
 	"google.golang.org/grpc"
 )
 
@@ -15,7 +17,17 @@ type rafter interface {
 
 // Quorum initialize the quorum daemon service.
 func Quorum(dctx Context, cc rafter) (_ Context, err error) {
-	transport := raftutil.ProtocolOptionMuxerTransport(dctx.Config.P2PBind, dctx.Config.P2PAdvertised, dctx.Muxer, raftutil.NewTLSStreamDialer(dctx.RPCCredentials))
+	var d dialer = raftutil.NewTLSStreamDialer(dctx.RPCCredentials)
+	if dctx.Config.Multiplex {
+		d = muxer.NewSessions(d)
+	}
+
+	// the raft addresses of the servers are their primary advertised address, the connections are
+	// routed via the address of the server preferred by the local networks.
+	router := clustering.NewRouter()
+	router.Track(dctx.Cluster)
+
+	transport := raftutil.ProtocolOptionMuxerTransport(dctx.Config.P2PBind, dctx.Config.P2PAdvertised, dctx.Muxer, router.Dialer(d))
 
 	if dctx.Raft, err = cc.Raft(dctx.Context, dctx.Config, agent.PeerToNode(dctx.Local.Peer), dctx.Inmem, transport); err != nil {
 		return dctx, err
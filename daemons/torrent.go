@@ -28,7 +28,7 @@ func Torrent(ctx Context) (tc storage.TorrentConfig, err error) {
 			Listener: bind,
 			Dialer: muxer.NewDialer(
 				bw.ProtocolTorrent,
-				tlsx.NewDialer(tlsx.MustClone(ctx.RPCCredentials, tlsx.OptionInsecureSkipVerify, tlsx.OptionNoClientCert)),
+				tlsx.NewDialer(tlsx.MustClone(ctx.RPCCredentials, tlsx.OptionInsecureSkipVerify, tlsx.OptionNoClientCert, anonymous(ctx.Config)), tlsx.DialerOptionTimeout(ctx.Config.TLSHandshakeTimeout)),
 			),
 		},
 	)
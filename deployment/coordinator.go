@@ -2,6 +2,7 @@ package deployment
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"log"
 	"os"
@@ -21,7 +22,11 @@ import (
 	"github.com/james-lawrence/bw/internal/envx"
 	"github.com/james-lawrence/bw/internal/errorsx"
 	"github.com/james-lawrence/bw/internal/iox"
+	"github.com/james-lawrence/bw/internal/tracex"
 	"github.com/james-lawrence/bw/storage"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
@@ -80,6 +85,17 @@ func CoordinatorOptionKeepN(n int) CoordinatorOption {
 	}
 }
 
+// CoordinatorOptionKeepLastSuccessful always keep the most recent successful deploy in addition
+// to the deploys kept by CoordinatorOptionKeepN, ensuring a series of failed deploys never evicts
+// the last known good release. each deploy is stored in its own directory, deploys/<deployment id>,
+// alongside a deploy.metadata file recording the outcome of the deploy which is used to locate
+// the successful deploy.
+func CoordinatorOptionKeepLastSuccessful(b bool) CoordinatorOption {
+	return func(d *Coordinator) {
+		d.keepLastSuccessful = b
+	}
+}
+
 // CoordinatorOptionDispatcher sets the dispatcher for the coordinator.
 func CoordinatorOptionDispatcher(di dispatcher) CoordinatorOption {
 	return func(d *Coordinator) {
@@ -101,25 +117,41 @@ func CoordinatorOptionStorage(reg storage.DownloadFactory) CoordinatorOption {
 	}
 }
 
+// CoordinatorOptionVerifyArchives verify downloaded archives against their checksum, reading
+// ahead with the given number of workers, see archive.Checksum. workers <= 0 disables verification.
+func CoordinatorOptionVerifyArchives(workers int) CoordinatorOption {
+	return func(d *Coordinator) {
+		d.verifyWorkers = workers
+	}
+}
+
 // Coordinator for a deploy
 type Coordinator struct {
-	keepN             int // never set manually. always set by CoordinatorOptionKeepN
-	root              string
-	deploysRoot       string // never set manually. always set by CoordinatorOptionRoot
-	local             *agent.Peer
-	deployer          deployer
-	dispatcher        dispatcher
-	dlreg             storage.DownloadFactory
-	cleanup           agentutil.Cleaner // never set manually. always set by CoordinatorOptionKeepN
-	completedObserver chan *DeployResult
-	ds                *DeployState
-	m                 *sync.Mutex
+	keepN              int // never set manually. always set by CoordinatorOptionKeepN
+	root               string
+	deploysRoot        string // never set manually. always set by CoordinatorOptionRoot
+	local              *agent.Peer
+	deployer           deployer
+	dispatcher         dispatcher
+	dlreg              storage.DownloadFactory
+	cleanup            agentutil.Cleaner // never set manually. always set by CoordinatorOptionKeepN
+	completedObserver  chan *DeployResult
+	ds                 *DeployState
+	m                  *sync.Mutex
+	verifyWorkers      int
+	keepLastSuccessful bool
 }
 
-func (t *Coordinator) background(dctx *DeployContext) {
+func (t *Coordinator) background(dctx *DeployContext, span trace.Span) {
 	defer close(dctx.completed)
+	defer span.End()
 
 	done := <-dctx.completed
+	if done.Error != nil {
+		span.RecordError(done.Error)
+		span.SetStatus(codes.Error, done.Error.Error())
+	}
+
 	if envx.Boolean(false, bw.EnvLogsDeploy, bw.EnvLogsVerbose) {
 		log.Println("deployment completed", dctx.ID.String())
 	}
@@ -196,6 +228,10 @@ func (t *Coordinator) Deploy(ctx context.Context, by string, opts *agent.DeployO
 		dctx *DeployContext
 	)
 
+	if opts.GetSimulate() {
+		return t.simulate(ctx, by, opts, archive)
+	}
+
 	// set the timestamp of the archive to as this marks the time the archive was actually deployed.
 	// and ensures it shows up properly in the deployments history
 	archive.Dts = time.Now().UTC().Unix()
@@ -206,7 +242,7 @@ func (t *Coordinator) Deploy(ctx context.Context, by string, opts *agent.DeployO
 	// downloads from becoming permanently blocked waiting for the archive to be downloaded.
 	// without this behaviour the torrent can be removed while nodes are still trying to deploy.
 	// preventing further deploys.
-	if soft := agentutil.MaybeClean(t.cleanup)(agentutil.Dirs(t.deploysRoot)); soft != nil {
+	if soft := agentutil.MaybeClean(t.cleanup)(t.cleanable()); soft != nil {
 		soft = errors.Wrap(soft, "failed to clear workspace directory")
 		log.Println(soft)
 		errorsx.MaybeLog(agentutil.Dispatch(ctx, t.dispatcher, agent.LogError(t.local, soft)))
@@ -216,8 +252,14 @@ func (t *Coordinator) Deploy(ctx context.Context, by string, opts *agent.DeployO
 		DeployContextOptionDispatcher(t.dispatcher),
 	}
 
+	ctx, span := tracex.Start(ctx, "agent.execute")
+	span.SetAttributes(attribute.String("deploy.id", bw.RandomID(archive.DeploymentID).String()))
+
 	if dctx, err = NewRemoteDeployContext(ctx, t.deploysRoot, t.local, by, opts, archive, dcopts...); err != nil {
 		errorsx.MaybeLog(agentutil.Dispatch(ctx, t.dispatcher, agent.LogError(t.local, err)))
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		span.End()
 		return t.ds.current, err
 	}
 
@@ -228,13 +270,13 @@ func (t *Coordinator) Deploy(ctx context.Context, by string, opts *agent.DeployO
 	dctx.Log.Printf("deploy recieved: deployID(%s) primary(%s) location(%s)\n", dctx.ID, dctx.Archive.Peer.Name, dctx.Archive.Location)
 	go func() {
 		defer dctx.Log.Printf("deploy complete: deployID(%s) primary(%s) location(%s)\n", dctx.ID, dctx.Archive.Peer.Name, dctx.Archive.Location)
-		t.background(dctx)
+		t.background(dctx, span)
 	}()
 
 	if ok = atomic.CompareAndSwapUint32(t.ds.state, coordinaterWaiting, coordinatorDeploying); !ok {
-		err = errors.Errorf("already deploying - unknown deployment - %s", t.ds.current.Stage)
+		err = agent.NewDeployRejection(agent.DeployRejection_Busy, fmt.Sprintf("already deploying - unknown deployment - %s", t.ds.current.Stage))
 		if t.ds.current.Archive != nil {
-			err = errors.Errorf("%s is already deploying: %s - %s", t.ds.current.Initiator, bw.RandomID(t.ds.current.Archive.DeploymentID).String(), t.ds.current.Stage)
+			err = agent.NewDeployRejection(agent.DeployRejection_Busy, fmt.Sprintf("%s is already deploying: %s - %s", t.ds.current.Initiator, bw.RandomID(t.ds.current.Archive.DeploymentID).String(), t.ds.current.Stage))
 		}
 
 		errorsx.MaybeLog(dctx.Dispatch(agent.LogError(t.local, err)))
@@ -251,7 +293,7 @@ func (t *Coordinator) Deploy(ctx context.Context, by string, opts *agent.DeployO
 
 	errorsx.MaybeLog(dctx.Dispatch(agent.DeployEvent(dctx.Local, d)))
 
-	if err = downloadArchive(t.dlreg, dctx); err != nil {
+	if err = downloadArchive(t.dlreg, dctx, t.verifyWorkers); err != nil {
 		return d, dctx.Done(err)
 	}
 
@@ -260,6 +302,18 @@ func (t *Coordinator) Deploy(ctx context.Context, by string, opts *agent.DeployO
 	return d, nil
 }
 
+// simulate a deploy, exercises the deploy rpc without downloading the archive,
+// running the deployer, or recording the deploy in the history of the node.
+func (t *Coordinator) simulate(ctx context.Context, by string, opts *agent.DeployOptions, archive *agent.Archive) (d *agent.Deploy, err error) {
+	if atomic.LoadUint32(t.ds.state) != coordinaterWaiting {
+		return t.ds.current, agent.NewDeployRejection(agent.DeployRejection_Busy, "already deploying, unable to simulate deploy")
+	}
+
+	errorsx.MaybeLog(agentutil.Dispatch(ctx, t.dispatcher, agent.LogEvent(t.local, fmt.Sprintf("simulated deploy: %s", bw.RandomID(archive.DeploymentID)))))
+
+	return &agent.Deploy{Initiator: by, Archive: archive, Options: opts, Stage: agent.Deploy_Completed}, nil
+}
+
 // Logs return the logs for the given deployment ID.
 func (t *Coordinator) Logs(did []byte) (logs io.ReadCloser) {
 	var (
@@ -298,6 +352,35 @@ func (t *Coordinator) update(dctx *DeployContext, d *agent.Deploy, c agentutil.C
 	return d
 }
 
+// cleanable returns the deploy directories eligible for cleanup.
+func (t *Coordinator) cleanable() (dirs []agentutil.FileInfo, err error) {
+	if dirs, err = agentutil.Dirs(t.deploysRoot); err != nil || !t.keepLastSuccessful {
+		return dirs, err
+	}
+
+	successful, err := lastSuccessfulDeploy(t.deploysRoot)
+	if err != nil {
+		log.Println("unable to locate the last successful deploy, it may be removed", err)
+		return dirs, nil
+	}
+
+	if successful == nil {
+		return dirs, nil
+	}
+
+	_, root, _ := deployDirs(t.deploysRoot, successful.Archive)
+	filtered := make([]agentutil.FileInfo, 0, len(dirs))
+	for _, d := range dirs {
+		if filepath.Clean(d.Path) == filepath.Clean(root) {
+			continue
+		}
+
+		filtered = append(filtered, d)
+	}
+
+	return filtered, nil
+}
+
 func (t *Coordinator) correctLatestDeploy(deploys ...*agent.Deploy) error {
 	if len(deploys) == 0 {
 		return nil
@@ -320,9 +403,10 @@ func (t *Coordinator) correctLatestDeploy(deploys ...*agent.Deploy) error {
 	return writeDeployMetadata(root, d)
 }
 
-func downloadArchive(dlreg storage.DownloadFactory, dctx *DeployContext) (err error) {
+func downloadArchive(dlreg storage.DownloadFactory, dctx *DeployContext, verifyWorkers int) (err error) {
 	var (
-		dst *os.File
+		dst  *os.File
+		size int64
 	)
 
 	dctx.Log.Println("download initiated", dctx.Archive.Location)
@@ -334,10 +418,16 @@ func downloadArchive(dlreg storage.DownloadFactory, dctx *DeployContext) (err er
 		errorsx.MaybeLog(errors.Wrap(errorsx.Compact(dst.Sync(), dst.Close()), "archive cleanup failed"))
 	}()
 
-	if _, err = io.Copy(dst, dlreg.New(dctx.Archive.Location).Download(dctx.deadline, dctx.Archive)); err != nil {
+	if size, err = io.Copy(dst, dlreg.New(dctx.Archive.Location).Download(dctx.deadline, dctx.Archive)); err != nil {
 		return errors.Wrapf(err, "retrieve archive")
 	}
 
+	if verifyWorkers > 0 && len(dctx.Archive.Checksum) > 0 {
+		if err = archive.Verify(dst, size, dctx.Archive.Checksum, verifyWorkers); err != nil {
+			return errors.Wrap(err, "verify archive")
+		}
+	}
+
 	if err = iox.Rewind(dst); err != nil {
 		return errors.Wrap(err, "unable to rewind archive")
 	}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/james-lawrence/bw"
 	"github.com/james-lawrence/bw/agent"
+	"github.com/james-lawrence/bw/agentutil"
 	"github.com/james-lawrence/bw/internal/testingx"
 	"github.com/james-lawrence/bw/storage"
 
@@ -61,6 +62,86 @@ var _ = Describe("Coordinator", func() {
 		g.Expect(deploys[0].Stage).To(g.Equal(agent.Deploy_Failed))
 	})
 
+	DescribeTable("retention should preserve the last successful deploy", func(keep bool, cleaner agentutil.Cleaner, stages ...agent.Deploy_Stage) {
+		var (
+			dirs       []string
+			successful = -1
+		)
+
+		p := agent.NewPeer("node1")
+		c := New(
+			p,
+			NewDirective(),
+			CoordinatorOptionRoot(workdir),
+			CoordinatorOptionStorage(storage.NoopRegistry{}),
+			CoordinatorOptionKeepLastSuccessful(keep),
+		)
+
+		ts := time.Now().Add(-time.Hour)
+		for i, s := range stages {
+			a := agent.Archive{
+				DeploymentID: bw.MustGenerateID(),
+				Peer:         p,
+				Dts:          ts.Add(time.Duration(i) * time.Minute).Unix(),
+			}
+			deploydir := filepath.Join(workdir, "deploys", bw.RandomID(a.DeploymentID).String())
+			g.Expect(os.MkdirAll(deploydir, 0755)).To(g.Succeed())
+			g.Expect(writeDeployMetadata(deploydir, &agent.Deploy{Archive: &a, Stage: s})).To(g.Succeed())
+			mtime := ts.Add(time.Duration(i) * time.Minute)
+			g.Expect(os.Chtimes(deploydir, mtime, mtime)).To(g.Succeed())
+			dirs = append(dirs, deploydir)
+
+			if s == agent.Deploy_Completed {
+				successful = i
+			}
+		}
+
+		g.Expect(agentutil.MaybeClean(cleaner)(c.cleanable())).To(g.Succeed())
+
+		if keep {
+			g.Expect(dirs[successful]).To(g.BeADirectory())
+		} else {
+			g.Expect(dirs[successful]).ToNot(g.BeADirectory())
+		}
+	},
+		Entry("newest failures evict the success", false, agentutil.KeepNewestN(1), agent.Deploy_Completed, agent.Deploy_Failed, agent.Deploy_Failed),
+		Entry("newest failures preserve the success", true, agentutil.KeepNewestN(1), agent.Deploy_Completed, agent.Deploy_Failed, agent.Deploy_Failed),
+		Entry("interleaved newest failures preserve the success", true, agentutil.KeepNewestN(2), agent.Deploy_Completed, agent.Deploy_Failed, agent.Deploy_Completed, agent.Deploy_Failed, agent.Deploy_Failed, agent.Deploy_Failed),
+		Entry("oldest failures evict the success", false, agentutil.KeepOldestN(2), agent.Deploy_Failed, agent.Deploy_Failed, agent.Deploy_Completed, agent.Deploy_Failed),
+		Entry("oldest failures preserve the success", true, agentutil.KeepOldestN(2), agent.Deploy_Failed, agent.Deploy_Failed, agent.Deploy_Completed, agent.Deploy_Failed),
+		Entry("no retention preserves the success", true, agentutil.KeepNewestN(0), agent.Deploy_Failed, agent.Deploy_Completed, agent.Deploy_Failed, agent.Deploy_Completed, agent.Deploy_Failed),
+	)
+
+	It("retention should keep the n most recent deploys in addition to the last successful deploy", func() {
+		p := agent.NewPeer("node1")
+		c := New(
+			p,
+			NewDirective(),
+			CoordinatorOptionRoot(workdir),
+			CoordinatorOptionStorage(storage.NoopRegistry{}),
+			CoordinatorOptionKeepLastSuccessful(true),
+		)
+
+		ts := time.Now().Add(-time.Hour)
+		for i, s := range []agent.Deploy_Stage{agent.Deploy_Completed, agent.Deploy_Failed, agent.Deploy_Failed, agent.Deploy_Failed} {
+			a := agent.Archive{DeploymentID: bw.MustGenerateID(), Peer: p, Dts: ts.Add(time.Duration(i) * time.Minute).Unix()}
+			deploydir := filepath.Join(workdir, "deploys", bw.RandomID(a.DeploymentID).String())
+			g.Expect(os.MkdirAll(deploydir, 0755)).To(g.Succeed())
+			g.Expect(writeDeployMetadata(deploydir, &agent.Deploy{Archive: &a, Stage: s})).To(g.Succeed())
+			mtime := ts.Add(time.Duration(i) * time.Minute)
+			g.Expect(os.Chtimes(deploydir, mtime, mtime)).To(g.Succeed())
+		}
+
+		g.Expect(agentutil.MaybeClean(agentutil.KeepNewestN(2))(c.cleanable())).To(g.Succeed())
+
+		deploys, err := c.Deployments()
+		g.Expect(err).To(g.Succeed())
+		g.Expect(deploys).To(g.HaveLen(3))
+		g.Expect(deploys[0].Stage).To(g.Equal(agent.Deploy_Failed))
+		g.Expect(deploys[1].Stage).To(g.Equal(agent.Deploy_Failed))
+		g.Expect(deploys[2].Stage).To(g.Equal(agent.Deploy_Completed))
+	})
+
 	DescribeTable("Reset should properly reset deploys directory", func(s agent.Deploy_Stage, result int) {
 		p := agent.NewPeer("node1")
 		c := New(
@@ -3,11 +3,13 @@ package deployment_test
 import (
 	"context"
 	"fmt"
+	"sync/atomic"
 	"time"
 
 	"github.com/james-lawrence/bw"
 	"github.com/james-lawrence/bw/agent"
 	"github.com/james-lawrence/bw/deployment"
+	"github.com/james-lawrence/bw/internal/errorsx"
 	"github.com/james-lawrence/bw/internal/testingx"
 	"github.com/james-lawrence/bw/storage"
 
@@ -90,6 +92,9 @@ var _ = Describe("Coordinator", func() {
 		}
 		_, err = c.Deploy(context.Background(), "test user 2", dopts, a2)
 		Expect(err).To(MatchError(fmt.Sprintf("test user is already deploying: %s - Deploying", bw.RandomID(a.DeploymentID).String())))
+		rejection, ok := agent.DeployRejectionFromError(err)
+		Expect(ok).To(BeTrue())
+		Expect(rejection.Reason).To(Equal(agent.DeployRejection_Busy))
 
 		Eventually(func() []*agent.Deploy {
 			deploys, err := c.Deployments()
@@ -165,4 +170,44 @@ var _ = Describe("Coordinator", func() {
 		c.Cancel()
 		c.Cancel()
 	})
+
+	It("should not execute or record simulated deploys", func() {
+		var (
+			executed int64
+		)
+
+		p := agent.NewPeer("node1")
+		c := deployment.New(
+			p,
+			countingDeployer{count: &executed},
+			deployment.CoordinatorOptionRoot(workdir),
+			deployment.CoordinatorOptionStorage(storage.NoopRegistry{}),
+		)
+		a := &agent.Archive{
+			DeploymentID: bw.MustGenerateID(),
+			Peer:         p,
+		}
+		dopts := &agent.DeployOptions{
+			Timeout:  int64(time.Minute),
+			Simulate: true,
+		}
+
+		d, err := c.Deploy(context.Background(), "test user", dopts, a)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(d.Stage).To(Equal(agent.Deploy_Completed))
+		Expect(atomic.LoadInt64(&executed)).To(Equal(int64(0)))
+
+		deploys, err := c.Deployments()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(deploys).To(HaveLen(0))
+	})
 })
+
+type countingDeployer struct {
+	count *int64
+}
+
+func (t countingDeployer) Deploy(dctx *deployment.DeployContext) {
+	atomic.AddInt64(t.count, 1)
+	errorsx.MaybeLog(dctx.Done(nil))
+}
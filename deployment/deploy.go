@@ -2,6 +2,7 @@ package deployment
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"sync"
 	"sync/atomic"
@@ -13,7 +14,10 @@ import (
 	"github.com/james-lawrence/bw/internal/envx"
 	"github.com/james-lawrence/bw/internal/errorsx"
 	"github.com/james-lawrence/bw/internal/timex"
+	"github.com/james-lawrence/bw/internal/tracex"
 	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 	"golang.org/x/time/rate"
 )
 
@@ -38,6 +42,23 @@ func (t OperationFunc) Visit(ctx context.Context, c *agent.Peer) (*agent.Deploy,
 	return t(ctx, c)
 }
 
+// OperationSimulated checks the state of nodes during a simulated deploy.
+// simulated deploys are not recorded by the nodes, so once a node is idle
+// the simulated deploy is considered complete.
+func OperationSimulated(check operation) OperationFunc {
+	return func(ctx context.Context, p *agent.Peer) (d *agent.Deploy, err error) {
+		if d, err = check.Visit(ctx, p); err != nil {
+			return d, err
+		}
+
+		if d.Stage == agent.Deploy_Deploying {
+			return d, nil
+		}
+
+		return &agent.Deploy{Stage: agent.Deploy_Completed}, nil
+	}
+}
+
 type constantChecker struct {
 	Deploy *agent.Deploy
 }
@@ -119,12 +140,65 @@ func DeployOptionHeartbeatFrequency(t time.Duration) Option {
 	}
 }
 
+// DeployOptionMaxTotal hard cap on the duration of the entire deploy, once exceeded
+// the remaining nodes are cancelled and the deploy is considered failed. <= 0 disables the cap.
+func DeployOptionMaxTotal(t time.Duration) Option {
+	return func(d *Deploy) {
+		d.maxTotal = t
+	}
+}
+
+// DeployOptionContext the deploy halts once the context is cancelled, nodes that already
+// completed are left intact and the remaining nodes are never deployed to.
+func DeployOptionContext(ctx context.Context) Option {
+	return func(d *Deploy) {
+		d.ctx = ctx
+	}
+}
+
+// DeployOptionLimiter cap the nodes simultaneously deployed to regardless of the partitioner.
+func DeployOptionLimiter(l *Limiter) Option {
+	return func(d *Deploy) {
+		d.worker.limiter = l
+	}
+}
+
+// DeployOptionTracing record a trace span for the deploy and each of the nodes.
+func DeployOptionTracing(b bool) Option {
+	return func(d *Deploy) {
+		d.tracing = b
+	}
+}
+
+// DeployOptionRetryFailedOnce defer the nodes that fail during the rollout, each is retried
+// once after the rollout completes instead of halting the deploy. a node is only deferred
+// once its attempts are exhausted, and the deferred retry makes the same attempts,
+// see DeployOptionRetries.
+func DeployOptionRetryFailedOnce(b bool) Option {
+	return func(d *Deploy) {
+		d.worker.retries = nil
+		if b {
+			d.worker.retries = &retries{}
+		}
+	}
+}
+
+// DeployOptionRetries re-attempt a node that fails up to n times before the node is considered
+// failed, waiting the backoff between attempts. the node must be alive, as determined by the
+// checker, for a retry to proceed. nodes that succeeded are never retried.
+func DeployOptionRetries(n int, backoff time.Duration) Option {
+	return func(d *Deploy) {
+		d.worker.attempts = n + 1
+		d.worker.backoff = backoff
+	}
+}
+
 // NewDeploy by default deploys operate in one-at-a-time mode.
 func NewDeploy(p *agent.Peer, di dispatcher, options ...Option) Deploy {
 	d := Deploy{
+		ctx:    context.Background(),
 		filter: AlwaysMatch,
 		worker: worker{
-			c:          make(chan func(context.Context) error),
 			wait:       new(sync.WaitGroup),
 			check:      constantChecker{Deploy: &agent.Deploy{Stage: agent.Deploy_Completed}},
 			deploy:     OperationFunc(loggingDeploy),
@@ -132,6 +206,7 @@ func NewDeploy(p *agent.Peer, di dispatcher, options ...Option) Deploy {
 			local:      p,
 			completed:  new(int64),
 			failed:     new(int64),
+			recovered:  new(int64),
 			timeout:    bw.DefaultDeployTimeout + deployGracePeriod,
 			heartbeat:  5 * time.Second,
 			queue:      make(chan *pending),
@@ -180,10 +255,16 @@ type worker struct {
 	deploy         operation
 	completed      *int64
 	failed         *int64
+	recovered      *int64
 	ignoreFailures bool
 	timeout        time.Duration
 	heartbeat      time.Duration
 	queue          chan *pending
+	limiter        *Limiter
+	total          *Limiter // caps the nodes deployed to simultaneously across the partitions of the deploy.
+	retries        *retries
+	attempts       int           // maximum attempts of the deploy to each node.
+	backoff        time.Duration // duration between the attempts to a node.
 }
 
 func (t worker) work(ctx context.Context) {
@@ -195,7 +276,11 @@ func (t worker) work(ctx context.Context) {
 			continue
 		}
 
-		if err := op(ctx); err != nil {
+		var deferred deferredFailure
+		if err := op(ctx); errors.As(err, &deferred) {
+			log.Println(err)
+			errorsx.MaybeLog(agentutil.ReliableDispatch(ctx, t.dispatcher, agent.LogEvent(t.local, err.Error())))
+		} else if err != nil {
 			log.Println(err)
 			atomic.AddInt64(t.failed, 1)
 			errorsx.MaybeLog(agentutil.ReliableDispatch(ctx, t.dispatcher, agent.LogError(t.local, err)))
@@ -205,6 +290,12 @@ func (t worker) work(ctx context.Context) {
 	}
 }
 
+// Recovered the number of nodes that failed and then succeeded once retried,
+// see DeployOptionRetries and DeployOptionRetryFailedOnce.
+func (t worker) Recovered() int64 {
+	return atomic.LoadInt64(t.recovered)
+}
+
 func (t worker) Complete() (int64, bool) {
 	t.wait.Wait()
 	failures := atomic.LoadInt64(t.failed)
@@ -212,13 +303,48 @@ func (t worker) Complete() (int64, bool) {
 }
 
 func (t worker) DeployTo(ctx context.Context, peer *agent.Peer) error {
+	perform := t.retrying(peer, false)
+	if t.retries != nil {
+		perform = t.retries.deferred(t, peer, perform)
+	}
+
+	select {
+	case t.c <- perform:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	return nil
+}
+
+func (t worker) perform(peer *agent.Peer) func(context.Context) error {
 	task := newPending(peer, t.timeout)
-	perform := func(deadline context.Context) error {
+	return func(deadline context.Context) (err error) {
+		deadline, span := tracex.Start(deadline, "deploy.node")
+		span.SetAttributes(attribute.String("peer.name", peer.Name), attribute.String("peer.ip", peer.Ip))
+		defer func() {
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+			}
+			span.End()
+		}()
+
 		if envx.Boolean(false, bw.EnvLogsDeploy, bw.EnvLogsVerbose) {
 			log.Println("deploy to", peer.Ip, "initiated")
 			defer log.Println("deploy to", peer.Ip, "completed")
 		}
 
+		if err := t.total.Acquire(deadline); err != nil {
+			return errors.Wrapf(err, "failed to deploy to: %s", peer.Ip)
+		}
+		defer t.total.Release()
+
+		if err := t.limiter.Acquire(deadline); err != nil {
+			return errors.Wrapf(err, "failed to deploy to: %s", peer.Ip)
+		}
+		defer t.limiter.Release()
+
 		if _, err := t.deploy.Visit(deadline, peer); err != nil {
 			return errors.Wrapf(err, "failed to deploy to: %s", peer.Ip)
 		}
@@ -237,36 +363,169 @@ func (t worker) DeployTo(ctx context.Context, peer *agent.Peer) error {
 			return errors.Wrapf(cause, "failed to deploy to: %s", peer.Ip)
 		}
 	}
+}
 
-	select {
-	case t.c <- perform:
-	case <-ctx.Done():
-		return ctx.Err()
+// retrying re-attempts the deploy to the node after a failure until the attempts are exhausted,
+// the node must be alive before each retry. failed indicates the node already failed previously,
+// e.g.) the retry of a deferred node.
+func (t worker) retrying(peer *agent.Peer, failed bool) func(context.Context) error {
+	return func(ctx context.Context) (err error) {
+		for attempt := 1; ; attempt++ {
+			// each attempt is monitored independently.
+			if err = t.perform(peer)(ctx); err == nil {
+				if failed || attempt > 1 {
+					t.recover(ctx, peer)
+				}
+
+				return nil
+			}
+
+			if attempt >= t.attempts {
+				return err
+			}
+
+			errorsx.MaybeLog(agentutil.ReliableDispatch(ctx, t.dispatcher, agent.LogEvent(t.local, fmt.Sprintf("%s, retrying (%d/%d) in %s", err, attempt, t.attempts-1, t.backoff))))
+
+			delay := time.NewTimer(t.backoff)
+			select {
+			case <-ctx.Done():
+				delay.Stop()
+				return errors.Wrapf(err, "retry of %s abandoned", peer.Name)
+			case <-delay.C:
+			}
+
+			if cause := t.alive(ctx, peer); cause != nil {
+				return errors.Wrapf(errorsx.Compact(err, cause), "retry of %s abandoned, node is not alive", peer.Name)
+			}
+		}
 	}
+}
 
-	return nil
+// recover records the node that failed then succeeded once retried.
+func (t worker) recover(ctx context.Context, peer *agent.Peer) {
+	atomic.AddInt64(t.recovered, 1)
+	errorsx.MaybeLog(agentutil.ReliableDispatch(ctx, t.dispatcher, agent.LogEvent(t.local, fmt.Sprintf("%s failed then recovered", peer.Name))))
+}
+
+// alive checks the node is reachable before it is retried.
+func (t worker) alive(ctx context.Context, peer *agent.Peer) error {
+	cctx, done := context.WithTimeout(ctx, t.heartbeat+t.backoff)
+	defer done()
+
+	_, err := t.check.Visit(cctx, peer)
+	return err
+}
+
+// deferredFailure a node failed during the rollout and will be retried once the rollout completes.
+type deferredFailure struct {
+	cause error
+}
+
+func (t deferredFailure) Error() string {
+	return fmt.Sprintf("%s, retrying once the rollout completes", t.cause)
+}
+
+// retries the nodes that failed during the rollout.
+type retries struct {
+	m      sync.Mutex
+	failed []retry
+}
+
+type retry struct {
+	worker worker
+	peer   *agent.Peer
+}
+
+// deferred records the node for a retry when the operation fails.
+func (t *retries) deferred(w worker, peer *agent.Peer, op func(context.Context) error) func(context.Context) error {
+	return func(ctx context.Context) error {
+		cause := op(ctx)
+		if cause == nil {
+			return nil
+		}
+
+		t.m.Lock()
+		defer t.m.Unlock()
+		t.failed = append(t.failed, retry{worker: w, peer: peer})
+
+		return deferredFailure{cause: cause}
+	}
+}
+
+// retry each of the failed nodes once, distinguishing the nodes that recovered
+// from the nodes that failed permanently.
+func (t *retries) retry(ctx context.Context) {
+	if t == nil {
+		return
+	}
+
+	t.m.Lock()
+	failed := t.failed
+	t.failed = nil
+	t.m.Unlock()
+
+	for _, r := range failed {
+		w := r.worker
+		errorsx.MaybeLog(agentutil.ReliableDispatch(ctx, w.dispatcher, agent.LogEvent(w.local, fmt.Sprintf("retrying the deploy to %s", r.peer.Name))))
+
+		if err := w.retrying(r.peer, true)(ctx); err != nil {
+			log.Println(err)
+			atomic.AddInt64(w.failed, 1)
+			errorsx.MaybeLog(agentutil.ReliableDispatch(ctx, w.dispatcher, agent.LogError(w.local, errors.Wrap(err, "failed permanently"))))
+			continue
+		}
+
+		errorsx.MaybeLog(agentutil.ReliableDispatch(ctx, w.dispatcher, agent.PeersCompletedEvent(w.local, atomic.AddInt64(w.completed, 1))))
+	}
 }
 
 // Deploy - handles a deployment.
 type Deploy struct {
-	filter Filter
+	ctx      context.Context
+	filter   Filter
+	maxTotal time.Duration
+	tracing  bool
 	partitioner
 	worker
 }
 
 // Deploy deploy to the cluster. returns deployment results.
 // failed nodes and if it was considered a success.
-func (t Deploy) Deploy(c cluster) (int64, bool) {
-	ctx, done := context.WithTimeout(context.Background(), t.worker.timeout+deployGracePeriod)
+func (t Deploy) Deploy(c cluster) (failures int64, success bool) {
+	ctx, span := tracex.StartRoot(t.ctx, "deploy", t.tracing)
+	defer span.End()
+
+	started := time.Now()
+	metricDeploysStarted.Inc()
+	defer func() {
+		metricDeployDuration.Observe(time.Since(started).Seconds())
+		if success {
+			metricDeploysSucceeded.Inc()
+		} else {
+			metricDeploysFailed.Inc()
+		}
+	}()
+
+	ctx, done := context.WithTimeout(ctx, t.worker.timeout+deployGracePeriod)
 	defer done()
 
+	capped, cancel := context.WithCancel(ctx)
+	if t.maxTotal > 0 {
+		capped, cancel = context.WithTimeout(ctx, t.maxTotal)
+	}
+	defer cancel()
+
 	nodes := ApplyFilter(t.filter, c.Peers()...)
+	span.SetAttributes(attribute.Int64("nodes", int64(len(nodes))))
 	errorsx.MaybeLog(agentutil.Dispatch(ctx, t.dispatcher, agent.PeersFoundEvent(t.worker.local, int64(len(nodes)))))
 
-	concurrency := t.partitioner.Partition(len(nodes))
-	for i := 0; i < concurrency; i++ {
-		t.worker.wait.Add(1)
-		go t.worker.work(ctx)
+	classes := t.classify(nodes...)
+	for _, class := range classes {
+		metricPartitionSize.Observe(float64(len(class.nodes)))
+		for i := 0; i < class.concurrency; i++ {
+			t.worker.wait.Add(1)
+			go class.worker.work(capped)
+		}
 	}
 
 	initial := make(chan *pending, len(nodes))
@@ -277,7 +536,7 @@ func (t Deploy) Deploy(c cluster) (int64, bool) {
 
 	go heartbeat(ctx, t.worker.local, rate.Every(t.worker.heartbeat), t.dispatcher)
 
-	if failure := t.monitor.Await(ctx, initial, t.dispatcher, c, t.worker.check); failure != nil {
+	if failure := t.monitor.Await(capped, initial, t.dispatcher, c, t.worker.check); failure != nil {
 		switch errors.Cause(failure).(type) {
 		case errorsx.Timeout:
 			errorsx.MaybeLog(
@@ -295,17 +554,26 @@ func (t Deploy) Deploy(c cluster) (int64, bool) {
 	errorsx.MaybeLog(agentutil.Dispatch(ctx, t.dispatcher, agent.LogEvent(t.worker.local, "nodes are ready, deploying")))
 
 	go func() {
-		for _, peer := range nodes {
-			errorsx.MaybeLog(t.worker.DeployTo(ctx, peer))
+		feeders := new(sync.WaitGroup)
+		for _, class := range classes {
+			feeders.Add(1)
+			go func(class partition) {
+				defer feeders.Done()
+				for _, peer := range class.nodes {
+					errorsx.MaybeLog(class.worker.DeployTo(capped, peer))
+				}
+				close(class.worker.c)
+			}(class)
 		}
 
-		close(t.c)
+		feeders.Wait()
 		t.wait.Wait()
+		t.worker.retries.retry(capped)
 		close(t.queue)
 	}()
 
 	failure := errorsx.Ignore(
-		t.worker.monitor.Await(ctx, t.queue, t.dispatcher, c, t.worker.check),
+		t.worker.monitor.Await(capped, t.queue, t.dispatcher, c, t.worker.check),
 		context.Canceled,
 	)
 	if failure != nil {
@@ -318,7 +586,87 @@ func (t Deploy) Deploy(c cluster) (int64, bool) {
 		}
 	}
 
-	return t.worker.Complete()
+	failures, success = t.worker.Complete()
+
+	if recovered := t.worker.Recovered(); recovered > 0 {
+		errorsx.MaybeLog(
+			agentutil.Dispatch(ctx, t.dispatcher, agent.LogEvent(t.worker.local, fmt.Sprintf("%d of %d nodes failed then recovered", recovered, len(nodes)))),
+		)
+	}
+
+	// the deploy was cancelled, the nodes of the in progress partition were halted.
+	if t.ctx.Err() != nil {
+		halted, hdone := context.WithTimeout(context.Background(), 10*time.Second)
+		defer hdone()
+		errorsx.MaybeLog(
+			agentutil.Dispatch(halted, t.dispatcher, agent.LogEvent(t.worker.local, "deploy cancelled, the remaining nodes were not deployed to")),
+		)
+		return failures, false
+	}
+
+	// the cap was reached, the remaining nodes never deployed.
+	if t.maxTotal > 0 && errors.Is(capped.Err(), context.DeadlineExceeded) {
+		errorsx.MaybeLog(
+			agentutil.Dispatch(ctx, t.dispatcher, agent.LogEvent(t.worker.local, fmt.Sprintf("deploy exceeded the maximum duration %s, %d of %d nodes completed", t.maxTotal, atomic.LoadInt64(t.worker.completed), len(nodes)))),
+		)
+		return failures, false
+	}
+
+	return failures, success
+}
+
+// classifier partitions peers by class, each class of peers is deployed
+// to independently using the partitioner of the class. the nodes deployed to
+// simultaneously across the classes are capped by the partitioner itself.
+type classifier interface {
+	Classify(*agent.Peer) (class string, p bw.Partitioner)
+}
+
+// partition of the nodes deployed to by the worker.
+type partition struct {
+	worker      worker
+	nodes       []*agent.Peer
+	concurrency int
+}
+
+// classify the nodes into partitions, when the partitioner doesn't classify peers
+// all the nodes belong to a single partition. each partition has its own workers.
+func (t Deploy) classify(nodes ...*agent.Peer) (partitions []partition) {
+	c, ok := t.partitioner.(classifier)
+	if !ok {
+		w := t.worker
+		w.c = make(chan func(context.Context) error)
+		return []partition{{worker: w, nodes: nodes, concurrency: t.partitioner.Partition(len(nodes))}}
+	}
+
+	var (
+		order       []string
+		classes     = map[string][]*agent.Peer{}
+		partitioner = map[string]bw.Partitioner{}
+	)
+
+	for _, n := range nodes {
+		class, p := c.Classify(n)
+		if _, ok := classes[class]; !ok {
+			order = append(order, class)
+			partitioner[class] = p
+		}
+		classes[class] = append(classes[class], n)
+	}
+
+	total := NewLimiter(t.partitioner.Partition(len(nodes)))
+	for _, class := range order {
+		w := t.worker
+		w.c = make(chan func(context.Context) error)
+		w.total = total
+		partitions = append(partitions, partition{
+			worker:      w,
+			nodes:       classes[class],
+			concurrency: partitioner[class].Partition(len(classes[class])),
+		})
+	}
+
+	return partitions
 }
 
 // ApplyFilter applies the filter to the set of peers.
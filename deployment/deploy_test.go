@@ -2,15 +2,23 @@ package deployment_test
 
 import (
 	"context"
+	"fmt"
+	"net"
+	"sync"
 	"sync/atomic"
 	"time"
 
+	"github.com/hashicorp/memberlist"
+	"github.com/james-lawrence/bw"
 	"github.com/james-lawrence/bw/agent"
 	"github.com/james-lawrence/bw/agentutil"
 	"github.com/james-lawrence/bw/cluster"
 	"github.com/james-lawrence/bw/clustering"
 	"github.com/james-lawrence/bw/clustering/clusteringtestutil"
 	"github.com/james-lawrence/bw/deployment"
+	"github.com/james-lawrence/bw/internal/testingx"
+	"github.com/james-lawrence/bw/storage"
+	"github.com/prometheus/client_golang/prometheus"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -21,6 +29,23 @@ var (
 	failedDeploy    = agent.Deploy{Stage: agent.Deploy_Failed}
 )
 
+// partitions observed by the bw_deploy_partition_size metric and the total of their sizes.
+func partitionSizes() (count uint64, total float64) {
+	families, err := prometheus.DefaultGatherer.Gather()
+	Expect(err).To(Succeed())
+
+	for _, f := range families {
+		if f.GetName() != "bw_deploy_partition_size" {
+			continue
+		}
+
+		h := f.GetMetric()[0].GetHistogram()
+		return h.GetSampleCount(), h.GetSampleSum()
+	}
+
+	return 0, 0
+}
+
 var _ = Describe("Deploy", func() {
 	It("should run the deploys", func() {
 		var (
@@ -135,4 +160,417 @@ var _ = Describe("Deploy", func() {
 		Expect(success).To(BeFalse())
 		Expect(deployCount).To(Equal(int64(2)))
 	})
+
+	It("should retry a failed node once the rollout completes", func() {
+		var (
+			deployCount int64
+			attempts    int64
+		)
+
+		p := agent.NewPeer("node4")
+		c := cluster.New(
+			p,
+			clustering.NewMock(
+				agent.PeerToNode(p),
+				clusteringtestutil.NewNodeFromAddress("node1", "127.0.0.1"),
+				clusteringtestutil.NewNodeFromAddress("node2", "127.0.0.2"),
+				clusteringtestutil.NewNodeFromAddress("node3", "127.0.0.3"),
+			),
+		)
+
+		deploy := deployment.NewDeploy(
+			p,
+			agentutil.DiscardDispatcher{},
+			deployment.DeployOptionTimeout(100*time.Millisecond),
+			deployment.DeployOptionRetryFailedOnce(true),
+			deployment.DeployOptionDeployer(deployment.OperationFunc(func(ctx context.Context, p *agent.Peer) (ignored *agent.Deploy, err error) {
+				atomic.AddInt64(&deployCount, 1)
+				if p.Name == "node2" && atomic.AddInt64(&attempts, 1) == 1 {
+					return ignored, fmt.Errorf("boom")
+				}
+
+				return ignored, nil
+			})),
+		)
+
+		failures, success := deploy.Deploy(c)
+		Expect(failures).To(Equal(int64(0)))
+		Expect(success).To(BeTrue())
+		Expect(deploy.Recovered()).To(Equal(int64(1)))
+		Expect(attempts).To(Equal(int64(2)))
+		Expect(deployCount).To(Equal(int64(len(c.Peers()) + 1)))
+	})
+
+	It("should retry a node that fails transiently", func() {
+		var (
+			deployed = map[string]int{}
+			m        sync.Mutex
+		)
+
+		p := agent.NewPeer("node4")
+		c := cluster.New(
+			p,
+			clustering.NewMock(
+				agent.PeerToNode(p),
+				clusteringtestutil.NewNodeFromAddress("node1", "127.0.0.1"),
+				clusteringtestutil.NewNodeFromAddress("node2", "127.0.0.2"),
+				clusteringtestutil.NewNodeFromAddress("node3", "127.0.0.3"),
+			),
+		)
+
+		deploy := deployment.NewDeploy(
+			p,
+			agentutil.DiscardDispatcher{},
+			deployment.DeployOptionTimeout(100*time.Millisecond),
+			deployment.DeployOptionRetries(2, time.Millisecond),
+			deployment.DeployOptionDeployer(deployment.OperationFunc(func(ctx context.Context, p *agent.Peer) (ignored *agent.Deploy, err error) {
+				m.Lock()
+				defer m.Unlock()
+
+				deployed[p.Name]++
+				if p.Name == "node2" && deployed[p.Name] == 1 {
+					return ignored, fmt.Errorf("network blip")
+				}
+
+				return ignored, nil
+			})),
+		)
+
+		failures, success := deploy.Deploy(c)
+		Expect(failures).To(Equal(int64(0)))
+		Expect(success).To(BeTrue())
+		Expect(deploy.Recovered()).To(Equal(int64(1)))
+		Expect(deployed).To(Equal(map[string]int{"node1": 1, "node2": 2, "node3": 1, "node4": 1}))
+	})
+
+	It("should defer a node once its retries are exhausted", func() {
+		var attempts int64
+
+		p := agent.NewPeer("node2")
+		c := cluster.New(
+			p,
+			clustering.NewMock(
+				agent.PeerToNode(p),
+				clusteringtestutil.NewNodeFromAddress("node1", "127.0.0.1"),
+			),
+		)
+
+		deploy := deployment.NewDeploy(
+			p,
+			agentutil.DiscardDispatcher{},
+			deployment.DeployOptionTimeout(100*time.Millisecond),
+			deployment.DeployOptionRetries(1, time.Millisecond),
+			deployment.DeployOptionRetryFailedOnce(true),
+			deployment.DeployOptionDeployer(deployment.OperationFunc(func(ctx context.Context, p *agent.Peer) (ignored *agent.Deploy, err error) {
+				if p.Name == "node2" && atomic.AddInt64(&attempts, 1) <= 2 {
+					return ignored, fmt.Errorf("boom")
+				}
+
+				return ignored, nil
+			})),
+		)
+
+		failures, success := deploy.Deploy(c)
+		Expect(failures).To(Equal(int64(0)))
+		Expect(success).To(BeTrue())
+		Expect(deploy.Recovered()).To(Equal(int64(1)))
+		Expect(atomic.LoadInt64(&attempts)).To(Equal(int64(3)))
+	})
+
+	It("should fail a node once the retries are exhausted", func() {
+		var attempts int64
+
+		p := agent.NewPeer("node1")
+		c := cluster.New(p, clustering.NewMock(agent.PeerToNode(p)))
+
+		deploy := deployment.NewDeploy(
+			p,
+			agentutil.DiscardDispatcher{},
+			deployment.DeployOptionTimeout(100*time.Millisecond),
+			deployment.DeployOptionRetries(2, time.Millisecond),
+			deployment.DeployOptionDeployer(deployment.OperationFunc(func(ctx context.Context, p *agent.Peer) (ignored *agent.Deploy, err error) {
+				atomic.AddInt64(&attempts, 1)
+				return ignored, fmt.Errorf("boom")
+			})),
+		)
+
+		failures, success := deploy.Deploy(c)
+		Expect(failures).To(Equal(int64(1)))
+		Expect(success).To(BeFalse())
+		Expect(attempts).To(Equal(int64(3)))
+	})
+
+	It("should not retry a node that is no longer alive", func() {
+		var (
+			attempts int64
+			checks   int64
+		)
+
+		p := agent.NewPeer("node1")
+		c := cluster.New(p, clustering.NewMock(agent.PeerToNode(p)))
+
+		deploy := deployment.NewDeploy(
+			p,
+			agentutil.DiscardDispatcher{},
+			deployment.DeployOptionTimeout(100*time.Millisecond),
+			deployment.DeployOptionRetries(2, time.Millisecond),
+			deployment.DeployOptionChecker(deployment.OperationFunc(func(ctx context.Context, p *agent.Peer) (*agent.Deploy, error) {
+				// the node is alive for the initial readiness check only.
+				if atomic.AddInt64(&checks, 1) > 1 {
+					return nil, fmt.Errorf("unreachable")
+				}
+				return &agent.Deploy{Stage: agent.Deploy_Completed}, nil
+			})),
+			deployment.DeployOptionDeployer(deployment.OperationFunc(func(ctx context.Context, p *agent.Peer) (ignored *agent.Deploy, err error) {
+				atomic.AddInt64(&attempts, 1)
+				return ignored, fmt.Errorf("boom")
+			})),
+		)
+
+		failures, success := deploy.Deploy(c)
+		Expect(failures).To(Equal(int64(1)))
+		Expect(success).To(BeFalse())
+		Expect(attempts).To(Equal(int64(1)))
+	})
+
+	It("should produce a result for every node when simulated", func() {
+		var (
+			executed int64
+			deployed int64
+		)
+
+		p := agent.NewPeer("node4")
+		c := cluster.New(
+			p,
+			clustering.NewMock(
+				agent.PeerToNode(p),
+				clusteringtestutil.NewNodeFromAddress("node1", "127.0.0.1"),
+				clusteringtestutil.NewNodeFromAddress("node2", "127.0.0.2"),
+				clusteringtestutil.NewNodeFromAddress("node3", "127.0.0.3"),
+			),
+		)
+
+		coordinators := make(map[string]*deployment.Coordinator, len(c.Peers()))
+		for _, peer := range c.Peers() {
+			coord := deployment.New(
+				peer,
+				countingDeployer{count: &executed},
+				deployment.CoordinatorOptionRoot(testingx.TempDir()),
+				deployment.CoordinatorOptionStorage(storage.NoopRegistry{}),
+			)
+			coordinators[peer.Name] = &coord
+		}
+
+		dopts := &agent.DeployOptions{Timeout: int64(time.Minute), Simulate: true}
+		deploy := deployment.NewDeploy(
+			p,
+			agentutil.DiscardDispatcher{},
+			deployment.DeployOptionTimeout(time.Second),
+			deployment.DeployOptionPartitioner(bw.ConstantPartitioner(2)),
+			deployment.DeployOptionDeployer(deployment.OperationFunc(func(ctx context.Context, peer *agent.Peer) (*agent.Deploy, error) {
+				atomic.AddInt64(&deployed, 1)
+				return coordinators[peer.Name].Deploy(ctx, "test user", dopts, &agent.Archive{DeploymentID: bw.MustGenerateID(), Peer: peer})
+			})),
+			// the nodes last deploy failed, which is irrelevant to a simulation.
+			deployment.DeployOptionChecker(deployment.OperationSimulated(deployment.OperationFunc(func(ctx context.Context, peer *agent.Peer) (*agent.Deploy, error) {
+				return &failedDeploy, nil
+			}))),
+		)
+
+		failures, success := deploy.Deploy(c)
+		Expect(failures).To(Equal(int64(0)))
+		Expect(success).To(BeTrue())
+		Expect(deployed).To(Equal(int64(len(c.Peers()))))
+		Expect(executed).To(Equal(int64(0)))
+
+		for _, coord := range coordinators {
+			deploys, err := coord.Deployments()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(deploys).To(BeEmpty())
+		}
+	})
+
+	It("should abort the deploy once the maximum duration is exceeded", func() {
+		var (
+			deployCount int64
+		)
+
+		p := agent.NewPeer("node0")
+		nodes := []*memberlist.Node{}
+		for i := 1; i < 20; i++ {
+			nodes = append(nodes, clusteringtestutil.NewNodeFromAddress(fmt.Sprintf("node%d", i), fmt.Sprintf("127.0.0.%d", i)))
+		}
+		c := cluster.New(p, clustering.NewMock(agent.PeerToNode(p), nodes...))
+
+		deploy := deployment.NewDeploy(
+			p,
+			agentutil.DiscardDispatcher{},
+			deployment.DeployOptionTimeout(time.Second),
+			deployment.DeployOptionMaxTotal(200*time.Millisecond),
+			deployment.DeployOptionDeployer(deployment.OperationFunc(func(ctx context.Context, p *agent.Peer) (ignored *agent.Deploy, err error) {
+				atomic.AddInt64(&deployCount, 1)
+				select {
+				case <-time.After(50 * time.Millisecond):
+				case <-ctx.Done():
+				}
+				return ignored, nil
+			})),
+		)
+
+		started := time.Now()
+		_, success := deploy.Deploy(c)
+		Expect(success).To(BeFalse())
+		Expect(time.Since(started)).To(BeNumerically("<", time.Second))
+		Expect(atomic.LoadInt64(&deployCount)).To(And(BeNumerically(">", 0), BeNumerically("<", int64(len(c.Peers())))))
+	})
+
+	It("should halt the in progress partition when cancelled", func() {
+		var (
+			deployCount int64
+			halted      int64
+			completed   int64
+		)
+
+		p := agent.NewPeer("node0")
+		nodes := []*memberlist.Node{}
+		for i := 1; i < 10; i++ {
+			nodes = append(nodes, clusteringtestutil.NewNodeFromAddress(fmt.Sprintf("node%d", i), fmt.Sprintf("127.0.0.%d", i)))
+		}
+		c := cluster.New(p, clustering.NewMock(agent.PeerToNode(p), nodes...))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		deploy := deployment.NewDeploy(
+			p,
+			agentutil.DiscardDispatcher{},
+			deployment.DeployOptionContext(ctx),
+			deployment.DeployOptionTimeout(time.Second),
+			deployment.DeployOptionPartitioner(bw.ConstantPartitioner(2)),
+			deployment.DeployOptionDeployer(deployment.OperationFunc(func(ctx context.Context, p *agent.Peer) (ignored *agent.Deploy, err error) {
+				// cancel in the middle of the second partition.
+				if atomic.AddInt64(&deployCount, 1) > 2 {
+					cancel()
+					<-ctx.Done()
+					atomic.AddInt64(&halted, 1)
+					return ignored, ctx.Err()
+				}
+
+				atomic.AddInt64(&completed, 1)
+				return ignored, nil
+			})),
+		)
+
+		started := time.Now()
+		_, success := deploy.Deploy(c)
+		Expect(success).To(BeFalse())
+		Expect(time.Since(started)).To(BeNumerically("<", time.Second))
+		Expect(atomic.LoadInt64(&completed)).To(Equal(int64(2)))
+		Expect(atomic.LoadInt64(&halted)).To(And(BeNumerically(">", 0), BeNumerically("<=", 2)))
+		Expect(atomic.LoadInt64(&deployCount)).To(BeNumerically("<=", 4))
+	})
+
+	DescribeTable("should cap the nodes deployed to simultaneously by the limiter",
+		func(limit int, concurrency int, expected int64) {
+			var (
+				inflight int64
+				maximum  int64
+				total    int64
+			)
+
+			deployer := deployment.OperationFunc(func(ctx context.Context, p *agent.Peer) (ignored *agent.Deploy, err error) {
+				n := atomic.AddInt64(&inflight, 1)
+				defer atomic.AddInt64(&inflight, -1)
+				for m := atomic.LoadInt64(&maximum); n > m && !atomic.CompareAndSwapInt64(&maximum, m, n); m = atomic.LoadInt64(&maximum) {
+				}
+
+				atomic.AddInt64(&total, 1)
+				time.Sleep(20 * time.Millisecond)
+				return ignored, nil
+			})
+
+			p := agent.NewPeer("node0")
+			nodes := []*memberlist.Node{}
+			for i := 1; i <= 6; i++ {
+				nodes = append(nodes, clusteringtestutil.NewNodeFromAddress(fmt.Sprintf("node%d", i), fmt.Sprintf("127.0.3.%d", i)))
+			}
+			c := cluster.New(p, clustering.NewMock(agent.PeerToNode(p), nodes...))
+
+			_, success := deployment.NewDeploy(
+				p,
+				agentutil.DiscardDispatcher{},
+				deployment.DeployOptionTimeout(time.Second),
+				deployment.DeployOptionPartitioner(bw.ConstantPartitioner(concurrency)),
+				deployment.DeployOptionLimiter(deployment.NewLimiter(limit)),
+				deployment.DeployOptionDeployer(deployer),
+			).Deploy(c)
+
+			Expect(success).To(BeTrue())
+			Expect(atomic.LoadInt64(&total)).To(Equal(int64(len(c.Peers()))))
+			Expect(atomic.LoadInt64(&maximum)).To(Equal(expected))
+		},
+		Entry("clamp the concurrency requested by the client", 2, 6, int64(2)),
+		Entry("retain a concurrency below the limit", 4, 3, int64(3)),
+		Entry("unbounded", 0, 6, int64(6)),
+	)
+
+	It("should deploy to each class of nodes using the concurrency of its label", func() {
+		var (
+			deployCount int64
+			inflight    = map[string]*int64{"big": new(int64), "": new(int64), "total": new(int64)}
+			maximum     = map[string]*int64{"big": new(int64), "": new(int64), "total": new(int64)}
+		)
+
+		track := func(class string) func() {
+			current := atomic.AddInt64(inflight[class], 1)
+			for observed := atomic.LoadInt64(maximum[class]); current > observed && !atomic.CompareAndSwapInt64(maximum[class], observed, current); observed = atomic.LoadInt64(maximum[class]) {
+			}
+			return func() { atomic.AddInt64(inflight[class], -1) }
+		}
+
+		p := agent.NewPeer("node0", agent.PeerOptionIP(net.ParseIP("127.0.0.1")))
+		nodes := []*memberlist.Node{}
+		for i := 1; i <= 4; i++ {
+			nodes = append(nodes, agent.PeerToNode(agent.NewPeer(fmt.Sprintf("big%d", i), agent.PeerOptionIP(net.ParseIP(fmt.Sprintf("127.0.1.%d", i))), agent.PeerOptionLabels("big"))))
+		}
+		for i := 1; i <= 3; i++ {
+			nodes = append(nodes, agent.PeerToNode(agent.NewPeer(fmt.Sprintf("small%d", i), agent.PeerOptionIP(net.ParseIP(fmt.Sprintf("127.0.2.%d", i))), agent.PeerOptionLabels("small"))))
+		}
+		c := cluster.New(p, clustering.NewMock(agent.PeerToNode(p), nodes...))
+		partitions, sizes := partitionSizes()
+
+		deploy := deployment.NewDeploy(
+			p,
+			agentutil.DiscardDispatcher{},
+			deployment.DeployOptionTimeout(time.Second),
+			deployment.DeployOptionPartitioner(agent.NewLabelPartitioner(bw.ConstantPartitioner(3), map[string]float64{"big": 2})),
+			deployment.DeployOptionDeployer(deployment.OperationFunc(func(ctx context.Context, p *agent.Peer) (ignored *agent.Deploy, err error) {
+				class := ""
+				if len(p.Labels) > 0 && p.Labels[0] == "big" {
+					class = "big"
+				}
+
+				atomic.AddInt64(&deployCount, 1)
+				defer track(class)()
+				defer track("total")()
+
+				time.Sleep(50 * time.Millisecond)
+				return ignored, nil
+			})),
+		)
+
+		failures, success := deploy.Deploy(c)
+		Expect(failures).To(Equal(int64(0)))
+		Expect(success).To(BeTrue())
+		Expect(deployCount).To(Equal(int64(len(c.Peers()))))
+		Expect(atomic.LoadInt64(maximum["big"])).To(Equal(int64(2)))
+		Expect(atomic.LoadInt64(maximum[""])).To(BeNumerically("<=", 3))
+		// the concurrency of the deploy caps the nodes across the classes.
+		Expect(atomic.LoadInt64(maximum["total"])).To(Equal(int64(3)))
+
+		// the partition size is the nodes of the partition, not the concurrency of the partition.
+		observed, total := partitionSizes()
+		Expect(observed - partitions).To(Equal(uint64(2)))
+		Expect(total - sizes).To(Equal(float64(len(c.Peers()))))
+	})
 })
@@ -1,6 +1,7 @@
 package deployment
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
@@ -64,22 +65,16 @@ func (t Directive) Deploy(dctx *DeployContext) {
 
 func (t Directive) deploy(dctx *DeployContext) {
 	var (
-		err      error
-		dinterp  directives.InterpLoader
-		dfs      directives.ArchiveLoader
-		dshell   directives.ShellLoader
-		loaded   []directives.Loaded
-		environ  []string
-		tmpdir   string
-		cachedir string
+		err     error
+		dinterp directives.InterpLoader
+		dfs     directives.ArchiveLoader
+		dshell  directives.ShellLoader
+		loaded  []directives.Loaded
+		sctx    shell.Context
+		tmpdir  string
 	)
 
-	if environ, err = shell.EnvironFromFile(filepath.Join(dctx.ArchiveRoot, bw.EnvFile)); err != nil {
-		errorsx.MaybeLog(dctx.Done(err))
-		return
-	}
-
-	if tmpdir, err = mkdirTemp(dctx.TempRoot, ".bw-tmp-*"); err != nil {
+	if sctx, tmpdir, err = t.shell(dctx); err != nil {
 		errorsx.MaybeLog(dctx.Done(err))
 		return
 	}
@@ -87,28 +82,13 @@ func (t Directive) deploy(dctx *DeployContext) {
 		errorsx.MaybeLog(dctx.Done(errorsx.Compact(err, os.RemoveAll(tmpdir))))
 	}
 
-	cachedir = filepath.Join(dctx.CacheRoot, bw.DirCache)
-	if err = os.MkdirAll(cachedir, 0750); err != nil {
-		errorsx.MaybeLog(dctx.Done(err))
-		return
-	}
-
 	dc := directives.Context{
 		RootDirectory: dctx.Root,
 		Log:           dctx.Log,
 	}
 
 	dshell = directives.ShellLoader{
-		Context: shell.NewContext(
-			t.sctx,
-			shell.OptionDeployID(dctx.ID.String()),
-			shell.OptionLogger(dctx.Log),
-			shell.OptionEnviron(append(t.sctx.Environ, environ...)),
-			shell.OptionDir(dctx.ArchiveRoot),
-			shell.OptionVCSCommit(dctx.Archive.Commit),
-			shell.OptionTempDir(tmpdir),
-			shell.OptionCacheDir(cachedir),
-		),
+		Context: sctx,
 	}
 
 	dfs = directives.ArchiveLoader{
@@ -152,3 +132,38 @@ func (t Directive) deploy(dctx *DeployContext) {
 	}
 	done(err)
 }
+
+// shell builds the shell context for the deploy, the temporary directory
+// is the responsibility of the caller to cleanup.
+func (t Directive) shell(dctx *DeployContext) (sctx shell.Context, tmpdir string, err error) {
+	var (
+		environ  []string
+		cachedir string
+	)
+
+	if environ, err = shell.EnvironFromFile(filepath.Join(dctx.ArchiveRoot, bw.EnvFile)); err != nil {
+		return sctx, tmpdir, err
+	}
+
+	if tmpdir, err = mkdirTemp(dctx.TempRoot, ".bw-tmp-*"); err != nil {
+		return sctx, tmpdir, err
+	}
+
+	cachedir = filepath.Join(dctx.CacheRoot, bw.DirCache)
+	if err = os.MkdirAll(cachedir, 0750); err != nil {
+		return sctx, tmpdir, errorsx.Compact(err, os.RemoveAll(tmpdir))
+	}
+
+	return shell.NewContext(
+		t.sctx,
+		shell.OptionDeployID(dctx.ID.String()),
+		shell.OptionLogger(dctx.Log),
+		shell.OptionEnviron(append(t.sctx.Environ, environ...)),
+		shell.OptionAppendEnviron(fmt.Sprintf("%s=%d", bw.EnvDeployPerNodeConcurrency, dctx.DeployOptions.GetPerNodeConcurrency())),
+		shell.OptionConcurrency(int(dctx.DeployOptions.GetPerNodeConcurrency())),
+		shell.OptionDir(dctx.ArchiveRoot),
+		shell.OptionVCSCommit(dctx.Archive.Commit),
+		shell.OptionTempDir(tmpdir),
+		shell.OptionCacheDir(cachedir),
+	), tmpdir, nil
+}
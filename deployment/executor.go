@@ -0,0 +1,194 @@
+package deployment
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/james-lawrence/bw/agent"
+	"github.com/james-lawrence/bw/directives/shell"
+	"github.com/james-lawrence/bw/internal/errorsx"
+	"github.com/james-lawrence/bw/internal/iox"
+	"github.com/james-lawrence/bw/internal/stringsx"
+	"github.com/pkg/errors"
+)
+
+// directory within the cache root the systemd executor installs units into.
+const systemdDirName = "systemd"
+
+// Executor runs a deploy on the local node.
+type Executor interface {
+	Deploy(dctx *DeployContext)
+}
+
+// NewExecutors the built in executors, the executor used for a deploy is selected by
+// the deploy options. the options are shared by all the executors.
+func NewExecutors(options ...DirectiveOption) Executors {
+	return Executors{
+		executors: map[string]Executor{
+			agent.ExecutorDirective: NewDirective(options...),
+			agent.ExecutorShell:     NewShellExecutor(options...),
+			agent.ExecutorSystemd:   NewSystemdExecutor(options...),
+			agent.ExecutorCompose:   NewComposeExecutor(options...),
+		},
+	}
+}
+
+// Executors selects the executor to use for a deploy, defaults to the directive executor.
+type Executors struct {
+	executors map[string]Executor
+}
+
+// Deploy using the executor specified by the deploy options.
+func (t Executors) Deploy(dctx *DeployContext) {
+	name := stringsx.DefaultIfBlank(dctx.DeployOptions.Executor, agent.ExecutorDirective)
+	e, ok := t.executors[name]
+	if !ok {
+		// reported asynchronously like every other executor, the result is awaited by the caller.
+		go func() {
+			errorsx.MaybeLog(dctx.Done(errors.Errorf("unknown deploy executor: %s", name)))
+		}()
+		return
+	}
+
+	e.Deploy(dctx)
+}
+
+// NewShellExecutor runs the deploy.sh script from the directive directory.
+// the script must be executable.
+func NewShellExecutor(options ...DirectiveOption) Executor {
+	return commands{
+		Directive: NewDirective(options...),
+		plan: func(dctx *DeployContext, root string) ([]shell.Exec, error) {
+			script := filepath.Join(root, "deploy.sh")
+			if _, err := os.Stat(script); err != nil {
+				return nil, errors.Wrap(err, "missing deploy script")
+			}
+
+			return []shell.Exec{{Command: quote(script)}}, nil
+		},
+	}
+}
+
+// NewSystemdExecutor copies the systemd service units from the directive directory into
+// the agent's systemd directory, links them, reloads systemd, and then restarts each of the units.
+// the units are restarted in parallel, bounded by the per node concurrency of the deploy.
+// the units are copied because the deploy directory is removed once it is no longer retained.
+func NewSystemdExecutor(options ...DirectiveOption) Executor {
+	return commands{
+		Directive: NewDirective(options...),
+		plan: func(dctx *DeployContext, root string) (cmds []shell.Exec, err error) {
+			var (
+				units []string
+			)
+
+			if units, err = filepath.Glob(filepath.Join(root, "*.service")); err != nil {
+				return nil, errors.WithStack(err)
+			}
+
+			if len(units) == 0 {
+				return nil, errors.Errorf("no systemd units found: %s", root)
+			}
+
+			sort.Strings(units)
+
+			dir := filepath.Join(dctx.CacheRoot, systemdDirName)
+			if err = os.MkdirAll(dir, 0755); err != nil {
+				return nil, errors.Wrap(err, "failed to create systemd unit directory")
+			}
+
+			for _, unit := range units {
+				dst := filepath.Join(dir, filepath.Base(unit))
+				if err = iox.Copy(unit, dst); err != nil {
+					return nil, errors.Wrapf(err, "failed to copy systemd unit: %s", unit)
+				}
+
+				cmds = append(cmds, shell.Exec{Command: "systemctl link " + quote(dst)})
+			}
+
+			cmds = append(cmds, shell.Exec{Command: "systemctl daemon-reload"})
+
+			for _, unit := range units {
+				cmds = append(cmds, shell.Exec{Command: "systemctl restart " + quote(filepath.Base(unit)), Parallel: true})
+			}
+
+			return cmds, nil
+		},
+	}
+}
+
+// NewComposeExecutor brings up the docker compose project from the directive directory,
+// the per node concurrency of the deploy bounds the parallelism of compose.
+func NewComposeExecutor(options ...DirectiveOption) Executor {
+	return commands{
+		Directive: NewDirective(options...),
+		plan: func(dctx *DeployContext, root string) ([]shell.Exec, error) {
+			for _, name := range []string{"compose.yaml", "compose.yml", "docker-compose.yaml", "docker-compose.yml"} {
+				path := filepath.Join(root, name)
+				if _, err := os.Stat(path); err != nil {
+					continue
+				}
+
+				compose := "docker compose -f " + quote(path)
+				if n := dctx.DeployOptions.GetPerNodeConcurrency(); n > 0 {
+					compose = fmt.Sprintf("docker compose --parallel %d -f %s", n, quote(path))
+				}
+
+				return []shell.Exec{
+					{Command: compose + " pull"},
+					{Command: compose + " up --detach --remove-orphans"},
+				}, nil
+			}
+
+			return nil, errors.Errorf("no compose file found: %s", root)
+		},
+	}
+}
+
+// commands executes the planned shell commands.
+type commands struct {
+	Directive
+	plan func(dctx *DeployContext, root string) ([]shell.Exec, error)
+}
+
+// Deploy ...
+func (t commands) Deploy(dctx *DeployContext) {
+	for _, opt := range t.options {
+		opt(&t.Directive)
+	}
+
+	go t.deploy(dctx)
+}
+
+func (t commands) deploy(dctx *DeployContext) {
+	var (
+		err    error
+		sctx   shell.Context
+		tmpdir string
+		cmds   []shell.Exec
+	)
+
+	if sctx, tmpdir, err = t.shell(dctx); err != nil {
+		errorsx.MaybeLog(dctx.Done(err))
+		return
+	}
+	defer func() {
+		errorsx.MaybeLog(dctx.Done(errorsx.Compact(err, os.RemoveAll(tmpdir))))
+	}()
+
+	root := filepath.Join(dctx.ArchiveRoot, t.directory)
+	if cmds, err = t.plan(dctx, root); err != nil {
+		err = errors.Wrap(err, "failed to plan deploy")
+		return
+	}
+
+	dctx.Log.Println("---------------------- DURATION", dctx.timeout(), "----------------------")
+	err = shell.Execute(dctx.deadline, sctx, cmds...)
+}
+
+// quote a value for use as a single shell word.
+func quote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
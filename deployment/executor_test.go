@@ -0,0 +1,215 @@
+package deployment_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/james-lawrence/bw"
+	"github.com/james-lawrence/bw/agent"
+	"github.com/james-lawrence/bw/deployment"
+	"github.com/james-lawrence/bw/directives/shell"
+	"github.com/james-lawrence/bw/internal/testingx"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Executors", func() {
+	var (
+		workdir  string
+		recorded string
+		sctx     shell.Context
+	)
+
+	// deployspace creates the fixture files within the remote directory of the deployspace.
+	deployspace := func(files ...string) {
+		Expect(os.MkdirAll(filepath.Join(workdir, deployment.RemoteDirName), 0755)).To(Succeed())
+		for _, name := range files {
+			Expect(os.WriteFile(filepath.Join(workdir, deployment.RemoteDirName, name), []byte("#!/bin/sh\n"), 0755)).To(Succeed())
+		}
+	}
+
+	// run the deploy using the named executor and return the commands that were executed.
+	runc := func(executor string, concurrency int64) ([]string, error) {
+		dctx, err := deployment.NewDeployContext(
+			context.Background(),
+			workdir,
+			agent.NewPeer("node1"),
+			"test user",
+			&agent.DeployOptions{Timeout: int64(time.Minute), Executor: executor, PerNodeConcurrency: concurrency},
+			&agent.Archive{DeploymentID: bw.MustGenerateID()},
+			deployment.DeployContextOptionDisableReset,
+		)
+		Expect(err).ToNot(HaveOccurred())
+
+		deployment.NewExecutors(deployment.DirectiveOptionShellContext(sctx)).Deploy(dctx)
+		result := deployment.AwaitDeployResult(dctx)
+
+		raw, err := os.ReadFile(recorded)
+		if os.IsNotExist(err) {
+			return nil, result.Error
+		}
+		Expect(err).ToNot(HaveOccurred())
+
+		return strings.Split(strings.TrimSpace(string(raw)), "\n"), result.Error
+	}
+
+	run := func(executor string) ([]string, error) {
+		return runc(executor, 0)
+	}
+
+	BeforeEach(func() {
+		var err error
+		// absolute paths, the shell is executed from within the archive directory.
+		workdir, err = filepath.Abs(testingx.TempDir())
+		Expect(err).ToNot(HaveOccurred())
+		recorded = filepath.Join(workdir, "commands.log")
+
+		// the shell records the commands instead of executing them.
+		recorder := filepath.Join(workdir, "recorder.sh")
+		Expect(os.WriteFile(recorder, []byte("#!/bin/sh\necho \"$2\" >> "+recorded+"\n"), 0755)).To(Succeed())
+
+		sctx, err = shell.DefaultContext()
+		Expect(err).ToNot(HaveOccurred())
+		sctx.Shell = recorder
+	})
+
+	It("should run the deploy script with the shell executor", func() {
+		deployspace("deploy.sh")
+
+		cmds, err := run(agent.ExecutorShell)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cmds).To(Equal([]string{
+			"'" + filepath.Join(workdir, deployment.RemoteDirName, "deploy.sh") + "'",
+		}))
+	})
+
+	It("should install, link and restart the units with the systemd executor", func() {
+		deployspace("worker.service", "api.service", "README.md")
+		// units are installed outside of the deploy directory.
+		installed := filepath.Join(workdir, "systemd")
+
+		cmds, err := run(agent.ExecutorSystemd)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cmds).To(HaveLen(5))
+		Expect(cmds[:3]).To(Equal([]string{
+			"systemctl link '" + filepath.Join(installed, "api.service") + "'",
+			"systemctl link '" + filepath.Join(installed, "worker.service") + "'",
+			"systemctl daemon-reload",
+		}))
+		// the units are restarted in parallel.
+		Expect(cmds[3:]).To(ConsistOf(
+			"systemctl restart 'api.service'",
+			"systemctl restart 'worker.service'",
+		))
+		Expect(filepath.Join(installed, "api.service")).To(BeAnExistingFile())
+		Expect(filepath.Join(installed, "worker.service")).To(BeAnExistingFile())
+		Expect(filepath.Join(installed, "README.md")).ToNot(BeAnExistingFile())
+	})
+
+	It("should bring up the project with the compose executor", func() {
+		deployspace("docker-compose.yml")
+		compose := filepath.Join(workdir, deployment.RemoteDirName, "docker-compose.yml")
+
+		cmds, err := run(agent.ExecutorCompose)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cmds).To(Equal([]string{
+			"docker compose -f '" + compose + "' pull",
+			"docker compose -f '" + compose + "' up --detach --remove-orphans",
+		}))
+	})
+
+	It("should bound the parallelism of compose by the per node concurrency", func() {
+		deployspace("compose.yaml")
+		compose := filepath.Join(workdir, deployment.RemoteDirName, "compose.yaml")
+
+		cmds, err := runc(agent.ExecutorCompose, 2)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cmds).To(Equal([]string{
+			"docker compose --parallel 2 -f '" + compose + "' pull",
+			"docker compose --parallel 2 -f '" + compose + "' up --detach --remove-orphans",
+		}))
+	})
+
+	It("should fail when the deployspace is missing the executor's files", func() {
+		deployspace()
+
+		cmds, err := run(agent.ExecutorSystemd)
+		Expect(err).To(HaveOccurred())
+		Expect(cmds).To(BeEmpty())
+	})
+
+	It("should fail for unknown executors", func() {
+		_, err := run("unknown")
+		Expect(err).To(MatchError(ContainSubstring("unknown deploy executor")))
+	})
+})
+
+var _ = Describe("PerNodeConcurrency", func() {
+	// run the parallel commands of a directive with the real shell and return the maximum
+	// number of commands observed running simultaneously.
+	run := func(concurrency int64, commands int) int {
+		workdir, err := filepath.Abs(testingx.TempDir())
+		Expect(err).ToNot(HaveOccurred())
+		running := filepath.Join(workdir, "running")
+		observed := filepath.Join(workdir, "observed")
+		Expect(os.MkdirAll(running, 0755)).To(Succeed())
+		Expect(os.MkdirAll(filepath.Join(workdir, deployment.RemoteDirName), 0755)).To(Succeed())
+
+		directive := ""
+		for i := 0; i < commands; i++ {
+			marker := filepath.Join(running, strconv.Itoa(i))
+			directive += fmt.Sprintf(
+				"- command: \"touch %s; ls %s | wc -l >> %s; sleep 0.2; rm %s\"\n  parallel: true\n",
+				marker, running, observed, marker,
+			)
+		}
+		Expect(os.WriteFile(filepath.Join(workdir, deployment.RemoteDirName, "restart.bwcmd"), []byte(directive), 0600)).To(Succeed())
+
+		sctx, err := shell.DefaultContext()
+		Expect(err).ToNot(HaveOccurred())
+
+		dctx, err := deployment.NewDeployContext(
+			context.Background(),
+			workdir,
+			agent.NewPeer("node1"),
+			"test user",
+			&agent.DeployOptions{Timeout: int64(time.Minute), Executor: agent.ExecutorDirective, PerNodeConcurrency: concurrency},
+			&agent.Archive{DeploymentID: bw.MustGenerateID()},
+			deployment.DeployContextOptionDisableReset,
+		)
+		Expect(err).ToNot(HaveOccurred())
+
+		deployment.NewExecutors(deployment.DirectiveOptionShellContext(sctx)).Deploy(dctx)
+		Expect(deployment.AwaitDeployResult(dctx).Error).ToNot(HaveOccurred())
+
+		raw, err := os.ReadFile(observed)
+		Expect(err).ToNot(HaveOccurred())
+
+		maximum := 0
+		counts := strings.Fields(string(raw))
+		Expect(counts).To(HaveLen(commands))
+		for _, c := range counts {
+			n, err := strconv.Atoi(c)
+			Expect(err).ToNot(HaveOccurred())
+			if n > maximum {
+				maximum = n
+			}
+		}
+
+		return maximum
+	}
+
+	It("should limit the parallel commands of the directives to the per node concurrency", func() {
+		Expect(run(2, 6)).To(BeNumerically("==", 2))
+	})
+
+	It("should run the parallel commands simultaneously when unbounded", func() {
+		Expect(run(0, 4)).To(BeNumerically(">", 2))
+	})
+})
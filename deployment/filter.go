@@ -2,6 +2,7 @@ package deployment
 
 import (
 	"net"
+	"path"
 	"regexp"
 
 	"github.com/james-lawrence/bw/agent"
@@ -19,6 +20,20 @@ func Named(r *regexp.Regexp) Filter {
 	})
 }
 
+// Glob matches an agent.Peer by name against any of the patterns, see path.Match
+// for the syntax. malformed patterns never match.
+func Glob(patterns ...string) Filter {
+	return FilterFunc(func(i *agent.Peer) bool {
+		for _, pattern := range patterns {
+			if matched, _ := path.Match(pattern, i.Name); matched {
+				return true
+			}
+		}
+
+		return false
+	})
+}
+
 // IP matches an agent.Peer by ip.
 func IP(ip net.IP) Filter {
 	return FilterFunc(func(i *agent.Peer) bool {
@@ -0,0 +1,46 @@
+package deployment
+
+import (
+	"context"
+)
+
+// NewLimiter bounds the number of in flight node operations to n. n <= 0 returns a nil limiter
+// which is unbounded.
+func NewLimiter(n int) *Limiter {
+	if n <= 0 {
+		return nil
+	}
+
+	return &Limiter{sem: make(chan struct{}, n)}
+}
+
+// Limiter semaphore capping the number of nodes being deployed to simultaneously, regardless
+// of the concurrency requested by the deploy. the limiter is held in memory by the raft leader,
+// which only runs a single deploy at a time, concurrent deploys are rejected as busy.
+// a nil limiter is unbounded.
+type Limiter struct {
+	sem chan struct{}
+}
+
+// Acquire a slot, blocking until one is available or the context is done.
+func (t *Limiter) Acquire(ctx context.Context) error {
+	if t == nil {
+		return nil
+	}
+
+	select {
+	case t.sem <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Release a previously acquired slot.
+func (t *Limiter) Release() {
+	if t == nil {
+		return
+	}
+
+	<-t.sem
+}
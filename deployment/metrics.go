@@ -0,0 +1,31 @@
+package deployment
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	metricDeploysStarted = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "bw_deploys_started_total",
+		Help: "deploys to the cluster initiated by this agent.",
+	})
+	metricDeploysSucceeded = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "bw_deploys_succeeded_total",
+		Help: "deploys to the cluster that succeeded.",
+	})
+	metricDeploysFailed = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "bw_deploys_failed_total",
+		Help: "deploys to the cluster that failed, timed out, or were cancelled.",
+	})
+	metricDeployDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "bw_deploy_duration_seconds",
+		Help:    "duration of the deploys to the cluster.",
+		Buckets: []float64{.1, .5, 1, 5, 10, 30, 60, 300, 600, 1800},
+	})
+	metricPartitionSize = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "bw_deploy_partition_size",
+		Help:    "nodes assigned to each partition of a deploy.",
+		Buckets: []float64{1, 2, 5, 10, 25, 50, 100, 250},
+	})
+)
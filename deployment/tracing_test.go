@@ -0,0 +1,107 @@
+package deployment_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/memberlist"
+	"github.com/james-lawrence/bw"
+	"github.com/james-lawrence/bw/agent"
+	"github.com/james-lawrence/bw/agentutil"
+	"github.com/james-lawrence/bw/cluster"
+	"github.com/james-lawrence/bw/clustering"
+	"github.com/james-lawrence/bw/clustering/clusteringtestutil"
+	"github.com/james-lawrence/bw/deployment"
+	"github.com/james-lawrence/bw/internal/tracex"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/grpc/metadata"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// named spans recorded by the exporter.
+func named(exporter *tracetest.InMemoryExporter, name string) (results tracetest.SpanStubs) {
+	for _, s := range exporter.GetSpans() {
+		if s.Name == name {
+			results = append(results, s)
+		}
+	}
+
+	return results
+}
+
+var _ = Describe("Deploy tracing", func() {
+	var (
+		exporter *tracetest.InMemoryExporter
+	)
+
+	BeforeEach(func() {
+		exporter = tracetest.NewInMemoryExporter()
+		DeferCleanup(tracex.Register(exporter))
+	})
+
+	deploy := func(tracing bool, deployer deployment.OperationFunc) cluster.Cluster {
+		p := agent.NewPeer("node0")
+		nodes := []*memberlist.Node{}
+		for i := 1; i <= 3; i++ {
+			nodes = append(nodes, clusteringtestutil.NewNodeFromAddress(fmt.Sprintf("node%d", i), fmt.Sprintf("127.0.3.%d", i)))
+		}
+		c := cluster.New(p, clustering.NewMock(agent.PeerToNode(p), nodes...))
+
+		_, success := deployment.NewDeploy(
+			p,
+			agentutil.DiscardDispatcher{},
+			deployment.DeployOptionTimeout(time.Second),
+			deployment.DeployOptionPartitioner(bw.ConstantPartitioner(2)),
+			deployment.DeployOptionTracing(tracing),
+			deployment.DeployOptionDeployer(deployer),
+		).Deploy(c)
+		Expect(success).To(BeTrue())
+
+		return c
+	}
+
+	It("should produce a deploy span with a child span for each node", func() {
+		var (
+			m          sync.Mutex
+			propagated []string
+		)
+
+		c := deploy(true, func(ctx context.Context, p *agent.Peer) (ignored *agent.Deploy, err error) {
+			md, _ := metadata.FromOutgoingContext(tracex.Outgoing(ctx))
+			m.Lock()
+			propagated = append(propagated, md.Get("traceparent")...)
+			m.Unlock()
+			return ignored, nil
+		})
+
+		parents := named(exporter, "deploy")
+		Expect(parents).To(HaveLen(1))
+
+		children := named(exporter, "deploy.node")
+		Expect(children).To(HaveLen(len(c.Peers())))
+		for _, child := range children {
+			Expect(child.Parent.SpanID()).To(Equal(parents[0].SpanContext.SpanID()))
+			Expect(child.SpanContext.TraceID()).To(Equal(parents[0].SpanContext.TraceID()))
+			Expect(child.Attributes).To(ContainElement(HaveField("Key", attribute.Key("peer.name"))))
+		}
+
+		// the span of each node is propagated to the rpc initiating the deploy on the node.
+		Expect(propagated).To(HaveLen(len(c.Peers())))
+	})
+
+	It("should not record spans when tracing is disabled", func() {
+		deploy(false, func(ctx context.Context, p *agent.Peer) (ignored *agent.Deploy, err error) {
+			md, _ := metadata.FromOutgoingContext(tracex.Outgoing(ctx))
+			Expect(md.Get("traceparent")).To(BeEmpty())
+			return ignored, nil
+		})
+
+		Expect(named(exporter, "deploy")).To(BeEmpty())
+		Expect(named(exporter, "deploy.node")).To(BeEmpty())
+	})
+})
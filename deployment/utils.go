@@ -27,6 +27,29 @@ func less(a, b *agent.Deploy) bool {
 	return a.Archive.Dts > b.Archive.Dts
 }
 
+// lastSuccessfulDeploy the most recently completed deploy within the root, nil when none have completed.
+func lastSuccessfulDeploy(root string) (latest *agent.Deploy, err error) {
+	var (
+		deployments []*agent.Deploy
+	)
+
+	if deployments, err = readAllDeployMetadata(root); err != nil {
+		return nil, err
+	}
+
+	for _, d := range deployments {
+		if d.Stage != agent.Deploy_Completed || d.Archive == nil {
+			continue
+		}
+
+		if latest == nil || d.Archive.Dts > latest.Archive.Dts {
+			latest = d
+		}
+	}
+
+	return latest, nil
+}
+
 func readAllDeployMetadata(root string) ([]*agent.Deploy, error) {
 	deployments := make([]*agent.Deploy, 0, 10)
 	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
@@ -130,6 +130,13 @@ func OptionTimeout(d time.Duration) Option {
 	}
 }
 
+// OptionConcurrency limit the simultaneous parallel commands, <= 0 is unbounded. see Exec.Parallel.
+func OptionConcurrency(n int) Option {
+	return func(ctx *Context) {
+		ctx.concurrency = n
+	}
+}
+
 // OptionDeployID the id of the current deployment
 func OptionDeployID(id string) Option {
 	return func(ctx *Context) {
@@ -172,6 +179,7 @@ type Context struct {
 	cachedir      string
 	timeout       time.Duration
 	lenient       bool
+	concurrency   int
 }
 
 func (t Context) variableSubst(cmd string) string {
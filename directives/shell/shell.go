@@ -21,6 +21,7 @@ import (
 	"os"
 	"os/exec"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/james-lawrence/bw"
@@ -41,6 +42,9 @@ type Exec struct {
 	Environ string
 	WorkDir string   `yaml:"directory"`
 	LoadEnv []string `yaml:"loadenv"`
+	// run simultaneously with the adjacent parallel commands, bounded by the
+	// concurrency of the context. e.g.) restarting independent services.
+	Parallel bool `yaml:"parallel"`
 }
 
 func (t Exec) execute(ctx context.Context, sctx Context) error {
@@ -112,17 +116,59 @@ func (t Exec) retry(ctx Context, do func() error) (err error) {
 
 // Execute ...
 func Execute(ctx context.Context, sctx Context, commands ...Exec) error {
-	for _, c := range commands {
-		fmt.Fprintln(sctx.output, "executing", sctx.Shell, "-c", c.Command)
-		if err := c.execute(ctx, sctx); err != nil {
-			return errors.Wrapf(err, "failed to execute: '%s'", c.Command)
+	for len(commands) > 0 {
+		n := 1
+		for commands[0].Parallel && n < len(commands) && commands[n].Parallel {
+			n++
 		}
-		fmt.Fprintln(sctx.output, "completed", sctx.Shell, "-c", c.Command)
+
+		if err := parallel(ctx, sctx, commands[:n]...); err != nil {
+			return err
+		}
+
+		commands = commands[n:]
 	}
 
 	return nil
 }
 
+// parallel executes the commands simultaneously bounded by the concurrency of the context,
+// waits for every command to complete.
+func parallel(ctx context.Context, sctx Context, commands ...Exec) (err error) {
+	var (
+		m    sync.Mutex
+		wait sync.WaitGroup
+	)
+
+	limit := sctx.concurrency
+	if limit <= 0 || limit > len(commands) {
+		limit = len(commands)
+	}
+
+	semaphore := make(chan struct{}, limit)
+	for _, c := range commands {
+		semaphore <- struct{}{}
+		wait.Add(1)
+		go func(c Exec) {
+			defer wait.Done()
+			defer func() { <-semaphore }()
+
+			fmt.Fprintln(sctx.output, "executing", sctx.Shell, "-c", c.Command)
+			if cause := c.execute(ctx, sctx); cause != nil {
+				m.Lock()
+				err = errorsx.Compact(err, errors.Wrapf(cause, "failed to execute: '%s'", c.Command))
+				m.Unlock()
+				return
+			}
+			fmt.Fprintln(sctx.output, "completed", sctx.Shell, "-c", c.Command)
+		}(c)
+	}
+
+	wait.Wait()
+
+	return err
+}
+
 // ParseYAML ...
 func ParseYAML(r io.Reader) ([]Exec, error) {
 	var (
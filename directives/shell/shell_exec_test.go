@@ -7,10 +7,13 @@ import (
 	"io"
 	"os"
 	"os/user"
+	"path/filepath"
 	"time"
 
 	"github.com/onsi/ginkgo/v2"
 
+	"github.com/james-lawrence/bw/internal/testingx"
+
 	. "github.com/onsi/gomega"
 )
 
@@ -48,4 +51,41 @@ var _ = ginkgo.Describe("Shell", func() {
 			ginkgo.Entry("retries", ctx1, errors.New("signal: killed"), "command failed after 5 attempts sleep 0.01 signal: killed\n", Exec{Command: "sleep 0.01", Timeout: 2 * time.Millisecond, Retries: 5}),
 		)
 	})
+
+	ginkgo.Context("Execute parallel commands", func() {
+		var ctx Context
+
+		ginkgo.BeforeEach(func() {
+			ctx = Context{
+				Shell:   os.Getenv("SHELL"),
+				Environ: os.Environ(),
+				dir:     testingx.TempDir(),
+				timeout: time.Second,
+				output:  io.Discard,
+			}
+		})
+
+		ginkgo.It("should complete the parallel group before the following command", func() {
+			Expect(Execute(
+				context.Background(),
+				ctx,
+				Exec{Command: "sleep 0.1; touch a", Parallel: true},
+				Exec{Command: "sleep 0.1; touch b", Parallel: true},
+				Exec{Command: "test -f a && test -f b"},
+			)).To(Succeed())
+		})
+
+		ginkgo.It("should not run the following commands when a parallel command fails", func() {
+			err := Execute(
+				context.Background(),
+				ctx,
+				Exec{Command: "false", Parallel: true},
+				Exec{Command: "touch a", Parallel: true},
+				Exec{Command: "touch b"},
+			)
+			Expect(err).To(MatchError(ContainSubstring("failed to execute: 'false'")))
+			Expect(filepath.Join(ctx.dir, "a")).To(BeAnExistingFile())
+			Expect(filepath.Join(ctx.dir, "b")).ToNot(BeAnExistingFile())
+		})
+	})
 })
@@ -7,11 +7,12 @@ import (
 
 	"github.com/hashicorp/memberlist"
 	"github.com/james-lawrence/bw/agent"
+	"github.com/james-lawrence/bw/clustering/rendezvous"
 	"github.com/miekg/dns"
 )
 
 type cluster interface {
-	GetN(n int, key []byte) []*memberlist.Node
+	Members() []*memberlist.Node
 }
 
 // Option set common options for DNS managers.
@@ -38,10 +39,18 @@ func OptionTTL(ttl uint32) Option {
 	}
 }
 
+// OptionHealthyOnly only publish the nodes memberlist considers alive.
+func OptionHealthyOnly(b bool) Option {
+	return func(c *config) {
+		c.HealthyOnly = b
+	}
+}
+
 type config struct {
 	MaximumNodes int
 	FQDN         string
 	TTL          uint32
+	HealthyOnly  bool
 }
 
 func (t config) merge(options ...Option) config {
@@ -52,6 +61,23 @@ func (t config) merge(options ...Option) config {
 	return t
 }
 
+// sample the peers to publish, unhealthy nodes are removed before sampling
+// to ensure the records are filled with healthy nodes when available.
+func (t config) sample(c cluster) []*agent.Peer {
+	nodes := c.Members()
+	if t.HealthyOnly {
+		healthy := make([]*memberlist.Node, 0, len(nodes))
+		for _, n := range nodes {
+			if n.State == memberlist.StateAlive {
+				healthy = append(healthy, n)
+			}
+		}
+		nodes = healthy
+	}
+
+	return agent.NodesToPeers(rendezvous.MaxN(t.MaximumNodes, []byte(t.FQDN), nodes)...)
+}
+
 func (t config) peersToBind(peers ...*agent.Peer) []dns.A {
 	rrset := make([]dns.A, 0, len(peers))
 	for _, peer := range peers {
@@ -0,0 +1,46 @@
+package dns
+
+import (
+	"github.com/hashicorp/memberlist"
+	"github.com/james-lawrence/bw/agent"
+	"github.com/james-lawrence/bw/clustering"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("config", func() {
+	node := func(name, ip string, state memberlist.NodeStateType) *memberlist.Node {
+		n := agent.PeerToNode(&agent.Peer{Name: name, Ip: ip, P2PPort: 2000})
+		n.State = state
+		return n
+	}
+
+	addresses := func(c config, cx cluster) (results []string) {
+		for _, r := range c.peersToBind(c.sample(cx)...) {
+			results = append(results, r.A.String())
+		}
+		return results
+	}
+
+	cx := clustering.NewMock(
+		node("node1", "10.0.0.1", memberlist.StateAlive),
+		node("node2", "10.0.0.2", memberlist.StateSuspect),
+		node("node3", "10.0.0.3", memberlist.StateAlive),
+		node("node4", "10.0.0.4", memberlist.StateDead),
+	)
+
+	It("should only publish healthy nodes", func() {
+		c := config{}.merge(OptionFQDN("example.com."), OptionMaximumNodes(4), OptionHealthyOnly(true))
+		Expect(addresses(c, cx)).To(ConsistOf("10.0.0.1", "10.0.0.3"))
+	})
+
+	It("should fill the records with healthy nodes", func() {
+		c := config{}.merge(OptionFQDN("example.com."), OptionMaximumNodes(2), OptionHealthyOnly(true))
+		Expect(addresses(c, cx)).To(ConsistOf("10.0.0.1", "10.0.0.3"))
+	})
+
+	It("should publish every node when disabled", func() {
+		c := config{}.merge(OptionFQDN("example.com."), OptionMaximumNodes(4))
+		Expect(addresses(c, cx)).To(ConsistOf("10.0.0.1", "10.0.0.2", "10.0.0.3", "10.0.0.4"))
+	})
+})
@@ -5,7 +5,6 @@ import (
 	"time"
 
 	"cloud.google.com/go/compute/metadata"
-	"github.com/james-lawrence/bw/agent"
 	"github.com/miekg/dns"
 	"github.com/pkg/errors"
 	"golang.org/x/oauth2"
@@ -80,7 +79,7 @@ func (t GoogleCloudDNS) Sample(c cluster) (err error) {
 		return errors.Wrap(err, "failed to retrieve existing record")
 	}
 
-	sample := agent.NodesToPeers(c.GetN(t.MaximumNodes, []byte(t.config.FQDN))...)
+	sample := t.config.sample(c)
 	change := &gdns.Change{
 		Additions: t.convert(t.config.peersToBind(sample...)...),
 		Deletions: rr.Rrsets,
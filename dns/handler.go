@@ -0,0 +1,56 @@
+package dns
+
+import (
+	"strings"
+
+	"github.com/miekg/dns"
+)
+
+// NewHandler answers queries for the configured name (OptionFQDN) with the A records
+// of the cluster's members, queries for any other name are refused. the agent serves the
+// handler when dnsBind.address is configured.
+func NewHandler(c cluster, options ...Option) Handler {
+	conf := config{}.merge(options...)
+	conf.FQDN = dns.Fqdn(conf.FQDN)
+
+	return Handler{
+		c:      c,
+		config: conf,
+	}
+}
+
+// Handler serves the cluster's records.
+type Handler struct {
+	c cluster
+	config
+}
+
+// ServeDNS implements dns.Handler
+func (t Handler) ServeDNS(w dns.ResponseWriter, req *dns.Msg) {
+	resp := new(dns.Msg)
+	resp.SetReply(req)
+	resp.Authoritative = true
+
+	for _, q := range req.Question {
+		if !strings.EqualFold(dns.Fqdn(q.Name), t.FQDN) {
+			resp.SetRcode(req, dns.RcodeRefused)
+			_ = w.WriteMsg(resp)
+			return
+		}
+
+		switch q.Qtype {
+		case dns.TypeA, dns.TypeANY:
+			sampler := t.config
+			if sampler.MaximumNodes <= 0 {
+				sampler.MaximumNodes = len(t.c.Members())
+			}
+
+			for _, rr := range t.peersToBind(sampler.sample(t.c)...) {
+				rr := rr
+				resp.Answer = append(resp.Answer, &rr)
+			}
+		}
+	}
+
+	_ = w.WriteMsg(resp)
+}
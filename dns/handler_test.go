@@ -0,0 +1,71 @@
+package dns
+
+import (
+	"net"
+
+	"github.com/hashicorp/memberlist"
+	"github.com/james-lawrence/bw/agent"
+	"github.com/james-lawrence/bw/clustering"
+	"github.com/miekg/dns"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Handler", func() {
+	var (
+		srv     *dns.Server
+		address string
+	)
+
+	node := func(name, ip string) *memberlist.Node {
+		return agent.PeerToNode(&agent.Peer{Name: name, Ip: ip, P2PPort: 2000})
+	}
+
+	BeforeEach(func() {
+		conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+		Expect(err).To(Succeed())
+		address = conn.LocalAddr().String()
+
+		started := make(chan struct{})
+		srv = &dns.Server{
+			PacketConn: conn,
+			Handler: NewHandler(
+				clustering.NewMock(node("node1", "10.0.0.1"), node("node2", "10.0.0.2")),
+				OptionFQDN("bw.internal"),
+				OptionTTL(60),
+			),
+			NotifyStartedFunc: func() { close(started) },
+		}
+		go srv.ActivateAndServe()
+		Eventually(started).Should(BeClosed())
+	})
+
+	AfterEach(func() {
+		Expect(srv.Shutdown()).To(Succeed())
+	})
+
+	query := func(name string) *dns.Msg {
+		req := new(dns.Msg)
+		req.SetQuestion(dns.Fqdn(name), dns.TypeA)
+		resp, err := dns.Exchange(req, address)
+		Expect(err).To(Succeed())
+		return resp
+	}
+
+	It("should answer the configured name with the members", func() {
+		resp := query("bw.internal")
+		Expect(resp.Rcode).To(Equal(dns.RcodeSuccess))
+		addresses := []string{}
+		for _, rr := range resp.Answer {
+			Expect(rr.Header().Name).To(Equal("bw.internal."))
+			addresses = append(addresses, rr.(*dns.A).A.String())
+		}
+		Expect(addresses).To(ConsistOf("10.0.0.1", "10.0.0.2"))
+	})
+
+	It("should refuse other names", func() {
+		resp := query("example.com")
+		Expect(resp.Rcode).To(Equal(dns.RcodeRefused))
+		Expect(resp.Answer).To(BeEmpty())
+	})
+})
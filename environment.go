@@ -13,13 +13,25 @@ const (
 	EnvDisplayName                       = "BEARDED_WOOKIE_DISPLAY_NAME"                               // environment variable to determine display name to be used, defaults to current user's name.
 	EnvAgentP2PAdvertised                = "BEARDED_WOOKIE_AGENT_P2P_ADVERTISED"                       // environment variable to specify the network address to advertise to peers. e.g.) 127.0.0.1:2000
 	EnvAgentP2PBind                      = "BEARDED_WOOKIE_AGENT_P2P_BIND"                             // environment variable to specify the network address to listen to. e.g.) 0.0.0.0:2000
+	EnvAgentP2PBindCIDR                  = "BEARDED_WOOKIE_AGENT_P2P_BIND_CIDR"                        // environment variable to bind the first local address within the network. e.g.) 10.0.0.0/8
+	EnvAgentP2PAlternatesAdvertised      = "BEARDED_WOOKIE_AGENT_P2P_ADVERTISED_ALTERNATES"            // environment variable to specify additional network addresses to advertise to peers. e.g.) 10.0.0.1:2000
 	EnvAgentP2PAlternatesBind            = "BEARDED_WOOKIE_AGENT_P2P_ALTERNATES"                       // environment variable to specify the network address to listen to. e.g.) 127.0.0.1:2000
 	EnvAgentClusterBootstrap             = "BEARDED_WOOKIE_AGENT_BOOTSTRAP"                            // environment variable to specify the tcp address to connect to allowing for bootstrapping.
+	EnvAgentClusterBootstrapFile         = "BEARDED_WOOKIE_AGENT_BOOTSTRAP_FILE"                       // environment variable to specify a newline delimited file of addresses to bootstrap from, re-read when the file changes.
 	EnvAgentClusterPassiveCheckin        = "BEARDED_WOOKIE_AGENT_CLUSTER_PASSIVE_CHECKIN"              // environment variable to adjust the passive checking rate for the leader node.
 	EnvAgentClusterEnableAWSAutoscaling  = "BEARDED_WOOKIE_AGENT_CLUSTER_PEERS_AWS_AUTOSCALING_GROUPS" // enable aws autoscale group peer detection
 	EnvAgentClusterEnableGoogleCloudPool = "BEARDED_WOOKIE_AGENT_CLUSTER_PEERS_GCLOUD_POOL"            // enable gcloud pool peer detection
 	EnvAgentClusterEnableDNS             = "BEARDED_WOOKIE_AGENT_CLUSTER_PEERS_DNS"                    // enable dns peer detection
+	EnvAgentClusterDNSSRV                = "BEARDED_WOOKIE_AGENT_CLUSTER_PEERS_DNS_SRV"                // srv record to detect peers from, e.g.) _bw._tcp.example.com
 	EnvAgentClusterP2PDiscoveryPort      = "BEARDED_WOOKIE_AGENT_CLUSTER_P2P_DISCOVERY_PORT"           // override the p2p discovery port
 	EnvAgentSelfSignedExpiration         = "BEARDED_WOOKIE_AGENT_BOOTSTRAP_SELF_SIGNED_EXPIRATION"     // environment variable to adjust the expiration period for the self signed bootstrap certificate.
 	EnvAgentACMEDNSChallengeNameServer   = "BEARDED_WOOKIE_AGENT_ACME_DNS_CHALLENGE_NAMESERVER"        // provide a nameserver override for DNS challeges.
+	EnvAgentClusterEnableAzureScaleSet   = "BEARDED_WOOKIE_AGENT_CLUSTER_PEERS_AZURE_SCALE_SETS"       // enable azure scale set peer detection
+	EnvAgentClusterEnableConsul          = "BEARDED_WOOKIE_AGENT_CLUSTER_PEERS_CONSUL"                 // enable consul service peer detection
+	EnvAgentClusterEnableKubernetes      = "BEARDED_WOOKIE_AGENT_CLUSTER_PEERS_KUBERNETES"             // enable kubernetes endpoints peer detection
+	EnvAgentClusterEnableDigitalOcean    = "BEARDED_WOOKIE_AGENT_CLUSTER_PEERS_DIGITALOCEAN"           // enable digitalocean droplet tag peer detection
+	EnvAgentClusterTokens                = "BW_CLUSTER_TOKENS"                                         // additional cluster tokens, newline or comma separated.
+	EnvDefaultP2PPort                    = "BW_DEFAULT_P2P_PORT"                                       // override the default p2p port used when an address doesn't specify one, must be a valid tcp port.
+	EnvResolveTimeout                    = "BW_RESOLVE_TIMEOUT"                                        // upper bound for resolving the hostnames of the addresses provided on the command line, e.g.) 10s.
+	EnvDeployPerNodeConcurrency          = "BW_ENVIRONMENT_PER_NODE_CONCURRENCY"                       // per node concurrency of the deploy, provided to the directives. <= 0 is unbounded.
 )
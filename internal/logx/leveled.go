@@ -0,0 +1,223 @@
+package logx
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Level of a log message, the levels line up with the verbosity counter of the
+// command line, i.e.) -v enables info messages and -vv enables debug messages.
+type Level int
+
+// supported log levels.
+const (
+	LevelError Level = iota - 1
+	LevelWarn
+	LevelInfo
+	LevelDebug
+)
+
+func (t Level) String() string {
+	switch {
+	case t <= LevelError:
+		return "error"
+	case t == LevelWarn:
+		return "warn"
+	case t == LevelInfo:
+		return "info"
+	default:
+		return "debug"
+	}
+}
+
+// supported log formats.
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// NewVerbosity level shared between loggers, adjustments are observed by every logger
+// sharing the verbosity.
+func NewVerbosity(verbosity int) *Verbosity {
+	v := &Verbosity{}
+	v.Set(verbosity)
+	return v
+}
+
+// Verbosity see NewVerbosity.
+type Verbosity struct {
+	level int32
+}
+
+// Level the most verbose level emitted.
+func (t *Verbosity) Level() Level {
+	return Level(atomic.LoadInt32(&t.level))
+}
+
+// Set the verbosity.
+func (t *Verbosity) Set(verbosity int) {
+	atomic.StoreInt32(&t.level, int32(verbosity))
+}
+
+// NewLeveled logger emitting the messages enabled by the verbosity in the given format.
+func NewLeveled(verbosity int, format string) Leveled {
+	return Leveled{
+		Level: Level(verbosity),
+		JSON:  format == FormatJSON,
+	}
+}
+
+// NewShared logger emitting the messages enabled by the shared verbosity in the given format.
+func NewShared(v *Verbosity, format string) Leveled {
+	return Leveled{
+		Verbosity: v,
+		JSON:      format == FormatJSON,
+	}
+}
+
+// Leveled logger distinguishing errors, warnings, info, and debug messages. the zero value
+// emits warnings and errors as text using the standard logger.
+type Leveled struct {
+	Level     Level      // most verbose level emitted.
+	Verbosity *Verbosity // shared verbosity adjusted at runtime, overrides the level when set.
+	JSON      bool       // emit each message as a json object instead of text.
+	Output    io.Writer  // destination for json messages, defaults to the output of the standard logger.
+}
+
+// Enabled reports if messages of the level are emitted.
+func (t Leveled) Enabled(l Level) bool {
+	if t.Verbosity != nil {
+		return l <= t.Verbosity.Level()
+	}
+
+	return l <= t.Level
+}
+
+// Error log an error, kv are alternating key value pairs.
+func (t Leveled) Error(msg string, kv ...interface{}) {
+	t.emit(LevelError, msg, kv...)
+}
+
+// Warn log a warning, kv are alternating key value pairs.
+func (t Leveled) Warn(msg string, kv ...interface{}) {
+	t.emit(LevelWarn, msg, kv...)
+}
+
+// Info log an informational message, kv are alternating key value pairs.
+func (t Leveled) Info(msg string, kv ...interface{}) {
+	t.emit(LevelInfo, msg, kv...)
+}
+
+// Debug log a debug message, kv are alternating key value pairs.
+func (t Leveled) Debug(msg string, kv ...interface{}) {
+	t.emit(LevelDebug, msg, kv...)
+}
+
+func (t Leveled) emit(l Level, msg string, kv ...interface{}) {
+	if !t.Enabled(l) {
+		return
+	}
+
+	if t.JSON {
+		t.json(l, msg, kv...)
+		return
+	}
+
+	var b strings.Builder
+	b.WriteString(strings.ToUpper(l.String()))
+	b.WriteString(": ")
+	b.WriteString(msg)
+	for i := 0; i < len(kv); i += 2 {
+		k, v := pair(i, kv...)
+		fmt.Fprintf(&b, " %s=%v", k, v)
+	}
+
+	log.Output(3, b.String())
+}
+
+func (t Leveled) json(l Level, msg string, kv ...interface{}) {
+	dst := t.Output
+	if dst == nil {
+		dst = log.Writer()
+	}
+
+	m := make(map[string]interface{}, 3+len(kv)/2)
+	for i := 0; i < len(kv); i += 2 {
+		k, v := pair(i, kv...)
+		if err, ok := v.(error); ok {
+			v = err.Error()
+		}
+		m[k] = v
+	}
+
+	m["ts"] = time.Now().UTC().Format(time.RFC3339Nano)
+	m["level"] = l.String()
+	m["msg"] = msg
+
+	encoded, err := json.Marshal(m)
+	if err != nil {
+		log.Println("failed to encode log message", msg, err)
+		return
+	}
+
+	dst.Write(append(encoded, '\n'))
+}
+
+// pair returns the key value pair at offset i, a trailing value without a key uses the key 'arg'.
+func pair(i int, kv ...interface{}) (string, interface{}) {
+	if i+1 >= len(kv) {
+		return "arg", kv[i]
+	}
+
+	return fmt.Sprint(kv[i]), kv[i+1]
+}
+
+// Writer adapts the logger for libraries that log to an io.Writer, e.g.) hashicorp's
+// libraries. each line is emitted at the level of its [LEVEL] tag, the text preceding
+// the tag is discarded. lines without a tag are emitted at the fallback level.
+func (t Leveled) Writer(fallback Level) io.Writer {
+	return writer{Leveled: t, fallback: fallback}
+}
+
+type writer struct {
+	Leveled
+	fallback Level
+}
+
+func (t writer) Write(b []byte) (int, error) {
+	for _, line := range strings.Split(strings.TrimRight(string(b), "\n"), "\n") {
+		if line = strings.TrimSpace(line); line == "" {
+			continue
+		}
+
+		l, msg := tagged(t.fallback, line)
+		t.emit(l, msg)
+	}
+
+	return len(b), nil
+}
+
+// level tags of the lines written to a Writer.
+var tags = map[string]Level{
+	"[ERROR]": LevelError,
+	"[WARN]":  LevelWarn,
+	"[INFO]":  LevelInfo,
+	"[DEBUG]": LevelDebug,
+	"[TRACE]": LevelDebug,
+}
+
+// tagged extracts the level from the [LEVEL] tag of the line.
+func tagged(fallback Level, line string) (Level, string) {
+	for tag, l := range tags {
+		if idx := strings.Index(line, tag); idx >= 0 {
+			return l, strings.TrimSpace(line[idx+len(tag):])
+		}
+	}
+
+	return fallback, line
+}
@@ -0,0 +1,93 @@
+package logx_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"strings"
+
+	"github.com/james-lawrence/bw/internal/logx"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Leveled", func() {
+	var buf bytes.Buffer
+
+	BeforeEach(func() {
+		buf.Reset()
+		log.SetOutput(&buf)
+		DeferCleanup(log.SetOutput, io.Discard)
+	})
+
+	emit := func(l logx.Leveled) {
+		l.Error("error message")
+		l.Warn("warn message")
+		l.Info("info message")
+		l.Debug("debug message")
+	}
+
+	It("should only emit warnings and errors at verbosity 0", func() {
+		emit(logx.Leveled{})
+		Expect(buf.String()).To(ContainSubstring("ERROR: error message"))
+		Expect(buf.String()).To(ContainSubstring("WARN: warn message"))
+		Expect(buf.String()).ToNot(ContainSubstring("info message"))
+		Expect(buf.String()).ToNot(ContainSubstring("debug message"))
+	})
+
+	It("should unlock info and debug messages as the verbosity increases", func() {
+		emit(logx.NewLeveled(1, logx.FormatText))
+		Expect(buf.String()).To(ContainSubstring("INFO: info message"))
+		Expect(buf.String()).ToNot(ContainSubstring("debug message"))
+
+		buf.Reset()
+		emit(logx.NewLeveled(3, logx.FormatText))
+		Expect(buf.String()).To(ContainSubstring("DEBUG: debug message"))
+	})
+
+	It("should include the key value pairs", func() {
+		logx.Leveled{}.Warn("peering disabled", "error", errors.New("boom"), "trailing")
+		Expect(buf.String()).To(ContainSubstring("WARN: peering disabled error=boom arg=trailing"))
+	})
+
+	It("should emit json objects", func() {
+		l := logx.NewLeveled(0, logx.FormatJSON)
+		l.Warn("peering disabled", "error", errors.New("boom"), "port", 2000)
+		l.Info("ignored")
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		Expect(lines).To(HaveLen(1))
+
+		var decoded map[string]interface{}
+		Expect(json.Unmarshal([]byte(lines[0]), &decoded)).To(Succeed())
+		Expect(decoded).To(HaveKeyWithValue("level", "warn"))
+		Expect(decoded).To(HaveKeyWithValue("msg", "peering disabled"))
+		Expect(decoded).To(HaveKeyWithValue("error", "boom"))
+		Expect(decoded).To(HaveKeyWithValue("port", float64(2000)))
+		Expect(decoded).To(HaveKey("ts"))
+	})
+
+	It("should emit the lines written at the level of their tag", func() {
+		w := logx.NewLeveled(1, logx.FormatText).Writer(logx.LevelWarn)
+		_, err := io.WriteString(w, "2020-01-01T00:00:00.000Z [INFO]  snapshot: creating new snapshot\n[DEBUG] ignored\nuntagged\n")
+		Expect(err).To(Succeed())
+		Expect(buf.String()).To(ContainSubstring("INFO: snapshot: creating new snapshot"))
+		Expect(buf.String()).To(ContainSubstring("WARN: untagged"))
+		Expect(buf.String()).ToNot(ContainSubstring("ignored"))
+		Expect(buf.String()).ToNot(ContainSubstring("2020-01-01"))
+	})
+
+	It("should observe adjustments to the shared verbosity", func() {
+		v := logx.NewVerbosity(0)
+		l := logx.NewShared(v, logx.FormatText)
+
+		l.Info("hidden message")
+		Expect(buf.String()).ToNot(ContainSubstring("hidden message"))
+
+		v.Set(1)
+		l.Info("info message")
+		Expect(buf.String()).To(ContainSubstring("INFO: info message"))
+	})
+})
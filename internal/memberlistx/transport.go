@@ -14,6 +14,9 @@ import (
 	"github.com/hashicorp/memberlist"
 	"github.com/pkg/errors"
 	"golang.org/x/time/rate"
+
+	"github.com/james-lawrence/bw/internal/errorsx"
+	"github.com/james-lawrence/bw/internal/netx"
 )
 
 const (
@@ -46,6 +49,29 @@ func SWIMStreams(streams ...net.Listener) SWIMTransportOption {
 	}
 }
 
+// SWIMStreamFilter applied to every incoming stream before it is handed to memberlist.
+func SWIMStreamFilter(f func(net.Conn) net.Conn) SWIMTransportOption {
+	return func(t *SWIMTransport) {
+		t.filter = f
+	}
+}
+
+// SWIMFamily address family of the advertised address selected when bound to
+// the unspecified address, see netx.FamilyAuto.
+func SWIMFamily(family string) SWIMTransportOption {
+	return func(t *SWIMTransport) {
+		t.family = family
+	}
+}
+
+// SWIMRoutes routes to the address of a node in order of preference, packets are written
+// to the preferred route and streams are dialed via each route until one succeeds.
+func SWIMRoutes(routes func(address string) []string) SWIMTransportOption {
+	return func(t *SWIMTransport) {
+		t.routes = routes
+	}
+}
+
 // SWIMPackets packet transports.
 func SWIMPackets(packets ...net.PacketConn) SWIMTransportOption {
 	return func(t *SWIMTransport) {
@@ -68,6 +94,9 @@ type SWIMTransport struct {
 	streams  []net.Listener
 	packets  []net.PacketConn
 	shutdown int32
+	filter   func(net.Conn) net.Conn
+	family   string
+	routes   func(string) []string
 }
 
 // NewSWIMTransport returns a net transport with the given configuration. On
@@ -83,6 +112,7 @@ func NewSWIMTransport(d dialer, options ...SWIMTransportOption) (*SWIMTransport,
 		packetCh: make(chan *memberlist.Packet),
 		streamCh: make(chan net.Conn),
 		logger:   log.New(io.Discard, "", 0),
+		routes:   func(address string) []string { return []string{address} },
 	}
 
 	for _, opt := range options {
@@ -152,17 +182,8 @@ func (t *SWIMTransport) FinalAdvertiseAddr(ip string, port int) (_ net.IP, _ int
 		switch s := t.streams[0].Addr().(type) {
 		case *net.TCPAddr:
 			if s.IP.IsUnspecified() {
-				ip, err = sockaddr.GetPrivateIP()
-				if err != nil {
-					return nil, 0, fmt.Errorf("Failed to get interface addresses: %v", err)
-				}
-				if ip == "" {
-					return nil, 0, fmt.Errorf("No private IP address found, and explicit IP not provided")
-				}
-
-				advertiseAddr = net.ParseIP(ip)
-				if advertiseAddr == nil {
-					return nil, 0, fmt.Errorf("Failed to parse advertise address: %q", ip)
+				if advertiseAddr, err = t.unspecified(); err != nil {
+					return nil, 0, err
 				}
 			} else {
 				advertiseAddr = s.IP
@@ -182,7 +203,7 @@ func (t *SWIMTransport) FinalAdvertiseAddr(ip string, port int) (_ net.IP, _ int
 
 // WriteTo see Transport.
 func (t *SWIMTransport) WriteTo(b []byte, addr string) (time.Time, error) {
-	udpAddr, err := net.ResolveUDPAddr("udp", addr)
+	udpAddr, err := net.ResolveUDPAddr("udp", t.routes(addr)[0])
 	if err != nil {
 		return time.Time{}, err
 	}
@@ -205,7 +226,20 @@ func (t *SWIMTransport) DialTimeout(addr string, timeout time.Duration) (net.Con
 	ctx, done := context.WithTimeout(context.Background(), timeout)
 	defer done()
 
-	return t.dialer.DialContext(ctx, "tcp", addr)
+	var cause error
+	for _, route := range t.routes(addr) {
+		conn, err := t.dialer.DialContext(ctx, "tcp", route)
+		if err == nil {
+			return conn, nil
+		}
+
+		cause = errorsx.Compact(cause, err)
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	return nil, cause
 }
 
 // StreamCh see memberlist.Transport.
@@ -257,6 +291,10 @@ func (t *SWIMTransport) tcpListen(l net.Listener) {
 		// No error, reset loop delay
 		// loopDelay = 0
 
+		if t.filter != nil {
+			conn = t.filter(conn)
+		}
+
 		t.streamCh <- conn
 	}
 }
@@ -310,3 +348,36 @@ func (t *SWIMTransport) udpListen(udpLn net.PacketConn) {
 // 	}
 // 	return err
 // }
+
+// unspecified selects the address advertised when bound to the unspecified address.
+func (t *SWIMTransport) unspecified() (net.IP, error) {
+	if t.family == netx.FamilyIPv6 {
+		ips, err := netx.LocalIPs(netx.FamilyIPv6)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, ip := range ips {
+			if ip.IsGlobalUnicast() {
+				return ip, nil
+			}
+		}
+
+		return nil, fmt.Errorf("No global unicast IPv6 address found, and explicit IP not provided")
+	}
+
+	ip, err := sockaddr.GetPrivateIP()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get interface addresses: %v", err)
+	}
+	if ip == "" {
+		return nil, fmt.Errorf("No private IP address found, and explicit IP not provided")
+	}
+
+	advertiseAddr := net.ParseIP(ip)
+	if advertiseAddr == nil {
+		return nil, fmt.Errorf("Failed to parse advertise address: %q", ip)
+	}
+
+	return advertiseAddr, nil
+}
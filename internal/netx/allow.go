@@ -0,0 +1,86 @@
+package netx
+
+import (
+	"log"
+	"net"
+
+	"github.com/pkg/errors"
+)
+
+// ParseCIDRs parses the ip ranges in cidr notation, e.g.) 10.0.0.0/8.
+func ParseCIDRs(cidrs ...string) (networks []*net.IPNet, err error) {
+	for _, cidr := range cidrs {
+		var (
+			n *net.IPNet
+		)
+
+		if _, n, err = net.ParseCIDR(cidr); err != nil {
+			return nil, errors.Wrapf(err, "invalid cidr: %s", cidr)
+		}
+
+		networks = append(networks, n)
+	}
+
+	return networks, nil
+}
+
+// Allowed returns true when the ip of the address is within one of the networks.
+func Allowed(addr net.Addr, networks ...*net.IPNet) bool {
+	var (
+		ip net.IP
+	)
+
+	switch a := addr.(type) {
+	case *net.TCPAddr:
+		ip = a.IP
+	case *net.UDPAddr:
+		ip = a.IP
+	default:
+		host, _, err := net.SplitHostPort(addr.String())
+		if err != nil {
+			return false
+		}
+		ip = net.ParseIP(host)
+	}
+
+	for _, n := range networks {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// NewAllowListener only accepts connections from remote addresses within the networks,
+// connections from any other address are closed immediately. no networks allows every connection.
+func NewAllowListener(l net.Listener, networks ...*net.IPNet) net.Listener {
+	if len(networks) == 0 {
+		return l
+	}
+
+	return allowListener{Listener: l, networks: networks}
+}
+
+type allowListener struct {
+	net.Listener
+	networks []*net.IPNet
+}
+
+func (t allowListener) Accept() (net.Conn, error) {
+	for {
+		conn, err := t.Listener.Accept()
+		if err != nil {
+			return conn, err
+		}
+
+		if Allowed(conn.RemoteAddr(), t.networks...) {
+			return conn, nil
+		}
+
+		log.Println("rejected connection from disallowed address", conn.RemoteAddr().String())
+		if err = conn.Close(); err != nil {
+			log.Println("failed to close rejected connection", err)
+		}
+	}
+}
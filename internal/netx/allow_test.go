@@ -0,0 +1,70 @@
+package netx_test
+
+import (
+	"net"
+	"time"
+
+	"github.com/james-lawrence/bw/internal/netx"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("NewAllowListener", func() {
+	// accept a single connection from the listener, the connection is dialed from the source ip.
+	accept := func(source string, cidrs ...string) (net.Conn, error) {
+		networks, err := netx.ParseCIDRs(cidrs...)
+		Expect(err).ToNot(HaveOccurred())
+
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).ToNot(HaveOccurred())
+		allowed := netx.NewAllowListener(l, networks...)
+		DeferCleanup(allowed.Close)
+
+		d := net.Dialer{LocalAddr: &net.TCPAddr{IP: net.ParseIP(source)}, Timeout: time.Second}
+		conn, err := d.Dial("tcp", l.Addr().String())
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(conn.Close)
+
+		accepted := make(chan net.Conn, 1)
+		go func() {
+			if c, err := allowed.Accept(); err == nil {
+				accepted <- c
+			}
+		}()
+
+		select {
+		case c := <-accepted:
+			return c, nil
+		case <-time.After(200 * time.Millisecond):
+			// the rejected connection is closed by the listener.
+			_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+			_, err = conn.Read(make([]byte, 1))
+			return nil, err
+		}
+	}
+
+	It("should accept connections from addresses within the allowed ranges", func() {
+		conn, err := accept("127.0.0.1", "10.0.0.0/8", "127.0.0.0/24")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(conn).ToNot(BeNil())
+		Expect(conn.Close()).To(Succeed())
+	})
+
+	It("should reject connections from addresses outside the allowed ranges", func() {
+		conn, err := accept("127.0.0.1", "10.0.0.0/8")
+		Expect(conn).To(BeNil())
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should accept every connection without any ranges", func() {
+		conn, err := accept("127.0.0.1")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(conn.Close()).To(Succeed())
+	})
+
+	It("should reject invalid ranges", func() {
+		_, err := netx.ParseCIDRs("10.0.0.0")
+		Expect(err).To(MatchError(ContainSubstring("invalid cidr")))
+	})
+})
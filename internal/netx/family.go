@@ -0,0 +1,168 @@
+package netx
+
+import (
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// address families of the agent's addresses.
+const (
+	FamilyAuto = "auto" // every address regardless of family.
+	FamilyIPv4 = "ipv4"
+	FamilyIPv6 = "ipv6"
+)
+
+// ParseFamily validates the address family, blank is treated as auto.
+func ParseFamily(family string) (string, error) {
+	switch family {
+	case "", FamilyAuto:
+		return FamilyAuto, nil
+	case FamilyIPv4, FamilyIPv6:
+		return family, nil
+	default:
+		return "", errors.Errorf("unknown address family: %s, expected one of %s, %s, or %s", family, FamilyIPv4, FamilyIPv6, FamilyAuto)
+	}
+}
+
+// InFamily returns true when the ip belongs to the address family, every ip
+// belongs to the auto family.
+func InFamily(family string, ip net.IP) bool {
+	switch family {
+	case FamilyIPv4:
+		return ip.To4() != nil
+	case FamilyIPv6:
+		return ip.To4() == nil && ip.To16() != nil
+	default:
+		return true
+	}
+}
+
+// FilterFamily the ips belonging to the address family, order is preserved.
+func FilterFamily(family string, ips ...net.IP) (filtered []net.IP) {
+	for _, ip := range ips {
+		if InFamily(family, ip) {
+			filtered = append(filtered, ip)
+		}
+	}
+
+	return filtered
+}
+
+// LocalIPs the addresses of the local interfaces belonging to the address family.
+func LocalIPs(family string) (ips []net.IP, err error) {
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read the interface addresses")
+	}
+
+	for _, addr := range addrs {
+		if n, ok := addr.(*net.IPNet); ok {
+			ips = append(ips, n.IP)
+		}
+	}
+
+	return FilterFamily(family, ips...), nil
+}
+
+// LocalNetworks the networks of the local interfaces.
+func LocalNetworks() (networks []*net.IPNet, err error) {
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read the interface addresses")
+	}
+
+	for _, addr := range addrs {
+		if n, ok := addr.(*net.IPNet); ok {
+			networks = append(networks, n)
+		}
+	}
+
+	return networks, nil
+}
+
+// NewNetworkCache caches the networks of the local interfaces, the networks are
+// re-read once the ttl elapses to observe changes to the interfaces.
+func NewNetworkCache(ttl time.Duration) *NetworkCache {
+	return &NetworkCache{ttl: ttl}
+}
+
+// NetworkCache see NewNetworkCache.
+type NetworkCache struct {
+	ttl       time.Duration
+	m         sync.Mutex
+	refreshed time.Time
+	networks  []*net.IPNet
+}
+
+// Networks of the local interfaces, see LocalNetworks. failures to read the
+// interfaces aren't cached.
+func (t *NetworkCache) Networks() (networks []*net.IPNet, err error) {
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	if !t.refreshed.IsZero() && time.Since(t.refreshed) < t.ttl {
+		return t.networks, nil
+	}
+
+	if networks, err = LocalNetworks(); err != nil {
+		return nil, err
+	}
+
+	t.networks, t.refreshed = networks, time.Now()
+
+	return networks, nil
+}
+
+// Routable the first routable ip belonging to the address family, the auto family prefers ipv4.
+func Routable(family string, ips ...net.IP) (net.IP, error) {
+	candidates := FilterFamily(family, ips...)
+	if family == FamilyAuto || family == "" {
+		candidates = append(FilterFamily(FamilyIPv4, ips...), FilterFamily(FamilyIPv6, ips...)...)
+	}
+
+	for _, ip := range candidates {
+		if ip.IsGlobalUnicast() {
+			return ip, nil
+		}
+	}
+
+	return nil, errors.Errorf("no routable %s address available", family)
+}
+
+// PrimaryIP the routable address of the local interfaces belonging to the address family, see Routable.
+func PrimaryIP(family string) (net.IP, error) {
+	ips, err := LocalIPs(family)
+	if err != nil {
+		return nil, err
+	}
+
+	return Routable(family, ips...)
+}
+
+// Within the first ip within the network, the error lists the candidates considered.
+func Within(n *net.IPNet, ips ...net.IP) (net.IP, error) {
+	considered := make([]string, 0, len(ips))
+	for _, ip := range ips {
+		if n.Contains(ip) {
+			return ip, nil
+		}
+
+		considered = append(considered, ip.String())
+	}
+
+	return nil, errors.Errorf("no local address within %s, considered: [%s]", n, strings.Join(considered, ", "))
+}
+
+// LocalWithin the first address of the local interfaces belonging to the address family within the network, see Within.
+func LocalWithin(n *net.IPNet, family string) (net.IP, error) {
+	ips, err := LocalIPs(family)
+	if err != nil {
+		return nil, err
+	}
+
+	return Within(n, ips...)
+}
@@ -0,0 +1,111 @@
+package netx_test
+
+import (
+	"net"
+	"time"
+
+	"github.com/james-lawrence/bw/internal/netx"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("FilterFamily", func() {
+	resolved := []net.IP{
+		net.ParseIP("10.0.0.1"),
+		net.ParseIP("fd00::1"),
+		net.ParseIP("192.168.1.1"),
+		net.ParseIP("::1"),
+	}
+
+	DescribeTable("should only keep the addresses of the family",
+		func(family string, expected ...string) {
+			filtered := make([]string, 0, len(expected))
+			for _, ip := range netx.FilterFamily(family, resolved...) {
+				filtered = append(filtered, ip.String())
+			}
+			Expect(filtered).To(Equal(expected))
+		},
+		Entry("ipv4", netx.FamilyIPv4, "10.0.0.1", "192.168.1.1"),
+		Entry("ipv6", netx.FamilyIPv6, "fd00::1", "::1"),
+		Entry("auto", netx.FamilyAuto, "10.0.0.1", "fd00::1", "192.168.1.1", "::1"),
+	)
+
+	It("should treat ipv4 mapped ipv6 addresses as ipv4", func() {
+		Expect(netx.InFamily(netx.FamilyIPv4, net.ParseIP("::ffff:10.0.0.1"))).To(BeTrue())
+		Expect(netx.InFamily(netx.FamilyIPv6, net.ParseIP("::ffff:10.0.0.1"))).To(BeFalse())
+	})
+})
+
+var _ = Describe("ParseFamily", func() {
+	It("should default to auto", func() {
+		Expect(netx.ParseFamily("")).To(Equal(netx.FamilyAuto))
+	})
+
+	It("should reject unknown families", func() {
+		_, err := netx.ParseFamily("ipx")
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("Routable", func() {
+	local := []net.IP{
+		net.ParseIP("127.0.0.1"),
+		net.ParseIP("fe80::1"),
+		net.ParseIP("fd00::1"),
+		net.ParseIP("10.0.0.1"),
+	}
+
+	DescribeTable("should select a routable address of the family",
+		func(family string, expected string) {
+			Expect(netx.Routable(family, local...)).To(Equal(net.ParseIP(expected)))
+		},
+		Entry("ipv4", netx.FamilyIPv4, "10.0.0.1"),
+		Entry("ipv6", netx.FamilyIPv6, "fd00::1"),
+		Entry("auto prefers ipv4", netx.FamilyAuto, "10.0.0.1"),
+	)
+
+	It("should fail without a routable address", func() {
+		_, err := netx.Routable(netx.FamilyAuto, net.ParseIP("127.0.0.1"), net.ParseIP("fe80::1"))
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("Within", func() {
+	local := []net.IP{
+		net.ParseIP("127.0.0.1"),
+		net.ParseIP("172.17.0.4"),
+		net.ParseIP("10.1.2.3"),
+	}
+
+	It("should select the first address within the network", func() {
+		_, n, err := net.ParseCIDR("10.0.0.0/8")
+		Expect(err).To(Succeed())
+		Expect(netx.Within(n, local...)).To(Equal(net.ParseIP("10.1.2.3")))
+	})
+
+	It("should list the addresses considered when none are within the network", func() {
+		_, n, err := net.ParseCIDR("192.168.0.0/16")
+		Expect(err).To(Succeed())
+		_, err = netx.Within(n, local...)
+		Expect(err).To(MatchError(ContainSubstring("considered: [127.0.0.1, 172.17.0.4, 10.1.2.3]")))
+	})
+})
+
+var _ = Describe("NetworkCache", func() {
+	It("should return the networks of the local interfaces", func() {
+		networks, err := netx.NewNetworkCache(time.Minute).Networks()
+		Expect(err).To(Succeed())
+		Expect(networks).To(ContainElement(Satisfy(func(n *net.IPNet) bool { return n.Contains(net.ParseIP("127.0.0.1")) })))
+	})
+
+	It("should reuse the networks until the ttl elapses", func() {
+		c := netx.NewNetworkCache(time.Minute)
+		first, err := c.Networks()
+		Expect(err).To(Succeed())
+		second, err := c.Networks()
+		Expect(err).To(Succeed())
+		Expect(second).ToNot(BeEmpty())
+		Expect(&second[0]).To(BeIdenticalTo(&first[0]))
+	})
+})
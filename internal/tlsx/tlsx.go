@@ -4,6 +4,8 @@ import (
 	"crypto/md5"
 	"crypto/rand"
 	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
 	"crypto/tls"
 	"crypto/x509"
 	"crypto/x509/pkix"
@@ -14,6 +16,7 @@ import (
 	"math/big"
 	"net"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/grantae/certinfo"
@@ -259,6 +262,13 @@ func OptionNoClientCert(c *tls.Config) error {
 	return nil
 }
 
+// OptionNoClientCertificate never present a certificate when acting as a client.
+func OptionNoClientCertificate(c *tls.Config) error {
+	c.Certificates = nil
+	c.GetClientCertificate = nil
+	return nil
+}
+
 // OptionInsecureSkipVerify see tls.Config.InsecureSkipVerify
 func OptionInsecureSkipVerify(c *tls.Config) error {
 	c.InsecureSkipVerify = true
@@ -273,6 +283,124 @@ func OptionNextProtocols(protocols ...string) Option {
 	}
 }
 
+// OptionMinVersion minimum tls version accepted, see tls.Config.MinVersion.
+// 0 retains the default minimum version.
+func OptionMinVersion(v uint16) Option {
+	return func(c *tls.Config) error {
+		if v != 0 {
+			c.MinVersion = v
+		}
+		return nil
+	}
+}
+
+// OptionCipherSuites restrict the cipher suites negotiated for tls 1.2 and earlier,
+// see tls.Config.CipherSuites. tls 1.3 suites are not configurable. no suites retains the defaults.
+func OptionCipherSuites(suites ...uint16) Option {
+	return func(c *tls.Config) error {
+		if len(suites) > 0 {
+			c.CipherSuites = suites
+		}
+		return nil
+	}
+}
+
+// ParseFingerprint parses a hex encoded sha256 certificate fingerprint, separators
+// (e.g. AB:CD:...) and case are ignored. blank returns nil.
+func ParseFingerprint(s string) (fingerprint []byte, err error) {
+	s = strings.NewReplacer(":", "", " ", "").Replace(strings.TrimSpace(s))
+	if s == "" {
+		return nil, nil
+	}
+
+	if fingerprint, err = hex.DecodeString(s); err != nil {
+		return nil, errors.Wrapf(err, "invalid certificate fingerprint: %s", s)
+	}
+
+	if len(fingerprint) != sha256.Size {
+		return nil, errors.Errorf("invalid certificate fingerprint: %s, expected a %d byte sha256 digest", s, sha256.Size)
+	}
+
+	return fingerprint, nil
+}
+
+// Fingerprint the sha256 digest of the certificate.
+func Fingerprint(cert *x509.Certificate) []byte {
+	digest := sha256.Sum256(cert.Raw)
+	return digest[:]
+}
+
+// OptionPinSHA256 only accept the peer's leaf certificate with the hex encoded sha256
+// fingerprint, see ParseFingerprint. the pin replaces the verification of the certificate
+// chain, peers presenting any other certificate are rejected even when the chain is valid.
+// a blank fingerprint retains the standard verification.
+func OptionPinSHA256(s string) Option {
+	return func(c *tls.Config) error {
+		pinned, err := ParseFingerprint(s)
+		if err != nil || pinned == nil {
+			return err
+		}
+
+		verify := c.VerifyConnection
+		c.InsecureSkipVerify = true
+		c.VerifyConnection = func(cs tls.ConnectionState) error {
+			if len(cs.PeerCertificates) == 0 {
+				return errors.New("peer didn't present a certificate to verify against the pinned fingerprint")
+			}
+
+			if presented := Fingerprint(cs.PeerCertificates[0]); subtle.ConstantTimeCompare(presented, pinned) != 1 {
+				return errors.Errorf("certificate fingerprint %x doesn't match the pinned fingerprint %x", presented, pinned)
+			}
+
+			if verify != nil {
+				return verify(cs)
+			}
+
+			return nil
+		}
+
+		return nil
+	}
+}
+
+// ParseVersion parses a tls version, e.g.) 1.2 or 1.3. blank returns 0.
+func ParseVersion(s string) (uint16, error) {
+	switch strings.TrimPrefix(strings.ToLower(s), "tls") {
+	case "":
+		return 0, nil
+	case "1.0", "10":
+		return tls.VersionTLS10, nil
+	case "1.1", "11":
+		return tls.VersionTLS11, nil
+	case "1.2", "12":
+		return tls.VersionTLS12, nil
+	case "1.3", "13":
+		return tls.VersionTLS13, nil
+	default:
+		return 0, errors.Errorf("unknown tls version: %s", s)
+	}
+}
+
+// ParseCipherSuites parses the names of the cipher suites, see tls.CipherSuites.
+// insecure cipher suites are rejected.
+func ParseCipherSuites(names ...string) (suites []uint16, err error) {
+	known := make(map[string]uint16, len(tls.CipherSuites()))
+	for _, s := range tls.CipherSuites() {
+		known[s.Name] = s.ID
+	}
+
+	for _, n := range names {
+		id, ok := known[n]
+		if !ok {
+			return nil, errors.Errorf("unknown or insecure cipher suite: %s", n)
+		}
+
+		suites = append(suites, id)
+	}
+
+	return suites, nil
+}
+
 // Clone ...
 func Clone(c *tls.Config, options ...Option) (updated *tls.Config, err error) {
 	updated = c.Clone()
@@ -368,12 +496,36 @@ func DecodePEMCertificate(encoded []byte) (cert *x509.Certificate, err error) {
 	return cert, nil
 }
 
+// DefaultHandshakeTimeout the default maximum duration of a tls handshake.
+const DefaultHandshakeTimeout = 10 * time.Second
+
+// DialerOption options for dialers.
+type DialerOption func(*Dialer)
+
+// DialerOptionTimeout bounds establishing the connection including the tls handshake,
+// stalled handshakes are aborted. timeout <= 0 uses the DefaultHandshakeTimeout.
+func DialerOptionTimeout(timeout time.Duration) DialerOption {
+	return func(d *Dialer) {
+		if timeout <= 0 {
+			timeout = DefaultHandshakeTimeout
+		}
+
+		d.NetDialer.Timeout = timeout
+	}
+}
+
 // NewDialer for tls configurations.
-func NewDialer(c *tls.Config, options ...Option) *Dialer {
-	return &Dialer{
-		Config: MustClone(c, options...),
+func NewDialer(c *tls.Config, options ...DialerOption) *Dialer {
+	d := &Dialer{
+		Config: MustClone(c),
 		NetDialer: &net.Dialer{
 			Timeout: 30 * time.Second,
 		},
 	}
+
+	for _, opt := range options {
+		opt(d)
+	}
+
+	return d
 }
@@ -0,0 +1,13 @@
+package tlsx_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestTlsx(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Tlsx Suite")
+}
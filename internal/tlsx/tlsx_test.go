@@ -0,0 +1,117 @@
+package tlsx_test
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"net"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/james-lawrence/bw/internal/tlsx"
+)
+
+var _ = Describe("DialerOptionTimeout", func() {
+	It("should abort handshakes with peers that never complete them", func() {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).To(Succeed())
+		defer l.Close()
+
+		// accept the tcp connection but never respond to the handshake.
+		accepted := make(chan net.Conn, 1)
+		go func() {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			accepted <- conn
+		}()
+
+		d := tlsx.NewDialer(&tls.Config{InsecureSkipVerify: true}, tlsx.DialerOptionTimeout(100*time.Millisecond))
+
+		started := time.Now()
+		conn, err := d.DialContext(context.Background(), "tcp", l.Addr().String())
+		Expect(err).To(HaveOccurred())
+		Expect(conn).To(BeNil())
+		Expect(time.Since(started)).To(BeNumerically("<", time.Second))
+
+		// the underlying connection is closed.
+		var peer net.Conn
+		Eventually(accepted).Should(Receive(&peer))
+		defer peer.Close()
+		Expect(peer.SetReadDeadline(time.Now().Add(time.Second))).To(Succeed())
+		_, err = peer.Read(make([]byte, 1024))
+		for err == nil {
+			_, err = peer.Read(make([]byte, 1024))
+		}
+		Expect(err).ToNot(MatchError(ContainSubstring("timeout")))
+	})
+})
+
+var _ = Describe("OptionPinSHA256", func() {
+	var (
+		cert   *x509.Certificate
+		server *tls.Config
+	)
+
+	BeforeEach(func() {
+		template, err := tlsx.X509Template(time.Hour, tlsx.X509OptionHosts("localhost"))
+		Expect(err).To(Succeed())
+		priv, der, err := tlsx.SelfSignedRSAGen(2048, template)
+		Expect(err).To(Succeed())
+		cert, err = x509.ParseCertificate(der)
+		Expect(err).To(Succeed())
+
+		server = &tls.Config{
+			Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: priv}},
+		}
+	})
+
+	// handshake with the server using the client configuration.
+	handshake := func(client *tls.Config) error {
+		l, err := tls.Listen("tcp", "127.0.0.1:0", server)
+		Expect(err).To(Succeed())
+		DeferCleanup(l.Close)
+
+		go func() {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+			_ = conn.(*tls.Conn).Handshake()
+		}()
+
+		conn, err := tls.Dial("tcp", l.Addr().String(), client)
+		if err != nil {
+			return err
+		}
+
+		return conn.Close()
+	}
+
+	trusted := func(options ...tlsx.Option) *tls.Config {
+		pool := x509.NewCertPool()
+		pool.AddCert(cert)
+		return tlsx.MustClone(&tls.Config{ServerName: "localhost", RootCAs: pool}, options...)
+	}
+
+	It("should accept the pinned certificate without a trusted chain", func() {
+		fingerprint := strings.ToUpper(hex.EncodeToString(tlsx.Fingerprint(cert)))
+		Expect(handshake(tlsx.MustClone(&tls.Config{}, tlsx.OptionPinSHA256(fingerprint)))).To(Succeed())
+	})
+
+	It("should reject other certificates even when the chain is valid", func() {
+		Expect(handshake(trusted())).To(Succeed())
+		Expect(handshake(trusted(tlsx.OptionPinSHA256(strings.Repeat("ab", 32))))).To(MatchError(ContainSubstring("doesn't match the pinned fingerprint")))
+	})
+
+	It("should reject malformed fingerprints", func() {
+		_, err := tlsx.Clone(&tls.Config{}, tlsx.OptionPinSHA256("abcd"))
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,128 @@
+// Package tracex propagates trace spans across the grpc calls of a deploy using opentelemetry.
+// traces are only started when explicitly enabled (see StartRoot), and spans are only exported
+// once an exporter is registered (see Register), otherwise tracing is a no-op.
+package tracex
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/metadata"
+)
+
+// Instrumentation name of the tracer.
+const Instrumentation = "github.com/james-lawrence/bw"
+
+// the spans of a trace follow the sampling decision of the root span.
+var provider = sdktrace.NewTracerProvider(sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.AlwaysSample())))
+
+// the trace context is propagated via the w3c traceparent and tracestate grpc metadata.
+var propagator = propagation.TraceContext{}
+
+// Register the exporter, spans are exported as they end. returns a function unregistering
+// the exporter.
+func Register(e sdktrace.SpanExporter) func() {
+	p := sdktrace.NewSimpleSpanProcessor(e)
+	provider.RegisterSpanProcessor(p)
+	return func() {
+		provider.UnregisterSpanProcessor(p)
+	}
+}
+
+// Start a span, the span is a child of the span within the context, otherwise of the remote
+// span propagated to the context. without a parent the span is a no-op.
+func Start(ctx context.Context, name string, options ...trace.SpanStartOption) (context.Context, trace.Span) {
+	if trace.SpanContextFromContext(ctx).IsValid() {
+		return provider.Tracer(Instrumentation).Start(ctx, name, options...)
+	}
+
+	if sc, ok := Remote(ctx); ok {
+		return provider.Tracer(Instrumentation).Start(trace.ContextWithRemoteSpanContext(ctx, sc), name, options...)
+	}
+
+	return ctx, trace.SpanFromContext(ctx)
+}
+
+// StartRoot a span continuing the trace of the context, see Start. when the context
+// isn't part of a trace a new trace is started if enabled.
+func StartRoot(ctx context.Context, name string, enabled bool, options ...trace.SpanStartOption) (context.Context, trace.Span) {
+	if _, ok := Remote(ctx); ok || trace.SpanContextFromContext(ctx).IsValid() || !enabled {
+		return Start(ctx, name, options...)
+	}
+
+	return provider.Tracer(Instrumentation).Start(ctx, name, append(options, trace.WithNewRoot())...)
+}
+
+// Outgoing propagates the sampled span of the context to the grpc metadata of outgoing requests.
+func Outgoing(ctx context.Context) context.Context {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		sc, _ = Remote(ctx)
+	}
+
+	if !sc.IsSampled() {
+		return ctx
+	}
+
+	md := metadata.MD{}
+	propagator.Inject(trace.ContextWithSpanContext(context.Background(), sc), carrier(md))
+
+	kv := make([]string, 0, 2*len(md))
+	for k, values := range md {
+		for _, v := range values {
+			kv = append(kv, k, v)
+		}
+	}
+
+	return metadata.AppendToOutgoingContext(ctx, kv...)
+}
+
+// Remote span context propagated by the grpc metadata of the incoming request.
+func Remote(ctx context.Context) (trace.SpanContext, bool) {
+	md, found := metadata.FromIncomingContext(ctx)
+	if !found {
+		return trace.SpanContext{}, false
+	}
+
+	sc := trace.SpanContextFromContext(propagator.Extract(context.Background(), carrier(md)))
+	return sc, sc.IsValid()
+}
+
+// Inherit the span of from, used when work outlives the request that initiated it.
+func Inherit(ctx context.Context, from context.Context) context.Context {
+	if span := trace.SpanFromContext(from); span.SpanContext().IsValid() {
+		return trace.ContextWithSpan(ctx, span)
+	}
+
+	if sc, ok := Remote(from); ok {
+		return trace.ContextWithRemoteSpanContext(ctx, sc)
+	}
+
+	return ctx
+}
+
+// carrier adapts grpc metadata for the propagator.
+type carrier metadata.MD
+
+func (t carrier) Get(key string) string {
+	if values := metadata.MD(t).Get(key); len(values) > 0 {
+		return values[0]
+	}
+
+	return ""
+}
+
+func (t carrier) Set(key, value string) {
+	metadata.MD(t).Set(key, value)
+}
+
+func (t carrier) Keys() []string {
+	keys := make([]string, 0, len(t))
+	for k := range t {
+		keys = append(keys, k)
+	}
+
+	return keys
+}
@@ -0,0 +1,13 @@
+package tracex_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestTracex(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Tracex Suite")
+}
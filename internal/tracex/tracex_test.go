@@ -0,0 +1,93 @@
+package tracex_test
+
+import (
+	"context"
+
+	"github.com/james-lawrence/bw/internal/tracex"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/grpc/metadata"
+)
+
+// incoming converts the outgoing metadata of the context into the incoming metadata of a request.
+func incoming(ctx context.Context) context.Context {
+	md, _ := metadata.FromOutgoingContext(tracex.Outgoing(ctx))
+	return metadata.NewIncomingContext(context.Background(), md)
+}
+
+var _ = Describe("Start", func() {
+	var exporter *tracetest.InMemoryExporter
+
+	BeforeEach(func() {
+		exporter = tracetest.NewInMemoryExporter()
+		DeferCleanup(tracex.Register(exporter))
+	})
+
+	It("should not record spans without a parent", func() {
+		_, span := tracex.Start(context.Background(), "root")
+		span.End()
+		Expect(span.SpanContext().IsValid()).To(BeFalse())
+		Expect(exporter.GetSpans()).To(BeEmpty())
+	})
+
+	It("should not record root spans unless enabled", func() {
+		_, span := tracex.StartRoot(context.Background(), "root", false)
+		span.End()
+		Expect(span.IsRecording()).To(BeFalse())
+		Expect(exporter.GetSpans()).To(BeEmpty())
+	})
+
+	It("should continue the trace of the incoming request", func() {
+		ctx, parent := tracex.StartRoot(context.Background(), "client", true)
+		_, child := tracex.Start(incoming(ctx), "server")
+		child.End()
+		parent.End()
+
+		Expect(child.SpanContext().IsSampled()).To(BeTrue())
+		Expect(child.SpanContext().TraceID()).To(Equal(parent.SpanContext().TraceID()))
+
+		spans := exporter.GetSpans()
+		Expect(spans).To(HaveLen(2))
+		Expect(spans[0].Name).To(Equal("server"))
+		Expect(spans[0].Parent.SpanID()).To(Equal(parent.SpanContext().SpanID()))
+		Expect(spans[0].Parent.IsRemote()).To(BeTrue())
+	})
+
+	It("should not propagate unsampled spans", func() {
+		ctx, parent := tracex.StartRoot(context.Background(), "client", false)
+		defer parent.End()
+
+		_, ok := tracex.Remote(incoming(ctx))
+		Expect(ok).To(BeFalse())
+	})
+})
+
+var _ = Describe("Inherit", func() {
+	var exporter *tracetest.InMemoryExporter
+
+	BeforeEach(func() {
+		exporter = tracetest.NewInMemoryExporter()
+		DeferCleanup(tracex.Register(exporter))
+	})
+
+	It("should retain the trace of the request once it completes", func() {
+		ctx, parent := tracex.StartRoot(context.Background(), "client", true)
+		defer parent.End()
+
+		req, done := context.WithCancel(incoming(ctx))
+		background := tracex.Inherit(context.Background(), req)
+		done()
+
+		bctx, child := tracex.Start(background, "background")
+		child.End()
+		Expect(bctx.Err()).To(Succeed())
+		Expect(child.SpanContext().TraceID()).To(Equal(parent.SpanContext().TraceID()))
+
+		// the inherited trace is propagated to the requests of the background work.
+		sc, ok := tracex.Remote(incoming(background))
+		Expect(ok).To(BeTrue())
+		Expect(sc.TraceID()).To(Equal(parent.SpanContext().TraceID()))
+		Expect(exporter.GetSpans()).To(HaveLen(1))
+	})
+})
@@ -76,7 +76,7 @@ func (t Dialer) DialContext(ctx context.Context, network string, address string)
 			log.Println("WARN: muxer.DialContext tls connection detected but no protocol was negotiated, expected to negotiate bw.mux")
 		}
 
-		if s.NegotiatedProtocol != "bw.mux" {
+		if s.NegotiatedProtocol != "bw.mux" && s.NegotiatedProtocol != ALPNSession {
 			return conn, nil
 		}
 	}
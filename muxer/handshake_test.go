@@ -0,0 +1,46 @@
+package muxer_test
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/james-lawrence/bw/internal/tlsx"
+	. "github.com/james-lawrence/bw/muxer"
+)
+
+var _ = Describe("OptionHandshakeTimeout", func() {
+	It("should tear down connections that never complete the tls handshake", func() {
+		template, err := tlsx.X509Template(time.Hour, tlsx.X509OptionHosts("127.0.0.1"))
+		Expect(err).To(Succeed())
+		priv, derBytes, err := tlsx.SelfSignedRSAGen(1024, template)
+		Expect(err).To(Succeed())
+
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).To(Succeed())
+		defer l.Close()
+
+		ctx, done := context.WithCancel(context.Background())
+		defer done()
+
+		m := New(OptionHandshakeTimeout(100 * time.Millisecond))
+		go Listen(ctx, m, tls.NewListener(l, &tls.Config{
+			Certificates: []tls.Certificate{{Certificate: [][]byte{derBytes}, PrivateKey: priv}},
+		}))
+
+		conn, err := net.Dial("tcp", l.Addr().String())
+		Expect(err).To(Succeed())
+		defer conn.Close()
+
+		started := time.Now()
+		Expect(conn.SetReadDeadline(time.Now().Add(5 * time.Second))).To(Succeed())
+		_, err = conn.Read(make([]byte, 1))
+		Expect(err).To(HaveOccurred())
+		Expect(err).ToNot(MatchError(ContainSubstring("timeout")))
+		Expect(time.Since(started)).To(BeNumerically("<", time.Second))
+	})
+})
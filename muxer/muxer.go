@@ -15,6 +15,7 @@ import (
 
 	"github.com/james-lawrence/bw/internal/debugx"
 	"github.com/james-lawrence/bw/internal/errorsx"
+	"github.com/james-lawrence/bw/internal/tlsx"
 	"github.com/pkg/errors"
 	"google.golang.org/protobuf/proto"
 )
@@ -31,19 +32,40 @@ func (t ErrAlreadyBound) Error() string {
 
 type option func(*M) error
 
+// OptionHandshakeTimeout maximum duration of the tls handshake of accepted connections,
+// stalled handshakes are aborted. <= 0 uses the tlsx.DefaultHandshakeTimeout.
+func OptionHandshakeTimeout(d time.Duration) option {
+	return func(m *M) error {
+		if d <= 0 {
+			d = tlsx.DefaultHandshakeTimeout
+		}
+
+		m.handshakeTimeout = d
+		return nil
+	}
+}
+
 func New(options ...option) *M {
-	return &M{
-		m:             &sync.RWMutex{},
-		protocols:     make(map[Protocol]*listener, 10),
-		acceptTimeout: time.Second,
+	m := &M{
+		m:                &sync.RWMutex{},
+		protocols:        make(map[Protocol]*listener, 10),
+		acceptTimeout:    time.Second,
+		handshakeTimeout: tlsx.DefaultHandshakeTimeout,
+	}
+
+	for _, opt := range options {
+		errorsx.MaybeLog(opt(m))
 	}
+
+	return m
 }
 
 type M struct {
-	m             *sync.RWMutex
-	protocols     map[Protocol]*listener
-	defaulted     *listener
-	acceptTimeout time.Duration
+	m                *sync.RWMutex
+	protocols        map[Protocol]*listener
+	defaulted        *listener
+	acceptTimeout    time.Duration
+	handshakeTimeout time.Duration
 }
 
 func (t *M) bind(p string, addr net.Addr) (net.Listener, error) {
@@ -162,12 +184,16 @@ func accept(ctx context.Context, m *M, conn net.Conn) (err error) {
 	// defer log.Println("accept completed")
 
 	if tlsconn, ok := conn.(*tls.Conn); ok {
-		if err = tlsconn.Handshake(); err != nil {
+		hctx, hdone := context.WithTimeout(ctx, m.handshakeTimeout)
+		err = tlsconn.HandshakeContext(hctx)
+		hdone()
+
+		if err != nil {
 			conn.Close()
 			return errors.Wrap(err, "tls handshake failed")
 		}
 
-		if s := tlsconn.ConnectionState(); s.NegotiatedProtocol != "bw.mux" {
+		if s := tlsconn.ConnectionState(); s.NegotiatedProtocol != "bw.mux" && s.NegotiatedProtocol != ALPNSession {
 			if m.defaulted == nil {
 				conn.Close()
 				return errors.Wrap(err, "tls unknown protocol")
@@ -183,6 +209,11 @@ func accept(ctx context.Context, m *M, conn net.Conn) (err error) {
 		return errors.Wrap(err, "muxer.handshakeInbound failed")
 	}
 
+	if req == Proto(protocolSession) {
+		go serveSession(ctx, m, conn)
+		return nil
+	}
+
 	m.m.RLock()
 	protocol, ok := m.protocols[req]
 	m.m.RUnlock()
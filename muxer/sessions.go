@@ -0,0 +1,242 @@
+package muxer
+
+import (
+	"context"
+	"crypto/tls"
+	"log"
+	"net"
+	sync "sync"
+
+	"github.com/hashicorp/yamux"
+	"github.com/pkg/errors"
+
+	"github.com/james-lawrence/bw/internal/debugx"
+)
+
+// protocol used to establish a multiplexed session, each stream of the session
+// is handshaked and routed to its protocol like an individual connection.
+const protocolSession = "bw.mux.session"
+
+// ALPNSession tls protocol advertising support for multiplexed sessions, listeners must prefer
+// it over bw.mux. peers that negotiate bw.mux (agents predating sessions) are dialed per connection.
+const ALPNSession = protocolSession
+
+func sessionConfig() *yamux.Config {
+	c := yamux.DefaultConfig()
+	c.LogOutput = log.Writer()
+	return c
+}
+
+// NewSessions dialer that multiplexes every connection to an address over a single
+// underlying connection, established using the provided dialer. tls connections are
+// only multiplexed when the peer negotiates ALPNSession.
+func NewSessions(d dialer) *Sessions {
+	return &Sessions{
+		d:        d,
+		digest:   Proto(protocolSession),
+		m:        &sync.Mutex{},
+		sessions: make(map[string]*yamux.Session),
+		dialing:  make(map[string]chan struct{}),
+	}
+}
+
+// Sessions maintains a multiplexed session per address.
+type Sessions struct {
+	d        dialer
+	digest   Protocol
+	m        *sync.Mutex
+	sessions map[string]*yamux.Session
+	dialing  map[string]chan struct{}
+}
+
+// DialContext opens a stream to the address, establishing the session if necessary.
+// when the address doesn't support the muxer the underlying connection is returned as is.
+func (t *Sessions) DialContext(ctx context.Context, network string, address string) (conn net.Conn, err error) {
+	var (
+		s *yamux.Session
+	)
+
+	if s, conn, err = t.session(ctx, network, address); err != nil || conn != nil {
+		return conn, err
+	}
+
+	if conn, err = s.Open(); err == nil {
+		return conn, nil
+	}
+
+	// the session is no longer usable, establish a new one.
+	t.release(network, address, s)
+	if s, conn, err = t.session(ctx, network, address); err != nil || conn != nil {
+		return conn, err
+	}
+
+	conn, err = s.Open()
+	return conn, errors.Wrapf(err, "muxer.Sessions failed to open stream: %s://%s", network, address)
+}
+
+// Close every session.
+func (t *Sessions) Close() (err error) {
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	for k, s := range t.sessions {
+		if cause := s.Close(); cause != nil {
+			err = cause
+		}
+		delete(t.sessions, k)
+	}
+
+	return err
+}
+
+func (t *Sessions) cached(key string) *yamux.Session {
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	if s, ok := t.sessions[key]; ok && !s.IsClosed() {
+		return s
+	}
+
+	return nil
+}
+
+// session to the address, when the peer doesn't support sessions the connection
+// is returned instead. the lock isn't held while dialing, a slow peer shouldn't block
+// the sessions of other peers. concurrent callers await the in flight dial to the address.
+func (t *Sessions) session(ctx context.Context, network, address string) (s *yamux.Session, conn net.Conn, err error) {
+	key := network + "://" + address
+
+	t.m.Lock()
+	if s, ok := t.sessions[key]; ok && !s.IsClosed() {
+		t.m.Unlock()
+		return s, nil, nil
+	}
+
+	pending, inflight := t.dialing[key]
+	if !inflight {
+		pending = make(chan struct{})
+		t.dialing[key] = pending
+	}
+	t.m.Unlock()
+
+	if !inflight {
+		defer func() {
+			t.m.Lock()
+			delete(t.dialing, key)
+			t.m.Unlock()
+			close(pending)
+		}()
+
+		return t.establish(ctx, network, address)
+	}
+
+	select {
+	case <-pending:
+	case <-ctx.Done():
+		return nil, nil, ctx.Err()
+	}
+
+	if s = t.cached(key); s != nil {
+		return s, nil, nil
+	}
+
+	// the in flight dial failed or the peer doesn't support sessions.
+	return t.establish(ctx, network, address)
+}
+
+func (t *Sessions) establish(ctx context.Context, network, address string) (s *yamux.Session, conn net.Conn, err error) {
+	key := network + "://" + address
+
+	if conn, err = t.dial(ctx, network, address); err != nil {
+		return nil, nil, err
+	}
+
+	if tlsconn, ok := conn.(*tls.Conn); ok && tlsconn.ConnectionState().NegotiatedProtocol != ALPNSession {
+		return nil, conn, nil
+	}
+
+	if err = handshakeOutbound(t.digest[:], conn); err != nil {
+		conn.Close()
+		return nil, nil, errors.Wrapf(err, "muxer.Sessions outbound handshake failed: %s", key)
+	}
+
+	if s, err = yamux.Client(conn, sessionConfig()); err != nil {
+		conn.Close()
+		return nil, nil, errors.Wrapf(err, "muxer.Sessions failed to establish session: %s", key)
+	}
+
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	// another dial established the session in the meantime, use it instead.
+	if existing, ok := t.sessions[key]; ok && !existing.IsClosed() {
+		s.Close()
+		return existing, nil, nil
+	}
+
+	t.sessions[key] = s
+
+	return s, nil, nil
+}
+
+// dial the address, completing the tls handshake to learn the negotiated protocol.
+func (t *Sessions) dial(ctx context.Context, network, address string) (conn net.Conn, err error) {
+	if conn, err = t.d.DialContext(ctx, network, address); err != nil {
+		return nil, errors.Wrapf(err, "muxer.Sessions dial failed: %s://%s", network, address)
+	}
+
+	if tlsconn, ok := conn.(*tls.Conn); ok {
+		if err = tlsconn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return nil, errors.Wrapf(err, "muxer.Sessions tls handshake failed: %s://%s", network, address)
+		}
+	}
+
+	return conn, nil
+}
+
+func (t *Sessions) release(network, address string, s *yamux.Session) {
+	key := network + "://" + address
+
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	if t.sessions[key] == s {
+		delete(t.sessions, key)
+	}
+
+	s.Close()
+}
+
+// serve the streams of an inbound session, each stream is accepted like an individual connection.
+func serveSession(ctx context.Context, m *M, conn net.Conn) {
+	s, err := yamux.Server(conn, sessionConfig())
+	if err != nil {
+		conn.Close()
+		debugx.Println("session failed", err)
+		return
+	}
+	defer s.Close()
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			s.Close()
+		case <-s.CloseChan():
+		}
+	}()
+
+	for {
+		stream, err := s.Accept()
+		if err != nil {
+			return
+		}
+
+		go func() {
+			if err := accept(ctx, m, stream); err != nil {
+				stream.Close()
+				debugx.Println("session stream accept failed", err)
+			}
+		}()
+	}
+}
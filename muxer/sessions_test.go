@@ -0,0 +1,212 @@
+package muxer_test
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/james-lawrence/bw/internal/tlsx"
+	. "github.com/james-lawrence/bw/muxer"
+)
+
+// countingDialer records the number of underlying connections.
+type countingDialer struct {
+	net.Dialer
+	dialed int64
+}
+
+func (t *countingDialer) DialContext(ctx context.Context, network string, address string) (net.Conn, error) {
+	atomic.AddInt64(&t.dialed, 1)
+	return t.Dialer.DialContext(ctx, network, address)
+}
+
+// countingTLSDialer records the number of underlying tls connections.
+type countingTLSDialer struct {
+	tls.Dialer
+	dialed int64
+}
+
+func (t *countingTLSDialer) DialContext(ctx context.Context, network string, address string) (net.Conn, error) {
+	atomic.AddInt64(&t.dialed, 1)
+	return t.Dialer.DialContext(ctx, network, address)
+}
+
+// echo each line back prefixed with the name of the protocol.
+func echo(l net.Listener, name string) {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+
+		go func() {
+			defer conn.Close()
+			s := bufio.NewScanner(conn)
+			for s.Scan() {
+				if _, err := fmt.Fprintf(conn, "%s %s\n", name, s.Text()); err != nil {
+					return
+				}
+			}
+		}()
+	}
+}
+
+var _ = Describe("Sessions", func() {
+	It("should multiplex concurrent streams over a single connection", func() {
+		const streams = 10
+
+		m := New()
+		l, err := net.Listen("tcp", ":0")
+		Expect(err).To(Succeed())
+		defer l.Close()
+		l1, err := m.Bind("proto1", l.Addr())
+		Expect(err).To(Succeed())
+		defer l1.Close()
+		l2, err := m.Bind("proto2", l.Addr())
+		Expect(err).To(Succeed())
+		defer l2.Close()
+
+		ctx, done := context.WithCancel(context.Background())
+		defer done()
+		go echo(l1, "proto1")
+		go echo(l2, "proto2")
+		go Listen(ctx, m, l)
+
+		underlying := &countingDialer{}
+		sessions := NewSessions(underlying)
+		defer sessions.Close()
+
+		var wg sync.WaitGroup
+		for i := 0; i < streams; i++ {
+			i := i
+			protocol := fmt.Sprintf("proto%d", i%2+1)
+			wg.Add(1)
+			go func() {
+				defer GinkgoRecover()
+				defer wg.Done()
+
+				conn, err := NewDialer(protocol, sessions).DialContext(context.Background(), "tcp", l.Addr().String())
+				Expect(err).To(Succeed())
+				defer conn.Close()
+
+				r := bufio.NewReader(conn)
+				for j := 0; j < 3; j++ {
+					_, err = fmt.Fprintf(conn, "stream %d message %d\n", i, j)
+					Expect(err).To(Succeed())
+					line, err := r.ReadString('\n')
+					Expect(err).To(Succeed())
+					Expect(line).To(Equal(fmt.Sprintf("%s stream %d message %d\n", protocol, i, j)))
+				}
+			}()
+		}
+
+		wg.Wait()
+		Expect(atomic.LoadInt64(&underlying.dialed)).To(Equal(int64(1)))
+	})
+
+	It("should keep streams operating when another stream closes", func() {
+		m := New()
+		l, err := net.Listen("tcp", ":0")
+		Expect(err).To(Succeed())
+		defer l.Close()
+		l1, err := m.Bind("proto1", l.Addr())
+		Expect(err).To(Succeed())
+		defer l1.Close()
+
+		ctx, done := context.WithCancel(context.Background())
+		defer done()
+		go echo(l1, "proto1")
+		go Listen(ctx, m, l)
+
+		sessions := NewSessions(&net.Dialer{})
+		defer sessions.Close()
+		d := NewDialer("proto1", sessions)
+
+		c1, err := d.DialContext(context.Background(), "tcp", l.Addr().String())
+		Expect(err).To(Succeed())
+		c2, err := d.DialContext(context.Background(), "tcp", l.Addr().String())
+		Expect(err).To(Succeed())
+		defer c2.Close()
+
+		Expect(c1.Close()).To(Succeed())
+
+		_, err = fmt.Fprintln(c2, "hello")
+		Expect(err).To(Succeed())
+		line, err := bufio.NewReader(c2).ReadString('\n')
+		Expect(err).To(Succeed())
+		Expect(line).To(Equal("proto1 hello\n"))
+	})
+
+	Context("tls", func() {
+		// serve proto1 over tls negotiating the provided protocols.
+		serve := func(ctx context.Context, protocols ...string) net.Addr {
+			template, err := tlsx.X509Template(time.Hour, tlsx.X509OptionHosts("127.0.0.1"))
+			Expect(err).To(Succeed())
+			priv, derBytes, err := tlsx.SelfSignedRSAGen(1024, template)
+			Expect(err).To(Succeed())
+
+			m := New()
+			l, err := net.Listen("tcp", "127.0.0.1:0")
+			Expect(err).To(Succeed())
+			DeferCleanup(l.Close)
+			l1, err := m.Bind("proto1", l.Addr())
+			Expect(err).To(Succeed())
+			DeferCleanup(l1.Close)
+
+			go echo(l1, "proto1")
+			go Listen(ctx, m, tls.NewListener(l, &tls.Config{
+				Certificates: []tls.Certificate{{Certificate: [][]byte{derBytes}, PrivateKey: priv}},
+				NextProtos:   protocols,
+			}))
+
+			return l.Addr()
+		}
+
+		dial := func(underlying *countingTLSDialer, addr net.Addr, streams int) {
+			sessions := NewSessions(underlying)
+			defer sessions.Close()
+
+			for i := 0; i < streams; i++ {
+				conn, err := NewDialer("proto1", sessions).DialContext(context.Background(), "tcp", addr.String())
+				Expect(err).To(Succeed())
+				defer conn.Close()
+
+				_, err = fmt.Fprintf(conn, "stream %d\n", i)
+				Expect(err).To(Succeed())
+				line, err := bufio.NewReader(conn).ReadString('\n')
+				Expect(err).To(Succeed())
+				Expect(line).To(Equal(fmt.Sprintf("proto1 stream %d\n", i)))
+			}
+		}
+
+		client := func() *countingTLSDialer {
+			return &countingTLSDialer{Dialer: tls.Dialer{Config: &tls.Config{InsecureSkipVerify: true, NextProtos: []string{ALPNSession, "bw.mux"}}}}
+		}
+
+		It("should multiplex when the peer negotiates sessions", func() {
+			ctx, done := context.WithCancel(context.Background())
+			defer done()
+
+			underlying := client()
+			dial(underlying, serve(ctx, ALPNSession, "bw.mux"), 3)
+			Expect(atomic.LoadInt64(&underlying.dialed)).To(Equal(int64(1)))
+		})
+
+		It("should dial each connection when the peer predates sessions", func() {
+			ctx, done := context.WithCancel(context.Background())
+			defer done()
+
+			underlying := client()
+			dial(underlying, serve(ctx, "bw.mux"), 3)
+			Expect(atomic.LoadInt64(&underlying.dialed)).To(Equal(int64(3)))
+		})
+	})
+})
@@ -2,7 +2,9 @@ package notary
 
 import (
 	"context"
+	"io"
 
+	"github.com/pkg/errors"
 	"google.golang.org/grpc"
 
 	"github.com/james-lawrence/bw/internal/errorsx"
@@ -102,3 +104,29 @@ func (t Client) Search(ctx context.Context, req *SearchRequest) (resp Notary_Sea
 
 	return c.Search(ctx, req)
 }
+
+// Lookup the grant of the fingerprint.
+func (t Client) Lookup(ctx context.Context, fingerprint string) (g *Grant, err error) {
+	var (
+		s    Notary_SearchClient
+		page *SearchResponse
+	)
+
+	if s, err = t.Search(ctx, &SearchRequest{}); err != nil {
+		return g, err
+	}
+
+	for page, err = s.Recv(); err == nil; page, err = s.Recv() {
+		for _, g = range page.Grants {
+			if g.Fingerprint == fingerprint {
+				return g, nil
+			}
+		}
+	}
+
+	if err == io.EOF {
+		return nil, errors.Errorf("unknown fingerprint: %s", fingerprint)
+	}
+
+	return nil, err
+}
@@ -0,0 +1,149 @@
+package notary
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/james-lawrence/bw/internal/rsax"
+)
+
+// NewReloadingAgentSigner loads or generates the agent's ssh key, see NewAgentSigner.
+// the key is reloaded from disk once it changes, allowing long running agents to
+// pickup the replacement key committed by a rotation, see Rotation.
+func NewReloadingAgentSigner(root string) (s *ReloadingSigner, err error) {
+	return newReloadingSigner(AgentSignerPath(root), rsax.Auto)
+}
+
+func newReloadingSigner(location string, kgen keyGen) (s *ReloadingSigner, err error) {
+	var (
+		current Signer
+		info    os.FileInfo
+	)
+
+	if current, err = newAutoSignerPath(location, "", kgen); err != nil {
+		return nil, err
+	}
+
+	if info, err = os.Stat(location); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return &ReloadingSigner{location: location, current: current, info: info}, nil
+}
+
+// ReloadingSigner signs requests with the key on disk, see NewReloadingAgentSigner.
+type ReloadingSigner struct {
+	location string
+	m        sync.RWMutex
+	current  Signer
+	info     os.FileInfo
+}
+
+// Signer currently in use, reloaded when the key on disk has changed.
+func (t *ReloadingSigner) Signer() Signer {
+	info, err := os.Stat(t.location)
+
+	t.m.RLock()
+	current, changed := t.current, err == nil && modified(t.info, info)
+	t.m.RUnlock()
+
+	// the key is missing while a rotation commits the replacement, continue
+	// with the loaded key until the replacement is in place.
+	if !changed {
+		return current
+	}
+
+	if err = t.reload(info); err != nil {
+		log.Println("unable to reload the signing key, continuing with the loaded key", t.location, err)
+		return current
+	}
+
+	t.m.RLock()
+	defer t.m.RUnlock()
+	return t.current
+}
+
+// Reload the key from disk regardless of modifications.
+func (t *ReloadingSigner) Reload() (err error) {
+	var (
+		info os.FileInfo
+	)
+
+	if info, err = os.Stat(t.location); err != nil {
+		return errors.Wrapf(err, "unable to reload the signing key: %s", t.location)
+	}
+
+	return t.reload(info)
+}
+
+// ReloadOnSignal reloads the key each time one of the signals is received.
+// the signals are registered before returning, the reloads happen in the background
+// until the context is cancelled.
+func (t *ReloadingSigner) ReloadOnSignal(ctx context.Context, sigs ...os.Signal) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, sigs...)
+
+	go func() {
+		defer signal.Stop(signals)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-signals:
+				if err := t.Reload(); err != nil {
+					log.Println(err)
+					continue
+				}
+
+				log.Println("reloaded the signing key", t.location)
+			}
+		}
+	}()
+}
+
+// GetRequestMetadata implements grpc.PerRPCCredentials
+func (t *ReloadingSigner) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return t.Signer().GetRequestMetadata(ctx, uri...)
+}
+
+// RequireTransportSecurity implements grpc.PerRPCCredentials
+func (t *ReloadingSigner) RequireTransportSecurity() bool {
+	return t.Signer().RequireTransportSecurity()
+}
+
+func (t *ReloadingSigner) reload(info os.FileInfo) (err error) {
+	var (
+		encoded []byte
+		updated Signer
+	)
+
+	if encoded, err = os.ReadFile(t.location); err != nil {
+		return errors.Wrapf(err, "unable to read the signing key: %s", t.location)
+	}
+
+	if updated, err = NewSigner(encoded); err != nil {
+		return errors.Wrapf(err, "unable to load the signing key: %s", t.location)
+	}
+
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	if t.current.fingerprint != updated.fingerprint {
+		log.Println("signing key changed", t.location, t.current.fingerprint, "->", updated.fingerprint)
+	}
+
+	t.current, t.info = updated, info
+
+	return nil
+}
+
+// modified detects the replacement of the file as well as changes to its contents.
+func modified(previous, current os.FileInfo) bool {
+	return !os.SameFile(previous, current) || !previous.ModTime().Equal(current.ModTime()) || previous.Size() != current.Size()
+}
@@ -0,0 +1,89 @@
+package notary
+
+import (
+	"context"
+	"os"
+	"syscall"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/james-lawrence/bw/internal/rsax"
+	"github.com/james-lawrence/bw/internal/testingx"
+)
+
+var _ = Describe("ReloadingSigner", func() {
+	var (
+		location string
+	)
+
+	quick := func() ([]byte, error) {
+		return rsax.Generate(1024)
+	}
+
+	BeforeEach(func() {
+		location = AgentSignerPath(testingx.TempDir())
+	})
+
+	It("should generate the key when missing", func() {
+		s, err := newReloadingSigner(location, quick)
+		Expect(err).To(Succeed())
+		Expect(location).To(BeARegularFile())
+
+		loaded, err := newAutoSignerPath(location, "", quick)
+		Expect(err).To(Succeed())
+		Expect(s.Signer().fingerprint).To(Equal(loaded.fingerprint))
+	})
+
+	It("should pickup the committed replacement of a rotation", func() {
+		s, err := newReloadingSigner(location, quick)
+		Expect(err).To(Succeed())
+		original := s.Signer().fingerprint
+
+		r, err := newRotation(location, "", quick)
+		Expect(err).To(Succeed())
+		Expect(s.Signer().fingerprint).To(Equal(original))
+
+		Expect(r.Commit()).To(Succeed())
+		Expect(s.Signer().fingerprint).To(Equal(r.Next.fingerprint))
+	})
+
+	It("should continue with the loaded key while the key is missing", func() {
+		s, err := newReloadingSigner(location, quick)
+		Expect(err).To(Succeed())
+		original := s.Signer().fingerprint
+
+		Expect(os.Remove(location)).To(Succeed())
+		Expect(s.Signer().fingerprint).To(Equal(original))
+		Expect(s.Reload()).ToNot(Succeed())
+	})
+
+	It("should reload the key on signal", func() {
+		s, err := newReloadingSigner(location, quick)
+		Expect(err).To(Succeed())
+
+		replacement, err := quick()
+		Expect(err).To(Succeed())
+		expected, err := NewSigner(replacement)
+		Expect(err).To(Succeed())
+
+		// retain the modification time to ensure the signal forces the reload.
+		info, err := os.Stat(location)
+		Expect(err).To(Succeed())
+		Expect(os.WriteFile(location, replacement, 0600)).To(Succeed())
+		Expect(os.Chtimes(location, info.ModTime(), info.ModTime())).To(Succeed())
+
+		ctx, done := context.WithCancel(context.Background())
+		defer done()
+
+		s.ReloadOnSignal(ctx, syscall.SIGUSR1)
+		Expect(syscall.Kill(syscall.Getpid(), syscall.SIGUSR1)).To(Succeed())
+
+		Eventually(func() string {
+			s.m.RLock()
+			defer s.m.RUnlock()
+			return s.current.fingerprint
+		}, time.Second).Should(Equal(expected.fingerprint))
+	})
+})
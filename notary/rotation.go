@@ -0,0 +1,190 @@
+package notary
+
+import (
+	"context"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/james-lawrence/bw"
+	"github.com/james-lawrence/bw/internal/errorsx"
+	"github.com/james-lawrence/bw/internal/rsax"
+)
+
+// suffixes of the keys of an in progress rotation, stored alongside the current key.
+const (
+	suffixStaged  = ".next"
+	suffixRetired = ".retired"
+)
+
+// AgentSignerPath the location of the agent's signing key within the root, see NewAgentSigner.
+func AgentSignerPath(root string) string {
+	return filepath.Join(root, bw.DefaultAgentNotaryKey)
+}
+
+// NewRotation stages a replacement for the signing key at the location. an interrupted
+// rotation resumes with the previously staged key, or when interrupted after the
+// replacement was committed, with the retained key. the current key must exist.
+func NewRotation(location string, comment string) (r Rotation, err error) {
+	return newRotation(location, comment, rsax.Auto)
+}
+
+func newRotation(location string, comment string, kgen keyGen) (r Rotation, err error) {
+	var (
+		encoded []byte
+	)
+
+	r.location = location
+
+	// the replacement was committed but the retained key was never revoked.
+	if r.Current, err = loadSigner(location + suffixRetired); err == nil {
+		if r.Next, err = loadSigner(location); err != nil {
+			return r, errors.Wrap(err, "unable to load the committed replacement key")
+		}
+
+		r.committed = true
+		return r, nil
+	} else if !os.IsNotExist(errors.Cause(err)) {
+		return r, errors.Wrap(err, "unable to load the retained key")
+	}
+
+	if encoded, err = os.ReadFile(location); err != nil {
+		return r, errors.Wrapf(err, "unable to read the current key: %s", location)
+	}
+
+	if r.Current, err = NewSigner(encoded); err != nil {
+		return r, errors.Wrapf(err, "unable to load the current key: %s", location)
+	}
+
+	if r.Next, err = newAutoSignerPath(location+suffixStaged, comment, kgen); err != nil {
+		return r, errors.Wrap(err, "unable to stage the replacement key")
+	}
+
+	return r, nil
+}
+
+// loadSigner from the key at the location.
+func loadSigner(location string) (s Signer, err error) {
+	var (
+		encoded []byte
+	)
+
+	if encoded, err = os.ReadFile(location); err != nil {
+		return s, errors.WithStack(err)
+	}
+
+	if s, err = NewSigner(encoded); err != nil {
+		return s, errors.Wrapf(err, "invalid key: %s", location)
+	}
+
+	return s, nil
+}
+
+// Rotation of a signing key without downtime. the replacement key is granted the
+// permissions of the current key, then replaces the current key on disk. both keys
+// verify until the current key is revoked, allowing the processes still signing with
+// the current key to pickup the replacement during the grace window.
+type Rotation struct {
+	location  string
+	committed bool   // replacement was committed by an interrupted rotation.
+	Current   Signer // key being retired.
+	Next      Signer // replacement key.
+}
+
+// Grant of the replacement key with the permissions.
+func (t Rotation) Grant(p *Permission) *Grant {
+	_, pub, _ := t.Next.AutoSignerInfo()
+	return (&Grant{Permission: p, Authorization: pub}).EnsureDefaults()
+}
+
+// Commit replaces the current key on disk with the replacement key, the current key is
+// retained alongside the replacement until the rotation is retired.
+func (t Rotation) Commit() (err error) {
+	var (
+		staged  = t.location + suffixStaged
+		retired = t.location + suffixRetired
+	)
+
+	if err = rename(t.location, retired); err != nil {
+		return errors.Wrap(err, "unable to retain the current key")
+	}
+
+	if err = rename(staged, t.location); err != nil {
+		return errorsx.Compact(errors.Wrap(err, "unable to replace the current key"), rename(retired, t.location))
+	}
+
+	return nil
+}
+
+// Retire removes the retained key from disk, must be called after the key is revoked.
+func (t Rotation) Retire() error {
+	retired := t.location + suffixRetired
+	return errorsx.Compact(
+		os.Remove(retired),
+		os.Remove(retired+".pub"),
+	)
+}
+
+// Rotate the key across the cluster: grant the replacement key the permissions of the
+// current key, commit the replacement, wait out the grace window while both keys verify,
+// then revoke and retire the current key. a rotation interrupted after the commit resumes
+// with the grace window.
+func (t Rotation) Rotate(ctx context.Context, c Client, grace time.Duration) (err error) {
+	var (
+		fp = t.Current.fingerprint
+	)
+
+	if !t.committed {
+		if err = t.commit(ctx, c); err != nil {
+			return err
+		}
+	}
+
+	log.Println("replacement key", t.Next.fingerprint, "committed, revoking", fp, "in", grace)
+
+	select {
+	case <-ctx.Done():
+		return errors.Wrap(ctx.Err(), "rotation interrupted during the grace window, both keys remain valid")
+	case <-time.After(grace):
+	}
+
+	if _, err = c.Revoke(fp); err != nil {
+		return errors.Wrapf(err, "unable to revoke the current key: %s", fp)
+	}
+
+	return t.Retire()
+}
+
+// commit grants the replacement key the permissions of the current key then commits the replacement.
+func (t Rotation) commit(ctx context.Context, c Client) (err error) {
+	var (
+		current *Grant
+		fp      = t.Current.fingerprint
+	)
+
+	if current, err = c.Lookup(ctx, fp); err != nil {
+		return errors.Wrapf(err, "unable to lookup the permissions of the current key: %s", fp)
+	}
+
+	if _, err = c.Grant(t.Grant(current.Permission)); err != nil {
+		return errors.Wrap(err, "unable to grant the replacement key")
+	}
+
+	return t.Commit()
+}
+
+// rename the private key and the public key.
+func rename(from, to string) error {
+	if err := os.Rename(from, to); err != nil {
+		return err
+	}
+
+	if err := os.Rename(from+".pub", to+".pub"); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,176 @@
+package notary
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc"
+
+	"github.com/james-lawrence/bw/internal/errorsx"
+	"github.com/james-lawrence/bw/internal/rsax"
+	"github.com/james-lawrence/bw/internal/testingx"
+)
+
+type staticDialer struct {
+	conn *grpc.ClientConn
+}
+
+func (t staticDialer) DialContext(context.Context, ...grpc.DialOption) (*grpc.ClientConn, error) {
+	return t.conn, nil
+}
+
+var _ = Describe("Rotation", func() {
+	var (
+		location string
+		current  Signer
+		storage  Directory
+	)
+
+	quick := func() ([]byte, error) {
+		return rsax.Generate(1024)
+	}
+
+	// verify the token of the signer against the storage.
+	verify := func(s Signer) error {
+		token, err := s.Token()
+		Expect(err).To(Succeed())
+
+		return NewAuthChecker(storage, func(p *Permission) error {
+			if !p.Deploy {
+				return errorsx.String("invalid credentials")
+			}
+			return nil
+		}).Authorization([]byte(token))
+	}
+
+	BeforeEach(func() {
+		var err error
+
+		location = AgentSignerPath(testingx.TempDir())
+		current, err = newAutoSignerPath(location, "", quick)
+		Expect(err).To(Succeed())
+
+		_, pub, _ := current.AutoSignerInfo()
+		storage = NewDirectory(testingx.TempDir())
+		_, err = storage.Insert((&Grant{Authorization: pub, Permission: UserFull()}).EnsureDefaults())
+		Expect(err).To(Succeed())
+	})
+
+	It("should stage a replacement key", func() {
+		r, err := newRotation(location, "", quick)
+		Expect(err).To(Succeed())
+		Expect(r.Current.fingerprint).To(Equal(current.fingerprint))
+		Expect(r.Next.fingerprint).ToNot(Equal(current.fingerprint))
+		Expect(location + suffixStaged).To(BeARegularFile())
+
+		resumed, err := newRotation(location, "", quick)
+		Expect(err).To(Succeed())
+		Expect(resumed.Next.fingerprint).To(Equal(r.Next.fingerprint))
+	})
+
+	It("should fail without a current key", func() {
+		_, err := newRotation(AgentSignerPath(testingx.TempDir()), "", quick)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should verify both keys until the current key is revoked", func() {
+		r, err := newRotation(location, "", quick)
+		Expect(err).To(Succeed())
+
+		Expect(verify(r.Next)).ToNot(Succeed())
+
+		_, err = storage.Insert(r.Grant(UserFull()))
+		Expect(err).To(Succeed())
+		Expect(r.Commit()).To(Succeed())
+
+		Expect(verify(r.Current)).To(Succeed())
+		Expect(verify(r.Next)).To(Succeed())
+
+		loaded, err := newAutoSignerPath(location, "", quick)
+		Expect(err).To(Succeed())
+		Expect(loaded.fingerprint).To(Equal(r.Next.fingerprint))
+
+		revoked, err := storage.Lookup(r.Current.fingerprint)
+		Expect(err).To(Succeed())
+		_, err = storage.Delete(revoked)
+		Expect(err).To(Succeed())
+		Expect(r.Retire()).To(Succeed())
+
+		Expect(verify(r.Current)).ToNot(Succeed())
+		Expect(verify(r.Next)).To(Succeed())
+		Expect(location + suffixRetired).ToNot(BeAnExistingFile())
+	})
+
+	Describe("Rotate", func() {
+		client := func() (Client, func()) {
+			svc := New("", nil, storage)
+			conn, server := testingx.NewGRPCServer(func(s *grpc.Server) {
+				svc.Bind(s)
+			}, grpc.WithPerRPCCredentials(current))
+
+			return NewClient(staticDialer{conn: conn}), func() { testingx.GRPCCleanup(conn, server) }
+		}
+
+		It("should retain both keys when interrupted during the grace window", func() {
+			c, cleanup := client()
+			defer cleanup()
+
+			r, err := newRotation(location, "", quick)
+			Expect(err).To(Succeed())
+
+			ctx, done := context.WithTimeout(context.Background(), 100*time.Millisecond)
+			defer done()
+
+			Expect(r.Rotate(ctx, c, time.Hour)).To(MatchError(ContainSubstring("both keys remain valid")))
+			Expect(verify(r.Current)).To(Succeed())
+			Expect(verify(r.Next)).To(Succeed())
+			Expect(location + suffixRetired).To(BeARegularFile())
+		})
+
+		It("should revoke the current key after the grace window", func() {
+			c, cleanup := client()
+			defer cleanup()
+
+			r, err := newRotation(location, "", quick)
+			Expect(err).To(Succeed())
+
+			Expect(r.Rotate(context.Background(), c, 0)).To(Succeed())
+			Expect(verify(r.Current)).ToNot(Succeed())
+			Expect(verify(r.Next)).To(Succeed())
+
+			g, err := storage.Lookup(r.Next.fingerprint)
+			Expect(err).To(Succeed())
+			Expect(g.Permission.Grant).To(BeTrue())
+			Expect(location + suffixRetired).ToNot(BeAnExistingFile())
+		})
+
+		It("should revoke the retained key when resuming a rotation interrupted after the commit", func() {
+			c, cleanup := client()
+			defer cleanup()
+
+			r, err := newRotation(location, "", quick)
+			Expect(err).To(Succeed())
+
+			ctx, done := context.WithTimeout(context.Background(), 100*time.Millisecond)
+			defer done()
+			Expect(r.Rotate(ctx, c, time.Hour)).To(MatchError(ContainSubstring("both keys remain valid")))
+
+			resumed, err := newRotation(location, "", quick)
+			Expect(err).To(Succeed())
+			Expect(resumed.Current.fingerprint).To(Equal(r.Current.fingerprint))
+			Expect(resumed.Next.fingerprint).To(Equal(r.Next.fingerprint))
+			Expect(location + suffixStaged).ToNot(BeAnExistingFile())
+
+			Expect(resumed.Rotate(context.Background(), c, 0)).To(Succeed())
+			Expect(verify(r.Current)).ToNot(Succeed())
+			Expect(verify(r.Next)).To(Succeed())
+			Expect(location + suffixRetired).ToNot(BeAnExistingFile())
+
+			loaded, err := newAutoSignerPath(location, "", quick)
+			Expect(err).To(Succeed())
+			Expect(loaded.fingerprint).To(Equal(r.Next.fingerprint))
+		})
+	})
+})
@@ -2,6 +2,10 @@ package bw
 
 import (
 	"math"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
 )
 
 const (
@@ -28,6 +32,61 @@ func PartitionFromFloat64(p float64) Partitioner {
 	}
 }
 
+// ParsePartitioner generates a partitioner from a concurrency specification.
+// rules:
+// numeric values follow the rules of PartitionFromFloat64.
+// auto(min,pct,max): deploy pct percent (0-100, optionally suffixed with %) of the nodes
+// clamped to [min, max].
+func ParsePartitioner(spec string) (_ Partitioner, err error) {
+	var (
+		f float64
+	)
+
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return PartitionFromFloat64(0), nil
+	}
+
+	if strings.HasPrefix(spec, "auto(") && strings.HasSuffix(spec, ")") {
+		return parseAutoPartitioner(strings.TrimSuffix(strings.TrimPrefix(spec, "auto("), ")"))
+	}
+
+	if f, err = strconv.ParseFloat(spec, 64); err != nil {
+		return nil, errors.Errorf("invalid concurrency: %s", spec)
+	}
+
+	return PartitionFromFloat64(f), nil
+}
+
+func parseAutoPartitioner(args string) (p AutoPartitioner, err error) {
+	fields := strings.Split(args, ",")
+	if len(fields) != 3 {
+		return p, errors.Errorf("invalid concurrency auto(%s): expected auto(min,pct,max)", args)
+	}
+
+	if p.Minimum, err = strconv.Atoi(strings.TrimSpace(fields[0])); err != nil {
+		return p, errors.Errorf("invalid concurrency auto(%s): invalid minimum", args)
+	}
+
+	if p.Percent, err = strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(fields[1]), "%"), 64); err != nil {
+		return p, errors.Errorf("invalid concurrency auto(%s): invalid percentage", args)
+	}
+
+	if p.Maximum, err = strconv.Atoi(strings.TrimSpace(fields[2])); err != nil {
+		return p, errors.Errorf("invalid concurrency auto(%s): invalid maximum", args)
+	}
+
+	if p.Minimum < 1 || p.Maximum < p.Minimum {
+		return p, errors.Errorf("invalid concurrency auto(%s): requires 1 <= min <= max", args)
+	}
+
+	if p.Percent < 0 || p.Percent > 100 {
+		return p, errors.Errorf("invalid concurrency auto(%s): percentage must be between 0 and 100", args)
+	}
+
+	return p, nil
+}
+
 // Partitioner determines the number of nodes to simultaneously deploy to
 // based on the total number of nodes.
 type Partitioner interface {
@@ -52,6 +111,19 @@ func (t ConstantPartitioner) Partition(length int) int {
 	return max(1, min(length, int(t)))
 }
 
+// AutoPartitioner size is a percentage (0-100) of the nodes clamped to [Minimum, Maximum].
+type AutoPartitioner struct {
+	Minimum int
+	Percent float64
+	Maximum int
+}
+
+// Partition implements partitioner
+func (t AutoPartitioner) Partition(length int) int {
+	computed := int(math.Floor(float64(length) * t.Percent / 100))
+	return ConstantPartitioner(max(t.Minimum, min(computed, t.Maximum))).Partition(length)
+}
+
 func min(a, b int) int {
 	if a < b {
 		return a
@@ -0,0 +1,36 @@
+package bw_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/james-lawrence/bw"
+)
+
+var _ = Describe("Partition", func() {
+	DescribeTable("AutoPartitioner", func(spec string, length, expected int) {
+		p, err := ParsePartitioner(spec)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(p.Partition(length)).To(Equal(expected))
+	},
+		Entry("clamped to the minimum", "auto(2,10,20)", 5, 2),
+		Entry("minimum bounded by the cluster size", "auto(2,10,20)", 1, 1),
+		Entry("within range", "auto(2,10,20)", 100, 10),
+		Entry("within range with percent suffix", "auto(2, 10%, 20)", 150, 15),
+		Entry("clamped to the maximum", "auto(2,10,20)", 1000, 20),
+		Entry("numeric percentage", "0.5", 10, 5),
+		Entry("numeric constant", "3", 10, 3),
+		Entry("default", "", 10, 1),
+	)
+
+	DescribeTable("ParsePartitioner invalid specifications", func(spec string) {
+		_, err := ParsePartitioner(spec)
+		Expect(err).To(HaveOccurred())
+	},
+		Entry("not a number", "foo"),
+		Entry("missing arguments", "auto(2,10)"),
+		Entry("minimum greater than maximum", "auto(20,10,2)"),
+		Entry("zero minimum", "auto(0,10,2)"),
+		Entry("percentage out of range", "auto(2,110,20)"),
+	)
+})
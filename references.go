@@ -0,0 +1,238 @@
+package bw
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	yaml "gopkg.in/yaml.v2"
+
+	"github.com/james-lawrence/bw/internal/envx"
+)
+
+// matches ${name} within a configuration value.
+var referencePattern = regexp.MustCompile(`\$\{([^{}]+)\}`)
+
+// ExpandAndDecodeFileReferences expands the environment variables within the file at the
+// specified path and then decodes it as yaml, see ExpandEnvironAndDecodeReferences.
+func ExpandAndDecodeFileReferences(path string, dst interface{}) (err error) {
+	return decodeFile(path, dst, func(raw []byte, dst interface{}) error {
+		return ExpandEnvironAndDecodeReferences(raw, dst, os.LookupEnv)
+	})
+}
+
+// ExpandEnvironAndDecodeReferences expands the environment variables using the lookup and then
+// decodes the yaml. references to other fields of the document, e.g.) ${.environment} or ${deploy.dir},
+// are resolved once decoded. undefined environment variables and references are rejected.
+func ExpandEnvironAndDecodeReferences(raw []byte, dst interface{}, lookup func(string) (string, bool)) (err error) {
+	var (
+		undefined []string
+	)
+
+	m := func(in string) string {
+		// field references are resolved after decoding.
+		if isReference(in) {
+			return "${" + in + "}"
+		}
+
+		v, ok := lookup(in)
+		if !ok {
+			undefined = append(undefined, in)
+		}
+
+		return normalizeEnv(v)
+	}
+
+	expanded := os.Expand(string(raw), m)
+
+	if len(undefined) > 0 {
+		return errors.Errorf("undefined environment variable: ${%s}", undefined[0])
+	}
+
+	if envx.Boolean(false, EnvLogsConfiguration, EnvLogsVerbose) {
+		log.Println("configuration:\n", expanded)
+	}
+
+	if !strings.Contains(expanded, "${") {
+		return yaml.Unmarshal([]byte(expanded), dst)
+	}
+
+	return decodeReferences([]byte(expanded), dst)
+}
+
+// isReference returns true when the name of a ${name} expansion references another field of
+// the configuration rather than an environment variable. references are the yaml path to the
+// field separated by dots, top level fields are prefixed with a dot. e.g.) ${.serverName}, ${p2pBind.port}
+func isReference(name string) bool {
+	return strings.Contains(name, ".")
+}
+
+// resolveReferences replaces the field references within the decoded yaml document with the
+// referenced values. a value consisting of a single reference retains the type of the referenced
+// value, otherwise the referenced values are interpolated into the string.
+func resolveReferences(document interface{}) (interface{}, error) {
+	r := resolver{root: document, resolved: map[string]interface{}{}}
+	return r.walk(document, nil)
+}
+
+type resolver struct {
+	root     interface{}
+	resolved map[string]interface{}
+}
+
+func (t resolver) walk(v interface{}, stack []string) (_ interface{}, err error) {
+	switch v := v.(type) {
+	case map[interface{}]interface{}:
+		for k, child := range v {
+			if v[k], err = t.walk(child, stack); err != nil {
+				return nil, err
+			}
+		}
+		return v, nil
+	case []interface{}:
+		for i, child := range v {
+			if v[i], err = t.walk(child, stack); err != nil {
+				return nil, err
+			}
+		}
+		return v, nil
+	case string:
+		return t.interpolate(v, stack)
+	default:
+		return v, nil
+	}
+}
+
+func (t resolver) interpolate(s string, stack []string) (_ interface{}, err error) {
+	var (
+		resolved interface{}
+	)
+
+	matches := referencePattern.FindAllStringSubmatchIndex(s, -1)
+	references := make([][]int, 0, len(matches))
+	for _, m := range matches {
+		if isReference(s[m[2]:m[3]]) {
+			references = append(references, m)
+		}
+	}
+
+	if len(references) == 0 {
+		return s, nil
+	}
+
+	// the entire value is a reference, retain the type of the referenced value.
+	if m := references[0]; len(references) == 1 && m[0] == 0 && m[1] == len(s) {
+		return t.lookup(s[m[2]:m[3]], stack)
+	}
+
+	b := strings.Builder{}
+	offset := 0
+	for _, m := range references {
+		if resolved, err = t.lookup(s[m[2]:m[3]], stack); err != nil {
+			return nil, err
+		}
+
+		switch resolved.(type) {
+		case map[interface{}]interface{}, []interface{}:
+			return nil, errors.Errorf("unable to interpolate config reference ${%s}: not a scalar value", s[m[2]:m[3]])
+		}
+
+		b.WriteString(s[offset:m[0]])
+		if resolved != nil {
+			b.WriteString(fmt.Sprint(resolved))
+		}
+		offset = m[1]
+	}
+	b.WriteString(s[offset:])
+
+	return b.String(), nil
+}
+
+// lookup the value of the reference, resolving any references within the value.
+func (t resolver) lookup(name string, stack []string) (_ interface{}, err error) {
+	var (
+		current = t.root
+		path    = strings.TrimPrefix(name, ".")
+	)
+
+	if v, ok := t.resolved[path]; ok {
+		return v, nil
+	}
+
+	for _, visited := range stack {
+		if visited == path {
+			return nil, errors.Errorf("config reference cycle: %s -> %s", strings.Join(stack, " -> "), path)
+		}
+	}
+
+	for _, key := range strings.Split(path, ".") {
+		var ok bool
+
+		switch c := current.(type) {
+		case map[interface{}]interface{}:
+			current, ok = c[key]
+		case []interface{}:
+			var idx int
+			if idx, err = strconv.Atoi(key); err == nil && idx >= 0 && idx < len(c) {
+				current, ok = c[idx], true
+			}
+		}
+
+		if !ok {
+			return nil, errors.Errorf("undefined config reference: ${%s}", name)
+		}
+	}
+
+	if current, err = t.walk(copydocument(current), append(stack, path)); err != nil {
+		return nil, err
+	}
+
+	t.resolved[path] = current
+
+	return current, nil
+}
+
+// copydocument deep copies the collections of the document, walking replaces values in place.
+func copydocument(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[interface{}]interface{}:
+		dup := make(map[interface{}]interface{}, len(v))
+		for k, child := range v {
+			dup[k] = copydocument(child)
+		}
+		return dup
+	case []interface{}:
+		dup := make([]interface{}, 0, len(v))
+		for _, child := range v {
+			dup = append(dup, copydocument(child))
+		}
+		return dup
+	default:
+		return v
+	}
+}
+
+// decodeReferences decodes the yaml document after resolving its field references.
+func decodeReferences(raw []byte, dst interface{}) (err error) {
+	var (
+		document interface{}
+	)
+
+	if err = yaml.Unmarshal(raw, &document); err != nil {
+		return errors.WithStack(err)
+	}
+
+	if document, err = resolveReferences(document); err != nil {
+		return err
+	}
+
+	if raw, err = yaml.Marshal(document); err != nil {
+		return errors.WithStack(err)
+	}
+
+	return yaml.Unmarshal(raw, dst)
+}
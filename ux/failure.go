@@ -28,7 +28,7 @@ func (t failure) Consume(m *agent.Message) consumer {
 	switch m.Type {
 	case agent.Message_DeployCommandEvent:
 		t.logs()
-		t.cState.printDeployCommand(m)
+		t.cState.print(m)
 		return nil // done.
 	case agent.Message_DeployEvent:
 		d := m.GetDeploy()
@@ -5,6 +5,7 @@ import (
 	"crypto/md5"
 	"encoding/hex"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"time"
@@ -27,6 +28,13 @@ func OptionFailureDisplay(fd failureDisplay) Option {
 	}
 }
 
+// OptionObserver invoked with each message of the deploy as it is consumed.
+func OptionObserver(fn func(*agent.Message)) Option {
+	return func(cs *cState) {
+		cs.observer = fn
+	}
+}
+
 func OptionHeartbeat(d time.Duration) Option {
 	return func(cs *cState) {
 		cs.heartbeat = 3 * d
@@ -50,6 +58,8 @@ func Deploy(ctx context.Context, cached *dialers.Cached, events chan *agent.Mess
 				FailureDisplay: FailureDisplayNoop{},
 				au:             aurora.NewAurora(true),
 				Logger:         log.New(os.Stderr, "[CLIENT] ", 0),
+				output:         os.Stdout,
+				emitted:        new(int),
 			}.merge(options...),
 		}
 	)
@@ -68,6 +78,8 @@ func Logging(ctx context.Context, cached *dialers.Cached, events chan *agent.Mes
 				FailureDisplay: FailureDisplayNoop{},
 				au:             aurora.NewAurora(true),
 				Logger:         log.New(os.Stderr, "[CLIENT] ", 0),
+				output:         os.Stdout,
+				emitted:        new(int),
 			}.merge(options...),
 		}
 	)
@@ -80,6 +92,8 @@ func (t cState) run(ctx context.Context, events chan *agent.Message, s consumer)
 		last *agent.Message
 	)
 
+	defer t.closeProgress()
+
 	for {
 		select {
 		case <-time.After(t.heartbeat):
@@ -91,8 +105,10 @@ func (t cState) run(ctx context.Context, events chan *agent.Message, s consumer)
 			switch local := m.Event.(type) {
 			case *agent.Message_History:
 				replayable := slice(last, local.History.Messages...)
+				t.observe(replayable...)
 				s = consume(s, replayable...)
 			default:
+				t.observe(m)
 				s = consume(s, m)
 			}
 
@@ -129,6 +145,16 @@ func slice(last *agent.Message, messages ...*agent.Message) []*agent.Message {
 	return []*agent.Message{}
 }
 
+func (t cState) observe(messages ...*agent.Message) {
+	if t.observer == nil {
+		return
+	}
+
+	for _, m := range messages {
+		t.observer(m)
+	}
+}
+
 func consume(c consumer, messages ...*agent.Message) consumer {
 	for _, m := range messages {
 		// log.Println("consuming", messageDebug(m))
@@ -148,6 +174,10 @@ type cState struct {
 	au             aurora.Aurora
 	heartbeat      time.Duration
 	debug          bool
+	format         string
+	output         io.Writer
+	emitted        *int // number of progress events emitted, shared by the copies of the state.
+	observer       func(*agent.Message)
 }
 
 func (t cState) merge(options ...Option) cState {
@@ -161,6 +191,11 @@ func (t cState) merge(options ...Option) cState {
 }
 
 func (t cState) print(m *agent.Message) {
+	if t.machine() {
+		t.printProgress(m)
+		return
+	}
+
 	switch evt := m.Event.(type) {
 	case *agent.Message_Int:
 		switch m.Type {
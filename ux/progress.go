@@ -0,0 +1,179 @@
+package ux
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/james-lawrence/bw"
+	"github.com/james-lawrence/bw/agent"
+)
+
+// OptionProgressFormat set the format progress is emitted in.
+// see agent.ProgressFormatHuman, agent.ProgressFormatJSON, and agent.ProgressFormatNDJSON.
+// unknown formats fallback to human readable output.
+func OptionProgressFormat(kind string) Option {
+	return func(cs *cState) {
+		cs.format = kind
+	}
+}
+
+// OptionProgressOutput set the destination for machine readable progress events.
+// defaults to stdout.
+func OptionProgressOutput(w io.Writer) Option {
+	return func(cs *cState) {
+		cs.output = w
+	}
+}
+
+// Progress machine readable representation of a deploy event.
+type Progress struct {
+	Node       string    `json:"node"`
+	Phase      string    `json:"phase"`
+	Status     string    `json:"status"`
+	Timestamp  time.Time `json:"timestamp"`
+	Deployment string    `json:"deployment,omitempty"`
+	Count      int64     `json:"count,omitempty"`
+	Message    string    `json:"message,omitempty"`
+}
+
+func (t cState) machine() bool {
+	switch t.format {
+	case agent.ProgressFormatJSON, agent.ProgressFormatNDJSON:
+		return true
+	default:
+		return false
+	}
+}
+
+// printProgress ndjson emits each event as a line, json emits a single array
+// of events which is terminated by closeProgress.
+func (t cState) printProgress(m *agent.Message) {
+	var (
+		ok      bool
+		p       Progress
+		encoded []byte
+		err     error
+	)
+
+	if p, ok = t.progress(m); !ok {
+		return
+	}
+
+	if t.format == agent.ProgressFormatNDJSON {
+		if err = json.NewEncoder(t.output).Encode(p); err != nil {
+			t.Logger.Println("failed to encode progress", err)
+		}
+		return
+	}
+
+	if encoded, err = json.MarshalIndent(p, "  ", "  "); err != nil {
+		t.Logger.Println("failed to encode progress", err)
+		return
+	}
+
+	sep := ",\n  "
+	if *t.emitted == 0 {
+		sep = "[\n  "
+	}
+	*t.emitted++
+
+	if _, err = fmt.Fprint(t.output, sep, string(encoded)); err != nil {
+		t.Logger.Println("failed to write progress", err)
+	}
+}
+
+// closeProgress terminates the json array of events.
+func (t cState) closeProgress() {
+	var (
+		err error
+	)
+
+	if t.format != agent.ProgressFormatJSON {
+		return
+	}
+
+	if *t.emitted == 0 {
+		_, err = fmt.Fprintln(t.output, "[]")
+	} else {
+		_, err = fmt.Fprint(t.output, "\n]\n")
+	}
+
+	if err != nil {
+		t.Logger.Println("failed to write progress", err)
+	}
+}
+
+func (t cState) progress(m *agent.Message) (p Progress, ok bool) {
+	p = Progress{
+		Node:      m.GetPeer().GetName(),
+		Timestamp: time.Unix(m.GetTs(), 0).UTC(),
+	}
+
+	switch evt := m.Event.(type) {
+	case *agent.Message_Int:
+		p.Phase = "peers"
+		p.Count = evt.Int
+		switch m.Type {
+		case agent.Message_PeersFoundEvent:
+			p.Status = "found"
+		case agent.Message_PeersCompletedEvent:
+			p.Status = "completed"
+		default:
+			p.Status = lower(m.Type)
+		}
+	case *agent.Message_Log:
+		p.Phase = "log"
+		p.Status = "info"
+		p.Message = evt.Log.Log
+	case *agent.Message_DeployCommand:
+		p.Phase = "deploy"
+		p.Status = lower(evt.DeployCommand.Command)
+		p.Deployment = deploymentID(evt.DeployCommand.Archive)
+		p.Message = evt.DeployCommand.Initiator
+	case *agent.Message_Deploy:
+		p.Phase = "node"
+		p.Status = lower(evt.Deploy.Stage)
+		p.Deployment = deploymentID(evt.Deploy.Archive)
+		p.Message = evt.Deploy.Error
+	case *agent.Message_Membership:
+		p.Phase = "membership"
+		p.Status = lower(evt.Membership)
+	case *agent.Message_Connection:
+		if t.connection.State == evt.Connection.State {
+			return p, false
+		}
+
+		t.connection.State = evt.Connection.State
+		p.Phase = "connection"
+		p.Status = lower(evt.Connection.State)
+		p.Message = evt.Connection.Description
+	case *agent.Message_History:
+		return p, false
+	case *agent.Message_Heartbeat:
+		if !t.debug {
+			return p, false
+		}
+		p.Phase = "heartbeat"
+		p.Status = "alive"
+	default:
+		p.Phase = "event"
+		p.Status = lower(m.Type)
+	}
+
+	return p, true
+}
+
+func deploymentID(a *agent.Archive) string {
+	if a == nil || len(a.DeploymentID) == 0 {
+		return ""
+	}
+
+	return bw.RandomID(a.DeploymentID).String()
+}
+
+func lower(s interface{ String() string }) string {
+	return strings.ToLower(s.String())
+}
@@ -0,0 +1,98 @@
+package ux_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/james-lawrence/bw/agent"
+	"github.com/james-lawrence/bw/internal/contextx"
+	. "github.com/james-lawrence/bw/ux"
+)
+
+var _ = Describe("Progress", func() {
+	It("should emit a ndjson line for each phase transition", func() {
+		var (
+			out   bytes.Buffer
+			node1 = agent.NewPeer("node1")
+			node2 = agent.NewPeer("node2")
+			dopts = &agent.DeployOptions{}
+			a     = &agent.Archive{DeploymentID: []byte("deployment")}
+		)
+
+		messages := []*agent.Message{
+			agent.NewDeployCommand(node1, &agent.DeployCommand{Command: agent.DeployCommand_Begin, Archive: a, Options: dopts}),
+			agent.PeersFoundEvent(node1, 2),
+			agent.DeployEvent(node1, &agent.Deploy{Stage: agent.Deploy_Deploying, Archive: a, Options: dopts}),
+			agent.DeployEvent(node2, &agent.Deploy{Stage: agent.Deploy_Deploying, Archive: a, Options: dopts}),
+			agent.LogEvent(node1, "info message"),
+			agent.DeployEvent(node1, &agent.Deploy{Stage: agent.Deploy_Completed, Archive: a, Options: dopts}),
+			agent.DeployEvent(node2, &agent.Deploy{Stage: agent.Deploy_Completed, Archive: a, Options: dopts}),
+			agent.NewDeployCommand(node1, &agent.DeployCommand{Command: agent.DeployCommand_Done, Archive: a, Options: dopts}),
+		}
+
+		buf := make(chan *agent.Message, len(messages))
+		for _, m := range messages {
+			buf <- m
+		}
+
+		ctx := contextx.NewWaitGroup(context.Background())
+		Deploy(ctx, nil, buf, OptionProgressFormat(agent.ProgressFormatNDJSON), OptionProgressOutput(&out))
+		Expect(len(buf)).To(Equal(0))
+
+		transitions := make([][3]string, 0, len(messages))
+		scanner := bufio.NewScanner(&out)
+		for scanner.Scan() {
+			var p Progress
+			Expect(json.Unmarshal(scanner.Bytes(), &p)).To(Succeed())
+			Expect(p.Timestamp.IsZero()).To(BeFalse())
+			transitions = append(transitions, [3]string{p.Node, p.Phase, p.Status})
+		}
+		Expect(scanner.Err()).To(Succeed())
+
+		Expect(transitions).To(Equal([][3]string{
+			{"node1", "deploy", "begin"},
+			{"node1", "peers", "found"},
+			{"node1", "node", "deploying"},
+			{"node2", "node", "deploying"},
+			{"node1", "log", "info"},
+			{"node1", "node", "completed"},
+			{"node2", "node", "completed"},
+			{"node1", "deploy", "done"},
+		}))
+	})
+
+	It("should emit a single json array of events", func() {
+		var (
+			out   bytes.Buffer
+			node1 = agent.NewPeer("node1")
+			dopts = &agent.DeployOptions{}
+			a     = &agent.Archive{DeploymentID: []byte("deployment")}
+		)
+
+		messages := []*agent.Message{
+			agent.NewDeployCommand(node1, &agent.DeployCommand{Command: agent.DeployCommand_Begin, Archive: a, Options: dopts}),
+			agent.DeployEvent(node1, &agent.Deploy{Stage: agent.Deploy_Completed, Archive: a, Options: dopts}),
+			agent.NewDeployCommand(node1, &agent.DeployCommand{Command: agent.DeployCommand_Done, Archive: a, Options: dopts}),
+		}
+
+		buf := make(chan *agent.Message, len(messages))
+		for _, m := range messages {
+			buf <- m
+		}
+
+		ctx := contextx.NewWaitGroup(context.Background())
+		Deploy(ctx, nil, buf, OptionProgressFormat(agent.ProgressFormatJSON), OptionProgressOutput(&out))
+
+		var progress []Progress
+		Expect(json.Unmarshal(out.Bytes(), &progress)).To(Succeed())
+		Expect(progress).To(HaveLen(3))
+		Expect(progress[0].Status).To(Equal("begin"))
+		Expect(progress[1].Status).To(Equal("completed"))
+		Expect(progress[2].Status).To(Equal("done"))
+	})
+})